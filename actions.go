@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+	"github.com/JyotinderSingh/gitgraphed/render"
+)
+
+// writeGitHubActions appends graph's key stats to $GITHUB_OUTPUT, for later
+// workflow steps, and its Markdown summary with a heatmap to
+// $GITHUB_STEP_SUMMARY, for the run page. Either file is skipped when unset.
+func writeGitHubActions(graph *gitgraph.ContributionGraph, opts render.MarkdownOptions) {
+	outputPath, summaryPath := os.Getenv("GITHUB_OUTPUT"), os.Getenv("GITHUB_STEP_SUMMARY")
+	if outputPath == "" && summaryPath == "" {
+		fatal("--github-actions needs $GITHUB_OUTPUT or $GITHUB_STEP_SUMMARY, which are set in workflow steps")
+	}
+
+	if outputPath != "" {
+		streaks := gitgraph.ComputeStreaks(graph.Days, time.Now())
+		summary := gitgraph.Summarize(graph.Days)
+		var b bytes.Buffer
+		for _, kv := range [][2]string{
+			{"username", graph.Username},
+			{"total", strconv.Itoa(graph.TotalContribs)},
+			{"current_streak", strconv.Itoa(streaks.Current.Length)},
+			{"longest_streak", strconv.Itoa(streaks.Longest.Length)},
+			{"active_days", strconv.Itoa(summary.ActiveDays)},
+			{"busiest_weekday", summary.BusiestWeekday},
+			{"best_day", summary.MaxDay.Date},
+			{"best_day_count", strconv.Itoa(summary.MaxDay.Count)},
+		} {
+			fmt.Fprintf(&b, "%s=%s\n", kv[0], kv[1])
+		}
+		if err := appendFile(outputPath, b.Bytes()); err != nil {
+			fatal("writing GitHub Actions outputs", "err", err)
+		}
+	}
+
+	if summaryPath != "" {
+		opts.Heatmap = true
+		var b bytes.Buffer
+		if err := render.Markdown(&b, graph, opts); err != nil {
+			fatal("rendering step summary", "err", err)
+		}
+		b.WriteString("\n")
+		if err := appendFile(summaryPath, b.Bytes()); err != nil {
+			fatal("writing GitHub Actions step summary", "err", err)
+		}
+	}
+	slog.Debug("wrote GitHub Actions outputs", "output", outputPath, "summary", summaryPath)
+}
+
+// appendFile appends data to the file at path, as the runner expects for its
+// command files because earlier steps may have written to them
+func appendFile(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
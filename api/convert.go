@@ -0,0 +1,71 @@
+package api
+
+import "github.com/JyotinderSingh/gitgraphed/gitgraph"
+
+// FromGraph converts graph to its protobuf message
+func FromGraph(graph *gitgraph.ContributionGraph) *ContributionGraph {
+	msg := &ContributionGraph{
+		Username:             graph.Username,
+		TotalContributions:   int32(graph.TotalContribs),
+		PrivateContributions: int32(graph.PrivateContribs),
+	}
+	for _, year := range graph.Years {
+		msg.Years = append(msg.Years, int32(year))
+	}
+	for _, day := range graph.Days {
+		msg.Days = append(msg.Days, FromDay(day))
+	}
+	if graph.Streaks != nil {
+		msg.CurrentStreak = fromStreak(graph.Streaks.Current)
+		msg.LongestStreak = fromStreak(graph.Streaks.Longest)
+	}
+	return msg
+}
+
+// FromDay converts day to its protobuf message
+func FromDay(day gitgraph.ContributionDay) *ContributionDay {
+	return &ContributionDay{
+		Date:         day.Date,
+		Count:        int32(day.Count),
+		Level:        int32(day.Level),
+		DayOfWeek:    int32(day.DayOfWeek),
+		WeekOfYear:   int32(day.WeekOfYear),
+		ContribLevel: day.ContribLevel,
+		GridWeek:     int32(day.GridWeek),
+	}
+}
+
+func fromStreak(streak gitgraph.Streak) *Streak {
+	return &Streak{Length: int32(streak.Length), Start: streak.Start, End: streak.End}
+}
+
+// Graph converts the message back to a ContributionGraph
+func (m *ContributionGraph) Graph() *gitgraph.ContributionGraph {
+	graph := &gitgraph.ContributionGraph{
+		Username:        m.GetUsername(),
+		TotalContribs:   int(m.GetTotalContributions()),
+		PrivateContribs: int(m.GetPrivateContributions()),
+	}
+	for _, year := range m.GetYears() {
+		graph.Years = append(graph.Years, int(year))
+	}
+	for _, day := range m.GetDays() {
+		graph.Days = append(graph.Days, gitgraph.ContributionDay{
+			Date:         day.GetDate(),
+			Count:        int(day.GetCount()),
+			Level:        int(day.GetLevel()),
+			DayOfWeek:    int(day.GetDayOfWeek()),
+			WeekOfYear:   int(day.GetWeekOfYear()),
+			ContribLevel: day.GetContribLevel(),
+			GridWeek:     int(day.GetGridWeek()),
+		})
+	}
+	if m.CurrentStreak != nil || m.LongestStreak != nil {
+		graph.Streaks = &gitgraph.Streaks{Current: m.GetCurrentStreak().streak(), Longest: m.GetLongestStreak().streak()}
+	}
+	return graph
+}
+
+func (m *Streak) streak() gitgraph.Streak {
+	return gitgraph.Streak{Length: int(m.GetLength()), Start: m.GetStart(), End: m.GetEnd()}
+}
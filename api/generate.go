@@ -0,0 +1,5 @@
+// Package api holds the protobuf schema of contribution graphs, the gRPC
+// service definition and the code generated from them.
+package api
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative graph.proto gitgraphed.proto
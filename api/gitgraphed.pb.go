@@ -0,0 +1,444 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: gitgraphed.proto
+
+package api
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type WatchEvent_Type int32
+
+const (
+	WatchEvent_TYPE_UNSPECIFIED WatchEvent_Type = 0
+	WatchEvent_TYPE_SNAPSHOT    WatchEvent_Type = 1
+	WatchEvent_TYPE_CHANGE      WatchEvent_Type = 2
+	WatchEvent_TYPE_ERROR       WatchEvent_Type = 3
+)
+
+// Enum value maps for WatchEvent_Type.
+var (
+	WatchEvent_Type_name = map[int32]string{
+		0: "TYPE_UNSPECIFIED",
+		1: "TYPE_SNAPSHOT",
+		2: "TYPE_CHANGE",
+		3: "TYPE_ERROR",
+	}
+	WatchEvent_Type_value = map[string]int32{
+		"TYPE_UNSPECIFIED": 0,
+		"TYPE_SNAPSHOT":    1,
+		"TYPE_CHANGE":      2,
+		"TYPE_ERROR":       3,
+	}
+)
+
+func (x WatchEvent_Type) Enum() *WatchEvent_Type {
+	p := new(WatchEvent_Type)
+	*p = x
+	return p
+}
+
+func (x WatchEvent_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (WatchEvent_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_gitgraphed_proto_enumTypes[0].Descriptor()
+}
+
+func (WatchEvent_Type) Type() protoreflect.EnumType {
+	return &file_gitgraphed_proto_enumTypes[0]
+}
+
+func (x WatchEvent_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use WatchEvent_Type.Descriptor instead.
+func (WatchEvent_Type) EnumDescriptor() ([]byte, []int) {
+	return file_gitgraphed_proto_rawDescGZIP(), []int{3, 0}
+}
+
+// GraphRequest selects whose graph to fetch and for which period. With from and
+// to unset it covers the calendar year, defaulting to the current one.
+type GraphRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Year          int32                  `protobuf:"varint,2,opt,name=year,proto3" json:"year,omitempty"`
+	From          string                 `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"` // YYYY-MM-DD
+	To            string                 `protobuf:"bytes,4,opt,name=to,proto3" json:"to,omitempty"`     // YYYY-MM-DD
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GraphRequest) Reset() {
+	*x = GraphRequest{}
+	mi := &file_gitgraphed_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GraphRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GraphRequest) ProtoMessage() {}
+
+func (x *GraphRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gitgraphed_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GraphRequest.ProtoReflect.Descriptor instead.
+func (*GraphRequest) Descriptor() ([]byte, []int) {
+	return file_gitgraphed_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GraphRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *GraphRequest) GetYear() int32 {
+	if x != nil {
+		return x.Year
+	}
+	return 0
+}
+
+func (x *GraphRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *GraphRequest) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+// WatchRequest polls the rolling year of username every interval_seconds
+type WatchRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Username        string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	IntervalSeconds int32                  `protobuf:"varint,2,opt,name=interval_seconds,json=intervalSeconds,proto3" json:"interval_seconds,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *WatchRequest) Reset() {
+	*x = WatchRequest{}
+	mi := &file_gitgraphed_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRequest) ProtoMessage() {}
+
+func (x *WatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gitgraphed_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRequest.ProtoReflect.Descriptor instead.
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return file_gitgraphed_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *WatchRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *WatchRequest) GetIntervalSeconds() int32 {
+	if x != nil {
+		return x.IntervalSeconds
+	}
+	return 0
+}
+
+type DayChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Date          string                 `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"`
+	Before        int32                  `protobuf:"varint,2,opt,name=before,proto3" json:"before,omitempty"`
+	After         int32                  `protobuf:"varint,3,opt,name=after,proto3" json:"after,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DayChange) Reset() {
+	*x = DayChange{}
+	mi := &file_gitgraphed_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DayChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DayChange) ProtoMessage() {}
+
+func (x *DayChange) ProtoReflect() protoreflect.Message {
+	mi := &file_gitgraphed_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DayChange.ProtoReflect.Descriptor instead.
+func (*DayChange) Descriptor() ([]byte, []int) {
+	return file_gitgraphed_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *DayChange) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *DayChange) GetBefore() int32 {
+	if x != nil {
+		return x.Before
+	}
+	return 0
+}
+
+func (x *DayChange) GetAfter() int32 {
+	if x != nil {
+		return x.After
+	}
+	return 0
+}
+
+type WatchEvent struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Type               WatchEvent_Type        `protobuf:"varint,1,opt,name=type,proto3,enum=gitgraphed.v1.WatchEvent_Type" json:"type,omitempty"`
+	TimeUnix           int64                  `protobuf:"varint,2,opt,name=time_unix,json=timeUnix,proto3" json:"time_unix,omitempty"`
+	Username           string                 `protobuf:"bytes,3,opt,name=username,proto3" json:"username,omitempty"`
+	TotalContributions int32                  `protobuf:"varint,4,opt,name=total_contributions,json=totalContributions,proto3" json:"total_contributions,omitempty"`
+	Change             *DayChange             `protobuf:"bytes,5,opt,name=change,proto3" json:"change,omitempty"`
+	Error              string                 `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *WatchEvent) Reset() {
+	*x = WatchEvent{}
+	mi := &file_gitgraphed_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchEvent) ProtoMessage() {}
+
+func (x *WatchEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_gitgraphed_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchEvent.ProtoReflect.Descriptor instead.
+func (*WatchEvent) Descriptor() ([]byte, []int) {
+	return file_gitgraphed_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *WatchEvent) GetType() WatchEvent_Type {
+	if x != nil {
+		return x.Type
+	}
+	return WatchEvent_TYPE_UNSPECIFIED
+}
+
+func (x *WatchEvent) GetTimeUnix() int64 {
+	if x != nil {
+		return x.TimeUnix
+	}
+	return 0
+}
+
+func (x *WatchEvent) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *WatchEvent) GetTotalContributions() int32 {
+	if x != nil {
+		return x.TotalContributions
+	}
+	return 0
+}
+
+func (x *WatchEvent) GetChange() *DayChange {
+	if x != nil {
+		return x.Change
+	}
+	return nil
+}
+
+func (x *WatchEvent) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+var File_gitgraphed_proto protoreflect.FileDescriptor
+
+const file_gitgraphed_proto_rawDesc = "" +
+	"\n" +
+	"\x10gitgraphed.proto\x12\rgitgraphed.v1\x1a\vgraph.proto\"b\n" +
+	"\fGraphRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x12\n" +
+	"\x04year\x18\x02 \x01(\x05R\x04year\x12\x12\n" +
+	"\x04from\x18\x03 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x04 \x01(\tR\x02to\"U\n" +
+	"\fWatchRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12)\n" +
+	"\x10interval_seconds\x18\x02 \x01(\x05R\x0fintervalSeconds\"M\n" +
+	"\tDayChange\x12\x12\n" +
+	"\x04date\x18\x01 \x01(\tR\x04date\x12\x16\n" +
+	"\x06before\x18\x02 \x01(\x05R\x06before\x12\x14\n" +
+	"\x05after\x18\x03 \x01(\x05R\x05after\"\xc4\x02\n" +
+	"\n" +
+	"WatchEvent\x122\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x1e.gitgraphed.v1.WatchEvent.TypeR\x04type\x12\x1b\n" +
+	"\ttime_unix\x18\x02 \x01(\x03R\btimeUnix\x12\x1a\n" +
+	"\busername\x18\x03 \x01(\tR\busername\x12/\n" +
+	"\x13total_contributions\x18\x04 \x01(\x05R\x12totalContributions\x120\n" +
+	"\x06change\x18\x05 \x01(\v2\x18.gitgraphed.v1.DayChangeR\x06change\x12\x14\n" +
+	"\x05error\x18\x06 \x01(\tR\x05error\"P\n" +
+	"\x04Type\x12\x14\n" +
+	"\x10TYPE_UNSPECIFIED\x10\x00\x12\x11\n" +
+	"\rTYPE_SNAPSHOT\x10\x01\x12\x0f\n" +
+	"\vTYPE_CHANGE\x10\x02\x12\x0e\n" +
+	"\n" +
+	"TYPE_ERROR\x10\x032\xeb\x01\n" +
+	"\n" +
+	"GitGraphed\x12I\n" +
+	"\bGetGraph\x12\x1b.gitgraphed.v1.GraphRequest\x1a .gitgraphed.v1.ContributionGraph\x12K\n" +
+	"\n" +
+	"StreamDays\x12\x1b.gitgraphed.v1.GraphRequest\x1a\x1e.gitgraphed.v1.ContributionDay0\x01\x12E\n" +
+	"\tWatchUser\x12\x1b.gitgraphed.v1.WatchRequest\x1a\x19.gitgraphed.v1.WatchEvent0\x01B*Z(github.com/JyotinderSingh/gitgraphed/apib\x06proto3"
+
+var (
+	file_gitgraphed_proto_rawDescOnce sync.Once
+	file_gitgraphed_proto_rawDescData []byte
+)
+
+func file_gitgraphed_proto_rawDescGZIP() []byte {
+	file_gitgraphed_proto_rawDescOnce.Do(func() {
+		file_gitgraphed_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_gitgraphed_proto_rawDesc), len(file_gitgraphed_proto_rawDesc)))
+	})
+	return file_gitgraphed_proto_rawDescData
+}
+
+var file_gitgraphed_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_gitgraphed_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_gitgraphed_proto_goTypes = []any{
+	(WatchEvent_Type)(0),      // 0: gitgraphed.v1.WatchEvent.Type
+	(*GraphRequest)(nil),      // 1: gitgraphed.v1.GraphRequest
+	(*WatchRequest)(nil),      // 2: gitgraphed.v1.WatchRequest
+	(*DayChange)(nil),         // 3: gitgraphed.v1.DayChange
+	(*WatchEvent)(nil),        // 4: gitgraphed.v1.WatchEvent
+	(*ContributionGraph)(nil), // 5: gitgraphed.v1.ContributionGraph
+	(*ContributionDay)(nil),   // 6: gitgraphed.v1.ContributionDay
+}
+var file_gitgraphed_proto_depIdxs = []int32{
+	0, // 0: gitgraphed.v1.WatchEvent.type:type_name -> gitgraphed.v1.WatchEvent.Type
+	3, // 1: gitgraphed.v1.WatchEvent.change:type_name -> gitgraphed.v1.DayChange
+	1, // 2: gitgraphed.v1.GitGraphed.GetGraph:input_type -> gitgraphed.v1.GraphRequest
+	1, // 3: gitgraphed.v1.GitGraphed.StreamDays:input_type -> gitgraphed.v1.GraphRequest
+	2, // 4: gitgraphed.v1.GitGraphed.WatchUser:input_type -> gitgraphed.v1.WatchRequest
+	5, // 5: gitgraphed.v1.GitGraphed.GetGraph:output_type -> gitgraphed.v1.ContributionGraph
+	6, // 6: gitgraphed.v1.GitGraphed.StreamDays:output_type -> gitgraphed.v1.ContributionDay
+	4, // 7: gitgraphed.v1.GitGraphed.WatchUser:output_type -> gitgraphed.v1.WatchEvent
+	5, // [5:8] is the sub-list for method output_type
+	2, // [2:5] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_gitgraphed_proto_init() }
+func file_gitgraphed_proto_init() {
+	if File_gitgraphed_proto != nil {
+		return
+	}
+	file_graph_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_gitgraphed_proto_rawDesc), len(file_gitgraphed_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_gitgraphed_proto_goTypes,
+		DependencyIndexes: file_gitgraphed_proto_depIdxs,
+		EnumInfos:         file_gitgraphed_proto_enumTypes,
+		MessageInfos:      file_gitgraphed_proto_msgTypes,
+	}.Build()
+	File_gitgraphed_proto = out.File
+	file_gitgraphed_proto_goTypes = nil
+	file_gitgraphed_proto_depIdxs = nil
+}
@@ -0,0 +1,214 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: gitgraphed.proto
+
+package api
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	GitGraphed_GetGraph_FullMethodName   = "/gitgraphed.v1.GitGraphed/GetGraph"
+	GitGraphed_StreamDays_FullMethodName = "/gitgraphed.v1.GitGraphed/StreamDays"
+	GitGraphed_WatchUser_FullMethodName  = "/gitgraphed.v1.GitGraphed/WatchUser"
+)
+
+// GitGraphedClient is the client API for GitGraphed service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// GitGraphed serves contribution graphs to other services
+type GitGraphedClient interface {
+	// GetGraph returns the whole graph for a period
+	GetGraph(ctx context.Context, in *GraphRequest, opts ...grpc.CallOption) (*ContributionGraph, error)
+	// StreamDays returns the days of a period one message at a time
+	StreamDays(ctx context.Context, in *GraphRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ContributionDay], error)
+	// WatchUser polls a user's graph, sending a snapshot and then every change
+	WatchUser(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchEvent], error)
+}
+
+type gitGraphedClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewGitGraphedClient(cc grpc.ClientConnInterface) GitGraphedClient {
+	return &gitGraphedClient{cc}
+}
+
+func (c *gitGraphedClient) GetGraph(ctx context.Context, in *GraphRequest, opts ...grpc.CallOption) (*ContributionGraph, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ContributionGraph)
+	err := c.cc.Invoke(ctx, GitGraphed_GetGraph_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gitGraphedClient) StreamDays(ctx context.Context, in *GraphRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ContributionDay], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &GitGraphed_ServiceDesc.Streams[0], GitGraphed_StreamDays_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[GraphRequest, ContributionDay]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type GitGraphed_StreamDaysClient = grpc.ServerStreamingClient[ContributionDay]
+
+func (c *gitGraphedClient) WatchUser(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &GitGraphed_ServiceDesc.Streams[1], GitGraphed_WatchUser_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchRequest, WatchEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type GitGraphed_WatchUserClient = grpc.ServerStreamingClient[WatchEvent]
+
+// GitGraphedServer is the server API for GitGraphed service.
+// All implementations must embed UnimplementedGitGraphedServer
+// for forward compatibility.
+//
+// GitGraphed serves contribution graphs to other services
+type GitGraphedServer interface {
+	// GetGraph returns the whole graph for a period
+	GetGraph(context.Context, *GraphRequest) (*ContributionGraph, error)
+	// StreamDays returns the days of a period one message at a time
+	StreamDays(*GraphRequest, grpc.ServerStreamingServer[ContributionDay]) error
+	// WatchUser polls a user's graph, sending a snapshot and then every change
+	WatchUser(*WatchRequest, grpc.ServerStreamingServer[WatchEvent]) error
+	mustEmbedUnimplementedGitGraphedServer()
+}
+
+// UnimplementedGitGraphedServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedGitGraphedServer struct{}
+
+func (UnimplementedGitGraphedServer) GetGraph(context.Context, *GraphRequest) (*ContributionGraph, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetGraph not implemented")
+}
+func (UnimplementedGitGraphedServer) StreamDays(*GraphRequest, grpc.ServerStreamingServer[ContributionDay]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamDays not implemented")
+}
+func (UnimplementedGitGraphedServer) WatchUser(*WatchRequest, grpc.ServerStreamingServer[WatchEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchUser not implemented")
+}
+func (UnimplementedGitGraphedServer) mustEmbedUnimplementedGitGraphedServer() {}
+func (UnimplementedGitGraphedServer) testEmbeddedByValue()                    {}
+
+// UnsafeGitGraphedServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to GitGraphedServer will
+// result in compilation errors.
+type UnsafeGitGraphedServer interface {
+	mustEmbedUnimplementedGitGraphedServer()
+}
+
+func RegisterGitGraphedServer(s grpc.ServiceRegistrar, srv GitGraphedServer) {
+	// If the following call pancis, it indicates UnimplementedGitGraphedServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&GitGraphed_ServiceDesc, srv)
+}
+
+func _GitGraphed_GetGraph_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GraphRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GitGraphedServer).GetGraph(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GitGraphed_GetGraph_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GitGraphedServer).GetGraph(ctx, req.(*GraphRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GitGraphed_StreamDays_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GraphRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GitGraphedServer).StreamDays(m, &grpc.GenericServerStream[GraphRequest, ContributionDay]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type GitGraphed_StreamDaysServer = grpc.ServerStreamingServer[ContributionDay]
+
+func _GitGraphed_WatchUser_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GitGraphedServer).WatchUser(m, &grpc.GenericServerStream[WatchRequest, WatchEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type GitGraphed_WatchUserServer = grpc.ServerStreamingServer[WatchEvent]
+
+// GitGraphed_ServiceDesc is the grpc.ServiceDesc for GitGraphed service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var GitGraphed_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "gitgraphed.v1.GitGraphed",
+	HandlerType: (*GitGraphedServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetGraph",
+			Handler:    _GitGraphed_GetGraph_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamDays",
+			Handler:       _GitGraphed_StreamDays_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchUser",
+			Handler:       _GitGraphed_WatchUser_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "gitgraphed.proto",
+}
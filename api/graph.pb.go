@@ -0,0 +1,351 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: graph.proto
+
+package api
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ContributionDay is one calendar day of a contribution graph
+type ContributionDay struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Date          string                 `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"` // YYYY-MM-DD
+	Count         int32                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	Level         int32                  `protobuf:"varint,3,opt,name=level,proto3" json:"level,omitempty"`                            // 0-4
+	DayOfWeek     int32                  `protobuf:"varint,4,opt,name=day_of_week,json=dayOfWeek,proto3" json:"day_of_week,omitempty"` // 0 is Sunday
+	WeekOfYear    int32                  `protobuf:"varint,5,opt,name=week_of_year,json=weekOfYear,proto3" json:"week_of_year,omitempty"`
+	ContribLevel  string                 `protobuf:"bytes,6,opt,name=contrib_level,json=contribLevel,proto3" json:"contrib_level,omitempty"` // none, first_quartile, second_quartile, third_quartile or fourth_quartile
+	GridWeek      int32                  `protobuf:"varint,7,opt,name=grid_week,json=gridWeek,proto3" json:"grid_week,omitempty"`            // column in the graph's Sunday-started calendar, 0 holding its first day: 0-52 for a year, 53 when a leap year starts on a Saturday, counting on for longer graphs
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ContributionDay) Reset() {
+	*x = ContributionDay{}
+	mi := &file_graph_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ContributionDay) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContributionDay) ProtoMessage() {}
+
+func (x *ContributionDay) ProtoReflect() protoreflect.Message {
+	mi := &file_graph_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContributionDay.ProtoReflect.Descriptor instead.
+func (*ContributionDay) Descriptor() ([]byte, []int) {
+	return file_graph_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ContributionDay) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *ContributionDay) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *ContributionDay) GetLevel() int32 {
+	if x != nil {
+		return x.Level
+	}
+	return 0
+}
+
+func (x *ContributionDay) GetDayOfWeek() int32 {
+	if x != nil {
+		return x.DayOfWeek
+	}
+	return 0
+}
+
+func (x *ContributionDay) GetWeekOfYear() int32 {
+	if x != nil {
+		return x.WeekOfYear
+	}
+	return 0
+}
+
+func (x *ContributionDay) GetContribLevel() string {
+	if x != nil {
+		return x.ContribLevel
+	}
+	return ""
+}
+
+func (x *ContributionDay) GetGridWeek() int32 {
+	if x != nil {
+		return x.GridWeek
+	}
+	return 0
+}
+
+// Streak is a run of consecutive days with contributions
+type Streak struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Length        int32                  `protobuf:"varint,1,opt,name=length,proto3" json:"length,omitempty"`
+	Start         string                 `protobuf:"bytes,2,opt,name=start,proto3" json:"start,omitempty"` // YYYY-MM-DD
+	End           string                 `protobuf:"bytes,3,opt,name=end,proto3" json:"end,omitempty"`     // YYYY-MM-DD
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Streak) Reset() {
+	*x = Streak{}
+	mi := &file_graph_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Streak) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Streak) ProtoMessage() {}
+
+func (x *Streak) ProtoReflect() protoreflect.Message {
+	mi := &file_graph_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Streak.ProtoReflect.Descriptor instead.
+func (*Streak) Descriptor() ([]byte, []int) {
+	return file_graph_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Streak) GetLength() int32 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+func (x *Streak) GetStart() string {
+	if x != nil {
+		return x.Start
+	}
+	return ""
+}
+
+func (x *Streak) GetEnd() string {
+	if x != nil {
+		return x.End
+	}
+	return ""
+}
+
+// ContributionGraph is a user's contributions over a period, as written by
+// --format pb and returned by the gRPC service. Fields are only ever added, so
+// readers of gitgraphed.v1 keep working across releases.
+type ContributionGraph struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Username             string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	TotalContributions   int32                  `protobuf:"varint,2,opt,name=total_contributions,json=totalContributions,proto3" json:"total_contributions,omitempty"`
+	Years                []int32                `protobuf:"varint,3,rep,packed,name=years,proto3" json:"years,omitempty"`
+	Days                 []*ContributionDay     `protobuf:"bytes,4,rep,name=days,proto3" json:"days,omitempty"`
+	CurrentStreak        *Streak                `protobuf:"bytes,5,opt,name=current_streak,json=currentStreak,proto3" json:"current_streak,omitempty"`
+	LongestStreak        *Streak                `protobuf:"bytes,6,opt,name=longest_streak,json=longestStreak,proto3" json:"longest_streak,omitempty"`
+	PrivateContributions int32                  `protobuf:"varint,7,opt,name=private_contributions,json=privateContributions,proto3" json:"private_contributions,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *ContributionGraph) Reset() {
+	*x = ContributionGraph{}
+	mi := &file_graph_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ContributionGraph) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContributionGraph) ProtoMessage() {}
+
+func (x *ContributionGraph) ProtoReflect() protoreflect.Message {
+	mi := &file_graph_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContributionGraph.ProtoReflect.Descriptor instead.
+func (*ContributionGraph) Descriptor() ([]byte, []int) {
+	return file_graph_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ContributionGraph) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *ContributionGraph) GetTotalContributions() int32 {
+	if x != nil {
+		return x.TotalContributions
+	}
+	return 0
+}
+
+func (x *ContributionGraph) GetYears() []int32 {
+	if x != nil {
+		return x.Years
+	}
+	return nil
+}
+
+func (x *ContributionGraph) GetDays() []*ContributionDay {
+	if x != nil {
+		return x.Days
+	}
+	return nil
+}
+
+func (x *ContributionGraph) GetCurrentStreak() *Streak {
+	if x != nil {
+		return x.CurrentStreak
+	}
+	return nil
+}
+
+func (x *ContributionGraph) GetLongestStreak() *Streak {
+	if x != nil {
+		return x.LongestStreak
+	}
+	return nil
+}
+
+func (x *ContributionGraph) GetPrivateContributions() int32 {
+	if x != nil {
+		return x.PrivateContributions
+	}
+	return 0
+}
+
+var File_graph_proto protoreflect.FileDescriptor
+
+const file_graph_proto_rawDesc = "" +
+	"\n" +
+	"\vgraph.proto\x12\rgitgraphed.v1\"\xd5\x01\n" +
+	"\x0fContributionDay\x12\x12\n" +
+	"\x04date\x18\x01 \x01(\tR\x04date\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x05R\x05count\x12\x14\n" +
+	"\x05level\x18\x03 \x01(\x05R\x05level\x12\x1e\n" +
+	"\vday_of_week\x18\x04 \x01(\x05R\tdayOfWeek\x12 \n" +
+	"\fweek_of_year\x18\x05 \x01(\x05R\n" +
+	"weekOfYear\x12#\n" +
+	"\rcontrib_level\x18\x06 \x01(\tR\fcontribLevel\x12\x1b\n" +
+	"\tgrid_week\x18\a \x01(\x05R\bgridWeek\"H\n" +
+	"\x06Streak\x12\x16\n" +
+	"\x06length\x18\x01 \x01(\x05R\x06length\x12\x14\n" +
+	"\x05start\x18\x02 \x01(\tR\x05start\x12\x10\n" +
+	"\x03end\x18\x03 \x01(\tR\x03end\"\xdb\x02\n" +
+	"\x11ContributionGraph\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12/\n" +
+	"\x13total_contributions\x18\x02 \x01(\x05R\x12totalContributions\x12\x14\n" +
+	"\x05years\x18\x03 \x03(\x05R\x05years\x122\n" +
+	"\x04days\x18\x04 \x03(\v2\x1e.gitgraphed.v1.ContributionDayR\x04days\x12<\n" +
+	"\x0ecurrent_streak\x18\x05 \x01(\v2\x15.gitgraphed.v1.StreakR\rcurrentStreak\x12<\n" +
+	"\x0elongest_streak\x18\x06 \x01(\v2\x15.gitgraphed.v1.StreakR\rlongestStreak\x123\n" +
+	"\x15private_contributions\x18\a \x01(\x05R\x14privateContributionsB*Z(github.com/JyotinderSingh/gitgraphed/apib\x06proto3"
+
+var (
+	file_graph_proto_rawDescOnce sync.Once
+	file_graph_proto_rawDescData []byte
+)
+
+func file_graph_proto_rawDescGZIP() []byte {
+	file_graph_proto_rawDescOnce.Do(func() {
+		file_graph_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_graph_proto_rawDesc), len(file_graph_proto_rawDesc)))
+	})
+	return file_graph_proto_rawDescData
+}
+
+var file_graph_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_graph_proto_goTypes = []any{
+	(*ContributionDay)(nil),   // 0: gitgraphed.v1.ContributionDay
+	(*Streak)(nil),            // 1: gitgraphed.v1.Streak
+	(*ContributionGraph)(nil), // 2: gitgraphed.v1.ContributionGraph
+}
+var file_graph_proto_depIdxs = []int32{
+	0, // 0: gitgraphed.v1.ContributionGraph.days:type_name -> gitgraphed.v1.ContributionDay
+	1, // 1: gitgraphed.v1.ContributionGraph.current_streak:type_name -> gitgraphed.v1.Streak
+	1, // 2: gitgraphed.v1.ContributionGraph.longest_streak:type_name -> gitgraphed.v1.Streak
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_graph_proto_init() }
+func file_graph_proto_init() {
+	if File_graph_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_graph_proto_rawDesc), len(file_graph_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_graph_proto_goTypes,
+		DependencyIndexes: file_graph_proto_depIdxs,
+		MessageInfos:      file_graph_proto_msgTypes,
+	}.Build()
+	File_graph_proto = out.File
+	file_graph_proto_goTypes = nil
+	file_graph_proto_depIdxs = nil
+}
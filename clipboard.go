@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// binaryFormats can't be pasted as text, so --copy refuses them
+var binaryFormats = []string{"png", "gif", "pbm", "bmp", "parquet", "pb", "msgpack"}
+
+// addCopyFlag registers --copy, which checkCopy validates against the format
+func addCopyFlag(fs *flag.FlagSet) *bool {
+	return fs.Bool("copy", false, "place the output on the clipboard instead of stdout, for pasting into chats and documents; --out is still written when set")
+}
+
+// checkCopy exits when format can't be copied as text
+func checkCopy(copyOut bool, format string) {
+	if copyOut && contains(binaryFormats, format) {
+		fatal("--copy needs a text format such as json, md, emoji or svg", "format", format)
+	}
+}
+
+// copyOutput places what write produces on the clipboard, also writing it
+// to path when set, exiting on failure
+func copyOutput(path string, write func(io.Writer) error) {
+	var buf bytes.Buffer
+	if err := write(&buf); err != nil {
+		fatal("writing output", "err", err)
+	}
+	if path != "" {
+		out, err := createOutput(path)
+		if err != nil {
+			fatal("creating output", "err", err)
+		}
+		_, err = out.Write(buf.Bytes())
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			fatal("writing output", "err", err)
+		}
+	}
+	if err := copyToClipboard(context.Background(), buf.Bytes()); err != nil {
+		fatal("copying to the clipboard", "err", err)
+	}
+	slog.Info("copied to the clipboard", "bytes", buf.Len())
+}
+
+// copyToClipboard puts data on the system clipboard, using pbcopy on macOS,
+// PowerShell on Windows, and wl-copy, xclip or xsel elsewhere
+func copyToClipboard(ctx context.Context, data []byte) error {
+	switch runtime.GOOS {
+	case "darwin":
+		return runWithInput(ctx, data, "pbcopy")
+	case "windows":
+		// clip.exe would read the input in the console's code page, garbling emoji
+		script := "[Console]::InputEncoding = [Text.Encoding]::UTF8; Set-Clipboard -Value ([Console]::In.ReadToEnd())"
+		return runWithInput(ctx, data, "powershell", "-NoProfile", "-NonInteractive", "-Command", script)
+	}
+	if _, err := exec.LookPath("wl-copy"); err == nil && os.Getenv("WAYLAND_DISPLAY") != "" {
+		return runWithInput(ctx, data, "wl-copy")
+	}
+	if _, err := exec.LookPath("xclip"); err == nil && os.Getenv("DISPLAY") != "" {
+		return runWithInput(ctx, data, "xclip", "-selection", "clipboard")
+	}
+	if _, err := exec.LookPath("xsel"); err == nil && os.Getenv("DISPLAY") != "" {
+		return runWithInput(ctx, data, "xsel", "--clipboard", "--input")
+	}
+	return errors.New("no clipboard found: need wl-copy on Wayland, or xclip or xsel on X11")
+}
+
+// runWithInput runs a command with data on its stdin. Output isn't captured:
+// xclip and wl-copy stay behind to serve the clipboard, and a pipe they
+// inherit would keep the command from returning.
+func runWithInput(ctx context.Context, data []byte, name string, args ...string) error {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdin = bytes.NewReader(data)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/JyotinderSingh/gitgraphed/export"
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+// batchResult is one user's outcome in batch mode
+type batchResult struct {
+	Username string                      `json:"username"`
+	Graph    *gitgraph.ContributionGraph `json:"graph,omitempty"`
+	Error    string                      `json:"error,omitempty"`
+	Code     string                      `json:"code,omitempty"` // e.g. user_not_found, see classifyError
+}
+
+func runBatch(ctx context.Context, config *Config, args []string) {
+	fs := newFlagSet("batch")
+	clientFlags := addClientFlags(fs, config)
+	targetFlags := addTargetFlags(fs)
+	levelFlags := addLevelFlags(fs)
+	file := fs.String("f", "", "file of usernames, one per line (- for stdin)")
+	rate := fs.Float64("rate", 2, "maximum requests per second, 0 for no limit")
+	merge := fs.Bool("merge", false, "write one JSON array in input order instead of JSON lines as users complete")
+	format := fs.String("format", "json", "json for one result per user, jsonl for one line per day, or parquet for one file of every user's days")
+	outPath := fs.String("out", "", "write output to this file or s3:// or gs:// URL instead of stdout")
+	fieldFlags := addFieldFlags(fs)
+	args = parseInterspersed(fs, args)
+
+	if *file == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+	if *format != "json" && *format != "jsonl" && *format != "parquet" || *format != "json" && *merge {
+		fatal("unsupported format", "format", *format)
+	}
+	fields := fieldFlags.apply(*format)
+	usernames, err := readUsernames(*file)
+	if err != nil {
+		fatal("reading usernames", "err", err)
+	}
+	spec := ""
+	if len(args) > 0 {
+		spec = args[0]
+	}
+	p, err := targetFlags.period(spec)
+	if err != nil {
+		fatal("invalid period", "err", err)
+	}
+
+	client := clientFlags.newClient()
+	if *rate > 0 {
+		retry := client.HTTPClient.Transport.(*gitgraph.RetryTransport)
+		retry.Base = gitgraph.NewRateLimitTransport(retry.Base, *rate)
+	}
+
+	out, err := createOutput(*outPath)
+	if err != nil {
+		fatal("creating output", "err", err)
+	}
+
+	var mu sync.Mutex
+	encoder := json.NewEncoder(out)
+	var table *export.ParquetWriter
+	if *format == "parquet" {
+		table = export.NewParquetWriter(out)
+	}
+	// With --merge, results are written in input order as soon as all before
+	// them are done, holding only those that arrive early. Workers more than
+	// a window ahead of the next to write wait for it, so a slow user can't
+	// leave the rest of the batch pending behind them.
+	array := &jsonArray{w: out}
+	pending := map[int]batchResult{}
+	next := 0
+	window := 4 * max(*clientFlags.workers, 1)
+	caughtUp := sync.NewCond(&mu)
+	var results []batchResult
+	if levelFlags.global() {
+		results = make([]batchResult, len(usernames))
+	}
+	failed := 0
+	write := func(result batchResult) {
+		switch {
+		case table != nil:
+			if result.Graph != nil {
+				if err := table.Write(result.Graph); err != nil {
+					slog.Error("writing result", "username", result.Username, "err", err)
+				}
+			}
+		case *format == "jsonl":
+			// Each user's days are written and dropped as soon as they arrive
+			if result.Graph != nil {
+				if err := export.JSONLFields(out, result.Graph, fields); err != nil {
+					slog.Error("writing result", "username", result.Username, "err", err)
+				}
+			}
+		case *merge:
+			if err := array.Write(selectResult(result, fields)); err != nil {
+				slog.Error("writing result", "username", result.Username, "err", err)
+			}
+		default:
+			if err := encoder.Encode(selectResult(result, fields)); err != nil {
+				slog.Error("writing result", "username", result.Username, "err", err)
+			}
+		}
+	}
+	fetchAll(usernames, *clientFlags.workers, func(i int, username string) {
+		// Years of one user are fetched one at a time; the pool spreads across users
+		graph, err := fetchGraph(ctx, client, username, p, 1)
+		result := batchResult{Username: username, Graph: graph}
+		if err != nil {
+			result.Error = err.Error()
+			result.Code, _ = classifyError(err)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			failed++
+			slog.Error("fetching contribution data", "error", result.Code, "err", err, "username", username)
+		}
+		if graph != nil && !levelFlags.global() {
+			levelFlags.apply(graph, client.CurrentTime())
+		}
+		// A global scale needs every user's counts, so results wait for the last
+		if levelFlags.global() {
+			results[i] = result
+			return
+		}
+		if !*merge {
+			write(result)
+			return
+		}
+		pending[i] = result
+		for ; ; next++ {
+			result, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			write(result)
+		}
+		caughtUp.Broadcast()
+		for i >= next+window {
+			caughtUp.Wait()
+		}
+	})
+	exitIfInterrupted(ctx)
+
+	if levelFlags.global() {
+		graphs := []*gitgraph.ContributionGraph{}
+		for _, result := range results {
+			if result.Graph != nil {
+				graphs = append(graphs, result.Graph)
+			}
+		}
+		levelFlags.applyAll(graphs, client.CurrentTime())
+		for _, result := range results {
+			write(result)
+		}
+	}
+
+	if *merge {
+		if err := array.Close(); err != nil {
+			fatal("writing output", "err", err)
+		}
+	}
+	if table != nil {
+		if err := table.Close(); err != nil {
+			fatal("writing output", "err", err)
+		}
+	}
+	slog.Info("fetched users", "succeeded", len(usernames)-failed, "total", len(usernames))
+	if err := out.Close(); err != nil {
+		fatal("writing output", "err", err)
+	}
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// selectResult reduces the days of result's graph to fields, unless there are none
+func selectResult(result batchResult, fields []string) any {
+	if len(fields) == 0 || result.Graph == nil {
+		return result
+	}
+	graph, err := export.SelectFields(result.Graph, fields)
+	if err != nil {
+		fatal("writing output", "err", err)
+	}
+	return struct {
+		batchResult
+		Graph any `json:"graph"`
+	}{result, graph}
+}
+
+// fetchAll calls fn for every username using at most workers goroutines
+func fetchAll(usernames []string, workers int, fn func(i int, username string)) {
+	if workers < 1 {
+		workers = 1
+	}
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				fn(i, usernames[i])
+			}
+		}()
+	}
+	for i := range usernames {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
+
+// readUsernames reads one username per line from path ("-" for stdin),
+// skipping blank lines and # comments
+func readUsernames(path string) ([]string, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	usernames := []string{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		usernames = append(usernames, line)
+	}
+	return usernames, scanner.Err()
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/metrics"
+	"strings"
+	"testing"
+	"time"
+)
+
+// peakHeap runs fn while sampling the heap each garbage collection finds
+// live, returning the most seen
+func peakHeap(fn func()) uint64 {
+	sample := []metrics.Sample{{Name: "/gc/heap/live:bytes"}}
+	done := make(chan struct{})
+	peak := make(chan uint64)
+	runtime.GC()
+	go func() {
+		var most uint64
+		ticker := time.NewTicker(100 * time.Microsecond)
+		defer ticker.Stop()
+		for {
+			metrics.Read(sample)
+			most = max(most, sample[0].Value.Uint64())
+			select {
+			case <-done:
+				peak <- most
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	fn()
+	close(done)
+	return <-peak
+}
+
+// BenchmarkBatch writes ever longer batches of fake users, as JSON lines and
+// as one merged array. Results are written as they complete, so the live heap
+// stays about the same while allocations grow with the users.
+func BenchmarkBatch(b *testing.B) {
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	for _, size := range []int{100, 1000, 3000} {
+		usernames := make([]string, size)
+		for i := range usernames {
+			usernames[i] = fmt.Sprintf("user%d", i)
+		}
+		file := filepath.Join(b.TempDir(), "usernames.txt")
+		if err := os.WriteFile(file, []byte(strings.Join(usernames, "\n")), 0o644); err != nil {
+			b.Fatal(err)
+		}
+		for _, format := range []string{"json", "merge"} {
+			b.Run(fmt.Sprintf("%s/users=%d", format, size), func(b *testing.B) {
+				args := []string{"-f", file, "--provider", "fake", "--rate", "0", "--out", os.DevNull, "2024"}
+				if format == "merge" {
+					args = append(args, "--merge")
+				}
+				b.ReportAllocs()
+				var peak uint64
+				for i := 0; i < b.N; i++ {
+					peak = max(peak, peakHeap(func() {
+						runBatch(context.Background(), &Config{}, args)
+					}))
+				}
+				b.ReportMetric(float64(peak), "peak-live-B")
+			})
+		}
+	}
+}
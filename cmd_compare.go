@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+	"github.com/JyotinderSingh/gitgraphed/render"
+)
+
+func runCompare(ctx context.Context, config *Config, args []string) {
+	fs := newFlagSet("compare")
+	clientFlags := addClientFlags(fs, config)
+	levelFlags := addLevelFlags(fs)
+	year := fs.Int("year", time.Now().Year(), "year to compare")
+	yearsSpec := fs.String("years", "", "compare these years of one user instead, e.g. 2023,2024 or 2020-2024 (svg only)")
+	format := fs.String("format", "json", "output format: json, or svg for the calendars in one image with a shared legend")
+	layout := fs.String("layout", "stacked", "how svg places the calendars: stacked or side-by-side")
+	outPath := fs.String("out", "", "write output to this file or s3:// or gs:// URL instead of stdout")
+	themeName := fs.String("theme", config.Theme, "color theme for svg output")
+	localeTag := fs.String("locale", config.Locale, "language of month and weekday labels in svg output, e.g. de-DE")
+	usernames := parseInterspersed(fs, args)
+	if len(usernames) == 0 {
+		usernames = config.Usernames
+	}
+
+	if *format != "json" && *format != "svg" {
+		fatal("unsupported format", "format", *format)
+	}
+	if *layout != "stacked" && *layout != "side-by-side" {
+		fatal("unsupported layout, expected stacked or side-by-side", "layout", *layout)
+	}
+	var years []int
+	if *yearsSpec != "" {
+		var err error
+		if years, err = parseYears(*yearsSpec); err != nil {
+			fatal("invalid --years", "err", err)
+		}
+		if *format != "svg" {
+			fatal("--years needs --format svg; yoy compares two years as text or JSON")
+		}
+		if len(usernames) == 0 || len(years) < 2 {
+			fs.Usage()
+			os.Exit(1)
+		}
+		usernames = usernames[:1]
+	} else if len(usernames) < 2 {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	client := clientFlags.newClient()
+	var graphs []*gitgraph.ContributionGraph
+	if len(years) > 0 {
+		for _, y := range years {
+			graphs = append(graphs, mustFetchGraph(ctx, client, usernames[0], period{Years: []int{y}}, *clientFlags.workers))
+		}
+	} else {
+		var err error
+		graphs, err = client.FetchUsers(ctx, usernames, gitgraph.Options{Year: *year}, *clientFlags.workers)
+		if err != nil {
+			exitIfInterrupted(ctx)
+			fatalError("fetching contribution data", err)
+		}
+	}
+	levelFlags.applyAll(graphs, client.CurrentTime())
+	if *format == "json" {
+		writeJSON(gitgraph.Compare(graphs, time.Now()))
+		return
+	}
+
+	opts := render.CompareOptions{SVGOptions: render.DefaultSVGOptions, SideBySide: *layout == "side-by-side"}
+	opts.Locale = parseLocale(*localeTag)
+	if *themeName != "" {
+		theme, err := config.theme(*themeName)
+		if err != nil {
+			fatal("invalid theme", "err", err)
+		}
+		opts.Theme = theme
+	}
+	panels := make([]render.ComparePanel, len(graphs))
+	for i, graph := range graphs {
+		y := *year
+		if len(years) > 0 {
+			y = years[i]
+		}
+		panels[i] = render.ComparePanel{
+			Graph:   graph,
+			Caption: fmt.Sprintf("%s: %d %s in %d", graph.Username, graph.TotalContribs, pluralize(graph.TotalContribs, "contribution", "contributions"), y),
+		}
+	}
+
+	out, err := createOutput(*outPath)
+	if err != nil {
+		fatal("creating output", "err", err)
+	}
+	err = render.CompareSVG(out, panels, opts)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		fatal("writing output", "format", *format, "err", err)
+	}
+}
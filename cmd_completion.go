@@ -0,0 +1,242 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+	"github.com/JyotinderSingh/gitgraphed/locale"
+	"github.com/JyotinderSingh/gitgraphed/render"
+)
+
+// completionShells are the shells completion writes scripts for
+var completionShells = []string{"bash", "zsh", "fish"}
+
+func runCompletion(ctx context.Context, config *Config, args []string) {
+	fs := newFlagSet("completion")
+	args = parseInterspersed(fs, args)
+	if len(args) != 1 || !contains(completionShells, args[0]) {
+		fs.Usage()
+		os.Exit(1)
+	}
+	// The scripts ask the executable for candidates, so they stay current
+	// as commands and flags change
+	fmt.Print(completionScripts[args[0]])
+}
+
+// completionScripts call gitgraphed __complete with the words up to the
+// cursor, offering the candidates it prints one per line, each description
+// after a tab. Without candidates the shell completes file names.
+var completionScripts = map[string]string{
+	"bash": `# gitgraphed completion for bash: source this file, or add to ~/.bashrc
+#   source <(gitgraphed completion bash)
+_gitgraphed() {
+    local cur=${COMP_WORDS[COMP_CWORD]}
+    [[ $cur == "=" ]] && cur=""
+    local IFS=$'\n'
+    COMPREPLY=($(compgen -W "$(gitgraphed __complete "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null | cut -f1)" -- "$cur"))
+}
+complete -o default -F _gitgraphed gitgraphed
+`,
+	"zsh": `#compdef gitgraphed
+# gitgraphed completion for zsh: save as _gitgraphed in a directory of $fpath,
+# or add to ~/.zshrc
+#   source <(gitgraphed completion zsh)
+_gitgraphed() {
+    local -a candidates
+    local line
+    for line in "${(@f)$(gitgraphed __complete "${(@)words[2,CURRENT]}" 2>/dev/null)}"; do
+        [[ -z $line ]] && continue
+        if [[ $line == *$'\t'* ]]; then
+            candidates+=("${${line%%$'\t'*}//:/\\:}:${line#*$'\t'}")
+        else
+            candidates+=("${line//:/\\:}")
+        fi
+    done
+    if (( ${#candidates} )); then
+        _describe gitgraphed candidates
+    else
+        _files
+    fi
+}
+if [[ $funcstack[1] == _gitgraphed ]]; then
+    _gitgraphed "$@"
+else
+    compdef _gitgraphed gitgraphed
+fi
+`,
+	"fish": `# gitgraphed completion for fish: save as
+# ~/.config/fish/completions/gitgraphed.fish, or run
+#   gitgraphed completion fish | source
+function __gitgraphed_complete
+    set -l tokens (commandline -opc) (commandline -ct)
+    gitgraphed __complete $tokens[2..-1] 2>/dev/null
+end
+complete -c gitgraphed -a '(__gitgraphed_complete)'
+`,
+}
+
+// subcommands are the words that may follow a command
+var subcommands = map[string][]string{
+	"local":      {"org"},
+	"store":      {"prune"},
+	"completion": completionShells,
+}
+
+// commandFormats are the values of each command's --format
+var commandFormats = map[string][]string{
+	"fetch":       dataFormats,
+	"query":       dataFormats,
+	"render":      renderFormats,
+	"batch":       {"json", "jsonl", "parquet"},
+	"compare":     {"json", "svg"},
+	"diff":        {"json", "text"},
+	"leaderboard": {"term", "md", "json"},
+	"plan":        {"csv", "script", "json", "term"},
+	"progress":    {"text", "json"},
+	"punchcard":   {"json", "svg"},
+	"report":      {"html", "pdf"},
+	"seasons":     {"text", "md", "json"},
+	"trends":      {"json", "svg", "spark"},
+	"widget":      {"svg", "md"},
+	"yoy":         {"json", "text", "svg"},
+}
+
+// flagValues lists the values of the flag name of command, nil for flags
+// taking file names or free-form values
+func flagValues(config *Config, command, name string) []string {
+	switch name {
+	case "format":
+		return commandFormats[command]
+	case "theme":
+		names := render.ThemeNames()
+		for custom := range config.Themes {
+			names = append(names, custom)
+		}
+		sort.Strings(names)
+		return names
+	case "color":
+		return colorModes
+	case "sort":
+		return gitgraph.DayOrders
+	case "locale":
+		return locale.Tags()
+	case "provider":
+		return gitgraph.ProviderNames()
+	case "type":
+		return gitgraph.ContributionTypeNames
+	case "week-start":
+		return []string{"sunday", "monday"}
+	case "week-number":
+		return []string{"iso", "grid"}
+	case "levels":
+		return []string{"quartiles", "global"}
+	case "shape":
+		return jsonShapes
+	case "rollup":
+		return []string{"week", "month"}
+	case "log-format":
+		return []string{"text", "json"}
+	}
+	return nil
+}
+
+// completeFlagSet, when set, receives each command's flag set as it is
+// parsed instead of the arguments, which is how __complete learns the flags
+// of a command without running it
+var completeFlagSet func(fs *flag.FlagSet)
+
+// runComplete prints the candidates for the last of words, the one at the
+// cursor, given the words of the command line before it
+func runComplete(ctx context.Context, config *Config, words []string) {
+	if len(words) == 0 {
+		words = []string{""}
+	}
+	current, before := words[len(words)-1], words[:len(words)-1]
+	if len(before) == 0 {
+		for _, cmd := range commands {
+			printCandidate(cmd.Name, cmd.Summary, current)
+		}
+		return
+	}
+
+	// Bash splits --flag=value into --flag, = and value
+	flagName, prefix := "", ""
+	switch {
+	case current == "=" && len(before) > 0 && strings.HasPrefix(before[len(before)-1], "-"):
+		flagName, current = before[len(before)-1], ""
+	case len(before) > 1 && before[len(before)-1] == "=" && strings.HasPrefix(before[len(before)-2], "-"):
+		flagName = before[len(before)-2]
+	case strings.HasPrefix(current, "-") && strings.Contains(current, "="):
+		flagName, current, _ = strings.Cut(current, "=")
+		prefix = flagName + "="
+	case strings.HasPrefix(before[len(before)-1], "-") && !strings.Contains(before[len(before)-1], "="):
+		flagName = before[len(before)-1]
+	}
+	flagName = strings.TrimLeft(flagName, "-")
+
+	cmd, found := findCommand(before[0])
+	args := before[1:]
+	if !found {
+		// Arguments without a command go to fetch
+		cmd, args = commands[0], before
+	}
+	if found && len(args) == 0 && !strings.HasPrefix(current, "-") && flagName == "" {
+		for _, sub := range subcommands[cmd.Name] {
+			printCandidate(sub, "", current)
+		}
+		return
+	}
+
+	completeFlagSet = func(fs *flag.FlagSet) {
+		if f := fs.Lookup(flagName); f != nil && !isBoolFlag(f) {
+			for _, value := range flagValues(config, fs.Name(), f.Name) {
+				printCandidate(prefix+value, "", prefix+current)
+			}
+			os.Exit(0)
+		}
+		if strings.HasPrefix(current, "-") {
+			fs.VisitAll(func(f *flag.Flag) {
+				printCandidate("--"+f.Name, flagSummary(f.Usage), current)
+			})
+		}
+		os.Exit(0)
+	}
+	// Commands exit here once their flags are defined
+	cmd.Run(ctx, config, args)
+}
+
+// printCandidate prints candidate when it extends current, with its
+// description after a tab
+func printCandidate(candidate, description, current string) {
+	if !strings.HasPrefix(candidate, current) {
+		return
+	}
+	if description == "" {
+		fmt.Println(candidate)
+		return
+	}
+	fmt.Printf("%s\t%s\n", candidate, description)
+}
+
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// flagSummary shortens a flag's usage to its first clause, to fit beside it
+func flagSummary(usage string) string {
+	for _, sep := range []string{"; ", ": ", " ("} {
+		usage, _, _ = strings.Cut(usage, sep)
+	}
+	if utf8.RuneCountInString(usage) > 60 {
+		runes := []rune(usage)
+		usage = strings.TrimRight(string(runes[:59]), " ,") + "…"
+	}
+	return usage
+}
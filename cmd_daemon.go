@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+	"github.com/JyotinderSingh/gitgraphed/schedule"
+	"github.com/JyotinderSingh/gitgraphed/store"
+)
+
+func runDaemon(ctx context.Context, config *Config, args []string) {
+	fs := newFlagSet("daemon")
+	clientFlags := addClientFlags(fs, config)
+	spec := fs.String("schedule", config.Daemon.Schedule, `cron expression for users without a schedule in the config, e.g. "0 8 * * *"`)
+	outPath := fs.String("out", config.Daemon.Output, "render each run to this file, in the format of its extension; {user} is replaced by the username")
+	statePath := fs.String("state", "", "file recording each user's last run, for catching up (default in the cache directory)")
+	noCatchUp := fs.Bool("no-catch-up", false, "skip runs missed while the daemon was not running")
+	storeSpec := fs.String("store", config.Store, "also save fetched days to this store, e.g. sqlite:history.db")
+	retentionFlags := addRetentionFlags(fs, config)
+	slackURL := fs.String("slack-url", config.SlackWebhook, "send daily summaries and milestones to this Slack incoming webhook")
+	discordURL := fs.String("discord-url", config.DiscordWebhook, "send daily summaries and milestones to this Discord webhook")
+	statsdAddr := fs.String("statsd", config.Daemon.StatsD, "send each run's gauges (today's count, total, current streak) and a run counter to this StatsD host:port, e.g. localhost:8125")
+	statsdPrefix := fs.String("statsd-prefix", "gitgraphed", "prefix of the StatsD metric names")
+	dogstatsd := fs.Bool("dogstatsd", config.Daemon.DogStatsD, "tag StatsD metrics with the user, DogStatsD style, instead of naming them after it")
+	usernames := parseInterspersed(fs, args)
+	if len(usernames) == 0 {
+		usernames = config.Usernames
+	}
+
+	var jobs []daemonJob
+	seen := map[string]bool{}
+	for _, job := range config.Daemon.Jobs {
+		jobs = append(jobs, newDaemonJob(job.Username, firstNonEmpty(job.Schedule, *spec), firstNonEmpty(job.Output, *outPath)))
+		seen[job.Username] = true
+	}
+	for _, username := range usernames {
+		if !seen[username] {
+			jobs = append(jobs, newDaemonJob(username, *spec, *outPath))
+			seen[username] = true
+		}
+	}
+	if len(jobs) == 0 {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	if *statePath == "" {
+		dir, err := gitgraph.DefaultCacheDir()
+		if err != nil {
+			fatal("locating cache directory", "err", err)
+		}
+		*statePath = filepath.Join(dir, "daemon-state.json")
+	}
+	state, err := loadDaemonState(*statePath)
+	if err != nil {
+		fatal("loading daemon state", "path", *statePath, "err", err)
+	}
+
+	client := clientFlags.newClient()
+	opts := daemonOptions{
+		Notifiers: newNotifiers(watchOptions{SlackURL: *slackURL, DiscordURL: *discordURL}, client.HTTPClient),
+		State:     state,
+		CatchUp:   !*noCatchUp,
+	}
+	if *statsdAddr != "" {
+		statsd, err := newStatsD(*statsdAddr, *statsdPrefix, *dogstatsd)
+		if err != nil {
+			fatal("connecting to StatsD", "addr", *statsdAddr, "err", err)
+		}
+		defer statsd.Close()
+		opts.StatsD = statsd
+	}
+	if *storeSpec != "" {
+		s, err := store.Open(*storeSpec)
+		if err != nil {
+			fatal("opening store", "err", err)
+		}
+		defer s.Close()
+		opts.Store = s
+		opts.Retention = retentionFlags.retention()
+	}
+	runScheduler(ctx, client, jobs, opts)
+}
+
+// newDaemonJob parses spec and expands {user} in output, exiting on an invalid schedule
+func newDaemonJob(username, spec, output string) daemonJob {
+	if username == "" {
+		fatal("daemon job without a username")
+	}
+	if spec == "" {
+		fatal("no schedule, use --schedule or daemon.schedule in the config", "username", username)
+	}
+	sched, err := schedule.Parse(spec)
+	if err != nil {
+		fatal("invalid schedule", "username", username, "err", err)
+	}
+	return daemonJob{Username: username, Schedule: sched, Output: strings.ReplaceAll(output, "{user}", username)}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
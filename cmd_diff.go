@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+func runDiff(ctx context.Context, config *Config, args []string) {
+	fs := newFlagSet("diff")
+	logFlags := addLogFlags(fs)
+	// --config is read before flags are parsed, but must still be accepted here
+	fs.String("config", defaultConfigPath(), "path to the config file")
+	storeSpec := fs.String("store", "", "compare a single snapshot against the days saved in this store")
+	format := fs.String("format", "json", "output format: json or text")
+	exitCode := fs.Bool("exit-code", false, "exit with status 1 when any day changed")
+	args = parseInterspersed(fs, args)
+	logFlags.apply()
+
+	if *format != "json" && *format != "text" {
+		fatal("unsupported format", "format", *format)
+	}
+
+	var old, new *gitgraph.ContributionGraph
+	switch {
+	case len(args) == 2 && *storeSpec == "":
+		old = mustReadGraph(args[0])
+		new = mustReadGraph(args[1])
+	case len(args) == 1 && *storeSpec != "":
+		new = mustReadGraph(args[0])
+		old = mustReadStored(ctx, *storeSpec, new)
+	default:
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	diff := gitgraph.Diff(old, new, time.Now())
+	if *format == "text" {
+		printDiff(diff)
+	} else {
+		writeJSON(diff)
+	}
+	if *exitCode && len(diff.Changes) > 0 {
+		os.Exit(1)
+	}
+}
+
+// mustReadGraph decodes a graph written by fetch from path ("-" for stdin), exiting on failure
+func mustReadGraph(path string) *gitgraph.ContributionGraph {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			fatal("opening snapshot", "path", path, "err", err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	graph := &gitgraph.ContributionGraph{}
+	if err := json.NewDecoder(r).Decode(graph); err != nil {
+		fatal("reading snapshot", "path", path, "err", err)
+	}
+	return graph
+}
+
+// mustReadStored reads the stored days covering the same user and dates as graph
+func mustReadStored(ctx context.Context, spec string, graph *gitgraph.ContributionGraph) *gitgraph.ContributionGraph {
+	if len(graph.Days) == 0 {
+		return &gitgraph.ContributionGraph{Username: graph.Username}
+	}
+	from, err1 := time.Parse("2006-01-02", graph.Days[0].Date)
+	to, err2 := time.Parse("2006-01-02", graph.Days[len(graph.Days)-1].Date)
+	if err1 != nil || err2 != nil {
+		fatal("snapshot has invalid dates", "username", graph.Username)
+	}
+
+	st := mustOpenStore(spec)
+	defer st.Close()
+	stored, err := st.Graph(ctx, graph.Username, from, to)
+	if err != nil {
+		fatal("reading store", "err", err)
+	}
+	return stored
+}
+
+// printDiff writes a human-readable summary of diff to stdout
+func printDiff(diff gitgraph.GraphDiff) {
+	fmt.Printf("%s: %d changed %s\n", diff.Username, len(diff.Changes), pluralize(len(diff.Changes), "day", "days"))
+	for _, change := range diff.Changes {
+		fmt.Printf("  %s  %d -> %d\n", change.Date, change.Before, change.After)
+	}
+	fmt.Printf("  Total:          %d -> %d (%s)\n", diff.TotalBefore, diff.TotalAfter, formatDelta(diff.TotalAfter, diff.TotalBefore))
+	fmt.Printf("  Current streak: %s -> %s\n", formatStreak(diff.StreaksBefore.Current), formatStreak(diff.StreaksAfter.Current))
+	fmt.Printf("  Longest streak: %s -> %s\n", formatStreak(diff.StreaksBefore.Longest), formatStreak(diff.StreaksAfter.Longest))
+}
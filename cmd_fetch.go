@@ -0,0 +1,274 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"text/template"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+	"github.com/JyotinderSingh/gitgraphed/locale"
+	"github.com/JyotinderSingh/gitgraphed/render"
+	"github.com/JyotinderSingh/gitgraphed/store"
+)
+
+// dataFormats are the formats written by fetch
+var dataFormats = []string{"json", "jsonl", "csv", "table", "ics", "parquet", "pb", "msgpack", "xml", "influx", "digest"}
+
+func runFetch(ctx context.Context, config *Config, args []string) {
+	fs := newFlagSet("fetch")
+	clientFlags := addClientFlags(fs, config)
+	breakerFlags := addBreakerFlags(fs)
+	targetFlags := addTargetFlags(fs).withMerge(fs)
+	levelFlags := addLevelFlags(fs)
+	dayFlags := addDayFlags(fs, config)
+	format := fs.String("format", formatOr(config.Format, dataFormats, "json"), "output format: json, jsonl, csv, table, ics, parquet, pb (protobuf), msgpack, xml, influx line protocol, digest, or foo to run a gitgraphed-format-foo plugin on PATH")
+	outPath := fs.String("out", "", "write output to this file or s3:// or gs:// URL instead of stdout")
+	copyOut := addCopyFlag(fs)
+	noHeader := fs.Bool("no-header", false, "omit the CSV and table header row")
+	fieldFlags := addFieldFlags(fs)
+	orderFlags := addOrderFlags(fs)
+	groupMonths := fs.Bool("group-months", false, "in table output, head each month's days with its name")
+	period := fs.String("period", "week", "digest period (only week is supported)")
+	localeTag := fs.String("locale", config.Locale, "language of dates in digest and table output, e.g. de-DE")
+	watch := fs.Bool("watch", false, "keep polling and emit JSON events for days whose counts change")
+	interval := fs.Duration("interval", time.Hour, "polling interval in watch mode")
+	notifyURL := fs.String("notify-url", "", "in watch mode, POST change and streak-at-risk events to this webhook")
+	templatePath := fs.String("template", "", "render the graph through this text/template file instead of --format")
+	rollup := fs.String("rollup", "", "aggregate days into week or month buckets (json and csv only)")
+	rolling := fs.Int("rolling", 0, "include a rolling average over this many days in json output, e.g. 7")
+	storeSpec := fs.String("store", config.Store, "also save fetched days to this store, e.g. sqlite:history.db")
+	slackURL := fs.String("slack-url", config.SlackWebhook, "in watch mode, send daily summaries, streak-at-risk warnings and milestones to this Slack incoming webhook")
+	discordURL := fs.String("discord-url", config.DiscordWebhook, "in watch mode, post the same events as --slack-url to this Discord webhook, with a recent heatmap")
+	publishTo := fs.String("publish", "", "publish the JSON and an SVG rendering instead of writing output; only gist is supported")
+	gistID := fs.String("gist", "", "with --publish gist, update this gist instead of the one found by its description")
+	githubActions := fs.Bool("github-actions", false, "also write key stats to $GITHUB_OUTPUT and a Markdown summary to $GITHUB_STEP_SUMMARY")
+	enrich := fs.String("enrich", "", "annotate recent days with data beyond the calendar; only events is supported: the repositories and event types of the past 90 days, from the Events API (needs a token)")
+	influxURL := fs.String("influx-url", "", "write the days as line protocol to this InfluxDB or VictoriaMetrics write URL instead of writing output, e.g. http://localhost:8086/api/v2/write?org=o&bucket=b")
+	influxToken := fs.String("influx-token", os.Getenv("INFLUX_TOKEN"), "InfluxDB 2 API token for --influx-url (defaults to $INFLUX_TOKEN)")
+	nudgeHour := fs.Int("nudge-hour", 0, "in watch mode, warn when nothing is contributed by this local hour (0-23, 0 disables)")
+	args = parseInterspersed(fs, args)
+
+	client := clientFlags.newClient()
+
+	if *format == "digest" {
+		if *period != "week" {
+			fatal("unsupported digest period", "period", *period)
+		}
+		usernames := args
+		if len(usernames) == 0 {
+			usernames = config.Usernames
+		}
+		if len(usernames) == 0 {
+			fs.Usage()
+			os.Exit(1)
+		}
+		runDigest(ctx, client, usernames, parseLocale(*localeTag))
+		return
+	}
+	if *enrich != "" && *enrich != "events" {
+		fatal("unsupported enrichment, expected events", "enrich", *enrich)
+	}
+	if *publishTo != "" && *publishTo != "gist" {
+		fatal("unsupported publish target, expected gist", "publish", *publishTo)
+	}
+	plugin := ""
+	if !contains(dataFormats, *format) {
+		if contains(renderFormats, *format) {
+			fatal("unsupported format, see 'gitgraphed render' for images", "format", *format)
+		}
+		plugin = mustFormatPlugin(*format)
+	}
+
+	if *rollup != "" && (*rollup != "week" && *rollup != "month" || *format == "ics" || *format == "jsonl" || *format == "table" || *format == "parquet" || *format == "pb" || *format == "msgpack" || *format == "xml" || *format == "influx" || plugin != "") {
+		fatal("unsupported rollup", "rollup", *rollup, "format", *format)
+	}
+
+	checkCopy(*copyOut, *format)
+	fields := fieldFlags.apply(*format)
+	if len(fields) > 0 && (*rollup != "" || *templatePath != "") {
+		fatal("--fields can't be combined with --rollup or --template")
+	}
+	order := orderFlags.order(*format)
+	if order != nil && (*rollup != "" || *templatePath != "" || *fieldFlags.shape != "") {
+		fatal("--sort, --desc and --top can't be combined with --rollup, --template or --shape")
+	}
+	if *groupMonths && *format != "table" {
+		fatal("--group-months only applies to table output", "format", *format)
+	}
+
+	// Parse the template up front so mistakes surface before any fetching
+	var tmpl *template.Template
+	if *templatePath != "" {
+		tmpl = mustParseTemplate(*templatePath)
+	}
+
+	username, p, err := targetFlags.target(args, config)
+	if err != nil {
+		slog.Error("invalid arguments", "err", err)
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	var st store.Store
+	if *storeSpec != "" {
+		st = mustOpenStore(*storeSpec)
+		defer st.Close()
+	}
+
+	if *watch {
+		if *nudgeHour < 0 || *nudgeHour > 23 {
+			fatal("invalid --nudge-hour, expected 0-23", "hour", *nudgeHour)
+		}
+		breakerFlags.apply(client)
+		runWatch(ctx, client, username, p, *clientFlags.workers, watchOptions{
+			Interval:   *interval,
+			NotifyURL:  *notifyURL,
+			SlackURL:   *slackURL,
+			DiscordURL: *discordURL,
+			NudgeHour:  *nudgeHour,
+			Store:      st,
+		})
+		return
+	}
+
+	graph := mustFetchGraph(ctx, client, username, p, *clientFlags.workers)
+	if *enrich == "events" {
+		// Events land on the days of --tz when given, and local days otherwise
+		loc, _ := clientFlags.location()
+		if loc == nil {
+			loc = time.Local
+		}
+		enrichEvents(ctx, client, graph, loc)
+	}
+	if st != nil {
+		if err := st.Save(ctx, graph, time.Now()); err != nil {
+			fatal("saving to store", "err", err)
+		}
+	}
+	dayFlags.apply(graph, client.CurrentTime())
+	levelFlags.apply(graph, client.CurrentTime())
+	if *rolling > 0 {
+		graph.UpdateRollingAverage(*rolling)
+	}
+	if *githubActions {
+		writeGitHubActions(graph, render.MarkdownOptions{Locale: parseLocale(*localeTag), WeekStart: dayFlags.start()})
+	}
+	if *publishTo == "gist" {
+		publishGist(ctx, client, config, graph, *gistID)
+		return
+	}
+	if *influxURL != "" {
+		if err := writeInflux(ctx, client.HTTPClient, *influxURL, *influxToken, graph); err != nil {
+			fatal("writing to InfluxDB", "err", err)
+		}
+		return
+	}
+	writeOutput(*outPath, graph, outputOptions{Format: *format, Header: !*noHeader, Rollup: *rollup, Template: tmpl, Plugin: plugin, Fields: fields, Shape: *fieldFlags.shape, Copy: *copyOut, Order: order, Now: client.CurrentTime(), Table: render.TableOptions{Months: *groupMonths, Locale: parseLocale(*localeTag)}})
+}
+
+// mustFetchGraph fetches username's graph over p, exiting on failure
+func mustFetchGraph(ctx context.Context, client *gitgraph.Client, username string, p period, workers int) *gitgraph.ContributionGraph {
+	graph, err := fetchGraph(ctx, client, username, p, workers)
+	if err != nil {
+		exitIfInterrupted(ctx)
+		fatalError("fetching contribution data", err, "username", username)
+	}
+	return graph
+}
+
+// enrichEvents annotates graph's days within reach of the Events API with
+// their repositories and event types, dated in loc, exiting on failure
+func enrichEvents(ctx context.Context, client *gitgraph.Client, graph *gitgraph.ContributionGraph, loc *time.Location) {
+	since := time.Now().AddDate(0, 0, -90)
+	if len(graph.Days) > 0 {
+		if first, err := time.ParseInLocation("2006-01-02", graph.Days[0].Date, loc); err == nil && first.After(since) {
+			since = first
+		}
+	}
+	events, err := client.GitHub().Events(ctx, graph.Username, since)
+	if err != nil {
+		exitIfInterrupted(ctx)
+		fatalError("fetching events", err, "username", graph.Username)
+	}
+	graph.Enrich(events, since, loc)
+}
+
+// exitIfInterrupted exits with the conventional SIGINT status once ctx has been cancelled
+func exitIfInterrupted(ctx context.Context) {
+	if ctx.Err() != nil {
+		slog.Warn("interrupted")
+		os.Exit(130)
+	}
+}
+
+// fetchGraph fetches username's graph over p, merging several years when more
+// than one is given and summing p's accounts when it has any
+func fetchGraph(ctx context.Context, client *gitgraph.Client, username string, p period, workers int) (*gitgraph.ContributionGraph, error) {
+	if len(p.Accounts) > 0 {
+		accounts := p.Accounts
+		p.Accounts = nil
+		graphs := make([]*gitgraph.ContributionGraph, len(accounts))
+		for i, account := range accounts {
+			graph, err := fetchGraph(ctx, client, account, p, workers)
+			if err != nil {
+				return nil, fmt.Errorf("fetching %s: %w", account, err)
+			}
+			graphs[i] = graph
+		}
+		merged := gitgraph.MergeAccounts(username, graphs)
+		merged.UpdateStreaks(time.Now())
+		return merged, nil
+	}
+	if p.AllYears {
+		years, err := client.AllYears(ctx, username)
+		if err != nil {
+			return nil, err
+		}
+		slog.Debug("fetching every year", "username", username, "from", years[0], "to", years[len(years)-1])
+		return client.FetchYears(ctx, username, years, workers)
+	}
+	switch {
+	case !p.From.IsZero():
+		return client.Fetch(ctx, username, gitgraph.Options{From: p.From, To: p.To})
+	case len(p.Years) == 1:
+		return client.Fetch(ctx, username, gitgraph.Options{Year: p.Years[0]})
+	default:
+		return client.FetchYears(ctx, username, p.Years, workers)
+	}
+}
+
+func runDigest(ctx context.Context, client *gitgraph.Client, usernames []string, loc *locale.Locale) {
+	now := time.Now()
+	for i, username := range usernames {
+		digest, err := buildWeeklyDigest(ctx, client, username, now)
+		if err != nil {
+			exitIfInterrupted(ctx)
+			fatalError("fetching contribution data", err, "username", username)
+		}
+		digest.Locale = loc
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Print(digest.String())
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// formatOr returns the configured format if the command supports it
+func formatOr(configured string, supported []string, fallback string) string {
+	if contains(supported, configured) {
+		return configured
+	}
+	return fallback
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+func runLeaderboard(ctx context.Context, config *Config, args []string) {
+	fs := newFlagSet("leaderboard")
+	clientFlags := addClientFlags(fs, config)
+	targetFlags := addTargetFlags(fs)
+	team := fs.String("team", "", "rank the usernames of this team from the config's teams")
+	org := fs.String("org", "", "rank the members of this GitHub organization (requires a token)")
+	by := fs.String("by", "total", "rank by total, streak (the longest in the period) or active (days with contributions)")
+	format := fs.String("format", "term", "output format: term, md or json")
+	noBots := fs.Bool("no-bots", false, "leave out automation accounts such as dependabot[bot] or release-bot")
+	minTotal := fs.Int("min-total", 0, "leave out users with fewer contributions")
+	minActive := fs.Int("min-active", 0, "leave out users active on fewer days")
+	outPath := fs.String("out", "", "write output to this file or s3:// or gs:// URL instead of stdout")
+	usernames := parseInterspersed(fs, args)
+
+	if *format != "term" && *format != "md" && *format != "json" {
+		fatal("unsupported format", "format", *format)
+	}
+	if !contains(gitgraph.LeaderboardMetrics, *by) {
+		fatal("unsupported --by", "by", *by, "supported", strings.Join(gitgraph.LeaderboardMetrics, ", "))
+	}
+	p, err := targetFlags.period("")
+	if err != nil {
+		fatal("invalid period", "err", err)
+	}
+
+	client := clientFlags.newClient()
+	switch {
+	case *team != "" && *org != "", (*team != "" || *org != "") && len(usernames) > 0:
+		fatal("--team, --org and usernames can't be combined")
+	case *team != "":
+		members, ok := config.Teams[*team]
+		if !ok {
+			fatal("unknown team", "team", *team)
+		}
+		usernames = members
+	case *org != "":
+		if usernames, err = client.OrgMembers(ctx, *org); err != nil {
+			exitIfInterrupted(ctx)
+			fatalError("listing organization members", err, "org", *org)
+		}
+	case len(usernames) == 0:
+		usernames = config.Usernames
+	}
+	if len(usernames) == 0 {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	graphs := make([]*gitgraph.ContributionGraph, len(usernames))
+	var mu sync.Mutex
+	var fetchErr error
+	fetchAll(usernames, *clientFlags.workers, func(i int, username string) {
+		graph, err := fetchGraph(ctx, client, username, p, 1)
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil && fetchErr == nil {
+			fetchErr = fmt.Errorf("%s: %w", username, err)
+		}
+		graphs[i] = graph
+	})
+	if fetchErr != nil {
+		exitIfInterrupted(ctx)
+		fatalError("fetching contribution data", fetchErr)
+	}
+
+	board, err := gitgraph.Rank(graphs, gitgraph.LeaderboardOptions{
+		Metric:        *by,
+		ExcludeBots:   *noBots,
+		MinTotal:      *minTotal,
+		MinActiveDays: *minActive,
+	}, time.Now())
+	if err != nil {
+		fatal("ranking users", "err", err)
+	}
+
+	out, err := createOutput(*outPath)
+	if err != nil {
+		fatal("creating output", "err", err)
+	}
+	switch *format {
+	case "md":
+		err = printLeaderboardMarkdown(out, board)
+	case "term":
+		err = printLeaderboard(out, board)
+	default:
+		err = encodeJSON(out, board)
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		fatal("writing output", "format", *format, "err", err)
+	}
+}
+
+func printLeaderboard(w io.Writer, board *gitgraph.Leaderboard) error {
+	width := len("User")
+	for _, e := range board.Entries {
+		width = max(width, len(e.Username))
+	}
+	fmt.Fprintf(w, "Rank  %-*s  %6s  %6s  %7s  %7s\n", width, "User", "Total", "Active", "Longest", "Current")
+	for _, e := range board.Entries {
+		fmt.Fprintf(w, "%4d  %-*s  %6d  %6d  %7d  %7d\n", e.Rank, width, e.Username, e.Total, e.ActiveDays, e.LongestStreak, e.CurrentStreak)
+	}
+	return nil
+}
+
+func printLeaderboardMarkdown(w io.Writer, board *gitgraph.Leaderboard) error {
+	fmt.Fprintln(w, "| Rank | User | Total | Active days | Longest streak | Current streak |")
+	fmt.Fprintln(w, "| ---: | --- | ---: | ---: | ---: | ---: |")
+	for _, e := range board.Entries {
+		fmt.Fprintf(w, "| %d | %s | %d | %d | %d | %d |\n",
+			e.Rank, e.Username, e.Total, e.ActiveDays, e.LongestStreak, e.CurrentStreak)
+	}
+	return nil
+}
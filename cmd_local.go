@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+	"github.com/JyotinderSingh/gitgraphed/local"
+)
+
+// analysisFlags are the flags of local and local org choosing which commits count
+type analysisFlags struct {
+	author       *string
+	year         *int
+	excludeBots  *bool
+	noMerges     *bool
+	excludePaths *string
+	branches     *string
+}
+
+func addAnalysisFlags(fs *flag.FlagSet) *analysisFlags {
+	return &analysisFlags{
+		author:       fs.String("author", local.DefaultAuthor(), "author to count commits for (defaults to git config user.email), or an identity from the config counting all its names and emails"),
+		year:         fs.Int("year", time.Now().Year(), "year to analyze"),
+		excludeBots:  fs.Bool("exclude-bots", false, "skip commits by bot accounts, such as dependabot[bot]"),
+		noMerges:     fs.Bool("no-merges", false, "skip merge commits"),
+		excludePaths: fs.String("exclude-path", "", "comma-separated paths whose commits don't count, e.g. vendor,third_party"),
+		branches:     fs.String("branch", "", "comma-separated branches to count commits on, instead of every branch and tag"),
+	}
+}
+
+// analyze graphs the commits in repos and writes the graph as JSON
+func (f *analysisFlags) analyze(ctx context.Context, config *Config, repos []string) {
+	slog.Debug("analyzing repositories", "repositories", len(repos))
+	name, aliases := config.identity(*f.author)
+	if len(aliases) > 0 {
+		slog.Debug("counting identity", "identity", name, "aliases", aliases)
+	}
+	from, to := gitgraph.Options{Year: *f.year}.Range()
+	graph, err := local.Analyze(ctx, repos, local.Options{
+		Author:       name,
+		Aliases:      aliases,
+		From:         from,
+		To:           to,
+		ExcludeBots:  *f.excludeBots,
+		NoMerges:     *f.noMerges,
+		ExcludePaths: splitList(*f.excludePaths),
+		Branches:     splitList(*f.branches),
+	})
+	if err != nil {
+		exitIfInterrupted(ctx)
+		fatal("analyzing repositories", "err", err)
+	}
+	writeJSON(graph)
+}
+
+func runLocal(ctx context.Context, config *Config, args []string) {
+	if len(args) > 0 && args[0] == "org" {
+		runLocalOrg(ctx, config, args[1:])
+		return
+	}
+	fs := newFlagSet("local")
+	logFlags := addLogFlags(fs)
+	// --config is read before flags are parsed, but must still be accepted here
+	fs.String("config", defaultConfigPath(), "path to the config file")
+	analysis := addAnalysisFlags(fs)
+	roots := parseInterspersed(fs, args)
+	logFlags.apply()
+
+	if len(roots) == 0 {
+		roots = []string{"."}
+	}
+
+	repos := []string{}
+	for _, root := range roots {
+		found, err := local.FindRepos(root)
+		if err != nil {
+			fatal("scanning for repositories", "path", root, "err", err)
+		}
+		repos = append(repos, found...)
+	}
+	analysis.analyze(ctx, config, repos)
+}
+
+// runLocalOrg mirrors an organization's repositories and graphs the commits
+// in them, counting private work GitHub's calendar may not show
+func runLocalOrg(ctx context.Context, config *Config, args []string) {
+	fs := newFlagSet("local org")
+	clientFlags := addClientFlags(fs, config)
+	analysis := addAnalysisFlags(fs)
+	cacheDir := fs.String("cache-dir", "", "directory to keep the mirrored repositories in as <owner>/<name>.git (default: <user cache dir>/gitgraphed/repos/<host>)")
+	includeForks := fs.Bool("include-forks", false, "also mirror the organization's forks")
+	includeArchived := fs.Bool("include-archived", false, "also mirror archived repositories")
+	args = parseInterspersed(fs, args)
+	if len(args) != 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	org := args[0]
+
+	client := clientFlags.newClient()
+	listed, err := client.OrgRepos(ctx, org)
+	if err != nil {
+		exitIfInterrupted(ctx)
+		fatalError("listing organization repositories", err, "org", org)
+	}
+	remotes := []local.Remote{}
+	for _, repo := range listed {
+		if (repo.Fork && !*includeForks) || (repo.Archived && !*includeArchived) {
+			continue
+		}
+		remotes = append(remotes, local.Remote{Name: repo.FullName, URL: repo.CloneURL})
+	}
+	if len(remotes) == 0 {
+		fatal("no repositories to mirror", "org", org, "listed", len(listed))
+	}
+
+	dir := *cacheDir
+	if dir == "" {
+		root, err := gitgraph.DefaultCacheDir()
+		if err != nil {
+			fatal("finding the cache directory", "err", err)
+		}
+		host := "github.com"
+		if u, err := url.Parse(*clientFlags.ghURL); err == nil && u.Host != "" {
+			host = u.Host
+		}
+		dir = filepath.Join(root, "repos", host)
+	}
+	slog.Info("mirroring repositories", "org", org, "repositories", len(remotes), "dir", dir)
+	repos, failed := local.Mirror(ctx, remotes, local.MirrorOptions{
+		Dir:     dir,
+		Token:   client.GitHub().Token,
+		Workers: *clientFlags.workers,
+	})
+	exitIfInterrupted(ctx)
+	for _, err := range failed {
+		slog.Warn("skipping repository", "err", err)
+	}
+	if len(repos) == 0 {
+		fatal("mirroring repositories", "err", errors.Join(failed...))
+	}
+	analysis.analyze(ctx, config, repos)
+}
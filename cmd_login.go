@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+// oauthClientID identifies the OAuth app, with device flow enabled, that
+// login asks the user to authorize. Release builds set it with
+// -ldflags "-X main.oauthClientID=...".
+var oauthClientID string
+
+func runLogin(ctx context.Context, config *Config, args []string) {
+	fs := newFlagSet("login")
+	logFlags := addLogFlags(fs)
+	// --config is read before flags are parsed, but must still be accepted here
+	fs.String("config", defaultConfigPath(), "path to the config file")
+	ghURL := fs.String("github-url", config.GitHubURL, "log in to a GitHub Enterprise Server instance instead, e.g. https://github.mycompany.com")
+	clientID := fs.String("client-id", stringOr(os.Getenv("GITGRAPHED_CLIENT_ID"), stringOr(config.OAuthClientID, oauthClientID)), "client ID of an OAuth app with device flow enabled ($GITGRAPHED_CLIENT_ID)")
+	scopes := fs.String("scopes", "read:user", "space-separated OAuth scopes to request")
+	printToken := fs.Bool("print", false, "print the token instead of saving it")
+	withToken := fs.Bool("with-token", false, "save a token read from stdin, e.g. a personal access token, instead of logging in in the browser")
+	insecure := fs.Bool("insecure-storage", false, "save the token in a plain-text file even when an OS keychain is available")
+	parseInterspersed(fs, args)
+	logFlags.apply()
+
+	host := githubHost(*ghURL)
+	if *withToken {
+		data, err := io.ReadAll(io.LimitReader(os.Stdin, 4096))
+		if err != nil {
+			fatal("reading token", "err", err)
+		}
+		token := strings.TrimSpace(string(data))
+		if token == "" {
+			fatal("no token on stdin")
+		}
+		saveLogin(host, token, *insecure)
+		return
+	}
+	if *clientID == "" {
+		fatal("no OAuth app to log in with: register one with device flow enabled and pass its --client-id")
+	}
+	webURL := strings.TrimRight(stringOr(*ghURL, gitgraph.DefaultGitHubURL), "/")
+	client := &http.Client{Timeout: 30 * time.Second}
+
+	code, err := requestDeviceCode(ctx, client, webURL, *clientID, *scopes)
+	if err != nil {
+		fatal("starting login", "err", err)
+	}
+	fmt.Fprintf(os.Stderr, "First copy your one-time code: %s\n", code.UserCode)
+	fmt.Fprintf(os.Stderr, "Then open %s in a browser, enter it and authorize gitgraphed.\n", code.VerificationURI)
+	fmt.Fprintln(os.Stderr, "Waiting for authorization...")
+
+	token, err := pollDeviceToken(ctx, client, webURL, *clientID, code)
+	if err != nil {
+		fatal("logging in", "err", err)
+	}
+	if *printToken {
+		fmt.Println(token)
+		return
+	}
+	saveLogin(host, token, *insecure)
+}
+
+// saveLogin saves the token of host, telling where
+func saveLogin(host, token string, insecure bool) {
+	where, err := saveToken(host, token, insecure)
+	if err != nil {
+		fatal("saving token", "err", err)
+	}
+	fmt.Fprintf(os.Stderr, "Logged in to %s; the token is saved in %s.\n", host, where)
+}
+
+// deviceCode is GitHub's answer to starting the device flow
+type deviceCode struct {
+	DeviceCode      string `json:"device_code"`
+	UserCode        string `json:"user_code"`
+	VerificationURI string `json:"verification_uri"`
+	ExpiresIn       int    `json:"expires_in"` // seconds
+	Interval        int    `json:"interval"`   // seconds between polls
+}
+
+// deviceFlowError is GitHub's error response, sent with 200 while polling
+type deviceFlowError struct {
+	Error       string `json:"error"`
+	Description string `json:"error_description"`
+	Interval    int    `json:"interval"`
+}
+
+// requestDeviceCode starts the device flow at webURL, e.g. https://github.com
+func requestDeviceCode(ctx context.Context, client *http.Client, webURL, clientID, scopes string) (*deviceCode, error) {
+	var code struct {
+		deviceCode
+		Error       string `json:"error"`
+		Description string `json:"error_description"`
+	}
+	if err := postForm(ctx, client, webURL+"/login/device/code", url.Values{"client_id": {clientID}, "scope": {scopes}}, &code); err != nil {
+		return nil, err
+	}
+	if code.Error != "" {
+		return nil, fmt.Errorf("%s: %s", code.Error, code.Description)
+	}
+	if code.DeviceCode == "" {
+		return nil, errors.New("no device code in the response")
+	}
+	return &code.deviceCode, nil
+}
+
+// pollDeviceToken waits for the user to authorize code, returning the
+// access token
+func pollDeviceToken(ctx context.Context, client *http.Client, webURL, clientID string, code *deviceCode) (string, error) {
+	interval := time.Duration(max(code.Interval, 5)) * time.Second
+	deadline := time.Now().Add(time.Duration(code.ExpiresIn) * time.Second)
+	form := url.Values{
+		"client_id":   {clientID},
+		"device_code": {code.DeviceCode},
+		"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(interval):
+		}
+		var resp struct {
+			AccessToken string `json:"access_token"`
+			deviceFlowError
+		}
+		if err := postForm(ctx, client, webURL+"/login/oauth/access_token", form, &resp); err != nil {
+			return "", err
+		}
+		switch resp.Error {
+		case "":
+			if resp.AccessToken == "" {
+				return "", errors.New("no access token in the response")
+			}
+			return resp.AccessToken, nil
+		case "authorization_pending":
+		case "slow_down":
+			// GitHub adds 5 seconds each time it is polled too often
+			interval += 5 * time.Second
+			if resp.Interval > 0 {
+				interval = time.Duration(resp.Interval) * time.Second
+			}
+		case "expired_token":
+			return "", errors.New("the code expired before it was entered, run login again")
+		case "access_denied":
+			return "", errors.New("authorization was denied")
+		default:
+			return "", fmt.Errorf("%s: %s", resp.Error, resp.Description)
+		}
+		if code.ExpiresIn > 0 && time.Now().After(deadline) {
+			return "", errors.New("the code expired before it was entered, run login again")
+		}
+	}
+}
+
+// postForm POSTs form to endpoint and decodes the JSON response into v
+func postForm(ctx context.Context, client *http.Client, endpoint string, form url.Values, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "gitgraphed")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %s", endpoint, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
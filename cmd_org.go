@@ -0,0 +1,29 @@
+package main
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+func runOrg(ctx context.Context, config *Config, args []string) {
+	fs := newFlagSet("org")
+	clientFlags := addClientFlags(fs, config)
+	year := fs.Int("year", time.Now().Year(), "year to aggregate")
+	args = parseInterspersed(fs, args)
+	if len(args) != 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	client := clientFlags.newClient()
+	org, err := client.FetchOrg(ctx, args[0], gitgraph.Options{Year: *year}, *clientFlags.workers)
+	if err != nil {
+		exitIfInterrupted(ctx)
+		fatalError("fetching organization contributions", err)
+	}
+	org.Graph.UpdateStreaks(time.Now())
+	writeJSON(org)
+}
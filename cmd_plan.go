@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/plan"
+	"github.com/JyotinderSingh/gitgraphed/render"
+)
+
+// planFormats are the ways plan writes its schedule
+var planFormats = []string{"csv", "script", "json", "term"}
+
+func runPlan(ctx context.Context, config *Config, args []string) {
+	fs := newFlagSet("plan")
+	gridPath := fs.String("grid", "", "draw the pattern in this file: up to 7 rows, Sunday first, '.' empty, 1-4 a level, any other character 4")
+	year := fs.Int("year", time.Now().Year(), "calendar year to draw on")
+	offset := fs.Int("offset", -1, "week column the pattern starts at, -1 to center it")
+	unit := fs.Int("unit", 1, "commits per level; raise it above your usual daily count so your other activity doesn't wash out the shades")
+	level := fs.Int("level", 4, "level 1-4 of the pixels of text patterns")
+	format := fs.String("format", "csv", "output: csv schedule of date,commits, a shell script of dated empty commits, json graph, or a term preview")
+	message := fs.String("message", "gitgraphed", "commit message used by the script")
+	outPath := fs.String("out", "", "write output to this file instead of stdout")
+	colorMode := addColorFlag(fs)
+	args = parseInterspersed(fs, args)
+
+	if !contains(planFormats, *format) {
+		fatal("unsupported format", "format", *format)
+	}
+	var pattern plan.Pattern
+	var err error
+	switch {
+	case *gridPath != "" && len(args) == 0:
+		var data []byte
+		if data, err = os.ReadFile(*gridPath); err == nil {
+			pattern, err = plan.ParseGrid(string(data))
+		}
+	case *gridPath == "" && len(args) > 0:
+		pattern, err = plan.Text(strings.Join(args, " "), *level)
+	default:
+		fs.Usage()
+		os.Exit(1)
+	}
+	if err != nil {
+		fatal("invalid pattern", "err", err)
+	}
+
+	username := ""
+	if len(config.Usernames) > 0 {
+		username = config.Usernames[0]
+	}
+	graph, err := plan.Plan(pattern, plan.Options{
+		Username: username,
+		From:     time.Date(*year, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:       time.Date(*year, 12, 31, 0, 0, 0, 0, time.UTC),
+		Offset:   *offset,
+		Unit:     *unit,
+	})
+	if err != nil {
+		fatal("planning pattern", "err", err)
+	}
+
+	out, err := createOutput(*outPath)
+	if err != nil {
+		fatal("creating output", "err", err)
+	}
+	switch *format {
+	case "script":
+		err = plan.Script(out, graph, *message)
+	case "json":
+		err = encodeJSON(out, graph)
+	case "term":
+		err = render.Terminal(out, graph, render.TermOptions{TrueColor: render.DetectTrueColor(), NoColor: !useColor(*colorMode, *outPath)})
+	default:
+		fmt.Fprintln(out, "date,commits")
+		for _, day := range graph.Days {
+			if day.Count > 0 {
+				fmt.Fprintf(out, "%s,%d\n", day.Date, day.Count)
+			}
+		}
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		fatal("writing plan", "err", err)
+	}
+}
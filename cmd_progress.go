@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+// progressBarWidth is the number of cells in a progress bar
+const progressBarWidth = 20
+
+func runProgress(ctx context.Context, config *Config, args []string) {
+	fs := newFlagSet("progress")
+	clientFlags := addClientFlags(fs, config)
+	yearly := fs.Int("yearly", config.Goals.Yearly, "contributions goal for the current year")
+	monthly := fs.Int("monthly", config.Goals.Monthly, "contributions goal for the current month")
+	format := fs.String("format", "text", "output format: text or json")
+	args = parseInterspersed(fs, args)
+
+	username := ""
+	if len(args) > 0 {
+		username = args[0]
+	} else if len(config.Usernames) > 0 {
+		username = config.Usernames[0]
+	}
+	if username == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+	if *yearly <= 0 && *monthly <= 0 {
+		fatal("no goals set, use --yearly/--monthly or goals in the config")
+	}
+	if *format != "text" && *format != "json" {
+		fatal("unsupported format", "format", *format)
+	}
+
+	now := time.Now()
+	client := clientFlags.newClient()
+	graph := mustFetchGraph(ctx, client, username, period{Years: []int{now.Year()}}, *clientFlags.workers)
+
+	goals := []gitgraph.GoalProgress{}
+	if *yearly > 0 {
+		goals = append(goals, gitgraph.YearProgress(graph.Days, now, *yearly))
+	}
+	if *monthly > 0 {
+		goals = append(goals, gitgraph.MonthProgress(graph.Days, now, *monthly))
+	}
+
+	if *format == "json" {
+		writeJSON(struct {
+			Username string                  `json:"username"`
+			Goals    []gitgraph.GoalProgress `json:"goals"`
+		}{username, goals})
+		return
+	}
+	fmt.Println(username)
+	for _, goal := range goals {
+		fmt.Printf("  %-8s %5d / %-5d %5.1f%%  %s  %s\n", goal.Period, goal.Total, goal.Goal, goal.Percent, progressBar(goal.Percent), formatPace(goal.DaysAhead))
+	}
+}
+
+// progressBar draws percent (capped at 100) as a bar of progressBarWidth cells
+func progressBar(percent float64) string {
+	filled := int(math.Round(math.Min(percent, 100) / 100 * progressBarWidth))
+	return "[" + strings.Repeat("█", filled) + strings.Repeat("░", progressBarWidth-filled) + "]"
+}
+
+// formatPace describes how many days ahead or behind an even pace the total is
+func formatPace(daysAhead float64) string {
+	days := int(math.Round(math.Abs(daysAhead)))
+	switch {
+	case days == 0:
+		return "on pace"
+	case daysAhead > 0:
+		return fmt.Sprintf("%d %s ahead of pace", days, pluralize(days, "day", "days"))
+	default:
+		return fmt.Sprintf("%d %s behind pace", days, pluralize(days, "day", "days"))
+	}
+}
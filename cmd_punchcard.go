@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+	"github.com/JyotinderSingh/gitgraphed/render"
+)
+
+func runPunchCard(ctx context.Context, config *Config, args []string) {
+	fs := newFlagSet("punchcard")
+	clientFlags := addClientFlags(fs, config)
+	format := fs.String("format", "json", "output format: json, or svg for a weekday by hour grid of dots")
+	outPath := fs.String("out", "", "write output to this file or s3:// or gs:// URL instead of stdout")
+	days := fs.Int("days", 90, "count events of this many past days; the Events API keeps at most 90")
+	weekStart := fs.String("week-start", config.WeekStart, "first row of svg output, sunday or monday")
+	zone := fs.String("timezone", "Local", "IANA time zone the hours are counted in, e.g. Europe/Berlin")
+	themeName := fs.String("theme", config.Theme, "color theme for svg output")
+	localeTag := fs.String("locale", config.Locale, "language of weekday labels in svg output, e.g. de-DE")
+	args = parseInterspersed(fs, args)
+
+	if *format != "json" && *format != "svg" {
+		fatal("unsupported format", "format", *format)
+	}
+	if len(args) == 0 && len(config.Usernames) > 0 {
+		args = config.Usernames[:1]
+	}
+	if len(args) != 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	loc, err := time.LoadLocation(*zone)
+	if err != nil {
+		fatal("invalid --timezone", "err", err)
+	}
+	username := args[0]
+
+	client := clientFlags.newClient()
+	events, err := client.GitHub().Events(ctx, username, time.Now().AddDate(0, 0, -*days))
+	if err != nil {
+		exitIfInterrupted(ctx)
+		fatalError("fetching events", err, "username", username)
+	}
+	card := gitgraph.BuildPunchCard(username, events, loc)
+
+	out, err := createOutput(*outPath)
+	if err != nil {
+		fatal("creating output", "err", err)
+	}
+	if *format == "svg" {
+		opts := render.DefaultSVGOptions
+		opts.Locale = parseLocale(*localeTag)
+		opts.WeekStart = parseWeekStart(*weekStart)
+		if *themeName != "" {
+			if opts.Theme, err = config.theme(*themeName); err != nil {
+				fatal("invalid theme", "err", err)
+			}
+		}
+		err = render.PunchCardSVG(out, card, opts)
+	} else {
+		err = encodeJSON(out, card)
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		fatal("writing output", "format", *format, "err", err)
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+	"github.com/JyotinderSingh/gitgraphed/render"
+	"github.com/JyotinderSingh/gitgraphed/store"
+)
+
+func runQuery(ctx context.Context, config *Config, args []string) {
+	fs := newFlagSet("query")
+	logFlags := addLogFlags(fs)
+	// --config is read before flags are parsed, but must still be accepted here
+	fs.String("config", defaultConfigPath(), "path to the config file")
+	targetFlags := addTargetFlags(fs).withMerge(fs)
+	levelFlags := addLevelFlags(fs)
+	dayFlags := addDayFlags(fs, config)
+	storeSpec := fs.String("store", config.Store, "store to read, e.g. sqlite:history.db")
+	format := fs.String("format", formatOr(config.Format, dataFormats, "json"), "output format: json, jsonl, csv, table, ics, parquet, pb, msgpack, xml, or foo to run a gitgraphed-format-foo plugin on PATH")
+	outPath := fs.String("out", "", "write output to this file or s3:// or gs:// URL instead of stdout")
+	noHeader := fs.Bool("no-header", false, "omit the CSV and table header row")
+	fieldFlags := addFieldFlags(fs)
+	orderFlags := addOrderFlags(fs)
+	groupMonths := fs.Bool("group-months", false, "in table output, head each month's days with its name")
+	asOfStr := fs.String("as-of", "", "read the days as stored by the snapshots taken up to this date (YYYY-MM-DD, through its end in UTC) or RFC 3339 time")
+	args = parseInterspersed(fs, args)
+	logFlags.apply()
+
+	if *storeSpec == "" {
+		slog.Error("no store given, use --store or set store in the config")
+		fs.Usage()
+		os.Exit(1)
+	}
+	if *format == "digest" {
+		fatal("unsupported format", "format", *format)
+	}
+	plugin := ""
+	if !contains(dataFormats, *format) {
+		plugin = mustFormatPlugin(*format)
+	}
+	fields := fieldFlags.apply(*format)
+	order := orderFlags.order(*format)
+	if order != nil && *fieldFlags.shape != "" {
+		fatal("--sort, --desc and --top can't be combined with --shape")
+	}
+	if *groupMonths && *format != "table" {
+		fatal("--group-months only applies to table output", "format", *format)
+	}
+	var asOf time.Time
+	if *asOfStr != "" {
+		var err error
+		if asOf, err = parseAsOf(*asOfStr); err != nil {
+			fatal("invalid --as-of", "err", err)
+		}
+	}
+	username, p, err := targetFlags.target(args, config)
+	if err != nil {
+		slog.Error("invalid arguments", "err", err)
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	st := mustOpenStore(*storeSpec)
+	defer st.Close()
+
+	graph, err := queryStore(ctx, st, username, p, asOf)
+	if err != nil {
+		fatal("reading store", "err", err)
+	}
+	dayFlags.apply(graph, time.Now())
+	levelFlags.apply(graph, time.Now())
+	if len(graph.Days) == 0 {
+		slog.Info("no stored days in that period", "username", username)
+	}
+	writeOutput(*outPath, graph, outputOptions{Format: *format, Header: !*noHeader, Plugin: plugin, Fields: fields, Shape: *fieldFlags.shape, Order: order, Table: render.TableOptions{Months: *groupMonths, Locale: parseLocale(config.Locale)}})
+}
+
+// queryStore reads username's stored days over p, one read per year when p
+// lists years, as of the snapshots up to asOf unless it is zero. p's accounts
+// are read one by one and summed.
+func queryStore(ctx context.Context, st store.Store, username string, p period, asOf time.Time) (*gitgraph.ContributionGraph, error) {
+	if len(p.Accounts) > 0 {
+		accounts := p.Accounts
+		p.Accounts = nil
+		graphs := make([]*gitgraph.ContributionGraph, len(accounts))
+		for i, account := range accounts {
+			graph, err := queryStore(ctx, st, account, p, asOf)
+			if err != nil {
+				return nil, err
+			}
+			graphs[i] = graph
+		}
+		merged := gitgraph.MergeAccounts(username, graphs)
+		merged.UpdateStreaks(time.Now())
+		return merged, nil
+	}
+	read := st.Graph
+	if !asOf.IsZero() {
+		read = func(ctx context.Context, username string, from, to time.Time) (*gitgraph.ContributionGraph, error) {
+			return st.GraphAsOf(ctx, username, from, to, asOf)
+		}
+	}
+	if !p.From.IsZero() {
+		return read(ctx, username, p.From, p.To)
+	}
+	if p.AllYears {
+		// Everything stored, back to GitHub's launch
+		graph, err := read(ctx, username, time.Date(2008, 1, 1, 0, 0, 0, 0, time.UTC), time.Now())
+		if err == nil {
+			graph.UpdateStreaks(time.Now())
+		}
+		return graph, err
+	}
+
+	graphs := []*gitgraph.ContributionGraph{}
+	for _, year := range p.Years {
+		from, to := gitgraph.Options{Year: year}.Range()
+		graph, err := read(ctx, username, from, to)
+		if err != nil {
+			return nil, err
+		}
+		graphs = append(graphs, graph)
+	}
+	merged := gitgraph.MergeGraphs(graphs...)
+	merged.Username = username
+	merged.UpdateStreaks(time.Now())
+	return merged, nil
+}
+
+// parseAsOf parses an --as-of date, meaning the end of that day in UTC, or time
+func parseAsOf(s string) (time.Time, error) {
+	if date, err := time.Parse("2006-01-02", s); err == nil {
+		return date.AddDate(0, 0, 1).Add(-time.Second), nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+// mustOpenStore opens the store described by spec, exiting on failure
+func mustOpenStore(spec string) store.Store {
+	st, err := store.Open(spec)
+	if err != nil {
+		fatal("opening store", "store", spec, "err", err)
+	}
+	return st
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// runRemind watches a user like fetch --watch, showing a desktop notification
+// in the evening when today still has no contributions and a streak is at stake
+func runRemind(ctx context.Context, config *Config, args []string) {
+	fs := newFlagSet("remind")
+	clientFlags := addClientFlags(fs, config)
+	hour := fs.Int("hour", 20, "local hour (1-23) from which to remind, once a day, when nothing is contributed yet")
+	interval := fs.Duration("interval", 30*time.Minute, "how often to check today's count")
+	test := fs.Bool("test", false, "show a notification now and exit, to check they appear")
+	args = parseInterspersed(fs, args)
+	if len(args) == 0 && len(config.Usernames) > 0 {
+		args = config.Usernames[:1]
+	}
+	if len(args) != 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	if *hour < 1 || *hour > 23 {
+		fatal("invalid --hour, expected 1-23", "hour", *hour)
+	}
+	if *interval <= 0 {
+		fatal("invalid --interval, expected a positive duration", "interval", *interval)
+	}
+	username := args[0]
+
+	if *test {
+		if err := desktopNotify(ctx, "gitgraphed: "+username, "Streak reminders will appear like this"); err != nil {
+			fatal("showing a desktop notification", "err", err)
+		}
+		return
+	}
+	// Events are still written to stdout, as by fetch --watch
+	p := period{Years: []int{time.Now().Year()}}
+	runWatch(ctx, clientFlags.newClient(), username, p, *clientFlags.workers, watchOptions{
+		Interval:  *interval,
+		NudgeHour: *hour,
+		Desktop:   true,
+	})
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/JyotinderSingh/gitgraphed/locale"
+	"github.com/JyotinderSingh/gitgraphed/render"
+)
+
+// renderFormats are the formats drawn by render
+var renderFormats = []string{"svg", "png", "gif", "term", "md", "spark", "emoji", "braille", "pbm", "bmp", "tikz"}
+
+func runRender(ctx context.Context, config *Config, args []string) {
+	fs := newFlagSet("render")
+	clientFlags := addClientFlags(fs, config)
+	targetFlags := addTargetFlags(fs).withMerge(fs)
+	levelFlags := addLevelFlags(fs)
+	dayFlags := addDayFlags(fs, config)
+	format := fs.String("format", formatOr(config.Format, renderFormats, "svg"), "output format: svg, png, gif, term, md, spark, emoji blocks for chat, braille, 1-bit pbm or bmp for e-paper, tikz for LaTeX, or foo to run a gitgraphed-format-foo plugin on PATH")
+	outPath := fs.String("out", "", "write output to this file or s3:// or gs:// URL instead of stdout")
+	colorMode := addColorFlag(fs)
+	copyOut := addCopyFlag(fs)
+	cellSize := fs.Int("cell-size", render.DefaultSVGOptions.CellSize, "SVG cell size in pixels")
+	gap := fs.Int("gap", render.DefaultSVGOptions.Gap, "SVG gap between cells in pixels")
+	tikzCell := fs.String("tikz-cell-size", render.DefaultTikZOptions.CellSize.String(), "cell size in tikz output, in pt, mm, cm, in, bp or pc")
+	tikzGap := fs.String("tikz-gap", render.DefaultTikZOptions.Gap.String(), "gap between cells in tikz output, in pt, mm, cm, in, bp or pc")
+	radius := fs.Int("radius", render.DefaultSVGOptions.Radius, "SVG cell corner radius in pixels")
+	scale := fs.Int("scale", render.DefaultPNGOptions.Scale, "PNG pixel scale factor")
+	themeName := fs.String("theme", config.Theme, "color theme: github, github-dark, halloween, colorblind, colorblind-dark, viridis or one defined in the config (terminals default to github-dark)")
+	colors := fs.String("colors", "", "comma-separated hex colors for levels 0-4, overriding the theme's")
+	noCaption := fs.Bool("no-caption", false, "omit the username and total caption from PNG output")
+	frameDelay := fs.Duration("frame-delay", render.DefaultGIFOptions.FrameDelay, "time each week is shown in GIF output")
+	heatmap := fs.Bool("heatmap", false, "append an emoji-block heatmap to Markdown output")
+	brailleHeight := fs.Int("height", render.DefaultBrailleHeight, "rows of characters in braille output, four dots each")
+	skyline := fs.Bool("skyline", false, "draw svg or png as an isometric 3D skyline, bar height showing each day's count")
+	months := fs.Bool("months", false, "draw svg, png or term as a bar chart of contributions per month, where trends read more easily than on the calendar")
+	poster := fs.Bool("poster", false, "draw svg or png as a tall poster of every fetched year's calendar, stacked with its total, e.g. with --all-years")
+	patterns := fs.Bool("patterns", false, "dot svg cells more densely at each level, so they read without color")
+	rolling := fs.Int("rolling", 0, "draw this many days' rolling average as a line below svg output, e.g. 7")
+	sparkWeeks := fs.Int("weeks", 0, "show only the most recent weeks in spark and emoji output, 0 for all")
+	bitmapSize := fs.String("size", fmt.Sprintf("%dx%d", render.DefaultBitmapOptions.Width, render.DefaultBitmapOptions.Height), "exact pbm or bmp resolution as WIDTHxHEIGHT")
+	localeTag := fs.String("locale", config.Locale, "language of month and weekday labels, e.g. de-DE: "+strings.Join(locale.Tags(), ", "))
+	threshold := fs.Int("threshold", 0, "make pbm or bmp pixels darker than this grey (1-255) black instead of dithering")
+	args = parseInterspersed(fs, args)
+
+	plugin := ""
+	if !contains(renderFormats, *format) {
+		plugin = mustFormatPlugin(*format)
+	}
+	checkCopy(*copyOut, *format)
+
+	// An unset theme lets each renderer pick its default
+	var theme render.Theme
+	if *themeName != "" {
+		var err error
+		if theme, err = config.theme(*themeName); err != nil {
+			fatal("invalid theme", "err", err)
+		}
+	}
+	if *colors != "" {
+		base := theme
+		if *themeName == "" {
+			base = render.GitHubTheme
+			if *format == "term" {
+				base = render.GitHubDarkTheme
+			}
+		}
+		custom, err := render.NewTheme("custom", strings.Split(*colors, ","), "", "", base)
+		if err != nil {
+			fatal("invalid colors", "err", err)
+		}
+		theme = custom
+	}
+
+	loc := parseLocale(*localeTag)
+	opts := outputOptions{
+		Format: *format,
+		Plugin: plugin,
+		Copy:   *copyOut,
+		SVG:    render.SVGOptions{CellSize: *cellSize, Gap: *gap, Radius: *radius, Theme: theme, Trend: *rolling, Locale: loc, Patterns: *patterns},
+		Term:   render.TermOptions{TrueColor: render.DetectTrueColor(), Theme: theme, Locale: loc, NoColor: !useColor(*colorMode, *outPath)},
+		PNG:    render.DefaultPNGOptions,
+		MD:     render.MarkdownOptions{Heatmap: *heatmap, Locale: loc},
+		Spark:  render.SparkOptions{Weeks: *sparkWeeks},
+		Emoji:  render.EmojiOptions{Weeks: *sparkWeeks, Dark: theme.Background != ""},
+		Dots:   render.BrailleOptions{Height: *brailleHeight, Locale: loc},
+	}
+	opts.PNG.Locale = loc
+	start := dayFlags.start()
+	opts.SVG.WeekStart = start
+	opts.Term.WeekStart = start
+	opts.PNG.WeekStart = start
+	opts.MD.WeekStart = start
+	opts.Spark.WeekStart = start
+	opts.Emoji.WeekStart = start
+	opts.PNG.Scale = *scale
+	opts.PNG.Caption = !*noCaption
+	if len(theme.Levels) > 0 {
+		opts.PNG.Theme = theme
+	}
+	if *skyline {
+		if *format != "svg" && *format != "png" {
+			fatal("--skyline needs svg or png output", "format", *format)
+		}
+		sky := render.DefaultSkylineOptions
+		sky.Caption = !*noCaption
+		sky.WeekStart = start
+		if len(theme.Levels) > 0 {
+			sky.Theme = theme
+		}
+		opts.Skyline = &sky
+	}
+	if *months {
+		if *format != "svg" && *format != "png" && *format != "term" {
+			fatal("--months needs svg, png or term output", "format", *format)
+		}
+		if *skyline {
+			fatal("--months and --skyline are different charts, choose one")
+		}
+		chart := render.DefaultMonthOptions
+		chart.Locale = loc
+		chart.Scale = *scale
+		chart.Caption = !*noCaption
+		if len(theme.Levels) > 0 {
+			chart.Theme = theme
+		}
+		opts.Months = &chart
+	}
+	if *poster {
+		if *format != "svg" && *format != "png" {
+			fatal("--poster needs svg or png output", "format", *format)
+		}
+		if *skyline || *months {
+			fatal("--poster is a chart of its own, it can't be combined with --skyline or --months")
+		}
+		sheet := render.DefaultPosterOptions
+		sheet.CellSize, sheet.Gap, sheet.Radius = *cellSize, *gap, *radius
+		sheet.Theme = theme
+		sheet.Locale = loc
+		sheet.WeekStart = start
+		sheet.Patterns = *patterns
+		sheet.Scale = *scale
+		sheet.Caption = !*noCaption
+		opts.Poster = &sheet
+	}
+	if *format == "pbm" || *format == "bmp" {
+		opts.Bitmap = render.DefaultBitmapOptions
+		var err error
+		if opts.Bitmap.Width, opts.Bitmap.Height, err = parseResolution(*bitmapSize); err != nil {
+			fatal("invalid --size", "err", err)
+		}
+		if *threshold < 0 || *threshold > 255 {
+			fatal("--threshold must be between 0 and 255", "threshold", *threshold)
+		}
+		opts.Bitmap.Threshold = *threshold
+		opts.Bitmap.Caption = !*noCaption
+		opts.Bitmap.WeekStart = start
+		if len(theme.Levels) > 0 {
+			opts.Bitmap.Theme = theme
+		}
+	}
+	if *format == "tikz" {
+		opts.TikZ = render.TikZOptions{Theme: theme, Locale: loc, WeekStart: start}
+		var err error
+		if opts.TikZ.CellSize, err = render.ParseTeXLength(*tikzCell); err != nil {
+			fatal("invalid --tikz-cell-size", "err", err)
+		}
+		if opts.TikZ.Gap, err = render.ParseTeXLength(*tikzGap); err != nil {
+			fatal("invalid --tikz-gap", "err", err)
+		}
+	}
+	opts.GIF = render.DefaultGIFOptions
+	opts.GIF.PNG = opts.PNG
+	opts.GIF.FrameDelay = *frameDelay
+
+	username, p, err := targetFlags.target(args, config)
+	if err != nil {
+		slog.Error("invalid arguments", "err", err)
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	client := clientFlags.newClient()
+	graph := mustFetchGraph(ctx, client, username, p, *clientFlags.workers)
+	dayFlags.apply(graph, client.CurrentTime())
+	levelFlags.apply(graph, client.CurrentTime())
+	opts.Now = client.CurrentTime()
+	writeOutput(*outPath, graph, opts)
+}
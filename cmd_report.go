@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+	"github.com/JyotinderSingh/gitgraphed/render"
+)
+
+func runReport(ctx context.Context, config *Config, args []string) {
+	fs := newFlagSet("report")
+	clientFlags := addClientFlags(fs, config)
+	email := fs.Bool("email", false, "send the report through the smtp server in the config instead of writing HTML")
+	to := fs.String("to", strings.Join(config.SMTP.To, ","), "comma-separated recipients for --email")
+	date := fs.String("date", "", "report on the week before the one containing this YYYY-MM-DD date (default today)")
+	localeTag := fs.String("locale", config.Locale, "language of weekday names and dates, e.g. de-DE")
+	format := fs.String("format", "html", "html for the weekly report, or pdf for a year in review with a page per user")
+	year := fs.Int("year", time.Now().Year(), "year reviewed by --format pdf")
+	repos := fs.Int("repos", 10, "repositories listed per user by --format pdf, which needs a token")
+	themeName := fs.String("theme", config.Theme, "color theme of --format pdf")
+	outPath := fs.String("out", "", "write the report to this file or s3:// or gs:// URL instead of stdout")
+	usernames := parseInterspersed(fs, args)
+	if len(usernames) == 0 {
+		usernames = config.Usernames
+	}
+	if len(usernames) == 0 {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	if *format != "html" && *format != "pdf" {
+		fatal("unsupported format, expected html or pdf", "format", *format)
+	}
+	if *format == "pdf" {
+		if *email {
+			fatal("--email sends the weekly HTML report; write the PDF with --out and attach it")
+		}
+		writeReview(ctx, config, clientFlags.newClient(), usernames, *year, *repos, *themeName, *localeTag, *outPath, *clientFlags.workers)
+		return
+	}
+
+	now := time.Now()
+	if *date != "" {
+		parsed, err := time.Parse("2006-01-02", *date)
+		if err != nil {
+			fatal("invalid --date, expected YYYY-MM-DD", "date", *date)
+		}
+		now = parsed
+	}
+	var recipients []string
+	for _, rcpt := range strings.Split(*to, ",") {
+		if rcpt = strings.TrimSpace(rcpt); rcpt != "" {
+			recipients = append(recipients, rcpt)
+		}
+	}
+	if *email && len(recipients) == 0 {
+		fatal("no recipients, use --to or smtp.to in the config")
+	}
+
+	client := clientFlags.newClient()
+	report, err := buildWeeklyReport(ctx, client, usernames, now, *clientFlags.workers, parseLocale(*localeTag))
+	if err != nil {
+		exitIfInterrupted(ctx)
+		fatalError("fetching contribution data", err)
+	}
+
+	if *email {
+		smtpConfig := config.SMTP
+		if password := os.Getenv("GITGRAPHED_SMTP_PASSWORD"); password != "" {
+			smtpConfig.Password = password
+		}
+		msg, err := buildReportEmail(report, smtpConfig.From, recipients, time.Now())
+		if err != nil {
+			fatal("building email", "err", err)
+		}
+		if err := sendMail(smtpConfig, recipients, msg); err != nil {
+			fatal("sending email", "host", smtpConfig.Host, "err", err)
+		}
+		slog.Info("sent report", "to", recipients, "users", len(report.Users))
+		return
+	}
+
+	out, err := createOutput(*outPath)
+	if err != nil {
+		fatal("creating output", "err", err)
+	}
+	report.embedImages()
+	if err := report.HTML(out); err != nil {
+		fatal("writing report", "err", err)
+	}
+	if err := out.Close(); err != nil {
+		fatal("writing report", "err", err)
+	}
+}
+
+// writeReview writes the year in review of usernames as a PDF to path
+func writeReview(ctx context.Context, config *Config, client *gitgraph.Client, usernames []string, year, repos int, themeName, localeTag, path string, workers int) {
+	graphs, err := client.FetchUsers(ctx, usernames, gitgraph.Options{Year: year}, workers)
+	if err != nil {
+		exitIfInterrupted(ctx)
+		fatalError("fetching contribution data", err)
+	}
+	opts := render.ReviewOptions{Locale: parseLocale(localeTag), WeekStart: parseWeekStart(config.WeekStart), Repos: repos}
+	if themeName != "" {
+		if opts.Theme, err = config.theme(themeName); err != nil {
+			fatal("invalid theme", "err", err)
+		}
+	}
+
+	out, err := createOutput(path)
+	if err != nil {
+		fatal("creating output", "err", err)
+	}
+	err = render.ReviewPDF(out, graphs, opts)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		fatal("writing report", "format", "pdf", "err", err)
+	}
+}
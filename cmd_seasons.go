@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+func runSeasons(ctx context.Context, config *Config, args []string) {
+	fs := newFlagSet("seasons")
+	clientFlags := addClientFlags(fs, config)
+	format := fs.String("format", "text", "output format: text, md for a Markdown table or json")
+	outPath := fs.String("out", "", "write output to this file or s3:// or gs:// URL instead of stdout")
+	hemisphere := fs.String("hemisphere", "north", "hemisphere naming the seasons: north, or south where summer starts in December")
+	args = parseInterspersed(fs, args)
+
+	if *format != "text" && *format != "md" && *format != "json" {
+		fatal("unsupported format", "format", *format)
+	}
+	now := time.Now()
+	year := now.Year()
+	if len(args) > 0 {
+		if parsed, err := strconv.Atoi(args[len(args)-1]); err == nil {
+			year, args = parsed, args[:len(args)-1]
+		}
+	}
+	if len(args) == 0 && len(config.Usernames) > 0 {
+		args = config.Usernames[:1]
+	}
+	if len(args) != 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	if year > now.Year() {
+		fatal("year is in the future", "year", year)
+	}
+
+	// The previous year is compared against, from the December starting its winter
+	p := period{From: time.Date(year-2, 12, 1, 0, 0, 0, 0, time.UTC), To: time.Date(year, 12, 31, 0, 0, 0, 0, time.UTC)}
+	if year == now.Year() {
+		p.To = time.Date(year, now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	client := clientFlags.newClient()
+	graph := mustFetchGraph(ctx, client, args[0], p, *clientFlags.workers)
+	report, err := gitgraph.CompareSeasons(graph, year, *hemisphere, now)
+	if err != nil {
+		fatal("invalid --hemisphere", "err", err)
+	}
+
+	out, err := createOutput(*outPath)
+	if err != nil {
+		fatal("creating output", "err", err)
+	}
+	switch *format {
+	case "md":
+		err = printSeasonsMarkdown(out, report)
+	case "json":
+		err = encodeJSON(out, report)
+	default:
+		err = printSeasons(out, report)
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		fatal("writing output", "format", *format, "err", err)
+	}
+}
+
+func printSeasons(w io.Writer, report *gitgraph.SeasonalReport) error {
+	fmt.Fprintf(w, "%s: %d vs %d\n", report.Username, report.Year, report.Year-1)
+	for _, section := range []struct {
+		title   string
+		periods []gitgraph.PeriodStats
+	}{{"Quarter", report.Quarters}, {"Season", report.Seasons}} {
+		fmt.Fprintf(w, "\n  %-8s %6d %6d   %-14s %-11s %s\n", section.title, report.Year-1, report.Year, "Change", "Active days", "Share")
+		for _, stats := range section.periods {
+			fmt.Fprintf(w, "  %-8s %6d %6d   %-14s %4d -> %-4d %4.1f%%%s\n", stats.Period, stats.Total.Base, stats.Total.Current,
+				formatDelta(stats.Total.Current, stats.Total.Base), stats.ActiveDays.Base, stats.ActiveDays.Current, stats.Share, toDateNote(stats))
+		}
+	}
+	return nil
+}
+
+// printSeasonsMarkdown writes the report as tables ready to paste into a
+// write-up
+func printSeasonsMarkdown(w io.Writer, report *gitgraph.SeasonalReport) error {
+	fmt.Fprintf(w, "## %s's contributions in %d\n", report.Username, report.Year)
+	for _, section := range []struct {
+		title   string
+		periods []gitgraph.PeriodStats
+	}{{"Quarter", report.Quarters}, {"Season", report.Seasons}} {
+		fmt.Fprintf(w, "\n| %s | %d | %d | Change | Active days | Longest streak | Share of year |\n", section.title, report.Year-1, report.Year)
+		fmt.Fprintln(w, "| --- | ---: | ---: | ---: | ---: | ---: | ---: |")
+		for _, stats := range section.periods {
+			fmt.Fprintf(w, "| %s%s | %d | %d | %s | %d → %d | %d → %d | %.1f%% |\n", stats.Period, toDateNote(stats),
+				stats.Total.Base, stats.Total.Current, formatDelta(stats.Total.Current, stats.Total.Base),
+				stats.ActiveDays.Base, stats.ActiveDays.Current, stats.LongestStreak.Base, stats.LongestStreak.Current, stats.Share)
+		}
+	}
+	return nil
+}
+
+// toDateNote flags a period still under way, compared up to today only
+func toDateNote(stats gitgraph.PeriodStats) string {
+	if stats.ToDate {
+		return " (to date)"
+	}
+	return ""
+}
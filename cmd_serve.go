@@ -0,0 +1,250 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+	"github.com/JyotinderSingh/gitgraphed/server"
+	"github.com/JyotinderSingh/gitgraphed/telemetry"
+)
+
+func runServe(ctx context.Context, config *Config, args []string) {
+	opts := config.Server
+	if err := opts.applyEnv(); err != nil {
+		fatal("invalid environment", "err", err)
+	}
+	fs := newFlagSet("serve")
+	clientFlags := addClientFlags(fs, config)
+	breakerFlags := addBreakerFlags(fs)
+	listen := fs.String("listen", stringOr(opts.Listen, ":8080"), "address to listen on: host:port, unix:/path/to.sock, or systemd[:name] for a socket-activated one ($GITGRAPHED_LISTEN, or $GITGRAPHED_PORT)")
+	grpcListen := fs.String("grpc", opts.GRPC, "also serve the gRPC API on this address, e.g. :50051, unix:/path/to.sock or systemd:name ($GITGRAPHED_GRPC)")
+	cacheDir := fs.String("cache-dir", opts.CacheDir, "cache fetched graphs in this directory instead of in memory ($GITGRAPHED_CACHE_DIR)")
+	rateLimit := fs.Float64("rate-limit", opts.RateLimit, "requests per second allowed per client IP, 0 for no limit ($GITGRAPHED_RATE_LIMIT)")
+	rateBurst := fs.Int("rate-burst", intOr(opts.RateBurst, 20), "requests a client may make in a burst above --rate-limit ($GITGRAPHED_RATE_BURST)")
+	apiKeys := fs.String("api-keys", strings.Join(opts.APIKeys, ","), "comma-separated API keys, sent as X-API-Key or a bearer token, with limits of their own ($GITGRAPHED_API_KEYS)")
+	keyRateLimit := fs.Float64("key-rate-limit", floatOr(opts.KeyRateLimit, 10), "requests per second allowed per API key ($GITGRAPHED_KEY_RATE_LIMIT)")
+	keyRateBurst := fs.Int("key-rate-burst", intOr(opts.KeyRateBurst, 50), "requests an API key may make in a burst above --key-rate-limit ($GITGRAPHED_KEY_RATE_BURST)")
+	trustProxy := fs.Bool("trust-proxy", opts.TrustProxy, "take client IPs from X-Forwarded-For, when behind a reverse proxy ($GITGRAPHED_TRUST_PROXY)")
+	allowedOrigins := fs.String("allowed-origins", strings.Join(opts.AllowedOrigins, ","), "comma-separated origins, or *, whose pages may call the API from the browser ($GITGRAPHED_ALLOWED_ORIGINS)")
+	allowedMethods := fs.String("allowed-methods", strings.Join(opts.AllowedMethods, ","), "comma-separated methods those pages may use, GET,POST,OPTIONS by default ($GITGRAPHED_ALLOWED_METHODS)")
+	signingKeys := fs.String("signing-keys", strings.Join(opts.SigningKeys, ","), "comma-separated id:secret pairs for requests signed with HMAC-SHA256, sent with X-API-Key: id, X-Timestamp and X-Signature ($GITGRAPHED_SIGNING_KEYS)")
+	adminKeys := fs.String("admin-keys", strings.Join(opts.AdminKeys, ","), "comma-separated keys, sent like --api-keys, for the /admin/cache endpoints listing and invalidating cached graphs, which aren't served without any ($GITGRAPHED_ADMIN_KEYS)")
+	warm := fs.String("warm", strings.Join(opts.Warm, ","), "comma-separated usernames whose current year is fetched into the cache at startup ($GITGRAPHED_WARM)")
+	requireAPIKey := fs.Bool("require-api-key", opts.RequireAPIKey, "reject requests without one of --api-keys or a signed one of --signing-keys ($GITGRAPHED_REQUIRE_API_KEY)")
+	otlpEndpoint := fs.String("otlp-endpoint", opts.OTLPEndpoint, "send traces and metrics to this OpenTelemetry collector over OTLP/HTTP, e.g. http://localhost:4318 ($OTEL_EXPORTER_OTLP_ENDPOINT)")
+	hedgeAfter := fs.Duration("hedge-after", opts.HedgeAfter, "send a second upstream request when a cache miss's first is unanswered this long, e.g. 800ms, answering with whichever comes first; 0 disables ($GITGRAPHED_HEDGE_AFTER)")
+	eventsInterval := fs.Duration("events-interval", server.DefaultWatchInterval, "how often users with open /events/{username} streams are polled for changes")
+	eventsUsers := fs.Int("events-max-users", server.DefaultMaxWatches, "most users /events/{username} streams may watch at once, answering 503 beyond")
+	eventsStreams := fs.Int("events-max-streams", server.DefaultMaxClientStreams, "most /events/{username} streams one client address may keep open, answering 429 beyond")
+	parseInterspersed(fs, args)
+
+	client := clientFlags.newClient()
+	if !*clientFlags.noCache {
+		var cache gitgraph.Cache = gitgraph.NewMemoryCache()
+		if *cacheDir != "" {
+			cache = gitgraph.NewFileCache(*cacheDir)
+		}
+		setCache(client, cache)
+	}
+	breakerFlags.apply(client)
+	if *hedgeAfter < 0 {
+		fatal("invalid --hedge-after, expected 0 or more", "hedge-after", *hedgeAfter)
+	}
+	if *hedgeAfter > 0 {
+		// Above the retries, so a hedge isn't held up behind the first copy's backoff
+		client.HTTPClient.Transport = gitgraph.NewHedgeTransport(client.HTTPClient.Transport, *hedgeAfter)
+	}
+	// A badge embedded widely is requested many times at once when its cache expires
+	client.Coalescer = gitgraph.NewCoalescer()
+
+	exporter, err := telemetry.ExporterFromEnv(*otlpEndpoint, "gitgraphed", moduleVersion())
+	if err != nil {
+		fatal("invalid OpenTelemetry configuration", "err", err)
+	}
+	if exporter != nil {
+		exporter.Metrics = []telemetry.MetricSource{gitgraph.DefaultMetrics.Telemetry}
+		exporter.Start()
+		defer exporter.Shutdown(context.Background())
+		// Upstream requests become children of the fetch spans
+		client.HTTPClient.Transport = telemetry.NewTransport(client.HTTPClient.Transport)
+		slog.Info("exporting telemetry", "traces", exporter.TracesURL, "metrics", exporter.MetricsURL)
+	}
+
+	auth := &server.Authenticator{APIKeys: map[string]bool{}, SigningKeys: map[string][]byte{}, Required: *requireAPIKey}
+	for _, key := range splitList(*apiKeys) {
+		auth.APIKeys[key] = true
+	}
+	admins := map[string]bool{}
+	for _, key := range splitList(*adminKeys) {
+		// Admin keys pass the authenticator, and the rate limiter, as API keys too
+		admins[key] = true
+		auth.APIKeys[key] = true
+	}
+	for i, pair := range splitList(*signingKeys) {
+		id, secret, ok := strings.Cut(pair, ":")
+		if !ok || id == "" || secret == "" {
+			// Not logged, since it may hold a secret
+			fatal("invalid --signing-keys entry, expected id:secret", "index", i)
+		}
+		auth.SigningKeys[id] = []byte(secret)
+	}
+	if auth.Required && len(auth.APIKeys)+len(auth.SigningKeys) == 0 {
+		fatal("--require-api-key needs --api-keys or --signing-keys")
+	}
+
+	srv := server.New(client)
+	srv.WatchInterval = *eventsInterval
+	srv.MaxWatches, srv.MaxClientStreams = *eventsUsers, *eventsStreams
+	srv.TrustProxy = *trustProxy
+	srv.AdminKeys = admins
+	if usernames := splitList(*warm); len(usernames) > 0 {
+		go srv.Warm(ctx, usernames)
+	}
+	var handler http.Handler = srv
+	var limiter *server.RateLimiter
+	if *rateLimit > 0 {
+		limiter = server.NewRateLimiter(*rateLimit, *rateBurst)
+		limiter.KeyRate, limiter.KeyBurst = *keyRateLimit, *keyRateBurst
+		limiter.APIKeys = map[string]bool{}
+		for key := range auth.APIKeys {
+			limiter.APIKeys[key] = true
+		}
+		for id := range auth.SigningKeys {
+			limiter.APIKeys[id] = true
+		}
+		limiter.TrustProxy = *trustProxy
+		handler = limiter.Middleware(handler)
+	}
+	if len(auth.APIKeys)+len(auth.SigningKeys) > 0 {
+		handler = auth.Middleware(handler)
+		srv.Private = true
+	} else {
+		auth = nil
+	}
+	if *grpcListen != "" {
+		// Calls pass the same key checks and limits as HTTP requests
+		go listenAndServeGRPC(ctx, *grpcListen, server.NewGRPC(client, auth, limiter))
+	}
+	if origins := splitList(*allowedOrigins); len(origins) > 0 {
+		handler = server.CORS(origins, splitList(*allowedMethods), handler)
+	}
+	if exporter != nil {
+		// Outermost, so time spent rate limited or rejected is traced too
+		handler = telemetry.Middleware(handler)
+	}
+	listenAndServe(ctx, *listen, handler)
+}
+
+// moduleVersion is the version stamped by go install, (devel) otherwise
+func moduleVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		return info.Main.Version
+	}
+	return "(devel)"
+}
+
+// intOr returns value unless it is zero
+func intOr(value, fallback int) int {
+	if value == 0 {
+		return fallback
+	}
+	return value
+}
+
+// floatOr returns value unless it is zero
+func floatOr(value, fallback float64) float64 {
+	if value == 0 {
+		return fallback
+	}
+	return value
+}
+
+// listenAndServeGRPC serves srv on addr until ctx is cancelled, then stops it,
+// giving in-flight calls a grace period before watch streams are cut off
+func listenAndServeGRPC(ctx context.Context, addr string, srv *grpc.Server) {
+	lis, err := listen(addr)
+	if err != nil {
+		fatal("listening", "addr", addr, "err", err)
+	}
+
+	go func() {
+		<-ctx.Done()
+		stopped := make(chan struct{})
+		go func() {
+			srv.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-time.After(10 * time.Second):
+			srv.Stop()
+		}
+	}()
+
+	slog.Info("serving gRPC", "addr", addr)
+	if err := srv.Serve(lis); err != nil {
+		fatal("running gRPC server", "err", err)
+	}
+}
+
+func runExporter(ctx context.Context, config *Config, args []string) {
+	fs := newFlagSet("exporter")
+	clientFlags := addClientFlags(fs, config)
+	listen := fs.String("listen", ":9100", "address to listen on: host:port, unix:/path/to.sock, or systemd[:name] for a socket-activated one")
+	interval := fs.Duration("interval", 15*time.Minute, "how often to refresh the metrics")
+	usernames := parseInterspersed(fs, args)
+	if len(usernames) == 0 {
+		usernames = config.Usernames
+	}
+	if len(usernames) == 0 {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	// Refreshes must reach upstream each interval rather than reuse cached years
+	client := clientFlags.newClient()
+	client.Refresh = true
+	exporter := server.NewExporter(client, usernames, *interval)
+	go exporter.Run(ctx)
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", exporter)
+	listenAndServe(ctx, *listen, mux)
+}
+
+// listenAndServe serves handler on addr until ctx is cancelled, then shuts down gracefully
+func listenAndServe(ctx context.Context, addr string, handler http.Handler) {
+	srv := &http.Server{
+		Addr:    addr,
+		Handler: handler,
+	}
+
+	// Stop accepting connections once ctx is done and let in-flight requests finish
+	done := make(chan struct{})
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+		close(done)
+	}()
+
+	lis, err := listen(addr)
+	if err != nil {
+		fatal("listening", "addr", addr, "err", err)
+	}
+	slog.Info("listening", "addr", addr)
+	if err := srv.Serve(lis); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fatal("running server", "err", err)
+	}
+	<-done
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+	"github.com/JyotinderSingh/gitgraphed/render"
+	"github.com/JyotinderSingh/gitgraphed/store"
+)
+
+func runStats(ctx context.Context, config *Config, args []string) {
+	fs := newFlagSet("stats")
+	clientFlags := addClientFlags(fs, config)
+	targetFlags := addTargetFlags(fs).withMerge(fs)
+	dayFlags := addDayFlags(fs, config)
+	goal := fs.Int("goal", 0, "contributions to reach this year, reporting the daily pace it needs")
+	gapDays := fs.Int("gap-days", gitgraph.DefaultAnomalyOptions.MinGapDays, "inactive days in a row reported as a gap")
+	spikeZ := fs.Float64("spike-z", gitgraph.DefaultAnomalyOptions.SpikeZScore, "standard deviations above the daily mean reported as a spike")
+	repos := fs.Int("repos", 10, "repositories to list in the per-repository commit breakdown, which needs a token")
+	dropPercent := fs.Float64("drop-percent", gitgraph.DefaultAnomalyOptions.DropPercent, "month-over-month decline reported as a drop")
+	languagesSVG := fs.String("languages-svg", "", "also write the language breakdown as a stacked bar to this SVG file")
+	githubActions := fs.Bool("github-actions", false, "also write key stats to $GITHUB_OUTPUT and a Markdown summary to $GITHUB_STEP_SUMMARY")
+	milestones := fs.Int("milestones", 10, "latest milestones to list: first contributions of each year, totals reached, new longest streaks and busiest days, 0 for none")
+	storeSpec := fs.String("store", config.Store, "find milestones in the history saved to this store too, e.g. sqlite:history.db, not only in the fetched days")
+	args = parseInterspersed(fs, args)
+
+	username, p, err := targetFlags.target(args, config)
+	if err != nil {
+		slog.Error("invalid arguments", "err", err)
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	client := clientFlags.newClient()
+	graph := mustFetchGraph(ctx, client, username, p, *clientFlags.workers)
+	dayFlags.apply(graph, client.CurrentTime())
+	if *githubActions {
+		writeGitHubActions(graph, render.MarkdownOptions{Locale: parseLocale(config.Locale), WeekStart: dayFlags.start()})
+	}
+	printStats(graph, *goal)
+	printRepositories(graph.Repositories, *repos)
+	languages := gitgraph.LanguageBreakdown(graph.Repositories)
+	printLanguages(languages)
+	if *languagesSVG != "" {
+		writeLanguagesSVG(*languagesSVG, languages, config)
+	}
+	printAnomalies(gitgraph.DetectAnomalies(graph.Days, time.Now(), gitgraph.AnomalyOptions{
+		MinGapDays:  *gapDays,
+		SpikeZScore: *spikeZ,
+		DropPercent: *dropPercent,
+	}))
+	if *milestones > 0 {
+		var st store.Store
+		if *storeSpec != "" {
+			st = mustOpenStore(*storeSpec)
+			defer st.Close()
+		}
+		history, err := milestoneHistory(ctx, st, graph, time.Now())
+		if err != nil {
+			fatal("reading store", "err", err)
+		}
+		printMilestones(gitgraph.FindMilestones(history), *milestones)
+	}
+}
+
+// printStats writes a human-readable summary of graph to stdout, with a year-end
+// projection towards goal when the graph covers the current year
+func printStats(graph *gitgraph.ContributionGraph, goal int) {
+	now := time.Now()
+	streaks := gitgraph.ComputeStreaks(graph.Days, now)
+	summary := gitgraph.Summarize(graph.Days)
+
+	fmt.Printf("%s %v\n", graph.Username, graph.Years)
+	fmt.Printf("  Total contributions: %d\n", graph.TotalContribs)
+	fmt.Printf("  Current streak:      %s\n", formatStreak(streaks.Current))
+	fmt.Printf("  Longest streak:      %s\n", formatStreak(streaks.Longest))
+	fmt.Printf("  Busiest weekday:     %s\n", summary.BusiestWeekday)
+	fmt.Printf("  Daily mean/median:   %.2f / %g\n", summary.MeanDaily, summary.MedianDaily)
+	if summary.MaxDay.Count > 0 {
+		fmt.Printf("  Best day:            %s (%d)\n", summary.MaxDay.Date, summary.MaxDay.Count)
+	}
+	fmt.Printf("  Active days:         %d of %d (%.1f%%)\n", summary.ActiveDays, len(graph.Days), summary.ActiveDayPercent)
+	if t := graph.Types; t != nil {
+		fmt.Printf("  By type:             %d commits, %d pull requests, %d issues, %d reviews\n", t.Commits, t.PullRequests, t.Issues, t.Reviews)
+	}
+	for _, year := range graph.YearTotals {
+		fmt.Printf("  %-20s %d\n", fmt.Sprintf("%d:", year.Year), year.Total)
+	}
+	for _, quarter := range summary.Quarters {
+		fmt.Printf("  %-20s %d\n", quarter.Quarter+":", quarter.Total)
+	}
+
+	forecast, ok := gitgraph.ForecastYear(graph.Days, now, goal)
+	if !ok {
+		return
+	}
+	fmt.Printf("  %-20s %d in %d days\n", fmt.Sprintf("%d so far:", forecast.Year), forecast.YearToDate, forecast.DaysElapsed)
+	fmt.Printf("  Projected total:     %d (linear) / %d (weekday-weighted)\n", forecast.Linear, forecast.WeekdayWeighted)
+	if goal <= 0 {
+		return
+	}
+	label := fmt.Sprintf("Goal of %d:", goal)
+	switch {
+	case forecast.YearToDate >= goal:
+		fmt.Printf("  %-20s reached\n", label)
+	case forecast.DaysRemaining == 0:
+		fmt.Printf("  %-20s missed by %d\n", label, goal-forecast.YearToDate)
+	default:
+		fmt.Printf("  %-20s %.1f a day for the remaining %d days\n", label, forecast.RequiredPace, forecast.DaysRemaining)
+	}
+}
+
+// printAnomalies lists the gaps, spikes and drops found in the graph, if any
+func printAnomalies(anomalies gitgraph.Anomalies) {
+	if len(anomalies.Gaps)+len(anomalies.Spikes)+len(anomalies.Drops) == 0 {
+		return
+	}
+	fmt.Println("  Anomalies:")
+	for _, gap := range anomalies.Gaps {
+		fmt.Printf("    Gap:   %d inactive days (%s to %s)\n", gap.Days, gap.Start, gap.End)
+	}
+	for _, spike := range anomalies.Spikes {
+		fmt.Printf("    Spike: %d on %s (z=%.1f)\n", spike.Count, spike.Date, spike.ZScore)
+	}
+	for _, drop := range anomalies.Drops {
+		fmt.Printf("    Drop:  %s down %.0f%% (%d -> %d)\n", drop.Month, drop.DropPercent, drop.PreviousTotal, drop.Total)
+	}
+}
+
+func formatStreak(s gitgraph.Streak) string {
+	if s.Length == 0 {
+		return "0 days"
+	}
+	return fmt.Sprintf("%d %s (%s to %s)", s.Length, pluralize(s.Length, "day", "days"), s.Start, s.End)
+}
+
+// printRepositories lists the first limit repositories with their share of commits
+func printRepositories(repos []gitgraph.RepositoryContributions, limit int) {
+	if len(repos) == 0 || limit <= 0 {
+		return
+	}
+	total := 0
+	for _, repo := range repos {
+		total += repo.Commits
+	}
+	fmt.Printf("  Commits by repository (%d in %d %s):\n", total, len(repos), pluralize(len(repos), "repository", "repositories"))
+	for _, repo := range repos[:min(limit, len(repos))] {
+		name := repo.Repository
+		if repo.Private {
+			name += " (private)"
+		}
+		fmt.Printf("    %-40s %5d  %5.1f%%\n", name, repo.Commits, float64(repo.Commits)/float64(max(total, 1))*100)
+	}
+	if rest := len(repos) - limit; rest > 0 {
+		fmt.Printf("    and %d more\n", rest)
+	}
+}
+
+// printLanguages summarizes the language breakdown in one line, if there is one
+func printLanguages(shares []gitgraph.LanguageShare) {
+	if len(shares) == 0 {
+		return
+	}
+	parts := []string{}
+	for _, share := range shares[:min(6, len(shares))] {
+		parts = append(parts, fmt.Sprintf("%.0f%% %s", share.Percent, share.Language))
+	}
+	if len(shares) > 6 {
+		parts = append(parts, "...")
+	}
+	fmt.Printf("  Languages:           %s\n", strings.Join(parts, ", "))
+}
+
+// writeLanguagesSVG renders the language breakdown to path, exiting on failure
+func writeLanguagesSVG(path string, shares []gitgraph.LanguageShare, config *Config) {
+	if len(shares) == 0 {
+		slog.Warn("no language breakdown to render; it needs a token", "path", path)
+	}
+	opts := render.DefaultSVGOptions
+	if config.Theme != "" {
+		var err error
+		if opts.Theme, err = config.theme(config.Theme); err != nil {
+			fatal("invalid theme", "err", err)
+		}
+	}
+	out, err := createOutput(path)
+	if err != nil {
+		fatal("creating output", "err", err)
+	}
+	err = render.LanguagesSVG(out, shares, opts)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		fatal("writing output", "path", path, "err", err)
+	}
+}
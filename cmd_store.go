@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"time"
+)
+
+func runStore(ctx context.Context, config *Config, args []string) {
+	if len(args) == 0 || args[0] != "prune" {
+		slog.Error("expected a store subcommand: prune")
+		os.Exit(1)
+	}
+	runStorePrune(ctx, config, args[1:])
+}
+
+// runStorePrune drops the snapshots and samples outside the retention policy
+func runStorePrune(ctx context.Context, config *Config, args []string) {
+	fs := newFlagSet("store prune")
+	logFlags := addLogFlags(fs)
+	// --config is read before flags are parsed, but must still be accepted here
+	fs.String("config", defaultConfigPath(), "path to the config file")
+	storeSpec := fs.String("store", config.Store, "store to prune, e.g. sqlite:history.db")
+	retentionFlags := addRetentionFlags(fs, config)
+	usernames := parseInterspersed(fs, args)
+	logFlags.apply()
+
+	if *storeSpec == "" {
+		slog.Error("no store given, use --store or set store in the config")
+		fs.Usage()
+		os.Exit(1)
+	}
+	retention := retentionFlags.retention()
+	if retention.IsZero() {
+		fatal("nothing to prune, set --keep-per-day, --keep-per-week or --max-age, or retention in the config")
+	}
+	if len(usernames) == 0 {
+		usernames = []string{""}
+	}
+
+	st := mustOpenStore(*storeSpec)
+	defer st.Close()
+	now := time.Now()
+	for _, username := range usernames {
+		pruned, err := st.Prune(ctx, username, retention, now)
+		if err != nil {
+			exitIfInterrupted(ctx)
+			fatal("pruning store", "username", stringOr(username, "all"), "err", err)
+		}
+		slog.Info("pruned store", "username", stringOr(username, "all"), "snapshots", pruned.Snapshots, "samples", pruned.Samples)
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+	"github.com/JyotinderSingh/gitgraphed/store"
+)
+
+func runSync(ctx context.Context, config *Config, args []string) {
+	fs := newFlagSet("sync")
+	clientFlags := addClientFlags(fs, config)
+	storeSpec := fs.String("store", config.Store, "store to update, e.g. sqlite:history.db")
+	overlap := fs.Int("overlap", 14, "days before the last snapshot to fetch again, catching contributions counted late")
+	usernames := parseInterspersed(fs, args)
+	if len(usernames) == 0 {
+		usernames = config.Usernames
+	}
+	if len(usernames) == 0 || *storeSpec == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+	if *overlap < 0 {
+		fatal("invalid --overlap, expected 0 or more days", "overlap", *overlap)
+	}
+
+	// Syncs exist to pick up new contributions, not to reuse cached windows
+	client := clientFlags.newClient()
+	client.Refresh = true
+	st := mustOpenStore(*storeSpec)
+	defer st.Close()
+
+	failed := false
+	for _, username := range usernames {
+		if err := syncUser(ctx, client, st, username, *overlap, time.Now()); err != nil {
+			exitIfInterrupted(ctx)
+			slog.Error("syncing", "username", username, "err", err)
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// syncUser fetches only the days since username's last snapshot, less overlap
+// days, and saves them. Without a snapshot the past year is fetched.
+func syncUser(ctx context.Context, client *gitgraph.Client, st store.Store, username string, overlap int, now time.Time) error {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	yearAgo := today.AddDate(-1, 0, 1)
+	from := yearAgo
+	last, err := st.LastSnapshot(ctx, username)
+	if err != nil {
+		return err
+	}
+	if !last.IsZero() {
+		from = time.Date(last.Year(), last.Month(), last.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, -overlap)
+		if from.Before(yearAgo) {
+			from = yearAgo
+		}
+	}
+
+	window, err := client.Fetch(ctx, username, gitgraph.Options{From: from, To: today})
+	if err != nil {
+		return err
+	}
+	window.Username = username
+
+	// Upstream levels are quartiles of a short window alone, so rescale them
+	// against the stored year the window completes
+	if from.After(yearAgo) {
+		stored, err := st.Graph(ctx, username, yearAgo, today)
+		if err != nil {
+			return err
+		}
+		counts := make(map[string]int)
+		for _, day := range gitgraph.MergeGraphs(stored, window).Days {
+			counts[day.Date] = day.Count
+		}
+		window.Relevel(gitgraph.QuartileThresholds(counts))
+	}
+
+	before, err := st.Graph(ctx, username, from, today)
+	if err != nil {
+		return err
+	}
+	changes := gitgraph.DiffDays(before, window)
+	if err := st.Save(ctx, window, now); err != nil {
+		return err
+	}
+	slog.Info("synced", "username", username,
+		"from", from.Format("2006-01-02"), "to", today.Format("2006-01-02"),
+		"days", len(window.Days), "changed", len(changes))
+	return nil
+}
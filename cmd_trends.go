@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+	"github.com/JyotinderSingh/gitgraphed/render"
+)
+
+// trendReport is the JSON output of trends
+type trendReport struct {
+	Username string            `json:"username"`
+	Metric   string            `json:"metric"`
+	Current  *int              `json:"current,omitempty"` // latest sample in the period
+	Samples  []gitgraph.Sample `json:"samples"`
+}
+
+func runTrends(ctx context.Context, config *Config, args []string) {
+	fs := newFlagSet("trends")
+	clientFlags := addClientFlags(fs, config)
+	storeSpec := fs.String("store", config.Store, "store keeping the samples, e.g. sqlite:history.db")
+	metric := fs.String("metric", "followers", "profile count to show: "+strings.Join(gitgraph.ProfileMetrics, " or "))
+	sample := fs.Bool("sample", true, "sample every profile count into the store before reading; run it on a schedule to build the history")
+	days := fs.Int("days", 365, "days of history to show, ending today")
+	format := fs.String("format", "json", "output format: json for the samples, svg for daily gains on a calendar with a trend line, or spark")
+	outPath := fs.String("out", "", "write output to this file or s3:// or gs:// URL instead of stdout")
+	themeName := fs.String("theme", config.Theme, "color theme for svg output")
+	localeTag := fs.String("locale", config.Locale, "language of month and weekday labels in svg output, e.g. de-DE")
+	args = parseInterspersed(fs, args)
+
+	if *format != "json" && *format != "svg" && *format != "spark" {
+		fatal("unsupported format", "format", *format)
+	}
+	if !contains(gitgraph.ProfileMetrics, *metric) {
+		fatal("unsupported metric", "metric", *metric, "supported", strings.Join(gitgraph.ProfileMetrics, ", "))
+	}
+	if len(args) == 0 && len(config.Usernames) > 0 {
+		args = config.Usernames[:1]
+	}
+	if len(args) != 1 || *storeSpec == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+	username := args[0]
+
+	st := mustOpenStore(*storeSpec)
+	defer st.Close()
+
+	now := time.Now()
+	if *sample {
+		counts, err := clientFlags.newClient().GitHub().ProfileCounts(ctx, username)
+		if err != nil {
+			exitIfInterrupted(ctx)
+			fatalError("reading profile", err, "username", username)
+		}
+		for _, name := range gitgraph.ProfileMetrics {
+			value, _ := counts.Metric(name)
+			if err := st.SaveSample(ctx, username, name, value, now); err != nil {
+				fatal("saving to store", "err", err)
+			}
+		}
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	from := today.AddDate(0, 0, 1-*days)
+	// A sample before the period gives its first day a baseline to gain from
+	samples, err := st.Samples(ctx, username, *metric, from.AddDate(0, 0, -1), now)
+	if err != nil {
+		fatal("reading store", "err", err)
+	}
+	if len(samples) == 0 {
+		slog.Info("no samples in that period", "username", username, "metric", *metric)
+	}
+
+	out, err := createOutput(*outPath)
+	if err != nil {
+		fatal("creating output", "err", err)
+	}
+	graph := gitgraph.TrendGraph(username, samples, from, today, time.Local)
+	switch *format {
+	case "svg":
+		opts := render.DefaultSVGOptions
+		opts.Locale = parseLocale(*localeTag)
+		opts.Trend = 7
+		if *themeName != "" {
+			if opts.Theme, err = config.theme(*themeName); err != nil {
+				fatal("invalid theme", "err", err)
+			}
+		}
+		err = render.SVG(out, graph, opts)
+	case "spark":
+		err = render.Sparkline(out, graph, render.SparkOptions{})
+	default:
+		report := trendReport{Username: username, Metric: *metric, Samples: samples}
+		if len(samples) > 0 {
+			report.Current = &samples[len(samples)-1].Value
+		}
+		err = encodeJSON(out, report)
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		fatal("writing output", "format", *format, "err", err)
+	}
+}
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+	"github.com/JyotinderSingh/gitgraphed/render"
+)
+
+// firstContributionYear is the earliest year the browser pages back to
+const firstContributionYear = 2008
+
+func runTUI(ctx context.Context, config *Config, args []string) {
+	fs := newFlagSet("tui")
+	clientFlags := addClientFlags(fs, config)
+	dayFlags := addDayFlags(fs, config)
+	year := fs.Int("year", time.Now().Year(), "year to open")
+	themeName := fs.String("theme", config.Theme, "color theme (defaults to github-dark)")
+	localeTag := fs.String("locale", config.Locale, "language of month and weekday labels, e.g. de-DE")
+	args = parseInterspersed(fs, args)
+
+	usernames := args
+	if len(usernames) == 0 {
+		usernames = config.Usernames
+	}
+	if len(usernames) == 0 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stdout.Fd())) {
+		fatal("tui needs an interactive terminal")
+	}
+
+	opts := render.TermOptions{
+		TrueColor: render.DetectTrueColor(),
+		Locale:    parseLocale(*localeTag),
+		WeekStart: dayFlags.start(),
+		NoColor:   os.Getenv("NO_COLOR") != "",
+	}
+	if *themeName != "" {
+		theme, err := config.theme(*themeName)
+		if err != nil {
+			fatal("invalid theme", "err", err)
+		}
+		opts.Theme = theme
+	}
+
+	b := &browser{
+		ctx:       ctx,
+		client:    clientFlags.newClient(),
+		workers:   *clientFlags.workers,
+		dayFlags:  dayFlags,
+		opts:      opts,
+		usernames: usernames,
+		graphs:    map[string]*gitgraph.ContributionGraph{},
+	}
+	b.setCursor(time.Date(*year, time.Now().Month(), time.Now().Day(), 0, 0, 0, 0, time.UTC))
+	if err := b.run(); err != nil {
+		fatal("running tui", "err", err)
+	}
+}
+
+// browser is the state of the interactive heatmap: whose graph is shown, for
+// which year, and the day under the cursor
+type browser struct {
+	ctx       context.Context
+	client    *gitgraph.Client
+	workers   int
+	dayFlags  *dayFlags
+	opts      render.TermOptions
+	usernames []string
+	user      int
+	cursor    time.Time
+	graphs    map[string]*gitgraph.ContributionGraph // by username and year
+}
+
+// run draws the browser and handles keys until it is quit, restoring the
+// terminal on the way out
+func (b *browser) run() error {
+	fd := int(os.Stdin.Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return err
+	}
+	defer term.Restore(fd, state)
+	// Draw on the alternate screen without a cursor, like other full-screen programs
+	fmt.Print("\x1b[?1049h\x1b[?25l")
+	defer fmt.Print("\x1b[?25h\x1b[?1049l")
+
+	input := make([]byte, 64)
+	for {
+		b.draw()
+		n, err := os.Stdin.Read(input)
+		if err != nil {
+			return err
+		}
+		for keys := input[:n]; len(keys) > 0; {
+			var key string
+			key, keys = nextKey(keys)
+			if !b.handle(key) {
+				return nil
+			}
+		}
+	}
+}
+
+// nextKey splits the first key off input, keeping arrow escape sequences whole
+func nextKey(input []byte) (string, []byte) {
+	if input[0] == 0x1b && len(input) >= 3 && input[1] == '[' {
+		return string(input[:3]), input[3:]
+	}
+	return string(input[:1]), input[1:]
+}
+
+// handle applies a key, reporting false when it quits the browser
+func (b *browser) handle(key string) bool {
+	switch key {
+	case "q", "\x1b", "\x03": // q, Esc, Ctrl-C
+		return false
+	case "\x1b[A", "k":
+		b.setCursor(b.cursor.AddDate(0, 0, -1))
+	case "\x1b[B", "j":
+		b.setCursor(b.cursor.AddDate(0, 0, 1))
+	case "\x1b[D", "h":
+		b.setCursor(b.cursor.AddDate(0, 0, -7))
+	case "\x1b[C", "l":
+		b.setCursor(b.cursor.AddDate(0, 0, 7))
+	case "[", "p":
+		b.setCursor(b.cursor.AddDate(-1, 0, 0))
+	case "]", "n":
+		b.setCursor(b.cursor.AddDate(1, 0, 0))
+	case "t":
+		b.setCursor(truncateDay(time.Now()))
+	case "\t":
+		b.user = (b.user + 1) % len(b.usernames)
+	case "\x1b[Z": // Shift-Tab
+		b.user = (b.user + len(b.usernames) - 1) % len(b.usernames)
+	}
+	return true
+}
+
+// setCursor moves the cursor to day, kept within the years GitHub has
+// contributions for and no later than today
+func (b *browser) setCursor(day time.Time) {
+	first := time.Date(firstContributionYear, 1, 1, 0, 0, 0, 0, time.UTC)
+	today := truncateDay(time.Now())
+	if day.Before(first) {
+		day = first
+	}
+	if day.After(today) {
+		day = today
+	}
+	b.cursor = day
+}
+
+// graph returns the shown user's graph of the cursor's year, fetching it on first view
+func (b *browser) graph() (*gitgraph.ContributionGraph, error) {
+	username, year := b.usernames[b.user], b.cursor.Year()
+	key := fmt.Sprintf("%s %d", username, year)
+	if graph, ok := b.graphs[key]; ok {
+		return graph, nil
+	}
+	b.status(fmt.Sprintf("Fetching %s's %d contributions...", username, year))
+	graph, err := fetchGraph(b.ctx, b.client, username, period{Years: []int{year}}, b.workers)
+	if err != nil {
+		return nil, err
+	}
+	b.dayFlags.apply(graph, b.client.CurrentTime())
+	b.graphs[key] = graph
+	return graph, nil
+}
+
+// status replaces the screen with a single message while work is in progress
+func (b *browser) status(message string) {
+	fmt.Print("\x1b[H\x1b[2J" + message)
+}
+
+// draw redraws the screen: the heatmap with the stats panel beside it, or
+// below when the terminal is too narrow, then the cursor's day and key help
+func (b *browser) draw() {
+	graph, err := b.graph()
+	var screen strings.Builder
+	if err != nil {
+		fmt.Fprintf(&screen, "%s %d: %v\n", b.usernames[b.user], b.cursor.Year(), err)
+	} else {
+		var heatmap bytes.Buffer
+		opts := b.opts
+		opts.Cursor = b.cursor.Format(time.DateOnly)
+		if err := render.Terminal(&heatmap, graph, opts); err != nil {
+			fmt.Fprintf(&screen, "rendering: %v\n", err)
+		}
+		width, _, sizeErr := term.GetSize(int(os.Stdout.Fd()))
+		if sizeErr != nil {
+			width = 0
+		}
+		screen.WriteString(beside(strings.Split(strings.TrimRight(heatmap.String(), "\n"), "\n"), b.panel(graph), width))
+		screen.WriteString("\n")
+		screen.WriteString(b.details(graph))
+	}
+	fmt.Fprintf(&screen, "\n%s  [%d/%d]\n", b.usernames[b.user], b.user+1, len(b.usernames))
+	screen.WriteString("←↓↑→/hjkl move · [ ] year · tab user · t today · q quit\n")
+	// Raw mode doesn't translate newlines
+	fmt.Print("\x1b[H\x1b[2J" + strings.ReplaceAll(screen.String(), "\n", "\r\n"))
+}
+
+// panel lists summary statistics of the shown graph
+func (b *browser) panel(graph *gitgraph.ContributionGraph) []string {
+	streaks := gitgraph.ComputeStreaks(graph.Days, time.Now())
+	summary := gitgraph.Summarize(graph.Days)
+	lines := []string{
+		fmt.Sprintf("%s in %d", graph.Username, b.cursor.Year()),
+		"",
+		fmt.Sprintf("Total:          %d", graph.TotalContribs),
+		fmt.Sprintf("Current streak: %d", streaks.Current.Length),
+		fmt.Sprintf("Longest streak: %d", streaks.Longest.Length),
+		fmt.Sprintf("Active days:    %d of %d", summary.ActiveDays, len(graph.Days)),
+		fmt.Sprintf("Busiest day:    %s", summary.BusiestWeekday),
+	}
+	if summary.MaxDay.Count > 0 {
+		lines = append(lines, fmt.Sprintf("Best day:       %s (%d)", summary.MaxDay.Date, summary.MaxDay.Count))
+	}
+	return lines
+}
+
+// details describes the day under the cursor
+func (b *browser) details(graph *gitgraph.ContributionGraph) string {
+	date := b.cursor.Format(time.DateOnly)
+	when := b.opts.Locale.Weekday(b.cursor.Weekday()) + " " + b.opts.Locale.FormatDate(b.cursor)
+	for _, day := range graph.Days {
+		if day.Date == date {
+			return fmt.Sprintf("%s: %d %s\n", when, day.Count, pluralize(day.Count, "contribution", "contributions"))
+		}
+	}
+	return when + ": no data\n"
+}
+
+// ansiEscape matches the color sequences in rendered heatmap lines
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// visibleWidth is the number of terminal columns s occupies
+func visibleWidth(s string) int {
+	return utf8.RuneCountInString(ansiEscape.ReplaceAllString(s, ""))
+}
+
+// beside joins left and right as columns, or stacks right below left when the
+// two don't fit in width columns (0 when unknown)
+func beside(left, right []string, width int) string {
+	leftWidth, rightWidth := 0, 0
+	for _, line := range left {
+		leftWidth = max(leftWidth, visibleWidth(line))
+	}
+	for _, line := range right {
+		rightWidth = max(rightWidth, visibleWidth(line))
+	}
+	const gutter = 4
+	if width > 0 && leftWidth+gutter+rightWidth > width {
+		return strings.Join(left, "\n") + "\n\n" + strings.Join(right, "\n") + "\n"
+	}
+
+	var b strings.Builder
+	for i := range max(len(left), len(right)) {
+		line := ""
+		if i < len(left) {
+			line = left[i]
+		}
+		if i < len(right) {
+			line += strings.Repeat(" ", leftWidth-visibleWidth(line)+gutter) + right[i]
+		}
+		b.WriteString(strings.TrimRight(line, " ") + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,413 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// updatePublicKey is the base64 Ed25519 key that signs release checksums.
+// Release builds set it with -ldflags "-X main.updatePublicKey=...", and
+// update then refuses releases without a valid checksums.txt.sig.
+var updatePublicKey string
+
+// defaultReleasesURL lists gitgraphed's releases in GitHub's REST API
+const defaultReleasesURL = "https://api.github.com/repos/JyotinderSingh/gitgraphed/releases"
+
+// maxReleaseAsset bounds how much of a release asset is downloaded
+const maxReleaseAsset = 200 << 20
+
+func runUpdate(ctx context.Context, config *Config, args []string) {
+	fs := newFlagSet("update")
+	logFlags := addLogFlags(fs)
+	// --config is read before flags are parsed, but must still be accepted here
+	fs.String("config", defaultConfigPath(), "path to the config file")
+	check := fs.Bool("check", false, "only report whether a newer release is available")
+	version := fs.String("version", "", "install this release, e.g. v1.4.0, instead of the latest; older ones are allowed")
+	force := fs.Bool("force", false, "install even when already up to date, or when the running version is unknown, as in builds from source")
+	releasesURL := fs.String("releases-url", defaultReleasesURL, "GitHub REST API URL of the releases to update from, for a mirror or fork")
+	publicKey := fs.String("public-key", updatePublicKey, "base64 Ed25519 key that must have signed the release's checksums.txt, as checksums.txt.sig")
+	unsigned := fs.Bool("unsigned", false, "install without a --public-key, trusting checksums.txt from the same place as the download")
+	parseInterspersed(fs, args)
+	logFlags.apply()
+	if *publicKey == "" && !*unsigned && !*check {
+		fatal("no key to verify the release's signature with; pass --public-key, or --unsigned to trust its checksums alone")
+	}
+
+	client := &http.Client{Timeout: 5 * time.Minute}
+	release, err := fetchRelease(ctx, client, *releasesURL, *version)
+	if err != nil {
+		exitIfInterrupted(ctx)
+		fatal("looking up the release", "err", err)
+	}
+	current := moduleVersion()
+	newer := compareVersions(release.TagName, current) > 0
+	if *check {
+		switch {
+		case !isVersion(current):
+			fmt.Printf("%s is the latest release; this build's version is unknown (%s)\n", release.TagName, current)
+		case newer:
+			fmt.Printf("%s is available, this is %s; run 'gitgraphed update' to install it\n", release.TagName, current)
+		default:
+			fmt.Printf("%s is up to date\n", current)
+		}
+		return
+	}
+	switch {
+	case *force:
+	case !isVersion(current):
+		fatal("this build's version is unknown, as in builds from source; use --force to replace it anyway", "version", current)
+	case *version == "" && !newer:
+		fmt.Printf("%s is up to date\n", current)
+		return
+	case release.TagName == current:
+		fmt.Printf("%s is already installed\n", current)
+		return
+	}
+
+	if *publicKey == "" {
+		slog.Warn("INSTALLING AN UNSIGNED RELEASE: checksums.txt comes from the same source as the download, so it only guards against corruption, not tampering", "release", release.TagName)
+	}
+	binary, err := downloadRelease(ctx, client, release, *publicKey)
+	if err != nil {
+		exitIfInterrupted(ctx)
+		fatal("downloading the release", "release", release.TagName, "err", err)
+	}
+	path, err := replaceExecutable(binary)
+	if err != nil {
+		fatal("replacing the executable", "err", err)
+	}
+	slog.Info("updated", "from", current, "to", release.TagName, "path", path)
+}
+
+// release is the part of a GitHub release update uses
+type release struct {
+	TagName string         `json:"tag_name"`
+	Assets  []releaseAsset `json:"assets"`
+}
+
+type releaseAsset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+// fetchRelease looks up the release tagged tag, or the latest one
+func fetchRelease(ctx context.Context, client *http.Client, releasesURL, tag string) (*release, error) {
+	endpoint := strings.TrimRight(releasesURL, "/") + "/latest"
+	if tag != "" {
+		endpoint = strings.TrimRight(releasesURL, "/") + "/tags/" + url.PathEscape(tag)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	// A token only raises the rate limit, so one from the environment will
+	// do, but it's never sent to a mirror
+	if token := stringOr(os.Getenv("GITHUB_TOKEN"), os.Getenv("GH_TOKEN")); token != "" && req.URL.Host == "api.github.com" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound && tag != "":
+		return nil, fmt.Errorf("no release tagged %s", tag)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("%s: %s", endpoint, resp.Status)
+	}
+	var r release
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return nil, fmt.Errorf("decoding release: %w", err)
+	}
+	return &r, nil
+}
+
+// downloadRelease fetches the release's archive for this platform and
+// returns the executable inside, once it matches checksums.txt and, with a
+// publicKey, checksums.txt matches its signature
+func downloadRelease(ctx context.Context, client *http.Client, r *release, publicKey string) ([]byte, error) {
+	asset, ok := platformAsset(r.Assets, runtime.GOOS, runtime.GOARCH)
+	if !ok {
+		return nil, fmt.Errorf("%s has no build for %s/%s", r.TagName, runtime.GOOS, runtime.GOARCH)
+	}
+	sums, ok := findAsset(r.Assets, "checksums.txt")
+	if !ok {
+		return nil, fmt.Errorf("%s has no checksums.txt to verify the download with", r.TagName)
+	}
+	checksums, err := download(ctx, client, sums.URL)
+	if err != nil {
+		return nil, err
+	}
+	if publicKey != "" {
+		if err := verifySignature(ctx, client, r, checksums, publicKey); err != nil {
+			return nil, err
+		}
+	}
+	want, ok := checksumOf(checksums, asset.Name)
+	if !ok {
+		return nil, fmt.Errorf("checksums.txt has no checksum of %s", asset.Name)
+	}
+
+	slog.Info("downloading", "asset", asset.Name)
+	data, err := download(ctx, client, asset.URL)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	if got := hex.EncodeToString(sum[:]); got != want {
+		return nil, fmt.Errorf("checksum mismatch for %s: got %s, want %s", asset.Name, got, want)
+	}
+	return extractExecutable(asset.Name, data)
+}
+
+// verifySignature checks checksums against the release's checksums.txt.sig,
+// a base64 Ed25519 signature by publicKey
+func verifySignature(ctx context.Context, client *http.Client, r *release, checksums []byte, publicKey string) error {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(publicKey))
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return errors.New("invalid public key, expected a base64 Ed25519 key")
+	}
+	sigAsset, ok := findAsset(r.Assets, "checksums.txt.sig")
+	if !ok {
+		return fmt.Errorf("%s has no checksums.txt.sig, and a signature is required", r.TagName)
+	}
+	data, err := download(ctx, client, sigAsset.URL)
+	if err != nil {
+		return err
+	}
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || !ed25519.Verify(key, checksums, sig) {
+		return fmt.Errorf("checksums.txt of %s isn't signed by the expected key", r.TagName)
+	}
+	return nil
+}
+
+// platformAsset finds the archive of goos/goarch, named like
+// gitgraphed_1.4.0_linux_amd64.tar.gz
+func platformAsset(assets []releaseAsset, goos, goarch string) (releaseAsset, bool) {
+	suffix := "_" + goos + "_" + goarch
+	for _, asset := range assets {
+		name := strings.TrimSuffix(strings.TrimSuffix(asset.Name, ".tar.gz"), ".zip")
+		if strings.HasPrefix(asset.Name, "gitgraphed") && strings.HasSuffix(strings.TrimSuffix(name, ".exe"), suffix) {
+			return asset, true
+		}
+	}
+	return releaseAsset{}, false
+}
+
+func findAsset(assets []releaseAsset, name string) (releaseAsset, bool) {
+	for _, asset := range assets {
+		if asset.Name == name {
+			return asset, true
+		}
+	}
+	return releaseAsset{}, false
+}
+
+// checksumOf finds name's SHA-256 in checksums, in sha256sum's format
+func checksumOf(checksums []byte, name string) (string, bool) {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return strings.ToLower(fields[0]), true
+		}
+	}
+	return "", false
+}
+
+func download(ctx context.Context, client *http.Client, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxReleaseAsset+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxReleaseAsset {
+		return nil, fmt.Errorf("%s: larger than %d MB", url, maxReleaseAsset>>20)
+	}
+	return data, nil
+}
+
+// extractExecutable returns the gitgraphed executable in a .tar.gz or .zip
+// archive, or data itself when the asset is a bare executable
+func extractExecutable(name string, data []byte) ([]byte, error) {
+	exe := "gitgraphed"
+	if runtime.GOOS == "windows" {
+		exe += ".exe"
+	}
+	switch {
+	case strings.HasSuffix(name, ".tar.gz"):
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		tr := tar.NewReader(gz)
+		for {
+			header, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			if header.Typeflag == tar.TypeReg && filepath.Base(header.Name) == exe {
+				return io.ReadAll(io.LimitReader(tr, maxReleaseAsset))
+			}
+		}
+	case strings.HasSuffix(name, ".zip"):
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range zr.File {
+			if !f.FileInfo().IsDir() && filepath.Base(f.Name) == exe {
+				rc, err := f.Open()
+				if err != nil {
+					return nil, err
+				}
+				defer rc.Close()
+				return io.ReadAll(io.LimitReader(rc, maxReleaseAsset))
+			}
+		}
+	default:
+		return data, nil
+	}
+	return nil, fmt.Errorf("%s has no %s", name, exe)
+}
+
+// replaceExecutable swaps the running executable for binary, returning its
+// path. The new file is written next to it and renamed into place, so an
+// interrupted update leaves the old one working. Windows can't replace a
+// running executable, only rename it, so it is moved aside first.
+func replaceExecutable(binary []byte) (string, error) {
+	path, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	if path, err = filepath.EvalSymlinks(path); err != nil {
+		return "", err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".gitgraphed-update-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(binary)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), info.Mode().Perm()|0o111)
+	}
+	if err != nil {
+		return "", err
+	}
+	if runtime.GOOS == "windows" {
+		old := path + ".old"
+		os.Remove(old)
+		if err := os.Rename(path, old); err != nil {
+			return "", err
+		}
+	}
+	return path, os.Rename(tmp.Name(), path)
+}
+
+// isVersion reports whether v is a release version like v1.4.0, not the
+// pseudo-version of a commit, as go install or a build from a checkout gives
+func isVersion(v string) bool {
+	if _, ok := parseVersion(v); !ok || strings.HasSuffix(v, "+dirty") {
+		return false
+	}
+	parts := strings.Split(strings.TrimSuffix(v, "+incompatible"), "-")
+	revision := parts[len(parts)-1]
+	if len(parts) >= 3 && len(revision) == 12 && strings.Trim(revision, "0123456789abcdef") == "" {
+		return false
+	}
+	return true
+}
+
+// compareVersions orders release versions, treating unknown ones as
+// older than any release
+func compareVersions(a, b string) int {
+	va, okA := parseVersion(a)
+	vb, okB := parseVersion(b)
+	switch {
+	case !okA && !okB:
+		return 0
+	case !okB:
+		return 1
+	case !okA:
+		return -1
+	}
+	for i := range va {
+		if va[i] != vb[i] {
+			if va[i] > vb[i] {
+				return 1
+			}
+			return -1
+		}
+	}
+	return 0
+}
+
+// parseVersion reads vMAJOR.MINOR.PATCH, ignoring any pre-release or build
+// suffix
+func parseVersion(v string) ([3]int, bool) {
+	var parts [3]int
+	v, ok := strings.CutPrefix(v, "v")
+	if !ok {
+		return parts, false
+	}
+	v, _, _ = strings.Cut(v, "+")
+	v, _, _ = strings.Cut(v, "-")
+	fields := strings.Split(v, ".")
+	if len(fields) != 3 {
+		return parts, false
+	}
+	for i, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 {
+			return parts, false
+		}
+		parts[i] = n
+	}
+	return parts, true
+}
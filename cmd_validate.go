@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+func runValidate(ctx context.Context, config *Config, args []string) {
+	fs := newFlagSet("validate")
+	logFlags := addLogFlags(fs)
+	// --config is read before flags are parsed, but must still be accepted here
+	fs.String("config", defaultConfigPath(), "path to the config file")
+	printSchema := fs.Bool("schema", false, "print the JSON Schema instead of validating")
+	args = parseInterspersed(fs, args)
+	logFlags.apply()
+
+	if *printSchema {
+		os.Stdout.Write(gitgraph.Schema)
+		return
+	}
+	if len(args) == 0 {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	valid := true
+	for _, path := range args {
+		errs, err := validateFile(path)
+		if err != nil {
+			fatal("reading output", "path", path, "err", err)
+		}
+		for _, err := range errs {
+			fmt.Printf("%s: %v\n", path, err)
+		}
+		valid = valid && len(errs) == 0
+	}
+	if !valid {
+		os.Exit(1)
+	}
+}
+
+// validateFile checks each graph in path ("-" for stdin) against the schema:
+// one JSON document, a JSON array of them, or JSON lines. The results of batch
+// are checked by their graph. Errors are prefixed with the graph's position
+// when there are several.
+func validateFile(path string) ([]error, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var graphs []json.RawMessage
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &graphs); err != nil {
+			return []error{err}, nil
+		}
+	} else {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		for {
+			var graph json.RawMessage
+			if err := dec.Decode(&graph); errors.Is(err, io.EOF) {
+				break
+			} else if err != nil {
+				return []error{err}, nil
+			}
+			graphs = append(graphs, graph)
+		}
+	}
+	if len(graphs) == 0 {
+		return []error{fmt.Errorf("no JSON found")}, nil
+	}
+
+	var errs []error
+	for i, graph := range graphs {
+		var result struct {
+			Days  json.RawMessage `json:"days"`
+			Graph json.RawMessage `json:"graph"`
+			Error string          `json:"error"`
+		}
+		if json.Unmarshal(graph, &result) == nil && result.Days == nil {
+			if result.Graph == nil && result.Error != "" {
+				continue // a failed batch result has no graph
+			}
+			if result.Graph != nil {
+				graph = result.Graph
+			}
+		}
+		for _, err := range gitgraph.ValidateJSON(graph) {
+			if len(graphs) > 1 {
+				err = fmt.Errorf("#%d: %w", i+1, err)
+			}
+			errs = append(errs, err)
+		}
+	}
+	return errs, nil
+}
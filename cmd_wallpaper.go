@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+	"github.com/JyotinderSingh/gitgraphed/publish"
+	"github.com/JyotinderSingh/gitgraphed/render"
+)
+
+func runWallpaper(ctx context.Context, config *Config, args []string) {
+	fs := newFlagSet("wallpaper")
+	clientFlags := addClientFlags(fs, config)
+	size := fs.String("size", fmt.Sprintf("%dx%d", render.DefaultWallpaperOptions.Width, render.DefaultWallpaperOptions.Height), "wallpaper resolution as WIDTHxHEIGHT")
+	themeName := fs.String("theme", firstNonEmpty(config.Theme, render.GitHubDarkTheme.Name), "color theme of the calendar and background")
+	background := fs.String("background", "", "hex color filling the screen, overriding the theme's background")
+	fill := fs.Float64("fill", render.DefaultWallpaperOptions.Fill, "fraction of the screen width the calendar may take")
+	noCaption := fs.Bool("no-caption", false, "omit the username and total caption")
+	weekStart := fs.String("week-start", config.WeekStart, "first day of the week, sunday or monday")
+	outPath := fs.String("out", "", "write the PNG to this file or s3:// or gs:// URL instead of stdout (default in the cache directory with --set)")
+	set := fs.Bool("set", false, "set the written file as the desktop wallpaper (macOS, GNOME, or X11 with feh)")
+	args = parseInterspersed(fs, args)
+
+	username := ""
+	if len(args) > 0 {
+		username = args[0]
+	} else if len(config.Usernames) > 0 {
+		username = config.Usernames[0]
+	}
+	if username == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	opts := render.DefaultWallpaperOptions
+	opts.WeekStart = parseWeekStart(*weekStart)
+	var err error
+	if opts.Width, opts.Height, err = parseResolution(*size); err != nil {
+		fatal("invalid --size", "err", err)
+	}
+	if opts.Theme, err = config.theme(*themeName); err != nil {
+		fatal("invalid theme", "err", err)
+	}
+	if *background != "" {
+		if opts.Theme, err = render.NewTheme(opts.Theme.Name, opts.Theme.Levels, *background, "", opts.Theme); err != nil {
+			fatal("invalid --background", "err", err)
+		}
+	}
+	opts.Fill = *fill
+	opts.Caption = !*noCaption
+
+	if *set {
+		if publish.IsRemote(*outPath) {
+			fatal("--set needs a local --out file", "out", *outPath)
+		}
+		if *outPath == "" {
+			dir, err := gitgraph.DefaultCacheDir()
+			if err != nil {
+				fatal("locating cache directory", "err", err)
+			}
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				fatal("creating cache directory", "err", err)
+			}
+			// Desktops cache images by path, so each user gets a stable file
+			*outPath = filepath.Join(dir, "wallpaper-"+username+".png")
+		}
+	}
+
+	// Like the profile calendar, the wallpaper shows the rolling year
+	to := truncateDay(time.Now())
+	client := clientFlags.newClient()
+	graph := mustFetchGraph(ctx, client, username, period{From: to.AddDate(-1, 0, 1), To: to}, *clientFlags.workers)
+
+	out, err := createOutput(*outPath)
+	if err == nil {
+		err = render.Wallpaper(out, graph, opts)
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		fatal("writing wallpaper", "err", err)
+	}
+
+	if *set {
+		path, err := filepath.Abs(*outPath)
+		if err == nil {
+			err = setWallpaper(ctx, path)
+		}
+		if err != nil {
+			fatal("setting wallpaper", "err", err)
+		}
+		slog.Info("set wallpaper", "path", path)
+	}
+}
+
+// parseResolution parses WIDTHxHEIGHT, e.g. 2560x1440
+func parseResolution(spec string) (int, int, error) {
+	w, h, ok := strings.Cut(strings.ToLower(spec), "x")
+	if !ok {
+		return 0, 0, fmt.Errorf("expected WIDTHxHEIGHT, got %q", spec)
+	}
+	width, err := strconv.Atoi(w)
+	if err != nil || width < 1 {
+		return 0, 0, fmt.Errorf("invalid width in %q", spec)
+	}
+	height, err := strconv.Atoi(h)
+	if err != nil || height < 1 {
+		return 0, 0, fmt.Errorf("invalid height in %q", spec)
+	}
+	return width, height, nil
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+	"github.com/JyotinderSingh/gitgraphed/render"
+)
+
+// widgetWorkflow is a GitHub Actions workflow that refreshes a committed widget daily
+const widgetWorkflow = `# .github/workflows/gitgraphed.yml
+name: Update contribution widget
+on:
+  schedule:
+    - cron: "0 3 * * *"
+  workflow_dispatch:
+permissions:
+  contents: write
+jobs:
+  widget:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/setup-go@v5
+        with:
+          go-version: stable
+      - run: go install github.com/JyotinderSingh/gitgraphed@latest
+      - run: gitgraphed widget --commit ${{ github.repository }} --path %[2]s %[1]s
+        env:
+          GITHUB_TOKEN: ${{ secrets.GITHUB_TOKEN }}
+
+# Or from cron on any machine with a token that can write to the repository:
+# 0 3 * * * GITHUB_TOKEN=... gitgraphed widget --commit %[1]s/%[1]s --path %[2]s %[1]s
+`
+
+func runWidget(ctx context.Context, config *Config, args []string) {
+	fs := newFlagSet("widget")
+	clientFlags := addClientFlags(fs, config)
+	format := fs.String("format", "svg", "widget format: svg, or md for a README snippet embedding --path")
+	themeName := fs.String("theme", config.Theme, "color theme for the SVG")
+	outPath := fs.String("out", "", "write the widget to this file or s3:// or gs:// URL instead of stdout")
+	commit := fs.String("commit", "", "commit the widget to this owner/repo through the GitHub API instead of writing it")
+	path := fs.String("path", "gitgraphed.svg", "file path of the widget in the repository")
+	branch := fs.String("branch", "", "branch to commit to (defaults to the repository's default branch)")
+	localeTag := fs.String("locale", config.Locale, "language of month and weekday labels, e.g. de-DE")
+	weekStart := fs.String("week-start", config.WeekStart, "first day of the week, sunday or monday")
+	instructions := fs.Bool("instructions", false, "print a workflow that keeps the widget updated, then exit")
+	args = parseInterspersed(fs, args)
+
+	username := ""
+	if len(args) > 0 {
+		username = args[0]
+	} else if len(config.Usernames) > 0 {
+		username = config.Usernames[0]
+	}
+	if username == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+	if *instructions {
+		fmt.Printf(widgetWorkflow, username, *path)
+		return
+	}
+	if *format != "svg" && *format != "md" {
+		fatal("unsupported format", "format", *format)
+	}
+
+	opts := render.WidgetOptions{SVG: render.DefaultSVGOptions}
+	opts.SVG.Locale = parseLocale(*localeTag)
+	opts.SVG.WeekStart = parseWeekStart(*weekStart)
+	if *themeName != "" {
+		theme, err := config.theme(*themeName)
+		if err != nil {
+			fatal("invalid theme", "err", err)
+		}
+		opts.SVG.Theme = theme
+	}
+
+	// Profile READMEs show the rolling year, like the profile calendar
+	to := truncateDay(time.Now())
+	client := clientFlags.newClient()
+	graph := mustFetchGraph(ctx, client, username, period{From: to.AddDate(-1, 0, 1), To: to}, *clientFlags.workers)
+
+	var widget bytes.Buffer
+	if *format == "md" {
+		writeWidgetMarkdown(&widget, graph, *path)
+	} else if err := render.Widget(&widget, graph, opts); err != nil {
+		fatal("rendering widget", "err", err)
+	}
+
+	if *commit != "" {
+		message := fmt.Sprintf("Update contribution widget for %s", username)
+		if err := client.GitHub().PutFile(ctx, *commit, *path, *branch, message, widget.Bytes()); err != nil {
+			exitIfInterrupted(ctx)
+			fatalError("committing widget", err, "repo", *commit)
+		}
+		slog.Info("committed widget", "path", *path, "repo", *commit)
+		return
+	}
+
+	out, err := createOutput(*outPath)
+	if err == nil {
+		_, err = out.Write(widget.Bytes())
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		fatal("writing widget", "err", err)
+	}
+}
+
+// writeWidgetMarkdown writes a README snippet embedding the SVG at svgPath with
+// a one-line summary, between markers so it can be replaced on later runs
+func writeWidgetMarkdown(w *bytes.Buffer, graph *gitgraph.ContributionGraph, svgPath string) {
+	streak := gitgraph.ComputeStreaks(graph.Days, time.Now()).Current.Length
+	fmt.Fprintln(w, "<!-- gitgraphed:start -->")
+	fmt.Fprintf(w, "![%s's contributions](%s)\n\n", graph.Username, svgPath)
+	fmt.Fprintf(w, "**%d** contributions in the last year · **%d**-day streak\n", graph.TotalContribs, streak)
+	fmt.Fprintln(w, "<!-- gitgraphed:end -->")
+}
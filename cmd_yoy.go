@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+	"github.com/JyotinderSingh/gitgraphed/render"
+)
+
+func runYoY(ctx context.Context, config *Config, args []string) {
+	fs := newFlagSet("yoy")
+	clientFlags := addClientFlags(fs, config)
+	format := fs.String("format", "json", "output format: json, text, or svg for both years' calendars aligned by ISO week")
+	outPath := fs.String("out", "", "write output to this file or s3:// or gs:// URL instead of stdout")
+	themeName := fs.String("theme", config.Theme, "color theme for svg output")
+	localeTag := fs.String("locale", config.Locale, "language of month and weekday labels in svg output, e.g. de-DE")
+	args = parseInterspersed(fs, args)
+
+	if *format != "json" && *format != "text" && *format != "svg" {
+		fatal("unsupported format", "format", *format)
+	}
+	if len(args) == 2 && len(config.Usernames) > 0 {
+		args = append([]string{config.Usernames[0]}, args...)
+	}
+	if len(args) != 3 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	username := args[0]
+	baseYear, err1 := strconv.Atoi(args[1])
+	year, err2 := strconv.Atoi(args[2])
+	if err1 != nil || err2 != nil {
+		fatal("invalid years", "base", args[1], "year", args[2])
+	}
+
+	client := clientFlags.newClient()
+	base := mustFetchGraph(ctx, client, username, isoYearPeriod(baseYear), *clientFlags.workers)
+	current := mustFetchGraph(ctx, client, username, isoYearPeriod(year), *clientFlags.workers)
+
+	out, err := createOutput(*outPath)
+	if err != nil {
+		fatal("creating output", "err", err)
+	}
+	switch *format {
+	case "svg":
+		opts := render.DefaultSVGOptions
+		opts.Locale = parseLocale(*localeTag)
+		if *themeName != "" {
+			if opts.Theme, err = config.theme(*themeName); err != nil {
+				fatal("invalid theme", "err", err)
+			}
+		}
+		err = render.YearOverYearSVG(out, base, current, baseYear, year, opts)
+	case "text":
+		err = printYoY(out, gitgraph.CompareYears(base, current, baseYear, year))
+	default:
+		err = encodeJSON(out, gitgraph.CompareYears(base, current, baseYear, year))
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		fatal("writing output", "format", *format, "err", err)
+	}
+}
+
+// isoYearPeriod covers both the calendar year and its ISO weeks
+func isoYearPeriod(year int) period {
+	from, to := gitgraph.ISOYearBounds(year)
+	if jan1 := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC); jan1.Before(from) {
+		from = jan1
+	}
+	if dec31 := time.Date(year, 12, 31, 0, 0, 0, 0, time.UTC); dec31.After(to) {
+		to = dec31
+	}
+	return period{From: from, To: to}
+}
+
+func printYoY(w io.Writer, yoy *gitgraph.YearOverYear) error {
+	fmt.Fprintf(w, "%s: %d vs %d\n", yoy.Username, yoy.Year, yoy.BaseYear)
+	fmt.Fprintf(w, "  Total:          %d -> %d (%s)\n", yoy.Total.Base, yoy.Total.Current, formatDelta(yoy.Total.Current, yoy.Total.Base))
+	fmt.Fprintf(w, "  Active days:    %d -> %d (%s)\n", yoy.ActiveDays.Base, yoy.ActiveDays.Current, formatDelta(yoy.ActiveDays.Current, yoy.ActiveDays.Base))
+	fmt.Fprintf(w, "  Longest streak: %d -> %d (%s)\n", yoy.LongestStreak.Base, yoy.LongestStreak.Current, formatDelta(yoy.LongestStreak.Current, yoy.LongestStreak.Base))
+	fmt.Fprintln(w, "\n  Month  "+strconv.Itoa(yoy.BaseYear)+"   "+strconv.Itoa(yoy.Year)+"   Change")
+	for _, m := range yoy.Months {
+		fmt.Fprintf(w, "  %-5s %6d %6d   %s\n", m.Period, m.Base, m.Current, formatDelta(m.Current, m.Base))
+	}
+	fmt.Fprintln(w, "\n  Week   "+strconv.Itoa(yoy.BaseYear)+"   "+strconv.Itoa(yoy.Year)+"   Change")
+	for _, wk := range yoy.Weeks {
+		fmt.Fprintf(w, "  %-5s %6d %6d   %s\n", wk.Period, wk.Base, wk.Current, formatDelta(wk.Current, wk.Base))
+	}
+	return nil
+}
@@ -0,0 +1,303 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/JyotinderSingh/gitgraphed/render"
+)
+
+// Config holds defaults loaded from config.yaml. Command-line flags take precedence.
+type Config struct {
+	Usernames []string      `yaml:"usernames"`
+	Token     string        `yaml:"token"`
+	Format    string        `yaml:"format"`
+	CacheTTL  time.Duration `yaml:"cacheTTL"`
+	Provider  string        `yaml:"provider"`
+	BaseURL   string        `yaml:"baseURL"`
+	Store     string        `yaml:"store"`
+	Proxy     string        `yaml:"proxy"`
+	// Retention bounds what the store keeps, applied by store prune and
+	// after each daemon run
+	Retention RetentionConfig `yaml:"retention"`
+	// CacheControl is sent with outputs uploaded to s3:// and gs:// paths
+	CacheControl string `yaml:"cacheControl"`
+	Theme        string `yaml:"theme"`
+	// Locale names months and weekdays in rendered output, e.g. de-DE
+	Locale string `yaml:"locale"`
+	// WeekStart is the first day of the week, sunday or monday
+	WeekStart string `yaml:"weekStart"`
+	// Themes defines custom themes usable with --theme
+	Themes map[string]ThemeConfig `yaml:"themes"`
+	// IncludePrivate adds private contribution counts when authenticated
+	IncludePrivate bool `yaml:"includePrivate"`
+	// GitHubURL points at a GitHub Enterprise Server instance, which uses its own token
+	GitHubURL       string `yaml:"githubURL"`
+	EnterpriseToken string `yaml:"enterpriseToken"`
+	// App authenticates as a GitHub App installation instead of with a token
+	App GitHubAppConfig `yaml:"app"`
+	// OAuthClientID is the OAuth app login authorizes, for instances without
+	// the built-in one, such as GitHub Enterprise Server
+	OAuthClientID string `yaml:"oauthClientID"`
+	// Goals are the contribution targets tracked by progress
+	Goals GoalsConfig `yaml:"goals"`
+	// SlackWebhook is a Slack incoming webhook URL notified by watch mode
+	SlackWebhook string `yaml:"slackWebhook"`
+	// DiscordWebhook is a Discord webhook URL notified by watch mode
+	DiscordWebhook string `yaml:"discordWebhook"`
+	// SMTP is the mail server used by report --email
+	SMTP SMTPConfig `yaml:"smtp"`
+	// Daemon holds the timetable of the daemon command
+	Daemon DaemonConfig `yaml:"daemon"`
+	// Teams name groups of usernames for leaderboard --team
+	Teams map[string][]string `yaml:"teams"`
+	// Server holds the defaults of serve
+	Server ServerConfig `yaml:"server"`
+	// MaxParallel and RequestsPerMinute bound outbound requests, as
+	// --max-parallel and --requests-per-minute
+	MaxParallel       int     `yaml:"maxParallel"`
+	RequestsPerMinute float64 `yaml:"requestsPerMinute"`
+	// TimeZone dates timestamped contributions, as --tz
+	TimeZone string `yaml:"timeZone"`
+	// Identities map a person to the names and emails they commit under,
+	// counted together by local
+	Identities map[string][]string `yaml:"identities"`
+}
+
+// identity returns the name and aliases of the configured identity author
+// names or belongs to, or author alone when there is none
+func (c *Config) identity(author string) (string, []string) {
+	if aliases, ok := c.Identities[author]; ok {
+		return author, aliases
+	}
+	for name, aliases := range c.Identities {
+		for _, alias := range aliases {
+			if strings.EqualFold(alias, author) {
+				return name, aliases
+			}
+		}
+	}
+	return author, nil
+}
+
+// ServerConfig holds the options of serve. Each can also be set by the
+// GITGRAPHED_* environment variable noted, which takes precedence, so
+// containers can be configured without mounting a file.
+type ServerConfig struct {
+	Listen         string   `yaml:"listen"`         // $GITGRAPHED_LISTEN, or $GITGRAPHED_PORT for ":<port>"
+	GRPC           string   `yaml:"grpc"`           // $GITGRAPHED_GRPC
+	CacheDir       string   `yaml:"cacheDir"`       // $GITGRAPHED_CACHE_DIR; empty caches in memory
+	RateLimit      float64  `yaml:"rateLimit"`      // $GITGRAPHED_RATE_LIMIT
+	RateBurst      int      `yaml:"rateBurst"`      // $GITGRAPHED_RATE_BURST
+	KeyRateLimit   float64  `yaml:"keyRateLimit"`   // $GITGRAPHED_KEY_RATE_LIMIT
+	KeyRateBurst   int      `yaml:"keyRateBurst"`   // $GITGRAPHED_KEY_RATE_BURST
+	APIKeys        []string `yaml:"apiKeys"`        // $GITGRAPHED_API_KEYS, comma-separated
+	TrustProxy     bool     `yaml:"trustProxy"`     // $GITGRAPHED_TRUST_PROXY
+	AllowedOrigins []string `yaml:"allowedOrigins"` // $GITGRAPHED_ALLOWED_ORIGINS, comma-separated
+	AllowedMethods []string `yaml:"allowedMethods"` // $GITGRAPHED_ALLOWED_METHODS, comma-separated
+	SigningKeys    []string `yaml:"signingKeys"`    // $GITGRAPHED_SIGNING_KEYS, comma-separated id:secret pairs
+	RequireAPIKey  bool     `yaml:"requireApiKey"`  // $GITGRAPHED_REQUIRE_API_KEY
+	OTLPEndpoint   string   `yaml:"otlpEndpoint"`   // $OTEL_EXPORTER_OTLP_ENDPOINT
+	AdminKeys      []string `yaml:"adminKeys"`      // $GITGRAPHED_ADMIN_KEYS, comma-separated
+	Warm           []string `yaml:"warm"`           // $GITGRAPHED_WARM, comma-separated usernames
+	// HedgeAfter sends a second upstream request when the first is slower,
+	// $GITGRAPHED_HEDGE_AFTER; 0 disables hedging
+	HedgeAfter time.Duration `yaml:"hedgeAfter"`
+}
+
+// applyEnv overrides c with the GITGRAPHED_* variables that are set
+func (c *ServerConfig) applyEnv() error {
+	if port := os.Getenv("GITGRAPHED_PORT"); port != "" {
+		c.Listen = ":" + port
+	}
+	var errs []error
+	for name, target := range map[string]any{
+		"GITGRAPHED_LISTEN":          &c.Listen,
+		"GITGRAPHED_GRPC":            &c.GRPC,
+		"GITGRAPHED_CACHE_DIR":       &c.CacheDir,
+		"GITGRAPHED_RATE_LIMIT":      &c.RateLimit,
+		"GITGRAPHED_RATE_BURST":      &c.RateBurst,
+		"GITGRAPHED_KEY_RATE_LIMIT":  &c.KeyRateLimit,
+		"GITGRAPHED_KEY_RATE_BURST":  &c.KeyRateBurst,
+		"GITGRAPHED_API_KEYS":        &c.APIKeys,
+		"GITGRAPHED_TRUST_PROXY":     &c.TrustProxy,
+		"GITGRAPHED_ALLOWED_ORIGINS": &c.AllowedOrigins,
+		"GITGRAPHED_ALLOWED_METHODS": &c.AllowedMethods,
+		"GITGRAPHED_SIGNING_KEYS":    &c.SigningKeys,
+		"GITGRAPHED_REQUIRE_API_KEY": &c.RequireAPIKey,
+		"GITGRAPHED_ADMIN_KEYS":      &c.AdminKeys,
+		"GITGRAPHED_WARM":            &c.Warm,
+		"GITGRAPHED_HEDGE_AFTER":     &c.HedgeAfter,
+	} {
+		value, ok := os.LookupEnv(name)
+		if !ok || value == "" {
+			continue
+		}
+		var err error
+		switch target := target.(type) {
+		case *string:
+			*target = value
+		case *float64:
+			*target, err = strconv.ParseFloat(value, 64)
+		case *int:
+			*target, err = strconv.Atoi(value)
+		case *time.Duration:
+			*target, err = time.ParseDuration(value)
+		case *bool:
+			*target, err = strconv.ParseBool(value)
+		case *[]string:
+			*target = splitList(value)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("$%s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// splitList splits a comma-separated list, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// GitHubAppConfig identifies a GitHub App and the installation to act as
+type GitHubAppConfig struct {
+	ID             string `yaml:"id"`             // $GITGRAPHED_APP_ID
+	PrivateKey     string `yaml:"privateKey"`     // path to the PEM file, $GITGRAPHED_APP_KEY
+	InstallationID string `yaml:"installationID"` // $GITGRAPHED_APP_INSTALLATION; the only one when empty
+}
+
+// DaemonConfig schedules fetches in daemon mode. Users listed in Jobs follow
+// their own schedule; the rest of Usernames follow Schedule.
+type DaemonConfig struct {
+	Schedule string      `yaml:"schedule"` // cron expression, e.g. "0 8 * * *"
+	Output   string      `yaml:"output"`   // file to render to, where {user} is replaced by the username
+	Jobs     []DaemonJob `yaml:"jobs"`
+	// StatsD is the host:port of a StatsD server or Datadog agent to send metrics to
+	StatsD    string `yaml:"statsd"`
+	DogStatsD bool   `yaml:"dogstatsd"` // tag metrics with the user
+}
+
+// RetentionConfig is how many store snapshots to keep, as the flags of store prune
+type RetentionConfig struct {
+	PerDay  int    `yaml:"perDay"`
+	PerWeek int    `yaml:"perWeek"`
+	MaxAge  string `yaml:"maxAge"` // e.g. 90d, 12w or 720h
+}
+
+// DaemonJob is one user's schedule; empty fields fall back to DaemonConfig
+type DaemonJob struct {
+	Username string `yaml:"username"`
+	Schedule string `yaml:"schedule"`
+	Output   string `yaml:"output"`
+}
+
+// SMTPConfig holds the mail server and recipients for emailed reports
+type SMTPConfig struct {
+	Host     string   `yaml:"host"`
+	Port     int      `yaml:"port"` // defaults to 587; 465 uses implicit TLS
+	Username string   `yaml:"username"`
+	Password string   `yaml:"password"` // $GITGRAPHED_SMTP_PASSWORD takes precedence
+	From     string   `yaml:"from"`
+	To       []string `yaml:"to"`
+}
+
+// GoalsConfig holds contribution goals per calendar year and month; zero means none
+type GoalsConfig struct {
+	Yearly  int `yaml:"yearly"`
+	Monthly int `yaml:"monthly"`
+}
+
+// ThemeConfig is a custom theme in config.yaml; unset colors come from the github theme
+type ThemeConfig struct {
+	Levels     []string `yaml:"levels"`
+	Background string   `yaml:"background"`
+	Text       string   `yaml:"text"`
+}
+
+// defaultConfigPath returns ~/.config/gitgraphed/config.yaml (or the platform equivalent)
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "gitgraphed", "config.yaml")
+}
+
+// loadConfig reads the config file at path. A missing default config is not an
+// error, but a missing file passed explicitly via --config is.
+func loadConfig(path string, explicit bool) (*Config, error) {
+	config := &Config{}
+	if path == "" {
+		return config, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && !explicit {
+		return config, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return config, nil
+}
+
+// configPathFromArgs finds a --config flag before flags are parsed, since the
+// config supplies the defaults of the other flags
+func configPathFromArgs(args []string) (string, bool) {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if name != "config" {
+			continue
+		}
+		if hasValue {
+			return value, true
+		}
+		if i+1 < len(args) {
+			return args[i+1], true
+		}
+	}
+	return defaultConfigPath(), false
+}
+
+// stringOr returns value unless it is empty
+func stringOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+// theme resolves a built-in or configured theme by name
+func (c *Config) theme(name string) (render.Theme, error) {
+	if custom, ok := c.Themes[name]; ok {
+		return render.NewTheme(name, custom.Levels, custom.Background, custom.Text, render.GitHubTheme)
+	}
+	if theme, ok := render.LookupTheme(name); ok {
+		return theme, nil
+	}
+	return render.Theme{}, fmt.Errorf("unknown theme %q (built-in: %s)", name, strings.Join(render.ThemeNames(), ", "))
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"log/slog"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// credentialsPath is where login saves tokens without an OS keychain, beside
+// the default config file
+func credentialsPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "gitgraphed", "credentials.yaml")
+}
+
+// loadCredentials reads the tokens saved by login, keyed by host, e.g.
+// github.com. A missing file has none.
+func loadCredentials() (map[string]string, error) {
+	credentials := map[string]string{}
+	path := credentialsPath()
+	if path == "" {
+		return credentials, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return credentials, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, &credentials); err != nil {
+		return nil, err
+	}
+	return credentials, nil
+}
+
+// savedToken is the token login saved for host in the OS keychain or the
+// credentials file, empty when there is none or neither can be read
+func savedToken(host string) string {
+	if token, err := keychainGet(host); err == nil && token != "" {
+		return token
+	} else if err != nil && !errors.Is(err, errNoKeychain) {
+		slog.Debug("reading the OS keychain", "err", err)
+	}
+	credentials, err := loadCredentials()
+	if err != nil {
+		return ""
+	}
+	return credentials[host]
+}
+
+// saveToken saves token for host in the OS keychain, or when there is none
+// or insecure is set, in the credentials file, readable only by the user.
+// It returns where the token went.
+func saveToken(host, token string, insecure bool) (string, error) {
+	credentials, err := loadCredentials()
+	if err != nil {
+		return "", err
+	}
+	if !insecure {
+		err := keychainSet(host, token)
+		if err == nil {
+			// Don't leave an older token of host in plain text
+			if _, ok := credentials[host]; ok {
+				delete(credentials, host)
+				if _, err := writeCredentials(credentials); err != nil {
+					return "", err
+				}
+			}
+			return keychainName(), nil
+		}
+		if !errors.Is(err, errNoKeychain) {
+			slog.Warn("saving to the OS keychain failed, saving to a file instead", "err", err)
+		}
+	}
+	credentials[host] = token
+	return writeCredentials(credentials)
+}
+
+// writeCredentials writes the credentials file, readable only by the user,
+// and returns its path
+func writeCredentials(credentials map[string]string) (string, error) {
+	data, err := yaml.Marshal(credentials)
+	if err != nil {
+		return "", err
+	}
+	path := credentialsPath()
+	if path == "" {
+		return "", errors.New("no user config directory to save the token in")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return "", err
+	}
+	// WriteFile keeps the mode of a file that already exists
+	return path, os.Chmod(path, 0o600)
+}
+
+// githubHost is the credentials key of a GitHub instance: github.com, or
+// the host of a GitHub Enterprise Server URL
+func githubHost(ghURL string) string {
+	if ghURL == "" {
+		return "github.com"
+	}
+	if u, err := url.Parse(ghURL); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return ghURL
+}
+
+// discoverToken is the token login saved for host, or failing that the gh
+// CLI's, so a machine already set up for gh needs nothing more
+func discoverToken(host string) string {
+	if token := savedToken(host); token != "" {
+		return token
+	}
+	return ghCLIToken(host)
+}
+
+// ghCLIToken is the token the gh CLI is logged in to host with, empty when
+// gh isn't installed or isn't logged in there
+func ghCLIToken(host string) string {
+	path, err := exec.LookPath("gh")
+	if err != nil {
+		return ""
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, path, "auth", "token", "--hostname", host).Output()
+	if err != nil {
+		slog.Debug("no token from the gh CLI", "host", host, "err", err)
+		return ""
+	}
+	slog.Debug("using the gh CLI's token", "host", host)
+	return strings.TrimSpace(string(out))
+}
@@ -0,0 +1,78 @@
+//go:build cgo
+
+// Command cshared exposes gitgraphed's fetching and parsing as a C library, so
+// other languages can call it without spawning the CLI. Build it with
+//
+//	go build -buildmode=c-shared -o libgitgraphed.so ./cshared
+//
+// which also writes libgitgraphed.h. From Python:
+//
+//	lib = ctypes.CDLL("./libgitgraphed.so")
+//	lib.GitgraphedFetchJSON.restype = ctypes.c_void_p
+//	p = lib.GitgraphedFetchJSON(b"octocat", 2024)
+//	graph = json.loads(ctypes.string_at(p))
+//	lib.GitgraphedFree(p)
+//
+// Results are JSON in the format of fetch, or {"error": "..."} on failure.
+// Strings returned are owned by the caller and freed with GitgraphedFree.
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"unsafe"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+func main() {}
+
+// GitgraphedFetchJSON fetches user's contributions in year (0 for the current
+// one) as JSON, authenticating with $GITHUB_TOKEN when it is set
+//
+//export GitgraphedFetchJSON
+func GitgraphedFetchJSON(user *C.char, year C.int) *C.char {
+	client := gitgraph.NewClient(nil)
+	client.Token = os.Getenv("GITHUB_TOKEN")
+	graph, err := client.Fetch(context.Background(), C.GoString(user), gitgraph.Options{Year: int(year)})
+	if err != nil {
+		return encode(map[string]string{"error": err.Error()})
+	}
+	return encode(graph)
+}
+
+// GitgraphedParseJSON parses a contributions page fetched by the caller into
+// the JSON of a graph without a username
+//
+//export GitgraphedParseJSON
+func GitgraphedParseJSON(page *C.char, length C.int) *C.char {
+	data := C.GoBytes(unsafe.Pointer(page), length)
+	graph, err := gitgraph.ParseContributions(bytes.NewReader(data))
+	if err != nil {
+		return encode(map[string]string{"error": err.Error()})
+	}
+	return encode(graph)
+}
+
+// GitgraphedFree releases a string returned by this library
+//
+//export GitgraphedFree
+func GitgraphedFree(s *C.char) {
+	C.free(unsafe.Pointer(s))
+}
+
+// encode returns v's JSON as a C string allocated with malloc
+func encode(v any) *C.char {
+	data, err := json.Marshal(v)
+	if err != nil {
+		data, _ = json.Marshal(map[string]string{"error": err.Error()})
+	}
+	return C.CString(string(data))
+}
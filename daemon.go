@@ -0,0 +1,252 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+	"github.com/JyotinderSingh/gitgraphed/render"
+	"github.com/JyotinderSingh/gitgraphed/schedule"
+	"github.com/JyotinderSingh/gitgraphed/store"
+)
+
+// daemonJob is one user's scheduled fetch, render and notify pipeline
+type daemonJob struct {
+	Username string
+	Schedule *schedule.Schedule
+	Output   string // file rendered after each run, in the format of its extension; empty skips rendering
+}
+
+// daemonOptions are shared by every job of a daemon
+type daemonOptions struct {
+	Notifiers []notifier
+	Store     store.Store     // when set, every run is saved
+	Retention store.Retention // pruned to after each save
+	State     *daemonState
+	CatchUp   bool // run at startup when a scheduled time passed since the last run
+	// StatsD, when set, receives each run's gauges and a counter of runs
+	StatsD *statsdClient
+}
+
+// jobState is what the daemon remembers about a job between restarts
+type jobState struct {
+	LastRun time.Time `json:"lastRun"`
+	Total   int       `json:"totalContributions"` // of the last run
+	// Milestones are the keys of the recent milestones already announced
+	Milestones []string `json:"milestones,omitempty"`
+}
+
+// daemonState persists jobState per username as a JSON file
+type daemonState struct {
+	Path string
+	mu   sync.Mutex
+	jobs map[string]jobState
+}
+
+// loadDaemonState reads the state file at path; a missing file is empty state
+func loadDaemonState(path string) (*daemonState, error) {
+	state := &daemonState{Path: path, jobs: map[string]jobState{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &state.jobs); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+func (s *daemonState) get(username string) jobState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.jobs[username]
+}
+
+// set records a job's state and rewrites the state file
+func (s *daemonState) set(username string, job jobState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs[username] = job
+	data, err := json.MarshalIndent(s.jobs, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0o755); err != nil {
+		return err
+	}
+	tmp := s.Path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.Path)
+}
+
+// runScheduler runs every job on its schedule until ctx is cancelled
+func runScheduler(ctx context.Context, client *gitgraph.Client, jobs []daemonJob, opts daemonOptions) {
+	// Scheduled runs must reach upstream, but keep the cache warm for other commands
+	client.Refresh = true
+
+	var wg sync.WaitGroup
+	for _, job := range jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			scheduleJob(ctx, client, job, opts)
+		}()
+	}
+	wg.Wait()
+}
+
+// scheduleJob sleeps until each of job's run times and runs it. Runs missed
+// while the daemon was down are caught up with a single run at startup.
+func scheduleJob(ctx context.Context, client *gitgraph.Client, job daemonJob, opts daemonOptions) {
+	now := time.Now()
+	last := opts.State.get(job.Username).LastRun
+	if prev := job.Schedule.Prev(now); opts.CatchUp && !last.IsZero() && prev.After(last) {
+		slog.Info("catching up missed run", "username", job.Username, "missed", prev)
+		runJob(ctx, client, job, opts)
+	}
+
+	for {
+		next := job.Schedule.Next(time.Now())
+		if next.IsZero() {
+			slog.Error("schedule never runs", "username", job.Username, "schedule", job.Schedule.String())
+			return
+		}
+		slog.Debug("next run", "username", job.Username, "at", next)
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		runJob(ctx, client, job, opts)
+	}
+}
+
+// runJob fetches the current year for job's user, then saves, renders and
+// notifies. Failures are logged so the schedule carries on.
+func runJob(ctx context.Context, client *gitgraph.Client, job daemonJob, opts daemonOptions) {
+	start := time.Now()
+	graph, err := client.Fetch(ctx, job.Username, gitgraph.Options{})
+	if err != nil {
+		if ctx.Err() == nil {
+			code, _ := classifyError(err)
+			slog.Error("scheduled fetch failed", "username", job.Username, "code", code, "err", err)
+			emitStatsD(opts.StatsD, job.Username, []statsdMetric{{Name: "runs.failed", Value: 1, Type: "c"}})
+		}
+		return
+	}
+	if opts.Store != nil {
+		if err := opts.Store.Save(ctx, graph, start); err != nil {
+			if ctx.Err() == nil {
+				slog.Error("saving to store", "username", job.Username, "err", err)
+			}
+		} else if pruned, err := opts.Store.Prune(ctx, job.Username, opts.Retention, start); err != nil {
+			if ctx.Err() == nil {
+				slog.Error("pruning store", "username", job.Username, "err", err)
+			}
+		} else if pruned.Snapshots+pruned.Samples > 0 {
+			slog.Debug("pruned store", "username", job.Username, "snapshots", pruned.Snapshots, "samples", pruned.Samples)
+		}
+	}
+	if job.Output != "" {
+		if err := renderToFile(job.Output, graph, client.CurrentTime()); err != nil {
+			slog.Error("rendering", "username", job.Username, "path", job.Output, "err", err)
+		}
+	}
+
+	previous := opts.State.get(job.Username)
+	streak := gitgraph.ComputeStreaks(graph.Days, start).Current.Length
+	today, _ := findDay(graph, start.Format("2006-01-02"))
+	emitStatsD(opts.StatsD, job.Username, []statsdMetric{
+		{Name: "runs", Value: 1, Type: "c"},
+		{Name: "contributions.today", Value: today.Count, Type: "g"},
+		{Name: "contributions.total", Value: graph.TotalContribs, Type: "g"},
+		{Name: "streak.current", Value: streak, Type: "g"},
+	})
+	var events []watchEvent
+	if day, ok := findDay(graph, start.AddDate(0, 0, -1).Format("2006-01-02")); ok {
+		events = append(events, watchEvent{Type: "daily-summary", Total: graph.TotalContribs, Streak: streak, Day: &day})
+	}
+	announced := previous.Milestones
+	if history, err := milestoneHistory(ctx, opts.Store, graph, start); err != nil {
+		slog.Error("reading stored history for milestones", "username", job.Username, "err", err)
+	} else {
+		// Milestones of yesterday and today are recent enough to announce,
+		// once each; on the first run they're only remembered
+		since := start.AddDate(0, 0, -1).Format("2006-01-02")
+		announced = nil
+		for _, m := range gitgraph.FindMilestones(history) {
+			if m.Date < since {
+				continue
+			}
+			if !contains(previous.Milestones, m.Key()) && !previous.LastRun.IsZero() {
+				events = append(events, milestoneEvent(m, graph.TotalContribs, streak))
+			}
+			announced = append(announced, m.Key())
+		}
+	}
+	for _, event := range events {
+		event.Time = start
+		event.Username = job.Username
+		for _, n := range opts.Notifiers {
+			if !n.Wants(event.Type) {
+				continue
+			}
+			if err := n.Notify(ctx, event, graph); err != nil && ctx.Err() == nil {
+				slog.Error("sending notification", "notifier", n.Name(), "username", job.Username, "err", err)
+			}
+		}
+	}
+
+	if err := opts.State.set(job.Username, jobState{LastRun: start, Total: graph.TotalContribs, Milestones: announced}); err != nil {
+		slog.Error("saving daemon state", "path", opts.State.Path, "err", err)
+	}
+	slog.Info("scheduled run", "username", job.Username, "total", graph.TotalContribs, "duration", time.Since(start))
+}
+
+// emitStatsD sends metrics when the daemon has a StatsD client, logging failures
+func emitStatsD(client *statsdClient, username string, metrics []statsdMetric) {
+	if client == nil {
+		return
+	}
+	if err := client.send(username, metrics); err != nil {
+		slog.Error("sending StatsD metrics", "username", username, "err", err)
+	}
+}
+
+// renderToFile writes graph to path, a file or object storage URL, in the
+// format named by its extension, e.g. .svg, .png or .json, with anomalies as
+// of now
+func renderToFile(path string, graph *gitgraph.ContributionGraph, now time.Time) error {
+	opts := outputOptions{
+		Format: strings.TrimPrefix(filepath.Ext(path), "."),
+		Header: true,
+		SVG:    render.DefaultSVGOptions,
+		PNG:    render.DefaultPNGOptions,
+		GIF:    render.DefaultGIFOptions,
+		Now:    now,
+	}
+	out, err := createOutput(path)
+	if err != nil {
+		return err
+	}
+	if err := writeGraph(out, graph, opts); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
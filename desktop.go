@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+// desktop shows watch events as native desktop notifications
+type desktop struct{}
+
+func (d *desktop) Name() string { return "desktop" }
+
+func (d *desktop) Wants(eventType string) bool {
+	return eventType == "streak-at-risk" || eventType == "milestone"
+}
+
+// Notify shows event's message, titled after the user
+func (d *desktop) Notify(ctx context.Context, event watchEvent, _ *gitgraph.ContributionGraph) error {
+	return desktopNotify(ctx, "gitgraphed: "+event.Username, eventMessage(event))
+}
+
+// desktopNotify shows a notification using osascript on macOS, a PowerShell
+// toast on Windows and notify-send elsewhere
+func desktopNotify(ctx context.Context, title, message string) error {
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf(`display notification %s with title %s`, appleScriptString(message), appleScriptString(title))
+		return runQuiet(ctx, "osascript", "-e", script)
+	case "windows":
+		// The text travels in the environment, sparing it PowerShell's quoting.
+		// Toasts need a registered app, so they're shown as PowerShell's.
+		script := `[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null
+$xml = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$text = $xml.GetElementsByTagName('text')
+$text[0].AppendChild($xml.CreateTextNode($env:GITGRAPHED_TITLE)) > $null
+$text[1].AppendChild($xml.CreateTextNode($env:GITGRAPHED_MESSAGE)) > $null
+$app = '{1AC14E77-02E7-4E5D-B744-2EB1AE5198B7}\WindowsPowerShell\v1.0\powershell.exe'
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier($app).Show([Windows.UI.Notifications.ToastNotification]::new($xml))`
+		cmd := exec.CommandContext(ctx, "powershell", "-NoProfile", "-NonInteractive", "-Command", script)
+		cmd.Env = append(os.Environ(), "GITGRAPHED_TITLE="+title, "GITGRAPHED_MESSAGE="+message)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("powershell: %w: %s", err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	}
+	if _, err := exec.LookPath("notify-send"); err != nil {
+		return errors.New("no notify-send found, install libnotify")
+	}
+	return runQuiet(ctx, "notify-send", "--app-name=gitgraphed", title, message)
+}
+
+// appleScriptString quotes s as an AppleScript string literal
+func appleScriptString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+	"github.com/JyotinderSingh/gitgraphed/locale"
+)
+
+// sparkRamp holds the ASCII characters used for sparklines, from lowest to highest
+const sparkRamp = "_.-=+*#"
+
+// WeeklyDigest summarises a user's activity over the last seven days
+type WeeklyDigest struct {
+	Username      string
+	From          time.Time
+	To            time.Time
+	ThisWeek      int
+	LastWeek      int
+	CurrentStreak int
+	Daily         []int
+	Locale        *locale.Locale // dates and weekday initials, defaults to English
+}
+
+// buildWeeklyDigest fetches the past year for username and summarises the week ending on now
+func buildWeeklyDigest(ctx context.Context, client *gitgraph.Client, username string, now time.Time) (*WeeklyDigest, error) {
+	to := truncateDay(now)
+	from := to.AddDate(-1, 0, 1)
+
+	graph, err := client.Fetch(ctx, username, gitgraph.Options{From: from, To: to})
+	if err != nil {
+		return nil, err
+	}
+
+	counts := countsByDate(graph.Days)
+	weekStart := to.AddDate(0, 0, -6)
+	thisWeek := windowCounts(counts, weekStart, to)
+	lastWeek := windowCounts(counts, weekStart.AddDate(0, 0, -7), weekStart.AddDate(0, 0, -1))
+
+	return &WeeklyDigest{
+		Username:      username,
+		From:          weekStart,
+		To:            to,
+		ThisWeek:      sum(thisWeek),
+		LastWeek:      sum(lastWeek),
+		CurrentStreak: gitgraph.ComputeStreaks(graph.Days, to).Current.Length,
+		Daily:         thisWeek,
+	}, nil
+}
+
+// String renders the digest as a plain-text block for an email body
+func (d *WeeklyDigest) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (%s - %s)\n", d.Username, d.Locale.FormatMonthDay(d.From), d.Locale.FormatDate(d.To))
+	fmt.Fprintf(&b, "  This week:  %d contributions (%s vs last week)\n", d.ThisWeek, formatDelta(d.ThisWeek, d.LastWeek))
+	fmt.Fprintf(&b, "  Last week:  %d contributions\n", d.LastWeek)
+	fmt.Fprintf(&b, "  Streak:     %d %s\n", d.CurrentStreak, pluralize(d.CurrentStreak, "day", "days"))
+	fmt.Fprintf(&b, "  Daily:      [%s] %s\n", sparkline(d.Daily), weekdayInitials(d.From, len(d.Daily), d.Locale))
+	return b.String()
+}
+
+// truncateDay drops the time of day, keeping the calendar date as UTC midnight
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// countsByDate indexes contribution counts by their YYYY-MM-DD date
+func countsByDate(days []gitgraph.ContributionDay) map[string]int {
+	counts := make(map[string]int, len(days))
+	for _, day := range days {
+		counts[day.Date] = day.Count
+	}
+	return counts
+}
+
+// windowCounts returns the daily counts from..to (inclusive), zero-filling missing days
+func windowCounts(counts map[string]int, from, to time.Time) []int {
+	window := []int{}
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		window = append(window, counts[d.Format("2006-01-02")])
+	}
+	return window
+}
+
+func sum(values []int) int {
+	total := 0
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
+
+// formatDelta describes the change from previous to current, e.g. "+12, +40%"
+func formatDelta(current, previous int) string {
+	diff := current - previous
+	if previous == 0 {
+		return fmt.Sprintf("%+d", diff)
+	}
+	return fmt.Sprintf("%+d, %+.0f%%", diff, float64(diff)/float64(previous)*100)
+}
+
+// sparkline maps each value onto sparkRamp, scaled to the largest value
+func sparkline(values []int) string {
+	ramp := []rune(sparkRamp)
+	max := 0
+	for _, v := range values {
+		if v > max {
+			max = v
+		}
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		idx := 0
+		if max > 0 {
+			idx = v * (len(ramp) - 1) / max
+		}
+		if v > 0 && idx == 0 {
+			idx = 1
+		}
+		b.WriteRune(ramp[idx])
+	}
+	return b.String()
+}
+
+// weekdayInitials labels n consecutive days starting at from, e.g. "MTWTFSS"
+func weekdayInitials(from time.Time, n int, loc *locale.Locale) string {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		name := []rune(loc.Weekday(from.AddDate(0, 0, i).Weekday()))
+		b.WriteString(strings.ToUpper(string(name[0])))
+	}
+	return b.String()
+}
+
+func pluralize(n int, singular, plural string) string {
+	if n == 1 {
+		return singular
+	}
+	return plural
+}
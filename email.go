@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultSMTPPort is the mail submission port, upgraded with STARTTLS
+const defaultSMTPPort = 587
+
+// buildReportEmail renders report as a multipart/related message whose HTML
+// refers to each heatmap by Content-ID
+func buildReportEmail(report *weeklyReport, from string, to []string, now time.Time) ([]byte, error) {
+	for i := range report.Users {
+		report.Users[i].ImageSrc = "cid:" + template.URL(heatmapCID(i))
+	}
+
+	var body bytes.Buffer
+	related := multipart.NewWriter(&body)
+
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", "text/html; charset=utf-8")
+	header.Set("Content-Transfer-Encoding", "quoted-printable")
+	part, err := related.CreatePart(header)
+	if err != nil {
+		return nil, err
+	}
+	qp := quotedprintable.NewWriter(part)
+	if err := report.HTML(qp); err != nil {
+		return nil, err
+	}
+	if err := qp.Close(); err != nil {
+		return nil, err
+	}
+
+	for i, user := range report.Users {
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Type", "image/png")
+		header.Set("Content-Transfer-Encoding", "base64")
+		header.Set("Content-ID", "<"+heatmapCID(i)+">")
+		header.Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", user.Username+".png"))
+		part, err := related.CreatePart(header)
+		if err != nil {
+			return nil, err
+		}
+		if err := writeBase64Lines(part, user.Heatmap); err != nil {
+			return nil, err
+		}
+	}
+	if err := related.Close(); err != nil {
+		return nil, err
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", report.Subject()))
+	fmt.Fprintf(&msg, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	fmt.Fprintf(&msg, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: multipart/related; type=\"text/html\"; boundary=%s\r\n\r\n", related.Boundary())
+	msg.Write(body.Bytes())
+	return msg.Bytes(), nil
+}
+
+// writeBase64Lines base64-encodes data in 76 character lines, as MIME requires
+func writeBase64Lines(w io.Writer, data []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 0 {
+		n := min(76, len(encoded))
+		if _, err := io.WriteString(w, encoded[:n]+"\r\n"); err != nil {
+			return err
+		}
+		encoded = encoded[n:]
+	}
+	return nil
+}
+
+// sendMail delivers msg through the configured server, authenticating when a
+// username is set. Port 465 connects over TLS; other ports use STARTTLS when
+// the server offers it.
+func sendMail(config SMTPConfig, to []string, msg []byte) error {
+	if config.Host == "" || config.From == "" {
+		return errors.New("smtp host and from address must be configured")
+	}
+	port := config.Port
+	if port == 0 {
+		port = defaultSMTPPort
+	}
+	addr := net.JoinHostPort(config.Host, strconv.Itoa(port))
+	var auth smtp.Auth
+	if config.Username != "" {
+		auth = smtp.PlainAuth("", config.Username, config.Password, config.Host)
+	}
+	if port != 465 {
+		return smtp.SendMail(addr, auth, config.From, to, msg)
+	}
+
+	conn, err := tls.Dial("tcp", addr, &tls.Config{ServerName: config.Host})
+	if err != nil {
+		return err
+	}
+	c, err := smtp.NewClient(conn, config.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+	if auth != nil {
+		if err := c.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(config.From); err != nil {
+		return err
+	}
+	for _, rcpt := range to {
+		if err := c.Rcpt(rcpt); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"errors"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+// Exit statuses, so scripts can tell why a run failed. Flag errors exit with 2.
+const (
+	exitFailure     = 1
+	exitNotFound    = 3
+	exitRateLimited = 4
+	exitNetwork     = 5
+	exitParse       = 6
+	exitPrivate     = 7
+)
+
+// classifyError returns the machine-readable code and exit status of a fetch error
+func classifyError(err error) (string, int) {
+	var urlErr *url.Error
+	var netErr net.Error
+	switch {
+	case errors.Is(err, gitgraph.ErrUserNotFound):
+		return "user_not_found", exitNotFound
+	case errors.Is(err, gitgraph.ErrProfilePrivate):
+		return "profile_private", exitPrivate
+	case errors.Is(err, gitgraph.ErrRateLimited):
+		return "rate_limited", exitRateLimited
+	case errors.Is(err, gitgraph.ErrParse):
+		return "parse_failed", exitParse
+	case errors.As(err, &urlErr), errors.As(err, &netErr):
+		return "network_error", exitNetwork
+	}
+	return "failed", exitFailure
+}
+
+// fatalError logs msg with err's code and attributes, exiting with the status for err
+func fatalError(msg string, err error, args ...any) {
+	code, status := classifyError(err)
+	slog.Error(msg, append([]any{"error", code, "err", err}, args...)...)
+	os.Exit(status)
+}
@@ -0,0 +1,111 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+// chartJSData is the data property of a Chart.js chart configuration
+type chartJSData struct {
+	Labels   []string         `json:"labels"`
+	Datasets []chartJSDataset `json:"datasets"`
+}
+
+type chartJSDataset struct {
+	Label string `json:"label"`
+	Data  []int  `json:"data"`
+	// BackgroundColor is one color for a dataset of a single type, or each
+	// day's level color for the total
+	BackgroundColor any    `json:"backgroundColor"`
+	Stack           string `json:"stack,omitempty"`
+}
+
+// chartJSTypes are the datasets of days split by type, with their colors
+var chartJSTypes = []struct {
+	label string
+	color string
+	count func(gitgraph.ContributionTypes) int
+}{
+	{"Commits", "#216e39", func(t gitgraph.ContributionTypes) int { return t.Commits }},
+	{"Pull requests", "#8250df", func(t gitgraph.ContributionTypes) int { return t.PullRequests }},
+	{"Issues", "#bf8700", func(t gitgraph.ContributionTypes) int { return t.Issues }},
+	{"Reviews", "#0969da", func(t gitgraph.ContributionTypes) int { return t.Reviews }},
+}
+
+// ChartJS writes graph as the data of a Chart.js bar chart: the dates as
+// labels and the counts as a dataset colored by level, or as stacked datasets
+// per type when the days are split by type. levels are the hex colors of
+// levels 0-4.
+func ChartJS(w io.Writer, graph *gitgraph.ContributionGraph, levels []string, indent bool) error {
+	data := chartJSData{Labels: make([]string, len(graph.Days))}
+	byType := false
+	for i, day := range graph.Days {
+		data.Labels[i] = day.Date
+		byType = byType || day.Types != nil
+	}
+
+	if byType {
+		for _, kind := range chartJSTypes {
+			dataset := chartJSDataset{Label: kind.label, Data: make([]int, len(graph.Days)), BackgroundColor: kind.color, Stack: "contributions"}
+			for i, day := range graph.Days {
+				if day.Types != nil {
+					dataset.Data[i] = kind.count(*day.Types)
+				}
+			}
+			data.Datasets = append(data.Datasets, dataset)
+		}
+	} else {
+		dataset := chartJSDataset{Label: graph.Username + "'s contributions", Data: make([]int, len(graph.Days))}
+		colors := make([]string, len(graph.Days))
+		for i, day := range graph.Days {
+			dataset.Data[i] = day.Count
+			colors[i] = levels[min(max(day.Level, 0), len(levels)-1)]
+		}
+		dataset.BackgroundColor = colors
+		data.Datasets = []chartJSDataset{dataset}
+	}
+	return encode(w, data, indent)
+}
+
+// d3Week is one entry of d3.nest's key/values output, grouping a week's days
+type d3Week struct {
+	Key    string  `json:"key"` // the week's Sunday
+	Values []d3Day `json:"values"`
+}
+
+type d3Day struct {
+	Date    string `json:"date"`
+	Count   int    `json:"count"`
+	Level   int    `json:"level"`
+	Weekday int    `json:"weekday"` // 0 for Sunday
+}
+
+// D3 writes graph's days nested in Sunday-started weeks, the key/values
+// entries of d3.nest, ready for a D3 calendar with one column per week
+func D3(w io.Writer, graph *gitgraph.ContributionGraph, indent bool) error {
+	weeks := []d3Week{}
+	for _, day := range graph.Days {
+		date, err := time.Parse("2006-01-02", day.Date)
+		if err != nil {
+			continue
+		}
+		key := date.AddDate(0, 0, -int(date.Weekday())).Format("2006-01-02")
+		if len(weeks) == 0 || weeks[len(weeks)-1].Key != key {
+			weeks = append(weeks, d3Week{Key: key})
+		}
+		week := &weeks[len(weeks)-1]
+		week.Values = append(week.Values, d3Day{Date: day.Date, Count: day.Count, Level: day.Level, Weekday: int(date.Weekday())})
+	}
+	return encode(w, weeks, indent)
+}
+
+func encode(w io.Writer, v any, indent bool) error {
+	encoder := json.NewEncoder(w)
+	if indent {
+		encoder.SetIndent("", "  ")
+	}
+	return encoder.Encode(v)
+}
@@ -0,0 +1,96 @@
+// Package export encodes contribution graphs into data interchange formats.
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"slices"
+	"strconv"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+// csvHeader names the columns written by CSV. They stay as they are for
+// readers going by position; columns added since, like gridWeek, are only
+// written when selected with CSVFields.
+var csvHeader = []string{"date", "count", "level", "dayOfWeek", "weekOfYear", "contribLevel"}
+
+// csvFields name every column CSVFields can write
+var csvFields = append(slices.Clip(csvHeader), "gridWeek")
+
+// csvColumns format each column of csvHeader
+var csvColumns = map[string]func(day gitgraph.ContributionDay) string{
+	"date":         func(day gitgraph.ContributionDay) string { return day.Date },
+	"count":        func(day gitgraph.ContributionDay) string { return strconv.Itoa(day.Count) },
+	"level":        func(day gitgraph.ContributionDay) string { return strconv.Itoa(day.Level) },
+	"dayOfWeek":    func(day gitgraph.ContributionDay) string { return strconv.Itoa(day.DayOfWeek) },
+	"weekOfYear":   func(day gitgraph.ContributionDay) string { return strconv.Itoa(day.WeekOfYear) },
+	"contribLevel": func(day gitgraph.ContributionDay) string { return day.ContribLevel },
+	"gridWeek":     func(day gitgraph.ContributionDay) string { return strconv.Itoa(day.GridWeek) },
+}
+
+// CSV writes one row per day, preceded by a header row when header is set
+func CSV(w io.Writer, graph *gitgraph.ContributionGraph, header bool) error {
+	return CSVFields(w, graph, header, nil)
+}
+
+// CSVFields writes CSV of only the columns named by fields, in their order.
+// The columns of CSV are written when fields is empty.
+func CSVFields(w io.Writer, graph *gitgraph.ContributionGraph, header bool, fields []string) error {
+	if len(fields) == 0 {
+		fields = csvHeader
+	}
+	if err := checkFields(fields, csvFields); err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	if header {
+		if err := cw.Write(fields); err != nil {
+			return err
+		}
+	}
+
+	record := make([]string, len(fields))
+	for _, day := range graph.Days {
+		for i, field := range fields {
+			record[i] = csvColumns[field](day)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// rollupCSVHeader names the columns written by RollupCSV
+var rollupCSVHeader = []string{"start", "end", "sum", "mean", "max", "activeDays", "days"}
+
+// RollupCSV writes one row per bucket, preceded by a header row when header is set
+func RollupCSV(w io.Writer, rollup *gitgraph.Rollup, header bool) error {
+	cw := csv.NewWriter(w)
+	if header {
+		if err := cw.Write(rollupCSVHeader); err != nil {
+			return err
+		}
+	}
+
+	for _, bucket := range rollup.Buckets {
+		record := []string{
+			bucket.Start,
+			bucket.End,
+			strconv.Itoa(bucket.Sum),
+			strconv.FormatFloat(bucket.Mean, 'f', 2, 64),
+			strconv.Itoa(bucket.Max),
+			strconv.Itoa(bucket.ActiveDays),
+			strconv.Itoa(bucket.Days),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
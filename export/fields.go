@@ -0,0 +1,107 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+// DayFields are the JSON names of a day's fields, as selected by SelectFields
+var DayFields = jsonNames(reflect.TypeOf(gitgraph.ContributionDay{}))
+
+// jsonlFields are the fields of a JSONL record
+var jsonlFields = append([]string{"username", "year"}, DayFields...)
+
+// Fields returns the fields that can be selected in format: json, jsonl or
+// csv. It returns nil for other formats.
+func Fields(format string) []string {
+	switch format {
+	case "json":
+		return DayFields
+	case "jsonl":
+		return jsonlFields
+	case "csv":
+		return csvFields
+	}
+	return nil
+}
+
+// selectedGraph is a graph whose days carry only some of their fields
+type selectedGraph struct {
+	*gitgraph.ContributionGraph
+	Days []json.RawMessage `json:"days"`
+}
+
+// SelectFields returns graph for JSON encoding with each day reduced to
+// fields, written in the order given
+func SelectFields(graph *gitgraph.ContributionGraph, fields []string) (any, error) {
+	if err := checkFields(fields, DayFields); err != nil {
+		return nil, err
+	}
+	days := make([]json.RawMessage, len(graph.Days))
+	for i, day := range graph.Days {
+		var err error
+		if days[i], err = pick(day, fields); err != nil {
+			return nil, err
+		}
+	}
+	return selectedGraph{ContributionGraph: graph, Days: days}, nil
+}
+
+// checkFields reports the first of fields that isn't one of known
+func checkFields(fields, known []string) error {
+	for _, field := range fields {
+		if !slices.Contains(known, field) {
+			return fmt.Errorf("unknown field %q (expected some of %s)", field, strings.Join(known, ", "))
+		}
+	}
+	return nil
+}
+
+// pick encodes v as a JSON object of only fields, in their order. Fields v
+// leaves out when empty are left out too.
+func pick(v any, fields []string) (json.RawMessage, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for _, field := range fields {
+		value, ok := values[field]
+		if !ok {
+			continue
+		}
+		if b.Len() > 1 {
+			b.WriteByte(',')
+		}
+		name, _ := json.Marshal(field)
+		b.Write(name)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// jsonNames lists the JSON names of a struct's fields in order
+func jsonNames(t reflect.Type) []string {
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,112 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+// graphQLResponse mirrors GitHub's GraphQL response to a query for a user's
+// contributionsCollection { contributionCalendar { ... } }
+type graphQLResponse struct {
+	Data struct {
+		User struct {
+			ContributionsCollection struct {
+				ContributionCalendar graphQLCalendar `json:"contributionCalendar"`
+			} `json:"contributionsCollection"`
+		} `json:"user"`
+	} `json:"data"`
+}
+
+type graphQLCalendar struct {
+	Colors             []string       `json:"colors"`
+	IsHalloween        bool           `json:"isHalloween"`
+	Months             []graphQLMonth `json:"months"`
+	TotalContributions int            `json:"totalContributions"`
+	Weeks              []graphQLWeek  `json:"weeks"`
+}
+
+type graphQLMonth struct {
+	FirstDay   string `json:"firstDay"`
+	Name       string `json:"name"`
+	TotalWeeks int    `json:"totalWeeks"` // weeks starting in the month
+	Year       int    `json:"year"`
+}
+
+type graphQLWeek struct {
+	ContributionDays []graphQLDay `json:"contributionDays"`
+	FirstDay         string       `json:"firstDay"`
+}
+
+type graphQLDay struct {
+	Color             string `json:"color"`
+	ContributionCount int    `json:"contributionCount"`
+	ContributionLevel string `json:"contributionLevel"` // NONE, FIRST_QUARTILE, ...
+	Date              string `json:"date"`
+	Weekday           int    `json:"weekday"` // 0 for Sunday
+}
+
+// GraphQL writes graph shaped like GitHub's GraphQL response for the user's
+// contributionCalendar, its days in Sunday-started weeks, so clients of that
+// API can read it unchanged. levels are the hex colors of levels 0-4.
+func GraphQL(w io.Writer, graph *gitgraph.ContributionGraph, levels []string, indent bool) error {
+	calendar := graphQLCalendar{
+		Colors:             levels[1:],
+		Months:             []graphQLMonth{},
+		TotalContributions: graph.TotalContribs,
+		Weeks:              []graphQLWeek{},
+	}
+	for _, day := range graph.Days {
+		date, err := time.Parse("2006-01-02", day.Date)
+		if err != nil {
+			continue
+		}
+		weekday := int(date.Weekday())
+		if len(calendar.Weeks) == 0 || weekday == 0 || weekday <= calendar.lastWeekday() {
+			calendar.Weeks = append(calendar.Weeks, graphQLWeek{ContributionDays: []graphQLDay{}, FirstDay: day.Date})
+			calendar.addWeek(date)
+		}
+		week := &calendar.Weeks[len(calendar.Weeks)-1]
+		week.ContributionDays = append(week.ContributionDays, graphQLDay{
+			Color:             levels[min(max(day.Level, 0), len(levels)-1)],
+			ContributionCount: day.Count,
+			ContributionLevel: strings.ToUpper(day.ContribLevel),
+			Date:              day.Date,
+			Weekday:           weekday,
+		})
+	}
+
+	var response graphQLResponse
+	response.Data.User.ContributionsCollection.ContributionCalendar = calendar
+	encoder := json.NewEncoder(w)
+	if indent {
+		encoder.SetIndent("", "  ")
+	}
+	return encoder.Encode(response)
+}
+
+// lastWeekday is the weekday of the last day in the calendar's last week
+func (c *graphQLCalendar) lastWeekday() int {
+	days := c.Weeks[len(c.Weeks)-1].ContributionDays
+	if len(days) == 0 {
+		return -1
+	}
+	return days[len(days)-1].Weekday
+}
+
+// addWeek counts a week starting on date towards its month
+func (c *graphQLCalendar) addWeek(date time.Time) {
+	if n := len(c.Months); n > 0 && c.Months[n-1].Year == date.Year() && c.Months[n-1].Name == date.Month().String()[:3] {
+		c.Months[n-1].TotalWeeks++
+		return
+	}
+	c.Months = append(c.Months, graphQLMonth{
+		FirstDay:   date.Format("2006-01-02"),
+		Name:       date.Month().String()[:3],
+		TotalWeeks: 1,
+		Year:       date.Year(),
+	})
+}
@@ -0,0 +1,54 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+// ICS writes an iCalendar file with an all-day event for each day that has
+// contributions, so activity can be overlaid on a personal calendar
+func ICS(w io.Writer, graph *gitgraph.ContributionGraph) error {
+	bw := bufio.NewWriter(w)
+	stamp := time.Now().UTC().Format("20060102T150405Z")
+
+	// iCalendar lines are CRLF-terminated (RFC 5545 §3.1)
+	line := func(format string, args ...any) {
+		fmt.Fprintf(bw, format+"\r\n", args...)
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//gitgraphed//contributions//EN")
+	line("CALSCALE:GREGORIAN")
+	line("X-WR-CALNAME:%s contributions", graph.Username)
+	for _, day := range graph.Days {
+		if day.Count == 0 {
+			continue
+		}
+		date, err := time.Parse("2006-01-02", day.Date)
+		if err != nil {
+			return fmt.Errorf("invalid date %q: %w", day.Date, err)
+		}
+		line("BEGIN:VEVENT")
+		line("UID:%s-%s@gitgraphed", graph.Username, date.Format("20060102"))
+		line("DTSTAMP:%s", stamp)
+		line("DTSTART;VALUE=DATE:%s", date.Format("20060102"))
+		line("DTEND;VALUE=DATE:%s", date.AddDate(0, 0, 1).Format("20060102"))
+		line("SUMMARY:%s", contributionSummary(day.Count))
+		line("TRANSP:TRANSPARENT")
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+	return bw.Flush()
+}
+
+func contributionSummary(count int) string {
+	if count == 1 {
+		return "1 contribution"
+	}
+	return fmt.Sprintf("%d contributions", count)
+}
@@ -0,0 +1,49 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+// InfluxMeasurement names the points written by Influx
+const InfluxMeasurement = "contributions"
+
+// influxTagEscaper escapes the characters line protocol gives meaning to in
+// tag values
+var influxTagEscaper = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `)
+
+// Influx writes one InfluxDB line protocol point per day, tagged with the
+// user and timestamped in seconds at the midnight starting the day in the
+// graph's time zone, e.g.
+//
+//	contributions,user=alice count=5,level=2 1704067200
+//
+// Days split by type also carry commits, pullRequests, issues and reviews.
+func Influx(w io.Writer, graph *gitgraph.ContributionGraph) error {
+	loc := time.UTC
+	if graph.TimeZone != "" {
+		if zone, err := time.LoadLocation(graph.TimeZone); err == nil {
+			loc = zone
+		}
+	}
+	tags := InfluxMeasurement + ",user=" + influxTagEscaper.Replace(graph.Username)
+
+	bw := bufio.NewWriter(w)
+	for _, day := range graph.Days {
+		date, err := time.ParseInLocation("2006-01-02", day.Date, loc)
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(bw, "%s count=%d,level=%d", tags, day.Count, day.Level)
+		if t := day.Types; t != nil {
+			fmt.Fprintf(bw, ",commits=%d,pullRequests=%d,issues=%d,reviews=%d", t.Commits, t.PullRequests, t.Issues, t.Reviews)
+		}
+		fmt.Fprintf(bw, " %d\n", date.Unix())
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,47 @@
+package export
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"strconv"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+// jsonlDay is one JSON Lines record: a day tagged with its user and year
+type jsonlDay struct {
+	Username string `json:"username"`
+	Year     int    `json:"year"`
+	gitgraph.ContributionDay
+}
+
+// JSONL writes one compact JSON object per day, each carrying the username and
+// year so lines from several graphs can be mixed in one stream
+func JSONL(w io.Writer, graph *gitgraph.ContributionGraph) error {
+	return JSONLFields(w, graph, nil)
+}
+
+// JSONLFields writes JSONL records of only fields, in their order: username,
+// year or any of DayFields. Every field is written when fields is empty.
+func JSONLFields(w io.Writer, graph *gitgraph.ContributionGraph, fields []string) error {
+	if err := checkFields(fields, jsonlFields); err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	encoder := json.NewEncoder(bw)
+	for _, day := range graph.Days {
+		year, _ := strconv.Atoi(day.Date[:min(4, len(day.Date))])
+		var record any = jsonlDay{Username: graph.Username, Year: year, ContributionDay: day}
+		if len(fields) > 0 {
+			var err error
+			if record, err = pick(record, fields); err != nil {
+				return err
+			}
+		}
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
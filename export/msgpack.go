@@ -0,0 +1,169 @@
+package export
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+// MsgPack writes graph as one MessagePack map laid out for small devices that
+// draw the calendar without parsing dates per day:
+//
+//	username            string
+//	totalContributions  int
+//	start               string, YYYY-MM-DD of the first day
+//	counts              array of int, one per consecutive day from start
+//	levels              bin, one byte (0-4) per day from start
+//	currentStreak       int
+//	longestStreak       int
+//
+// Days missing from the graph are zero in counts and levels.
+func MsgPack(w io.Writer, graph *gitgraph.ContributionGraph, today time.Time) error {
+	var start time.Time
+	var counts []int
+	var levels []byte
+	if len(graph.Days) > 0 {
+		var err error
+		if start, err = time.Parse("2006-01-02", graph.Days[0].Date); err != nil {
+			return err
+		}
+		for _, day := range graph.Days {
+			date, err := time.Parse("2006-01-02", day.Date)
+			if err != nil {
+				return err
+			}
+			i := int(date.Sub(start).Hours() / 24)
+			if i < 0 {
+				continue
+			}
+			for len(counts) <= i {
+				counts = append(counts, 0)
+				levels = append(levels, 0)
+			}
+			counts[i] = day.Count
+			levels[i] = byte(day.Level)
+		}
+	}
+	streaks := gitgraph.ComputeStreaks(graph.Days, today)
+
+	e := &msgpackEncoder{w: bufio.NewWriter(w)}
+	e.mapHeader(7)
+	e.str("username")
+	e.str(graph.Username)
+	e.str("totalContributions")
+	e.int(graph.TotalContribs)
+	e.str("start")
+	if start.IsZero() {
+		e.str("")
+	} else {
+		e.str(start.Format("2006-01-02"))
+	}
+	e.str("counts")
+	e.arrayHeader(len(counts))
+	for _, count := range counts {
+		e.int(count)
+	}
+	e.str("levels")
+	e.bin(levels)
+	e.str("currentStreak")
+	e.int(streaks.Current.Length)
+	e.str("longestStreak")
+	e.int(streaks.Longest.Length)
+	return e.flush()
+}
+
+// msgpackEncoder writes the few MessagePack types MsgPack needs, keeping the
+// first write error
+type msgpackEncoder struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (e *msgpackEncoder) write(b ...byte) {
+	if e.err == nil {
+		_, e.err = e.w.Write(b)
+	}
+}
+
+// header writes a type marker followed by n as a big-endian length of size bytes
+func (e *msgpackEncoder) header(marker byte, n uint64, size int) {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, n)
+	e.write(append([]byte{marker}, buf[8-size:]...)...)
+}
+
+func (e *msgpackEncoder) mapHeader(n int) {
+	switch {
+	case n < 16:
+		e.write(0x80 | byte(n))
+	case n <= 0xffff:
+		e.header(0xde, uint64(n), 2)
+	default:
+		e.header(0xdf, uint64(n), 4)
+	}
+}
+
+func (e *msgpackEncoder) arrayHeader(n int) {
+	switch {
+	case n < 16:
+		e.write(0x90 | byte(n))
+	case n <= 0xffff:
+		e.header(0xdc, uint64(n), 2)
+	default:
+		e.header(0xdd, uint64(n), 4)
+	}
+}
+
+func (e *msgpackEncoder) str(s string) {
+	switch n := len(s); {
+	case n < 32:
+		e.write(0xa0 | byte(n))
+	case n <= 0xff:
+		e.header(0xd9, uint64(n), 1)
+	case n <= 0xffff:
+		e.header(0xda, uint64(n), 2)
+	default:
+		e.header(0xdb, uint64(n), 4)
+	}
+	e.write([]byte(s)...)
+}
+
+func (e *msgpackEncoder) bin(b []byte) {
+	switch n := len(b); {
+	case n <= 0xff:
+		e.header(0xc4, uint64(n), 1)
+	case n <= 0xffff:
+		e.header(0xc5, uint64(n), 2)
+	default:
+		e.header(0xc6, uint64(n), 4)
+	}
+	e.write(b...)
+}
+
+// int uses the smallest encoding for v
+func (e *msgpackEncoder) int(v int) {
+	switch {
+	case v < 0:
+		e.header(0xd3, uint64(v), 8)
+	case v < 128:
+		e.write(byte(v))
+	case v <= 0xff:
+		e.header(0xcc, uint64(v), 1)
+	case v <= 0xffff:
+		e.header(0xcd, uint64(v), 2)
+	case v <= 0xffffffff:
+		e.header(0xce, uint64(v), 4)
+	default:
+		e.header(0xcf, uint64(v), 8)
+	}
+}
+
+func (e *msgpackEncoder) flush() error {
+	if e.err != nil {
+		return e.err
+	}
+	return e.w.Flush()
+}
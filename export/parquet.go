@@ -0,0 +1,82 @@
+package export
+
+import (
+	"io"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+// parquetDay is one row of the days table written by Parquet. Small values
+// are held in int32, Parquet's narrowest physical integer, and annotated as
+// INT(8) by parquetSchema.
+type parquetDay struct {
+	Username   string `parquet:"username"`
+	Date       int32  `parquet:"date"` // days since the Unix epoch
+	Count      int32  `parquet:"count"`
+	Level      int32  `parquet:"level"`
+	DayOfWeek  int32  `parquet:"day_of_week"`
+	WeekOfYear int32  `parquet:"week_of_year"`
+	GridWeek   int32  `parquet:"grid_week"`
+}
+
+// parquetSchema types the columns of parquetDay for readers like DuckDB and Spark
+var parquetSchema = parquet.NewSchema("day", parquet.Group{
+	"username":     parquet.Encoded(parquet.String(), &parquet.RLEDictionary),
+	"date":         parquet.Date(),
+	"count":        parquet.Int(32),
+	"level":        parquet.Int(8),
+	"day_of_week":  parquet.Int(8),
+	"week_of_year": parquet.Int(8),
+	"grid_week":    parquet.Int(8),
+})
+
+// ParquetWriter streams the days of many graphs into one Parquet file
+type ParquetWriter struct {
+	w    *parquet.GenericWriter[parquetDay]
+	rows []parquetDay
+}
+
+// NewParquetWriter starts a Snappy-compressed Parquet file on w
+func NewParquetWriter(w io.Writer) *ParquetWriter {
+	return &ParquetWriter{w: parquet.NewGenericWriter[parquetDay](w, parquetSchema, parquet.Compression(&parquet.Snappy))}
+}
+
+// Write appends graph's days as rows
+func (p *ParquetWriter) Write(graph *gitgraph.ContributionGraph) error {
+	p.rows = p.rows[:0]
+	for _, day := range graph.Days {
+		date, err := time.Parse("2006-01-02", day.Date)
+		if err != nil {
+			return err
+		}
+		p.rows = append(p.rows, parquetDay{
+			Username:   graph.Username,
+			Date:       int32(date.Unix() / 86400),
+			Count:      int32(day.Count),
+			Level:      int32(day.Level),
+			DayOfWeek:  int32(day.DayOfWeek),
+			WeekOfYear: int32(day.WeekOfYear),
+			GridWeek:   int32(day.GridWeek),
+		})
+	}
+	_, err := p.w.Write(p.rows)
+	return err
+}
+
+// Close writes the file footer; the underlying writer is left open
+func (p *ParquetWriter) Close() error {
+	return p.w.Close()
+}
+
+// Parquet writes graph's days as a Parquet file with typed columns, ready for
+// DuckDB or Spark
+func Parquet(w io.Writer, graph *gitgraph.ContributionGraph) error {
+	p := NewParquetWriter(w)
+	if err := p.Write(graph); err != nil {
+		return err
+	}
+	return p.Close()
+}
@@ -0,0 +1,71 @@
+package export
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+// XMLNamespace identifies version 1 of the schema in contributions.xsd
+const XMLNamespace = "https://github.com/JyotinderSingh/gitgraphed/xml/v1"
+
+type xmlGraph struct {
+	XMLName       xml.Name  `xml:"contributions"`
+	Namespace     string    `xml:"xmlns,attr"`
+	Username      string    `xml:"username,attr"`
+	Total         int       `xml:"total,attr"`
+	Private       int       `xml:"private,attr,omitempty"`
+	Generated     string    `xml:"generated,attr"`
+	Years         []int     `xml:"years>year"`
+	CurrentStreak xmlStreak `xml:"streaks>current"`
+	LongestStreak xmlStreak `xml:"streaks>longest"`
+	Days          []xmlDay  `xml:"days>day"`
+}
+
+type xmlStreak struct {
+	Length int    `xml:"length,attr"`
+	Start  string `xml:"start,attr,omitempty"`
+	End    string `xml:"end,attr,omitempty"`
+}
+
+type xmlDay struct {
+	Date         string `xml:"date,attr"`
+	Count        int    `xml:"count,attr"`
+	Level        int    `xml:"level,attr"`
+	DayOfWeek    int    `xml:"dayOfWeek,attr"`
+	WeekOfYear   int    `xml:"weekOfYear,attr"`
+	ContribLevel string `xml:"contribLevel,attr"`
+	GridWeek     int    `xml:"gridWeek,attr"`
+}
+
+// XML writes graph as a document valid against contributions.xsd in this
+// package, with streaks as of now
+func XML(w io.Writer, graph *gitgraph.ContributionGraph, now time.Time) error {
+	streaks := gitgraph.ComputeStreaks(graph.Days, now)
+	doc := xmlGraph{
+		Namespace:     XMLNamespace,
+		Username:      graph.Username,
+		Total:         graph.TotalContribs,
+		Private:       graph.PrivateContribs,
+		Generated:     now.UTC().Format(time.RFC3339),
+		Years:         graph.Years,
+		CurrentStreak: xmlStreak(streaks.Current),
+		LongestStreak: xmlStreak(streaks.Longest),
+	}
+	for _, day := range graph.Days {
+		doc.Days = append(doc.Days, xmlDay{day.Date, day.Count, day.Level, day.DayOfWeek, day.WeekOfYear, day.ContribLevel, day.GridWeek})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,872 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+
+	"github.com/JyotinderSingh/gitgraphed/export"
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+	"github.com/JyotinderSingh/gitgraphed/locale"
+	"github.com/JyotinderSingh/gitgraphed/store"
+)
+
+// colorModes are the values of --color
+var colorModes = []string{"auto", "always", "never"}
+
+func addColorFlag(fs *flag.FlagSet) *string {
+	return fs.String("color", "auto", "color term output: auto, only on a terminal and without $NO_COLOR, always or never, for ASCII shades")
+}
+
+// useColor resolves the --color mode of output written to path, stdout when
+// empty, exiting on an unsupported one
+func useColor(mode, path string) bool {
+	switch mode {
+	case "always":
+		return true
+	case "never":
+		return false
+	case "auto":
+	default:
+		fatal("unsupported --color", "color", mode, "supported", strings.Join(colorModes, ", "))
+	}
+	// https://no-color.org: set to anything but the empty string
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return path == "" && term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// newFlagSet creates the flag set of the named command with its usage message
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.Usage = commandUsage(name, fs.PrintDefaults)
+	return fs
+}
+
+// logFlags are the verbosity flags shared by every command
+type logFlags struct {
+	verbose *bool
+	quiet   *bool
+	format  *string
+}
+
+func addLogFlags(fs *flag.FlagSet) *logFlags {
+	return &logFlags{
+		verbose: fs.Bool("verbose", false, "log progress details to stderr"),
+		quiet:   fs.Bool("quiet", false, "only log errors"),
+		format:  fs.String("log-format", "text", "log format on stderr: text, or json for one object per line"),
+	}
+}
+
+// apply sets the log level and format from the parsed flags
+func (f *logFlags) apply() {
+	switch *f.format {
+	case "text":
+	case "json":
+		setLogHandler(slog.NewJSONHandler(os.Stderr, logOptions))
+	default:
+		fatal("unsupported log format", "format", *f.format)
+	}
+	switch {
+	case *f.quiet:
+		logLevel.Set(slog.LevelError)
+	case *f.verbose:
+		logLevel.Set(slog.LevelDebug)
+	}
+}
+
+// clientFlags are the flags every fetching command uses to build its Client
+type clientFlags struct {
+	*logFlags
+	token    *string
+	provider *string
+	baseURL  *string
+	ghURL    *string
+	ghToken  *string
+	bbToken  *string
+	noAuth   *bool
+	// appID, appKey and appInstallation authenticate as a GitHub App
+	appID           *string
+	appKey          *string
+	appInstallation *string
+	cacheTTL        *time.Duration
+	noCache         *bool
+	refresh         *bool
+	workers         *int
+	retries         *int
+	proxy           *string
+	private         *bool
+	byType          *bool
+	onlyType        *string
+	fixture         *string
+	record          *string
+	noEvents        *bool
+	tz              *string
+	seed            *int64
+	// maxParallel and perMinute bound the outbound requests of the whole run
+	maxParallel *int
+	perMinute   *float64
+}
+
+func addClientFlags(fs *flag.FlagSet, config *Config) *clientFlags {
+	cacheTTL := time.Hour
+	if config.CacheTTL > 0 {
+		cacheTTL = config.CacheTTL
+	}
+
+	// --config is read before flags are parsed, but must still be accepted here
+	fs.String("config", defaultConfigPath(), "path to the config file")
+	return &clientFlags{
+		logFlags:        addLogFlags(fs),
+		token:           fs.String("token", stringOr(os.Getenv("GITGRAPHED_TOKEN"), stringOr(os.Getenv("GITHUB_TOKEN"), stringOr(os.Getenv("GH_TOKEN"), config.Token))), "GitHub token for the GraphQL API, only sent to GitHub (defaults to $GITGRAPHED_TOKEN, $GITHUB_TOKEN or $GH_TOKEN, then the one saved by login, then the gh CLI's)"),
+		provider:        fs.String("provider", stringOr(config.Provider, "github"), "contribution source: "+strings.Join(gitgraph.ProviderNames(), ", ")+" (fake makes up realistic contributions, for demos and offline development)"),
+		baseURL:         fs.String("base-url", config.BaseURL, "base URL of a self-hosted provider instance"),
+		ghURL:           fs.String("github-url", config.GitHubURL, "URL of a GitHub Enterprise Server instance, e.g. https://github.mycompany.com"),
+		ghToken:         fs.String("enterprise-token", stringOr(os.Getenv("GH_ENTERPRISE_TOKEN"), stringOr(os.Getenv("GITHUB_ENTERPRISE_TOKEN"), config.EnterpriseToken)), "token for --github-url (defaults to $GH_ENTERPRISE_TOKEN or $GITHUB_ENTERPRISE_TOKEN, then the one saved by login --github-url, then the gh CLI's)"),
+		bbToken:         fs.String("bitbucket-token", os.Getenv("GITGRAPHED_BITBUCKET_TOKEN"), "Bitbucket access token or user:app-password for --provider bitbucket ($GITGRAPHED_BITBUCKET_TOKEN)"),
+		noAuth:          fs.Bool("no-auth", false, "send no token, ignoring --token, the environment, saved logins and the gh CLI's"),
+		appID:           fs.String("app-id", stringOr(os.Getenv("GITGRAPHED_APP_ID"), config.App.ID), "authenticate as this GitHub App, for an organization's rate limits and private repositories, instead of with a token ($GITGRAPHED_APP_ID)"),
+		appKey:          fs.String("app-key", stringOr(os.Getenv("GITGRAPHED_APP_KEY"), config.App.PrivateKey), "path to the private key of --app-id, as downloaded from its settings ($GITGRAPHED_APP_KEY)"),
+		appInstallation: fs.String("app-installation", stringOr(os.Getenv("GITGRAPHED_APP_INSTALLATION"), config.App.InstallationID), "installation of --app-id to act as, when it has several ($GITGRAPHED_APP_INSTALLATION)"),
+		cacheTTL:        fs.Duration("cache-ttl", cacheTTL, "how long fetched years stay cached"),
+		noCache:         fs.Bool("no-cache", false, "disable the on-disk cache"),
+		refresh:         fs.Bool("refresh", false, "ignore cached data and fetch again"),
+		workers:         fs.Int("workers", gitgraph.DefaultWorkers, "maximum concurrent requests when fetching several years or users"),
+		retries:         fs.Int("retries", gitgraph.DefaultRetries, "how many times to retry failed requests"),
+		private:         fs.Bool("include-private", config.IncludePrivate, "include private contribution counts (requires a token)"),
+		byType:          fs.Bool("by-type", false, "split each day into commits, pull requests, issues and reviews (requires a token; makes extra requests)"),
+		onlyType:        fs.String("type", "", "count only one type of contribution: "+strings.Join(gitgraph.ContributionTypeNames, ", ")+" (implies --by-type)"),
+		proxy:           fs.String("proxy", config.Proxy, "proxy URL, e.g. socks5://host:port (defaults to $HTTPS_PROXY/$HTTP_PROXY)"),
+		fixture:         fs.String("fixture", "", "answer every request with this saved response (.html page or .json API reply) instead of the network"),
+		record:          fs.String("record", "", "save the raw response to this file, for replaying with --fixture"),
+		noEvents:        fs.Bool("no-events-fallback", false, "fail when GitHub's calendar can't be read, instead of approximating the past 90 days from public events"),
+		seed:            fs.Int64("seed", 0, "seed of --provider fake: the same seed and username always make the same contributions"),
+		tz:              fs.String("tz", config.TimeZone, "IANA time zone, e.g. Asia/Kolkata, that event, commit and heatmap timestamps are dated in; GitHub's calendar is always UTC (default UTC)"),
+		maxParallel:     fs.Int("max-parallel", intOr(config.MaxParallel, gitgraph.DefaultMaxParallel), "most outbound requests in flight at once, however many --workers, 0 for no limit"),
+		perMinute:       fs.Float64("requests-per-minute", config.RequestsPerMinute, "most outbound requests started a minute, shared by every fetch of the run, 0 for no limit"),
+	}
+}
+
+// outbound schedules the requests of every client of the run, so their
+// bounds hold together
+var outbound *gitgraph.Scheduler
+
+// newClient builds a Client from the parsed flags, exiting on invalid settings
+func (f *clientFlags) newClient() *gitgraph.Client {
+	f.apply()
+	if *f.onlyType != "" && !contains(gitgraph.ContributionTypeNames, *f.onlyType) {
+		fatal("unsupported --type", "type", *f.onlyType, "supported", strings.Join(gitgraph.ContributionTypeNames, ", "))
+	}
+
+	loc, err := f.location()
+	if err != nil {
+		fatal("invalid --tz", "err", err)
+	}
+	transport, err := gitgraph.NewTransport(*f.proxy)
+	if err != nil {
+		fatal("invalid proxy", "err", err)
+	}
+	if *f.noAuth {
+		if *f.appID != "" {
+			fatal("--no-auth and --app-id can't be combined")
+		}
+		*f.token, *f.ghToken, *f.bbToken = "", "", ""
+	}
+	var upstream http.RoundTripper = transport
+	if *f.appID != "" {
+		app := f.githubApp(transport)
+		token, err := app.Token(context.Background())
+		if err != nil {
+			fatalError("authenticating as the GitHub App", err, "app", *f.appID)
+		}
+		// The first token selects the authenticated APIs; the transport
+		// swaps in fresh ones as they expire
+		if *f.ghURL != "" {
+			*f.ghToken = token
+		} else {
+			*f.token = token
+		}
+		upstream = app.Transport(transport)
+	}
+	var base http.RoundTripper = gitgraph.NewConditionalTransport(upstream, nil)
+	switch {
+	case *f.fixture != "" && *f.record != "":
+		fatal("--fixture and --record can't be combined")
+	case *f.fixture != "":
+		base = &gitgraph.FixtureTransport{Path: *f.fixture}
+	case *f.record != "":
+		// Recording needs the raw response, so skip revalidation and the cache below
+		base = gitgraph.NewRecordTransport(upstream, *f.record)
+	}
+	if *f.seed != 0 && *f.provider != "fake" {
+		fatal("--seed needs --provider fake", "provider", *f.provider)
+	}
+	if *f.maxParallel < 0 || *f.perMinute < 0 {
+		fatal("invalid outbound limits, expected 0 or more", "max-parallel", *f.maxParallel, "requests-per-minute", *f.perMinute)
+	}
+	if outbound == nil {
+		outbound = gitgraph.NewScheduler(*f.maxParallel, *f.perMinute)
+	}
+	// Retries wait their turn like any other request
+	client := gitgraph.NewClient(nil)
+	client.HTTPClient.Transport = gitgraph.NewRetryTransport(outbound.Transport(base), *f.retries)
+	if *f.provider == "github" && !*f.noAuth {
+		// Only the instance in use is looked up, as asking gh starts a process
+		if *f.ghURL == "" && *f.token == "" {
+			*f.token = discoverToken(githubHost(""))
+		}
+		if *f.ghURL != "" && *f.ghToken == "" {
+			*f.ghToken = discoverToken(githubHost(*f.ghURL))
+		}
+	}
+	client.CacheTTL = *f.cacheTTL
+	client.Refresh = *f.refresh
+	// Each provider gets only its own token, so GitHub tokens from the
+	// environment never reach another service
+	token := ""
+	switch *f.provider {
+	case "github":
+		token = *f.token
+		if *f.ghURL != "" {
+			// Enterprise tokens are separate so a github.com token is never sent to the instance
+			token = *f.ghToken
+		}
+	case "bitbucket":
+		token = *f.bbToken
+	}
+	baseURL := *f.baseURL
+	if *f.provider == "github" {
+		baseURL = *f.ghURL
+	}
+	provider, err := gitgraph.NewProvider(*f.provider, gitgraph.ProviderOptions{
+		HTTPClient:     client.HTTPClient,
+		BaseURL:        baseURL,
+		Token:          token,
+		IncludePrivate: *f.private,
+		DayTypes:       *f.byType || *f.onlyType != "",
+		NoEvents:       *f.noEvents,
+		Location:       loc,
+		Seed:           *f.seed,
+		Now:            client.Now,
+	})
+	if err != nil {
+		fatal("invalid provider", "err", err)
+	}
+	if *f.provider == "github" {
+		client.Token = token
+	}
+	client.Provider = provider
+	client.Type = *f.onlyType
+	// Replays must reflect the fixture file, recordings reach upstream, and
+	// made-up contributions are quicker to make again than to read back
+	if !*f.noCache && *f.fixture == "" && *f.record == "" && *f.provider != "fake" {
+		dir, err := gitgraph.DefaultCacheDir()
+		if err != nil {
+			fatal("locating cache directory", "err", err)
+		}
+		setCache(client, gitgraph.NewFileCache(dir))
+	}
+	return client
+}
+
+// githubApp builds the GitHub App of --app-id, minting its tokens through transport
+func (f *clientFlags) githubApp(transport http.RoundTripper) *gitgraph.GitHubApp {
+	if *f.provider != "github" {
+		fatal("--app-id needs the github provider", "provider", *f.provider)
+	}
+	if *f.appKey == "" {
+		fatal("--app-id needs the app's private key as --app-key")
+	}
+	data, err := os.ReadFile(*f.appKey)
+	if err != nil {
+		fatal("reading --app-key", "err", err)
+	}
+	key, err := gitgraph.ParseAppKey(data)
+	if err != nil {
+		fatal("invalid --app-key", "path", *f.appKey, "err", err)
+	}
+	app := &gitgraph.GitHubApp{AppID: *f.appID, PrivateKey: key, HTTPClient: &http.Client{Transport: transport, Timeout: 30 * time.Second}}
+	if *f.appInstallation != "" {
+		if app.InstallationID, err = strconv.ParseInt(*f.appInstallation, 10, 64); err != nil {
+			fatal("invalid --app-installation, expected an installation ID", "installation", *f.appInstallation)
+		}
+	}
+	if *f.ghURL != "" {
+		app.BaseURL = strings.TrimRight(*f.ghURL, "/") + "/api/v3"
+	}
+	return app
+}
+
+// location loads --tz, nil when it is unset
+func (f *clientFlags) location() (*time.Location, error) {
+	if *f.tz == "" {
+		return nil, nil
+	}
+	return time.LoadLocation(*f.tz)
+}
+
+// setCache makes cache back both the client's parsed graphs and the
+// validators its transport revalidates with
+func setCache(client *gitgraph.Client, cache gitgraph.Cache) {
+	client.Cache = cache
+	if retry, ok := client.HTTPClient.Transport.(*gitgraph.RetryTransport); ok {
+		base := retry.Base
+		if scheduled, ok := base.(*gitgraph.SchedulerTransport); ok {
+			base = scheduled.Base
+		}
+		if conditional, ok := base.(*gitgraph.ConditionalTransport); ok {
+			conditional.Cache = cache
+		}
+	}
+}
+
+// breakerFlags configure the circuit breaker of long-running commands
+type breakerFlags struct {
+	threshold *int
+	cooldown  *time.Duration
+}
+
+func addBreakerFlags(fs *flag.FlagSet) *breakerFlags {
+	return &breakerFlags{
+		threshold: fs.Int("breaker-threshold", 5, "after this many consecutive upstream failures, serve stale cached graphs and stop fetching until --breaker-cooldown passes, 0 to disable"),
+		cooldown:  fs.Duration("breaker-cooldown", 5*time.Minute, "how long to wait before trying upstream again once the circuit breaker opens"),
+	}
+}
+
+// apply gives client a circuit breaker unless it is disabled
+func (f *breakerFlags) apply(client *gitgraph.Client) {
+	if *f.threshold < 0 {
+		fatal("invalid --breaker-threshold, expected 0 or more", "threshold", *f.threshold)
+	}
+	if *f.threshold > 0 {
+		client.Breaker = gitgraph.NewCircuitBreaker(*f.threshold, *f.cooldown)
+	}
+}
+
+// jsonShapes are the alternatives to json output's own shape
+var jsonShapes = []string{"graphql", "chartjs", "d3"}
+
+// fieldFlags trim and reshape data output for piping into other tools
+type fieldFlags struct {
+	fields  *string
+	compact *bool
+	shape   *string
+}
+
+func addFieldFlags(fs *flag.FlagSet) *fieldFlags {
+	return &fieldFlags{
+		fields:  fs.String("fields", "", "comma-separated day fields to write in json, jsonl and csv output, in order, e.g. date,count; csv has gridWeek only when selected"),
+		compact: fs.Bool("compact", false, "write JSON without indentation"),
+		shape:   fs.String("shape", "", "shape of json output: graphql for days in weeks, as in a GitHub GraphQL contributionCalendar response; chartjs for Chart.js labels and datasets; d3 for days nested by week"),
+	}
+}
+
+// apply switches JSON output to compact and returns the selected fields,
+// exiting when they or --shape can't apply to format
+func (f *fieldFlags) apply(format string) []string {
+	compactJSON = *f.compact
+	fields := splitList(*f.fields)
+	if *f.shape != "" {
+		if !contains(jsonShapes, *f.shape) {
+			fatal("unsupported shape", "shape", *f.shape, "supported", strings.Join(jsonShapes, ", "))
+		}
+		if format != "json" || len(fields) > 0 {
+			fatal("--shape only applies to json output, without --fields", "format", format)
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	known := export.Fields(format)
+	if known == nil {
+		fatal("--fields only applies to json, jsonl and csv output", "format", format)
+	}
+	for _, field := range fields {
+		if !contains(known, field) {
+			fatal("unknown field", "field", field, "format", format, "supported", strings.Join(known, ", "))
+		}
+	}
+	return fields
+}
+
+// orderFlags sort and limit the days written by data formats
+type orderFlags struct {
+	sort *string
+	desc *bool
+	top  *int
+}
+
+func addOrderFlags(fs *flag.FlagSet) *orderFlags {
+	return &orderFlags{
+		sort: fs.String("sort", "", "order of the days in json, jsonl, csv and table output: date or count (default date)"),
+		desc: fs.Bool("desc", false, "sort the days in descending order"),
+		top:  fs.Int("top", 0, "write only the first this many days of --sort, by default the biggest; totals and summaries still cover every day"),
+	}
+}
+
+// order returns how to order the days of format, nil for date order, exiting
+// when the flags can't apply to it
+func (f *orderFlags) order(format string) *dayOrder {
+	if *f.sort == "" && !*f.desc && *f.top == 0 {
+		return nil
+	}
+	if !contains([]string{"json", "jsonl", "csv", "table"}, format) {
+		fatal("--sort, --desc and --top only apply to json, jsonl, csv and table output", "format", format)
+	}
+	if *f.top < 0 {
+		fatal("invalid --top, expected a positive number", "top", *f.top)
+	}
+	order := &dayOrder{By: *f.sort, Desc: *f.desc, Top: *f.top}
+	if order.By == "" {
+		order.By = "date"
+		// The top days are the biggest unless sorted otherwise
+		if order.Top > 0 {
+			order.By, order.Desc = "count", true
+		}
+	}
+	if !contains(gitgraph.DayOrders, order.By) {
+		fatal("unsupported --sort", "sort", order.By, "supported", strings.Join(gitgraph.DayOrders, ", "))
+	}
+	return order
+}
+
+// targetFlags select whose graph to fetch and for which period
+type targetFlags struct {
+	years    *string
+	from     *string
+	to       *string
+	allYears *bool
+	merge    *string // nil unless added with withMerge
+}
+
+func addTargetFlags(fs *flag.FlagSet) *targetFlags {
+	return &targetFlags{
+		years:    fs.String("years", "", "comma-separated years or a range to fetch, e.g. 2019,2021 or 2019-2024"),
+		from:     fs.String("from", "", "first day to fetch (YYYY-MM-DD), instead of calendar years"),
+		to:       fs.String("to", "", "last day to fetch (YYYY-MM-DD), defaults to today with --from"),
+		allYears: fs.Bool("all-years", false, "fetch every year since the user's first contribution (GitHub and fake only; without a token, since the account was created)"),
+	}
+}
+
+// withMerge adds --merge, for commands taking a single user
+func (f *targetFlags) withMerge(fs *flag.FlagSet) *targetFlags {
+	f.merge = fs.String("merge", "", "comma-separated accounts, e.g. work and personal, summed into one graph in place of the username")
+	return f
+}
+
+// period is the span selected on the command line: whole years, a from..to
+// range, or every year of the user's history
+type period struct {
+	Years    []int
+	From     time.Time
+	To       time.Time
+	AllYears bool // Years are found per user when fetching
+	// Accounts are fetched and summed with gitgraph.MergeAccounts instead of
+	// a single username
+	Accounts []string
+}
+
+// target resolves "<username> [years]" positional arguments, with --years, --from
+// and --to taking precedence, falling back to the first configured username
+func (f *targetFlags) target(args []string, config *Config) (string, period, error) {
+	if f.merge != nil && *f.merge != "" {
+		return f.mergeTarget(args)
+	}
+	username := ""
+	if len(args) > 0 {
+		username = args[0]
+	} else if len(config.Usernames) > 0 {
+		username = config.Usernames[0]
+	}
+	if username == "" {
+		return "", period{}, fmt.Errorf("no username given")
+	}
+
+	spec := ""
+	if len(args) > 1 {
+		spec = args[1]
+	}
+	p, err := f.period(spec)
+	if err != nil {
+		return "", period{}, err
+	}
+	return username, p, nil
+}
+
+// mergeTarget resolves the "[years]" positional argument of --merge, naming
+// the combined graph after its accounts
+func (f *targetFlags) mergeTarget(args []string) (string, period, error) {
+	accounts := splitList(*f.merge)
+	if len(accounts) < 2 {
+		return "", period{}, fmt.Errorf("--merge needs at least two accounts")
+	}
+	if len(args) > 1 {
+		return "", period{}, fmt.Errorf("--merge replaces the username; only years may follow")
+	}
+	spec := ""
+	if len(args) > 0 {
+		spec = args[0]
+	}
+	p, err := f.period(spec)
+	if err != nil {
+		return "", period{}, err
+	}
+	p.Accounts = accounts
+	return strings.Join(accounts, "+"), p, nil
+}
+
+// period resolves the selected period from --years, --from and --to, falling back
+// to the positional years spec and then the current year
+func (f *targetFlags) period(spec string) (period, error) {
+	if *f.allYears {
+		if *f.from != "" || *f.to != "" || *f.years != "" || spec != "" {
+			return period{}, fmt.Errorf("--all-years can't be combined with other years or dates")
+		}
+		return period{AllYears: true}, nil
+	}
+	if *f.from != "" || *f.to != "" {
+		return parseRange(*f.from, *f.to)
+	}
+
+	if *f.years != "" {
+		spec = *f.years
+	}
+	if spec == "" {
+		return period{Years: []int{time.Now().Year()}}, nil
+	}
+
+	years, err := parseYears(spec)
+	if err != nil {
+		return period{}, fmt.Errorf("invalid year: %w", err)
+	}
+	return period{Years: years}, nil
+}
+
+// parseRange parses --from/--to dates. A missing end defaults to today and a
+// missing start to a year before the end, like GitHub's profile calendar.
+func parseRange(fromStr, toStr string) (period, error) {
+	today := time.Now()
+	to := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	if toStr != "" {
+		parsed, err := time.Parse("2006-01-02", toStr)
+		if err != nil {
+			return period{}, fmt.Errorf("invalid --to date: %w", err)
+		}
+		to = parsed
+	}
+
+	from := to.AddDate(-1, 0, 1)
+	if fromStr != "" {
+		parsed, err := time.Parse("2006-01-02", fromStr)
+		if err != nil {
+			return period{}, fmt.Errorf("invalid --from date: %w", err)
+		}
+		from = parsed
+	}
+	if to.Before(from) {
+		return period{}, fmt.Errorf("--to %s is before --from %s", to.Format("2006-01-02"), from.Format("2006-01-02"))
+	}
+	return period{From: from, To: to}, nil
+}
+
+// parseLocale looks up a --locale tag, exiting when it isn't supported. The
+// empty tag selects English.
+func parseLocale(tag string) *locale.Locale {
+	if tag == "" {
+		return locale.English
+	}
+	loc, err := locale.Lookup(tag)
+	if err != nil {
+		fatal("invalid --locale", "err", err)
+	}
+	return loc
+}
+
+// levelFlags optionally recompute levels on a fixed scale so graphs are
+// comparable across users, and keep only the days reaching a threshold
+type levelFlags struct {
+	levels   *string
+	minLevel *int
+	minCount *int
+}
+
+func addLevelFlags(fs *flag.FlagSet) *levelFlags {
+	return &levelFlags{
+		levels:   fs.String("levels", "", "recompute levels from counts: minimum counts of levels 0-4 (e.g. 0,1,5,10,20), quartiles, or global for quartiles of every user's counts together"),
+		minLevel: fs.Int("min-level", 0, "keep only days of at least this level, 0-4, after any --levels, e.g. 3 for the heavy days"),
+		minCount: fs.Int("min-count", 0, "keep only days with at least this many contributions"),
+	}
+}
+
+// global reports whether levels should be computed across all graphs at once
+func (f *levelFlags) global() bool { return *f.levels == "global" }
+
+// applyAll relevels graphs as requested, on one scale shared by all of them
+// for --levels global, exiting on an invalid --levels value. Streaks of
+// filtered graphs are recomputed as of today.
+func (f *levelFlags) applyAll(graphs []*gitgraph.ContributionGraph, today time.Time) {
+	if f.global() {
+		thresholds := gitgraph.SharedThresholds(graphs)
+		for _, graph := range graphs {
+			graph.Relevel(thresholds)
+			f.filter(graph, today)
+		}
+		return
+	}
+	for _, graph := range graphs {
+		f.apply(graph, today)
+	}
+}
+
+// apply relevels graph as requested, then drops the days below --min-level
+// and --min-count, exiting on invalid values
+func (f *levelFlags) apply(graph *gitgraph.ContributionGraph, today time.Time) {
+	switch *f.levels {
+	case "":
+	case "quartiles", "global":
+		counts := make(map[string]int, len(graph.Days))
+		for _, day := range graph.Days {
+			counts[day.Date] = day.Count
+		}
+		graph.Relevel(gitgraph.QuartileThresholds(counts))
+	default:
+		thresholds, err := parseLevels(*f.levels)
+		if err != nil {
+			fatal("invalid --levels", "err", err)
+		}
+		graph.Relevel(thresholds)
+	}
+	f.filter(graph, today)
+}
+
+// filter drops graph's days below --min-level and --min-count, recomputing
+// its streaks as of today, exiting on an invalid --min-level
+func (f *levelFlags) filter(graph *gitgraph.ContributionGraph, today time.Time) {
+	if *f.minLevel < 0 || *f.minLevel > 4 {
+		fatal("invalid --min-level, expected 0-4", "level", *f.minLevel)
+	}
+	if *f.minLevel == 0 && *f.minCount <= 0 {
+		return
+	}
+	graph.FilterThreshold(*f.minLevel, *f.minCount)
+	graph.UpdateStreaks(today)
+}
+
+// dayFlags restrict graphs to some days of the week and choose where weeks begin
+type dayFlags struct {
+	weekdays   *bool
+	weekends   *bool
+	days       *string
+	weekStart  *string
+	weekNumber *string
+}
+
+func addDayFlags(fs *flag.FlagSet, config *Config) *dayFlags {
+	return &dayFlags{
+		weekdays:   fs.Bool("weekdays", false, "count only Monday to Friday"),
+		weekends:   fs.Bool("weekends", false, "count only Saturday and Sunday"),
+		days:       fs.String("days", "", "count only these days of the week, e.g. mon,wed,fri"),
+		weekStart:  fs.String("week-start", config.WeekStart, "first day of the week, sunday or monday, for calendar rows and the dayOfWeek and weekOfYear fields (unset: Sunday rows and ISO weeks)"),
+		weekNumber: fs.String("week-number", "", "what fills weekOfYear: iso for the ISO 8601 week, or grid for the calendar column also given as gridWeek"),
+	}
+}
+
+// start returns the weekday calendars begin their weeks on, exiting on an
+// invalid --week-start value
+func (f *dayFlags) start() time.Weekday {
+	return parseWeekStart(*f.weekStart)
+}
+
+// parseWeekStart parses a --week-start value, exiting when it is invalid. The
+// empty value selects Sunday, as on GitHub.
+func parseWeekStart(name string) time.Weekday {
+	if name == "" {
+		return time.Sunday
+	}
+	start, err := gitgraph.ParseWeekStart(name)
+	if err != nil {
+		fatal("invalid --week-start", "err", err)
+	}
+	return start
+}
+
+// apply drops the days not selected from graph and renumbers its weeks for
+// --week-start, exiting on invalid values. Streaks of a filtered graph are
+// recomputed as of today.
+func (f *dayFlags) apply(graph *gitgraph.ContributionGraph, today time.Time) {
+	if *f.weekStart != "" {
+		graph.SetWeekStart(f.start())
+	}
+	if *f.weekNumber != "" {
+		if err := graph.NumberWeeks(*f.weekNumber); err != nil {
+			fatal("invalid --week-number", "err", err)
+		}
+	}
+	var keep []time.Weekday
+	switch {
+	case *f.weekdays && *f.weekends, (*f.weekdays || *f.weekends) && *f.days != "":
+		fatal("--weekdays, --weekends and --days can't be combined")
+	case *f.weekdays:
+		keep = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+	case *f.weekends:
+		keep = []time.Weekday{time.Saturday, time.Sunday}
+	case *f.days != "":
+		var err error
+		if keep, err = parseWeekdays(*f.days); err != nil {
+			fatal("invalid --days", "err", err)
+		}
+	default:
+		return
+	}
+	graph.FilterWeekdays(keep)
+	graph.UpdateStreaks(today)
+}
+
+// parseWeekdays parses comma-separated weekday names, full or abbreviated to three letters
+func parseWeekdays(spec string) ([]time.Weekday, error) {
+	var weekdays []time.Weekday
+	for _, part := range strings.Split(spec, ",") {
+		name := strings.ToLower(strings.TrimSpace(part))
+		found := false
+		for wd := time.Sunday; wd <= time.Saturday; wd++ {
+			full := strings.ToLower(wd.String())
+			if name == full || name == full[:3] {
+				weekdays = append(weekdays, wd)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown day %q, expected e.g. mon or monday", part)
+		}
+	}
+	return weekdays, nil
+}
+
+// retentionFlags choose the snapshots a store keeps
+type retentionFlags struct {
+	perDay  *int
+	perWeek *int
+	maxAge  *string
+}
+
+func addRetentionFlags(fs *flag.FlagSet, config *Config) *retentionFlags {
+	return &retentionFlags{
+		perDay:  fs.Int("keep-per-day", config.Retention.PerDay, "keep the latest this many snapshots of each day, 0 for all"),
+		perWeek: fs.Int("keep-per-week", config.Retention.PerWeek, "keep the latest this many snapshots of each week, for those over a week old, 0 for all"),
+		maxAge:  fs.String("max-age", config.Retention.MaxAge, "drop snapshots older than this, e.g. 90d, 12w or 720h; the latest of each user is always kept"),
+	}
+}
+
+// retention is the parsed policy, exiting on invalid values
+func (f *retentionFlags) retention() store.Retention {
+	if *f.perDay < 0 || *f.perWeek < 0 {
+		fatal("invalid snapshot counts, expected 0 or more", "keep-per-day", *f.perDay, "keep-per-week", *f.perWeek)
+	}
+	r := store.Retention{PerDay: *f.perDay, PerWeek: *f.perWeek}
+	if *f.maxAge != "" {
+		age, err := parseAge(*f.maxAge)
+		if err != nil {
+			fatal("invalid --max-age", "err", err)
+		}
+		r.MaxAge = age
+	}
+	return r
+}
+
+// parseAge parses a positive age in days or weeks, e.g. 90d or 12w, or as a
+// Go duration such as 720h
+func parseAge(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	unit := map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour}
+	if n, err := strconv.Atoi(strings.TrimRight(s, "dw")); err == nil && len(s) > 1 && unit[s[len(s)-1:]] > 0 && n > 0 {
+		return time.Duration(n) * unit[s[len(s)-1:]], nil
+	}
+	age, err := time.ParseDuration(s)
+	if err != nil || age <= 0 {
+		return 0, fmt.Errorf("invalid age %q, expected e.g. 90d, 12w or 720h", s)
+	}
+	return age, nil
+}
+
+// parseLevels parses increasing minimum counts for levels 1-4, optionally
+// preceded by a 0 for level 0
+func parseLevels(spec string) ([]int, error) {
+	thresholds := []int{}
+	for _, part := range strings.Split(spec, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+		if len(thresholds) > 0 && n <= thresholds[len(thresholds)-1] {
+			return nil, fmt.Errorf("thresholds must increase: %s", spec)
+		}
+		thresholds = append(thresholds, n)
+	}
+	if len(thresholds) == 5 && thresholds[0] == 0 {
+		thresholds = thresholds[1:]
+	}
+	if len(thresholds) != 4 || thresholds[0] < 1 {
+		return nil, fmt.Errorf("expected minimum counts for levels 1-4, e.g. 1,5,10,20")
+	}
+	return thresholds, nil
+}
+
+// parseYears parses "2024", "2019-2024" or "2019,2021,2023" into a list of years
+func parseYears(spec string) ([]int, error) {
+	years := []int{}
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if from, to, ok := strings.Cut(part, "-"); ok {
+			start, err := strconv.Atoi(from)
+			if err != nil {
+				return nil, err
+			}
+			end, err := strconv.Atoi(to)
+			if err != nil {
+				return nil, err
+			}
+			if end < start {
+				return nil, fmt.Errorf("range %s ends before it starts", part)
+			}
+			for y := start; y <= end; y++ {
+				years = append(years, y)
+			}
+			continue
+		}
+
+		year, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, err
+		}
+		years = append(years, year)
+	}
+	return years, nil
+}
+
+// parseInterspersed parses fs flags that may appear before, between or after
+// positional arguments, returning the positional arguments
+func parseInterspersed(fs *flag.FlagSet, args []string) []string {
+	if completeFlagSet != nil {
+		completeFlagSet(fs)
+	}
+	positional := []string{}
+	for {
+		fs.Parse(args)
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+	"github.com/JyotinderSingh/gitgraphed/render"
+)
+
+// gistDescription identifies the gist that username's graph is published to,
+// so later runs update it in place
+func gistDescription(username string) string {
+	return "gitgraphed contributions of " + username
+}
+
+// publishGist writes graph as JSON and SVG to the gist id, or to the gist
+// found by its description, creating it when there is none, and prints the
+// raw URL of each file
+func publishGist(ctx context.Context, client *gitgraph.Client, config *Config, graph *gitgraph.ContributionGraph, id string) {
+	opts := render.DefaultSVGOptions
+	opts.Locale = parseLocale(config.Locale)
+	opts.WeekStart = parseWeekStart(config.WeekStart)
+	if config.Theme != "" {
+		theme, err := config.theme(config.Theme)
+		if err != nil {
+			fatal("invalid theme", "err", err)
+		}
+		opts.Theme = theme
+	}
+
+	var data, svg bytes.Buffer
+	if err := writeGraph(&data, graph, outputOptions{Format: "json"}); err != nil {
+		fatal("encoding JSON", "err", err)
+	}
+	if err := render.SVG(&svg, graph, opts); err != nil {
+		fatal("rendering SVG", "err", err)
+	}
+
+	github := client.GitHub()
+	description := gistDescription(graph.Username)
+	if id == "" {
+		existing, err := github.FindGist(ctx, description)
+		if err != nil {
+			exitIfInterrupted(ctx)
+			fatalError("finding gist", err)
+		}
+		if existing != nil {
+			id = existing.ID
+		}
+	}
+	gist, err := github.PutGist(ctx, id, description, map[string][]byte{
+		graph.Username + ".json": data.Bytes(),
+		graph.Username + ".svg":  svg.Bytes(),
+	})
+	if err != nil {
+		exitIfInterrupted(ctx)
+		fatalError("publishing gist", err, "gist", id)
+	}
+	slog.Info("published gist", "gist", gist.ID, "url", gist.HTMLURL)
+	for _, name := range gist.FileNames() {
+		fmt.Println(gist.Files[name].LatestRawURL())
+	}
+}
@@ -0,0 +1,44 @@
+package gitgraph
+
+import "sort"
+
+// MergeAccounts sums the graphs of several accounts of one person, such as a
+// work and a personal account, into a single graph named name. Each day's
+// Accounts and the graph's Accounts attribute the counts to their account;
+// levels are recomputed from the summed counts.
+func MergeAccounts(name string, graphs []*ContributionGraph) *ContributionGraph {
+	// Totals, types and repositories add up as they do across periods
+	merged := MergeGraphs(graphs...)
+	sum := SumGraphs(name, graphs)
+	merged.Username = name
+	merged.Days = sum.Days
+	merged.TotalContribs = sum.TotalContribs + merged.PrivateContribs
+	merged.YearTotals = nil
+
+	byDate := make(map[string]int, len(merged.Days))
+	for i, day := range merged.Days {
+		byDate[day.Date] = i
+	}
+	merged.Accounts = []MemberTotal{}
+	for _, graph := range graphs {
+		merged.Accounts = append(merged.Accounts, MemberTotal{Username: graph.Username, Total: graph.TotalContribs})
+		for _, day := range graph.Days {
+			summed := &merged.Days[byDate[day.Date]]
+			if day.Types != nil {
+				if summed.Types == nil {
+					summed.Types = &ContributionTypes{}
+				}
+				summed.Types.add(*day.Types)
+			}
+			if day.Count == 0 {
+				continue
+			}
+			if summed.Accounts == nil {
+				summed.Accounts = map[string]int{}
+			}
+			summed.Accounts[graph.Username] += day.Count
+		}
+	}
+	sort.SliceStable(merged.Accounts, func(i, j int) bool { return merged.Accounts[i].Total > merged.Accounts[j].Total })
+	return merged
+}
@@ -0,0 +1,144 @@
+package gitgraph
+
+import (
+	"math"
+	"time"
+)
+
+// AnomalyOptions set the thresholds of DetectAnomalies
+type AnomalyOptions struct {
+	MinGapDays  int     // shortest run of inactive days reported as a gap
+	SpikeZScore float64 // standard deviations above the daily mean that make a spike
+	DropPercent float64 // month-over-month decline reported as a drop
+}
+
+// DefaultAnomalyOptions flag two inactive weeks, 3σ spikes and months down by half
+var DefaultAnomalyOptions = AnomalyOptions{MinGapDays: 14, SpikeZScore: 3, DropPercent: 50}
+
+// Gap is a run of consecutive days without contributions
+type Gap struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+	Days  int    `json:"days"`
+}
+
+// Spike is a day far above the graph's daily mean
+type Spike struct {
+	Date   string  `json:"date"`
+	Count  int     `json:"count"`
+	ZScore float64 `json:"zScore"`
+}
+
+// MonthDrop is a month whose total fell sharply from the month before
+type MonthDrop struct {
+	Month         string  `json:"month"` // YYYY-MM
+	Total         int     `json:"total"`
+	PreviousTotal int     `json:"previousTotal"`
+	DropPercent   float64 `json:"dropPercent"`
+}
+
+// Anomalies are the unusual stretches of a graph, for self-review
+type Anomalies struct {
+	Gaps   []Gap       `json:"gaps"`
+	Spikes []Spike     `json:"spikes"`
+	Drops  []MonthDrop `json:"drops"`
+}
+
+// UpdateAnomalies recomputes the graph's Anomalies as of today with the default thresholds
+func (g *ContributionGraph) UpdateAnomalies(today time.Time) {
+	anomalies := DetectAnomalies(g.Days, today, DefaultAnomalyOptions)
+	g.Anomalies = &anomalies
+}
+
+// DetectAnomalies finds inactivity gaps, spike days and month-over-month drops
+// in days, which are assumed to be in date order. Days after today are ignored,
+// and only months that have ended are compared.
+func DetectAnomalies(days []ContributionDay, today time.Time, opts AnomalyOptions) Anomalies {
+	anomalies := Anomalies{Gaps: []Gap{}, Spikes: []Spike{}, Drops: []MonthDrop{}}
+	todayStr := today.Format("2006-01-02")
+	past := []ContributionDay{}
+	for _, day := range days {
+		if day.Date <= todayStr {
+			past = append(past, day)
+		}
+	}
+	if len(past) == 0 {
+		return anomalies
+	}
+
+	// Gaps
+	runStart := -1
+	closeRun := func(end int) {
+		if runStart >= 0 && end-runStart >= opts.MinGapDays {
+			anomalies.Gaps = append(anomalies.Gaps, Gap{Start: past[runStart].Date, End: past[end-1].Date, Days: end - runStart})
+		}
+		runStart = -1
+	}
+	for i, day := range past {
+		if day.Count == 0 && runStart < 0 {
+			runStart = i
+		} else if day.Count > 0 {
+			closeRun(i)
+		}
+	}
+	closeRun(len(past))
+
+	// Spikes
+	mean, sd := meanStdDev(past)
+	if sd > 0 {
+		for _, day := range past {
+			if z := (float64(day.Count) - mean) / sd; z >= opts.SpikeZScore {
+				anomalies.Spikes = append(anomalies.Spikes, Spike{Date: day.Date, Count: day.Count, ZScore: math.Round(z*100) / 100})
+			}
+		}
+	}
+
+	// Drops between consecutive months that have both ended
+	type monthTotal struct {
+		month string
+		total int
+	}
+	months := []monthTotal{}
+	currentMonth := todayStr[:7]
+	for _, day := range past {
+		month := day.Date[:7]
+		if month == currentMonth {
+			break
+		}
+		if n := len(months); n == 0 || months[n-1].month != month {
+			months = append(months, monthTotal{month: month})
+		}
+		months[len(months)-1].total += day.Count
+	}
+	for i := 1; i < len(months); i++ {
+		prev, cur := months[i-1], months[i]
+		if prev.total == 0 {
+			continue
+		}
+		drop := float64(prev.total-cur.total) / float64(prev.total) * 100
+		if drop >= opts.DropPercent {
+			anomalies.Drops = append(anomalies.Drops, MonthDrop{
+				Month:         cur.month,
+				Total:         cur.total,
+				PreviousTotal: prev.total,
+				DropPercent:   math.Round(drop*10) / 10,
+			})
+		}
+	}
+	return anomalies
+}
+
+// meanStdDev returns the mean and population standard deviation of the days' counts
+func meanStdDev(days []ContributionDay) (float64, float64) {
+	sum := 0.0
+	for _, day := range days {
+		sum += float64(day.Count)
+	}
+	mean := sum / float64(len(days))
+	variance := 0.0
+	for _, day := range days {
+		d := float64(day.Count) - mean
+		variance += d * d
+	}
+	return mean, math.Sqrt(variance / float64(len(days)))
+}
@@ -0,0 +1,189 @@
+package gitgraph
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// GitHubApp authenticates as an installation of a GitHub App, minting
+// installation tokens from the app's private key as they expire. Installation
+// tokens have the rate limit of the organization they're installed in and see
+// the repositories the installation was granted.
+type GitHubApp struct {
+	AppID string
+	// InstallationID is the installation to act as; when zero, the app's only
+	// installation
+	InstallationID int64
+	PrivateKey     *rsa.PrivateKey
+	// BaseURL is the REST API, https://api.github.com when empty
+	BaseURL string
+	// HTTPClient mints the tokens, so mustn't itself go through Transport
+	HTTPClient *http.Client
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// appTokenMargin is how long before expiring an installation token is replaced
+const appTokenMargin = 5 * time.Minute
+
+// ParseAppKey parses a GitHub App private key, as downloaded from the app's
+// settings: a PEM-encoded PKCS #1 or PKCS #8 RSA key
+func ParseAppKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM-encoded key found")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("the private key isn't an RSA key")
+	}
+	return key, nil
+}
+
+func (a *GitHubApp) restEndpoint() string {
+	if a.BaseURL != "" {
+		return strings.TrimRight(a.BaseURL, "/")
+	}
+	return restAPIURL
+}
+
+// jwt is the short-lived token authenticating as the app itself
+func (a *GitHubApp) jwt() (string, error) {
+	now := time.Now()
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	claims, err := json.Marshal(map[string]any{
+		// Backdated against clock drift; GitHub allows at most 10 minutes
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(9 * time.Minute).Unix(),
+		"iss": a.AppID,
+	})
+	if err != nil {
+		return "", err
+	}
+	unsigned := header + "." + base64.RawURLEncoding.EncodeToString(claims)
+	sum := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, a.PrivateKey, crypto.SHA256, sum[:])
+	if err != nil {
+		return "", err
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// Token returns an installation token valid for at least a few minutes,
+// minting a new one when the last is about to expire
+func (a *GitHubApp) Token(ctx context.Context) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.token != "" && time.Until(a.expires) > appTokenMargin {
+		return a.token, nil
+	}
+
+	jwt, err := a.jwt()
+	if err != nil {
+		return "", err
+	}
+	if a.InstallationID == 0 {
+		if a.InstallationID, err = a.onlyInstallation(ctx, jwt); err != nil {
+			return "", err
+		}
+	}
+	endpoint := fmt.Sprintf("%s/app/installations/%d/access_tokens", a.restEndpoint(), a.InstallationID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	resp, err := a.HTTPClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("minting an installation token: %w", newStatusError(resp))
+	}
+	var minted struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&minted); err != nil {
+		return "", parseError(err)
+	}
+	a.token, a.expires = minted.Token, minted.ExpiresAt
+	return a.token, nil
+}
+
+// onlyInstallation looks up the app's installation, failing when it has
+// none or several to choose from
+func (a *GitHubApp) onlyInstallation(ctx context.Context, jwt string) (int64, error) {
+	var installations []struct {
+		ID      int64 `json:"id"`
+		Account struct {
+			Login string `json:"login"`
+		} `json:"account"`
+	}
+	if _, err := getJSON(ctx, a.HTTPClient, jwt, a.restEndpoint()+"/app/installations", &installations); err != nil {
+		return 0, fmt.Errorf("listing the app's installations: %w", err)
+	}
+	switch len(installations) {
+	case 0:
+		return 0, errors.New("the app isn't installed anywhere")
+	case 1:
+		return installations[0].ID, nil
+	}
+	choices := make([]string, len(installations))
+	for i, installation := range installations {
+		choices[i] = installation.Account.Login + " (" + strconv.FormatInt(installation.ID, 10) + ")"
+	}
+	return 0, fmt.Errorf("the app has %d installations, choose one of %s", len(installations), strings.Join(choices, ", "))
+}
+
+// Transport sends requests to the app's API host with the current
+// installation token in place of the Authorization they carry, so
+// long-running commands outlive each token's hour
+func (a *GitHubApp) Transport(base http.RoundTripper) http.RoundTripper {
+	return &appTransport{app: a, base: base}
+}
+
+type appTransport struct {
+	app  *GitHubApp
+	base http.RoundTripper
+}
+
+func (t *appTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	api, err := url.Parse(t.app.restEndpoint())
+	if err != nil || req.Header.Get("Authorization") == "" || req.URL.Host != api.Host {
+		return t.base.RoundTrip(req)
+	}
+	token, err := t.app.Token(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token)
+	return t.base.RoundTrip(req)
+}
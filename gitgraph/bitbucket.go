@@ -0,0 +1,137 @@
+package gitgraph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultBitbucketURL is the base URL of the Bitbucket Cloud API
+const DefaultBitbucketURL = "https://api.bitbucket.org/2.0"
+
+// Bitbucket builds contributions from the commits a user authored in the
+// repositories of their workspace, since Bitbucket Cloud has no activity calendar.
+// Token is either an access token or "username:app-password".
+type Bitbucket struct {
+	HTTPClient *http.Client
+	BaseURL    string
+	Token      string
+	Location   *time.Location // zone commits are dated in; nil for UTC
+}
+
+// NewBitbucket creates a Bitbucket provider for baseURL, defaulting to Bitbucket Cloud
+func NewBitbucket(httpClient *http.Client, baseURL, token string) *Bitbucket {
+	if baseURL == "" {
+		baseURL = DefaultBitbucketURL
+	}
+	return &Bitbucket{HTTPClient: httpClient, BaseURL: strings.TrimRight(baseURL, "/"), Token: token}
+}
+
+func (b *Bitbucket) Name() string {
+	return "bitbucket:" + b.BaseURL + zoneSuffix(b.Location)
+}
+
+// bitbucketPage is the envelope of paginated Bitbucket API responses
+type bitbucketPage[T any] struct {
+	Values []T    `json:"values"`
+	Next   string `json:"next"`
+}
+
+type bitbucketRepo struct {
+	FullName string `json:"full_name"`
+}
+
+type bitbucketCommit struct {
+	Date   time.Time `json:"date"`
+	Author struct {
+		Raw  string `json:"raw"`
+		User struct {
+			Nickname string `json:"nickname"`
+		} `json:"user"`
+	} `json:"author"`
+}
+
+func (b *Bitbucket) FetchRange(ctx context.Context, username string, from, to time.Time) (*ContributionGraph, error) {
+	repos := []bitbucketRepo{}
+	next := fmt.Sprintf("%s/repositories/%s?pagelen=100", b.BaseURL, url.PathEscape(username))
+	for next != "" {
+		var page bitbucketPage[bitbucketRepo]
+		if err := b.getJSON(ctx, next, &page); err != nil {
+			return nil, fmt.Errorf("listing repositories: %w", err)
+		}
+		repos = append(repos, page.Values...)
+		next = page.Next
+	}
+
+	loc := orUTC(b.Location)
+	counts := make(map[string]int)
+	start, end := midnight(from, loc), midnight(to, loc).AddDate(0, 0, 1)
+	for _, repo := range repos {
+		if err := b.countCommits(ctx, repo.FullName, username, start, end, counts); err != nil {
+			return nil, fmt.Errorf("reading commits of %s: %w", repo.FullName, err)
+		}
+	}
+	graph := GraphFromCounts(username, counts, from, to, QuartileThresholds(counts))
+	graph.TimeZone = loc.String()
+	return graph, nil
+}
+
+// countCommits adds username's commits in repo between from and end (exclusive)
+// to counts, dated in from's zone. Commits are listed newest first, so paging
+// stops once past from.
+func (b *Bitbucket) countCommits(ctx context.Context, repo, username string, from, end time.Time, counts map[string]int) error {
+	next := fmt.Sprintf("%s/repositories/%s/commits?pagelen=100", b.BaseURL, repo)
+	for next != "" {
+		var page bitbucketPage[bitbucketCommit]
+		if err := b.getJSON(ctx, next, &page); err != nil {
+			return err
+		}
+
+		older := 0
+		for _, commit := range page.Values {
+			switch {
+			case commit.Date.Before(from):
+				older++
+			case !commit.Date.Before(end):
+			case commit.Author.User.Nickname == username:
+				counts[commit.Date.In(from.Location()).Format("2006-01-02")]++
+			}
+		}
+		if len(page.Values) > 0 && older == len(page.Values) {
+			return nil
+		}
+		next = page.Next
+	}
+	return nil
+}
+
+func (b *Bitbucket) getJSON(ctx context.Context, endpoint string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if user, password, ok := strings.Cut(b.Token, ":"); ok {
+		req.SetBasicAuth(user, password)
+	} else if b.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+b.Token)
+	}
+
+	resp, err := b.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newStatusError(resp)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return parseError(err)
+	}
+	return nil
+}
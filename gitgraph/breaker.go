@@ -0,0 +1,78 @@
+package gitgraph
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen means upstream failed repeatedly and isn't being asked again
+// until the breaker's cool-down passes
+var ErrCircuitOpen = errors.New("upstream unavailable, circuit open")
+
+// CircuitBreaker stops a Client fetching from upstream after Threshold
+// consecutive failures. Once Cooldown passes one fetch is let through: success
+// closes the circuit, failure keeps it open for another Cooldown. While it is
+// open the Client answers from stale cached graphs where it has them.
+type CircuitBreaker struct {
+	Threshold int
+	Cooldown  time.Duration
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+}
+
+// NewCircuitBreaker creates a CircuitBreaker opening after threshold failures
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{Threshold: threshold, Cooldown: cooldown}
+}
+
+// Allow reports whether a fetch may go upstream
+func (b *CircuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < b.Threshold {
+		return true
+	}
+	if time.Since(b.openedAt) < b.Cooldown {
+		return false
+	}
+	// Half-open: let this fetch probe upstream and hold the rest back
+	b.openedAt = time.Now()
+	return true
+}
+
+// Record counts the outcome of a fetch Allow let through. Errors that don't
+// mean upstream is down, like unknown users or cancelled requests, are ignored.
+func (b *CircuitBreaker) Record(err error) {
+	if err != nil && !outage(err) {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err == nil {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures >= b.Threshold {
+		b.openedAt = time.Now()
+	}
+}
+
+// Open reports whether the breaker is holding fetches back
+func (b *CircuitBreaker) Open() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.failures >= b.Threshold
+}
+
+// outage reports whether err suggests upstream is failing rather than the request
+func outage(err error) bool {
+	return !errors.Is(err, ErrUserNotFound) &&
+		!errors.Is(err, ErrProfilePrivate) &&
+		!errors.Is(err, context.Canceled) &&
+		!errors.Is(err, ErrCircuitOpen)
+}
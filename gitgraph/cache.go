@@ -0,0 +1,218 @@
+package gitgraph
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Cache stores fetched payloads by key. Implementations must be safe for concurrent use,
+// so shared backends such as Redis or S3 can be plugged into a Client.
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, val []byte, ttl time.Duration)
+}
+
+// ListableCache is a Cache that can list and delete its entries, for
+// inspecting and invalidating it by hand
+type ListableCache interface {
+	Cache
+	// Items lists the entries that haven't expired, in no particular order
+	Items() ([]CacheItem, error)
+	Delete(key string) error
+}
+
+// CacheItem describes a cache entry
+type CacheItem struct {
+	Key       string     `json:"key"`
+	Size      int        `json:"size"`                // of the value, in bytes
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"` // nil when it never does
+}
+
+// MemoryCache is an in-process Cache
+type MemoryCache struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	// Key lets FileCache list what its hashed file names hold
+	Key       string    `json:"key,omitempty"`
+	Value     []byte    `json:"value"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+func (e cacheEntry) expired() bool {
+	return !e.ExpiresAt.IsZero() && time.Now().After(e.ExpiresAt)
+}
+
+func (e cacheEntry) item() CacheItem {
+	item := CacheItem{Key: e.Key, Size: len(e.Value)}
+	if !e.ExpiresAt.IsZero() {
+		expires := e.ExpiresAt
+		item.ExpiresAt = &expires
+	}
+	return item
+}
+
+func newCacheEntry(key string, val []byte, ttl time.Duration) cacheEntry {
+	entry := cacheEntry{Key: key, Value: val}
+	if ttl > 0 {
+		entry.ExpiresAt = time.Now().Add(ttl)
+	}
+	return entry
+}
+
+// NewMemoryCache creates an empty MemoryCache
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: make(map[string]cacheEntry)}
+}
+
+func (c *MemoryCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if entry.expired() {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.Value, true
+}
+
+// Set stores val under key; a ttl of zero never expires
+func (c *MemoryCache) Set(key string, val []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = newCacheEntry(key, val, ttl)
+}
+
+func (c *MemoryCache) Items() ([]CacheItem, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	items := []CacheItem{}
+	for _, entry := range c.entries {
+		if !entry.expired() {
+			items = append(items, entry.item())
+		}
+	}
+	return items, nil
+}
+
+func (c *MemoryCache) Delete(key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+	return nil
+}
+
+// FileCache is a Cache storing one file per key under Dir
+type FileCache struct {
+	Dir string
+}
+
+// DefaultCacheDir returns the per-user cache directory, $XDG_CACHE_HOME/gitgraphed on Linux
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gitgraphed"), nil
+}
+
+// NewFileCache creates a FileCache rooted at dir
+func NewFileCache(dir string) *FileCache {
+	return &FileCache{Dir: dir}
+}
+
+func (c *FileCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.Dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (c *FileCache) Get(key string) ([]byte, bool) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, false
+	}
+	if entry.expired() {
+		os.Remove(c.path(key))
+		return nil, false
+	}
+	return entry.Value, true
+}
+
+// Set stores val under key; write failures are ignored since the cache is best-effort
+func (c *FileCache) Set(key string, val []byte, ttl time.Duration) {
+	data, err := json.Marshal(newCacheEntry(key, val, ttl))
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(c.Dir, 0o755); err != nil {
+		return
+	}
+
+	// Write to a temp file and rename so concurrent readers never see partial entries
+	tmp, err := os.CreateTemp(c.Dir, ".tmp-*")
+	if err != nil {
+		return
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return
+	}
+	tmp.Close()
+	if err := os.Rename(tmp.Name(), c.path(key)); err != nil {
+		os.Remove(tmp.Name())
+	}
+}
+
+// Items lists the entries whose keys are known: those written before keys
+// were stored in the files stay hidden until they expire
+func (c *FileCache) Items() ([]CacheItem, error) {
+	files, err := os.ReadDir(c.Dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []CacheItem{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	items := []CacheItem{}
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".json" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(c.Dir, file.Name()))
+		if err != nil {
+			continue
+		}
+		var entry cacheEntry
+		if json.Unmarshal(data, &entry) != nil || entry.Key == "" || entry.expired() {
+			continue
+		}
+		items = append(items, entry.item())
+	}
+	return items, nil
+}
+
+func (c *FileCache) Delete(key string) error {
+	if err := os.Remove(c.path(key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
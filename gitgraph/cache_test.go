@@ -0,0 +1,158 @@
+package gitgraph
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCaches(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		cache func(t *testing.T) ListableCache
+	}{
+		{"memory", func(t *testing.T) ListableCache { return NewMemoryCache() }},
+		{"file", func(t *testing.T) ListableCache { return NewFileCache(t.TempDir()) }},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := tt.cache(t)
+			cache.Set("forever", []byte("a"), 0)
+			cache.Set("hour", []byte("bb"), time.Hour)
+			cache.Set("expired", []byte("ccc"), time.Nanosecond)
+			time.Sleep(time.Millisecond)
+
+			for _, get := range []struct {
+				key  string
+				want string
+				ok   bool
+			}{
+				{"forever", "a", true},
+				{"hour", "bb", true},
+				{"expired", "", false},
+				{"missing", "", false},
+			} {
+				if val, ok := cache.Get(get.key); ok != get.ok || string(val) != get.want {
+					t.Errorf("Get(%q) = %q, %v, want %q, %v", get.key, val, ok, get.want, get.ok)
+				}
+			}
+
+			items, err := cache.Items()
+			if err != nil {
+				t.Fatal(err)
+			}
+			sizes := map[string]int{}
+			for _, item := range items {
+				sizes[item.Key] = item.Size
+				if (item.ExpiresAt == nil) != (item.Key == "forever") {
+					t.Errorf("%s expires at %v", item.Key, item.ExpiresAt)
+				}
+			}
+			if len(sizes) != 2 || sizes["forever"] != 1 || sizes["hour"] != 2 {
+				t.Errorf("items = %v, want forever and hour", sizes)
+			}
+
+			if err := cache.Delete("hour"); err != nil {
+				t.Fatal(err)
+			}
+			if err := cache.Delete("missing"); err != nil {
+				t.Errorf("deleting a missing key: %v", err)
+			}
+			if _, ok := cache.Get("hour"); ok {
+				t.Error("deleted key still cached")
+			}
+		})
+	}
+}
+
+func TestFileCacheItemsMissingDir(t *testing.T) {
+	items, err := NewFileCache(t.TempDir() + "/missing").Items()
+	if err != nil || len(items) != 0 {
+		t.Errorf("Items() = %v, %v, want none", items, err)
+	}
+}
+
+func TestConditionalTransport(t *testing.T) {
+	var got []string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get("If-None-Match")+"|"+r.Header.Get("If-Modified-Since"))
+		switch r.URL.Path {
+		case "/etag":
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				w.Header().Set("X-RateLimit-Remaining", "41")
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"v1"`)
+		case "/modified":
+			if r.Header.Get("If-Modified-Since") != "" {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("Last-Modified", "Fri, 01 Mar 2024 00:00:00 GMT")
+		case "/error":
+			w.Header().Set("ETag", `"v1"`)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		io.WriteString(w, "body of "+r.URL.Path)
+	}))
+	defer upstream.Close()
+
+	client := &http.Client{Transport: NewConditionalTransport(http.DefaultTransport, NewMemoryCache())}
+	for _, tt := range []struct {
+		name, method, path string
+		auth               string
+		status             int
+		body               string
+		sent               string // the validators sent upstream
+	}{
+		{"first fetch", "GET", "/etag", "", 200, "body of /etag", "|"},
+		{"revalidated", "GET", "/etag", "", 200, "body of /etag", `"v1"|`},
+		{"other token", "GET", "/etag", "token other", 200, "body of /etag", "|"},
+		{"last modified", "GET", "/modified", "", 200, "body of /modified", "|"},
+		{"modified since", "GET", "/modified", "", 200, "body of /modified", `|Fri, 01 Mar 2024 00:00:00 GMT`},
+		{"no validators", "GET", "/plain", "", 200, "body of /plain", "|"},
+		{"still none", "GET", "/plain", "", 200, "body of /plain", "|"},
+		{"error", "GET", "/error", "", 500, "body of /error", "|"},
+		{"error not stored", "GET", "/error", "", 500, "body of /error", "|"},
+		{"post", "POST", "/etag", "", 200, "body of /etag", "|"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got = nil
+			req, _ := http.NewRequest(tt.method, upstream.URL+tt.path, nil)
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if resp.StatusCode != tt.status || string(body) != tt.body {
+				t.Errorf("got %d %q, want %d %q", resp.StatusCode, body, tt.status, tt.body)
+			}
+			if len(got) != 1 || got[0] != tt.sent {
+				t.Errorf("sent validators %q, want %q", got, tt.sent)
+			}
+			if tt.name == "revalidated" {
+				if resp.Header.Get("Content-Type") != "text/plain" || resp.Header.Get("X-RateLimit-Remaining") != "41" {
+					t.Errorf("replayed headers %v, want the stored type and the 304's rate limit", resp.Header)
+				}
+			}
+		})
+	}
+}
+
+func TestTeeBodyClosedEarly(t *testing.T) {
+	var stored []byte
+	body := &teeBody{ReadCloser: io.NopCloser(bytes.NewReader([]byte("abcdef"))), done: func(b []byte) { stored = b }}
+	body.Read(make([]byte, 2))
+	body.Close()
+	if string(stored) != "abcdef" {
+		t.Errorf("stored %q, want the whole body", stored)
+	}
+}
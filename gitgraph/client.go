@@ -0,0 +1,401 @@
+package gitgraph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/telemetry"
+)
+
+// Options selects the period to fetch. When From and To are zero, the calendar
+// Year is used, defaulting to the current year.
+type Options struct {
+	Year int
+	From time.Time
+	To   time.Time
+}
+
+// Range resolves the options into an inclusive from..to date range
+func (o Options) Range() (time.Time, time.Time) {
+	return o.rangeAt(time.Now())
+}
+
+// rangeAt is Range with now deciding the current year
+func (o Options) rangeAt(now time.Time) (time.Time, time.Time) {
+	if !o.From.IsZero() && !o.To.IsZero() {
+		return o.From, o.To
+	}
+	year := o.Year
+	if year == 0 {
+		year = now.Year()
+	}
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+	return from, to
+}
+
+// Client fetches contribution graphs from a Provider, optionally caching results in a Cache.
+// Without a Provider it uses GitHub, authenticated with Token when one is set.
+type Client struct {
+	HTTPClient *http.Client
+	Cache      Cache
+	CacheTTL   time.Duration
+	Refresh    bool // skip cached entries but still store fresh results
+	Token      string
+	Provider   Provider
+	Logger     *slog.Logger     // receives a debug record per fetch; nil uses slog.Default()
+	BaseURL    string           // GitHub Enterprise Server URL used when Provider is nil
+	Now        func() time.Time // current time for the default year and streaks; nil uses time.Now
+	// Breaker, when set, stops fetching from upstream after repeated failures
+	// and answers from stale cached graphs until it closes again
+	Breaker *CircuitBreaker
+	// Type narrows graphs to one of ContributionTypeNames with OnlyType, which
+	// needs a provider splitting days by type
+	Type string
+	// Coalescer, when set, lets concurrent Fetches of the same graph share
+	// one upstream fetch
+	Coalescer *Coalescer
+
+	userAgent string
+}
+
+// NewClient creates a Client backed by cache; a nil cache disables caching.
+// Requests are retried with backoff, each attempt timing out after 10s without a response.
+func NewClient(cache Cache, opts ...Option) *Client {
+	c := &Client{
+		HTTPClient: &http.Client{
+			Transport: NewRetryTransport(nil, DefaultRetries),
+		},
+		Cache:    cache,
+		CacheTTL: time.Hour,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.userAgent != "" {
+		c.HTTPClient = withUserAgent(c.HTTPClient, c.userAgent)
+	}
+	return c
+}
+
+// Fetch fetches the contribution graph for username using a default, uncached Client
+func Fetch(ctx context.Context, username string, opts Options) (*ContributionGraph, error) {
+	return NewClient(nil).Fetch(ctx, username, opts)
+}
+
+// Fetch fetches the contribution graph for username over the period selected by opts
+func (c *Client) Fetch(ctx context.Context, username string, opts Options) (*ContributionGraph, error) {
+	from, to := opts.rangeAt(c.now())
+	if to.Before(from) {
+		return nil, fmt.Errorf("range ends (%s) before it starts (%s)", to.Format("2006-01-02"), from.Format("2006-01-02"))
+	}
+	// Upstream calendars cover at most a year per request
+	if to.After(from.AddDate(1, 0, -1)) {
+		return c.fetchWindows(ctx, username, from, to)
+	}
+	provider := c.provider()
+	start := time.Now()
+	ctx, span := telemetry.Start(ctx, "gitgraph.Fetch", telemetry.KindInternal,
+		telemetry.String("username", username),
+		telemetry.String("from", from.Format("2006-01-02")),
+		telemetry.String("to", to.Format("2006-01-02")),
+		telemetry.String("provider", provider.Name()),
+	)
+	log := c.logger().With(
+		"username", username,
+		"from", from.Format("2006-01-02"),
+		"to", to.Format("2006-01-02"),
+		"provider", provider.Name(),
+	)
+	if opts.Year != 0 {
+		log = log.With("year", opts.Year)
+	}
+
+	key := graphKey(provider.Name(), username, from, to)
+	if c.Cache != nil && !c.Refresh {
+		if data, ok := c.Cache.Get(key); ok {
+			var graph ContributionGraph
+			if err := json.Unmarshal(data, &graph); err == nil {
+				DefaultMetrics.ObserveCache(true)
+				span.SetAttributes(telemetry.Bool("cached", true))
+				span.End(nil)
+				log.DebugContext(ctx, "fetched graph", "cached", true, "duration", time.Since(start),
+					"cache_hit_ratio", DefaultMetrics.CacheHitRatio())
+				return c.narrow(&graph)
+			}
+		}
+		DefaultMetrics.ObserveCache(false)
+	}
+
+	var graph *ContributionGraph
+	var err error
+	if c.Coalescer != nil {
+		var shared bool
+		graph, shared, err = c.Coalescer.do(ctx, key, func() (*ContributionGraph, error) {
+			return c.fetchUpstream(ctx, provider, key, username, from, to)
+		})
+		if shared {
+			DefaultMetrics.ObserveCoalesced()
+			span.SetAttributes(telemetry.Bool("coalesced", true))
+			log = log.With("coalesced", true)
+		}
+	} else {
+		graph, err = c.fetchUpstream(ctx, provider, key, username, from, to)
+	}
+	if err != nil {
+		log.DebugContext(ctx, "fetching graph failed", "err", err, "duration", time.Since(start))
+		if stale, ok := c.stale(key); ok {
+			log.WarnContext(ctx, "serving stale graph", "err", err)
+			span.SetAttributes(telemetry.Bool("stale", true))
+			span.End(nil)
+			return c.narrow(stale)
+		}
+		span.End(err)
+		return nil, err
+	}
+	span.SetAttributes(telemetry.Bool("cached", false), telemetry.Int("days", len(graph.Days)))
+	span.End(nil)
+	log.DebugContext(ctx, "fetched graph", "cached", false, "days", len(graph.Days), "duration", time.Since(start))
+	return c.narrow(graph)
+}
+
+// fetchUpstream fetches from provider unless the breaker is open, caching
+// the graph under key
+func (c *Client) fetchUpstream(ctx context.Context, provider Provider, key, username string, from, to time.Time) (*ContributionGraph, error) {
+	if c.Breaker != nil && !c.Breaker.Allow() {
+		return nil, ErrCircuitOpen
+	}
+	fetchStart := time.Now()
+	ctx, span := telemetry.Start(ctx, "gitgraph.fetchUpstream", telemetry.KindInternal, telemetry.String("provider", provider.Name()))
+	graph, err := provider.FetchRange(ctx, username, from, to)
+	span.End(err)
+	DefaultMetrics.ObserveFetch(provider.Name(), time.Since(fetchStart), err)
+	if c.Breaker != nil {
+		c.Breaker.Record(err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// HTTP dates have no finer resolution
+	fetchedAt := time.Now().UTC().Truncate(time.Second)
+	graph.FetchedAt = &fetchedAt
+
+	if c.Cache != nil {
+		if data, err := json.Marshal(graph); err == nil {
+			c.Cache.Set(key, data, c.CacheTTL)
+			if c.Breaker != nil {
+				// Outlive the TTL, to be served while upstream is down
+				c.Cache.Set(staleKeyPrefix+key, data, 0)
+			}
+		}
+	}
+	return graph, nil
+}
+
+// staleKeyPrefix marks the cache keys of graphs kept past their TTL for a Breaker
+const staleKeyPrefix = "stale:"
+
+// graphKey is the cache key of username's graph from from to to
+func graphKey(provider, username string, from, to time.Time) string {
+	return fmt.Sprintf("%s:%s/%s/%s", provider, username, from.Format("2006-01-02"), to.Format("2006-01-02"))
+}
+
+// ErrCacheNotListable is returned by the cache administration of a Client
+// whose Cache isn't a ListableCache
+var ErrCacheNotListable = errors.New("cache can't list its entries")
+
+// CachedGraph is a graph held in a Client's cache
+type CachedGraph struct {
+	CacheItem
+	Provider string `json:"provider"`
+	Username string `json:"username"`
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Stale    bool   `json:"stale,omitempty"` // kept past its TTL, to serve while upstream is down
+}
+
+// parseGraphKey reads the graph a cache key names, reporting false for keys
+// of other entries, such as HTTP validators
+func parseGraphKey(item CacheItem) (CachedGraph, bool) {
+	key, stale := strings.CutPrefix(item.Key, staleKeyPrefix)
+	// Provider names can hold colons and slashes, e.g. github:https://host,
+	// so the key is read from the end
+	parts := strings.Split(key, "/")
+	if len(parts) < 3 {
+		return CachedGraph{}, false
+	}
+	from, to := parts[len(parts)-2], parts[len(parts)-1]
+	for _, date := range []string{from, to} {
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return CachedGraph{}, false
+		}
+	}
+	head := strings.Join(parts[:len(parts)-2], "/")
+	i := strings.LastIndex(head, ":")
+	if i <= 0 || i == len(head)-1 {
+		return CachedGraph{}, false
+	}
+	return CachedGraph{CacheItem: item, Provider: head[:i], Username: head[i+1:], From: from, To: to, Stale: stale}, true
+}
+
+// CachedGraphs lists the graphs in the client's cache, ordered by username
+// and then date
+func (c *Client) CachedGraphs() ([]CachedGraph, error) {
+	cache, ok := c.Cache.(ListableCache)
+	if !ok {
+		return nil, ErrCacheNotListable
+	}
+	items, err := cache.Items()
+	if err != nil {
+		return nil, err
+	}
+	graphs := []CachedGraph{}
+	for _, item := range items {
+		if graph, ok := parseGraphKey(item); ok {
+			graphs = append(graphs, graph)
+		}
+	}
+	sort.Slice(graphs, func(i, j int) bool {
+		a, b := graphs[i], graphs[j]
+		if a.Username != b.Username {
+			return strings.ToLower(a.Username) < strings.ToLower(b.Username)
+		}
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		return a.Key < b.Key
+	})
+	return graphs, nil
+}
+
+// Invalidate drops username's cached graphs, including those kept stale, so
+// they are next fetched from upstream. With a year only the graphs covering
+// some of it are dropped. It returns how many were.
+func (c *Client) Invalidate(username string, year int) (int, error) {
+	graphs, err := c.CachedGraphs()
+	if err != nil {
+		return 0, err
+	}
+	cache := c.Cache.(ListableCache)
+	dropped := 0
+	for _, graph := range graphs {
+		// Usernames are case-insensitive upstream, so a graph may be cached under any case
+		if !strings.EqualFold(graph.Username, username) {
+			continue
+		}
+		from, _ := time.Parse("2006-01-02", graph.From)
+		to, _ := time.Parse("2006-01-02", graph.To)
+		if year != 0 && (from.Year() > year || to.Year() < year) {
+			continue
+		}
+		if err := cache.Delete(graph.Key); err != nil {
+			return dropped, err
+		}
+		dropped++
+	}
+	return dropped, nil
+}
+
+// stale returns the last graph fetched for key, marked Stale, when the
+// breaker is open
+func (c *Client) stale(key string) (*ContributionGraph, bool) {
+	if c.Cache == nil || c.Breaker == nil || !c.Breaker.Open() {
+		return nil, false
+	}
+	data, ok := c.Cache.Get(staleKeyPrefix + key)
+	if !ok {
+		return nil, false
+	}
+	var graph ContributionGraph
+	if err := json.Unmarshal(data, &graph); err != nil {
+		return nil, false
+	}
+	graph.Stale = true
+	return &graph, true
+}
+
+// narrow applies c.Type to a freshly fetched or cached graph and computes its streaks
+func (c *Client) narrow(graph *ContributionGraph) (*ContributionGraph, error) {
+	if c.Type != "" {
+		if err := graph.OnlyType(c.Type); err != nil {
+			return nil, err
+		}
+	}
+	return c.withStreaks(graph), nil
+}
+
+// fetchWindows fetches a range longer than a year as consecutive year-long windows
+func (c *Client) fetchWindows(ctx context.Context, username string, from, to time.Time) (*ContributionGraph, error) {
+	graphs := []*ContributionGraph{}
+	for start := from; !start.After(to); start = start.AddDate(1, 0, 0) {
+		end := start.AddDate(1, 0, -1)
+		if end.After(to) {
+			end = to
+		}
+		graph, err := c.Fetch(ctx, username, Options{From: start, To: end})
+		if err != nil {
+			return nil, err
+		}
+		graphs = append(graphs, graph)
+	}
+	return c.withStreaks(MergeGraphs(graphs...)), nil
+}
+
+func (c *Client) logger() *slog.Logger {
+	if c.Logger != nil {
+		return c.Logger
+	}
+	return slog.Default()
+}
+
+func (c *Client) provider() Provider {
+	if c.Provider != nil {
+		return c.Provider
+	}
+	return NewGitHub(c.HTTPClient, c.BaseURL, c.Token)
+}
+
+// GitHub returns the GitHub instance used for APIs beyond contributions, like org membership
+func (c *Client) GitHub() *GitHub {
+	if gh, ok := c.Provider.(*GitHub); ok {
+		return gh
+	}
+	return NewGitHub(c.HTTPClient, c.BaseURL, c.Token)
+}
+
+// withStreaks computes the graph's streaks as of the client's now, and numbers
+// its calendar columns from its first day
+func (c *Client) withStreaks(graph *ContributionGraph) *ContributionGraph {
+	graph.numberGridWeeks(time.Sunday)
+	graph.UpdateStreaks(c.now())
+	return graph
+}
+
+// CurrentTime is the time by the client's clock, Now when set, for
+// recomputing what depends on today after changing a fetched graph
+func (c *Client) CurrentTime() time.Time {
+	return c.now()
+}
+
+func (c *Client) now() time.Time {
+	if c.Now != nil {
+		return c.Now()
+	}
+	return time.Now()
+}
+
+// yearsBetween lists every calendar year touched by the range from..to
+func yearsBetween(from, to time.Time) []int {
+	years := []int{}
+	for y := from.Year(); y <= to.Year(); y++ {
+		years = append(years, y)
+	}
+	return years
+}
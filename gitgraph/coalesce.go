@@ -0,0 +1,75 @@
+package gitgraph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+)
+
+// Coalescer shares one upstream fetch between concurrent Fetches of the same
+// graph, so a burst of requests for a popular badge scrapes it only once. It
+// is safe for concurrent use.
+type Coalescer struct {
+	mu      sync.Mutex
+	flights map[string]*flight
+}
+
+// flight is a fetch in progress, its result kept as JSON for each waiter to
+// decode a copy of its own
+type flight struct {
+	done chan struct{}
+	data []byte
+	err  error
+}
+
+// NewCoalescer creates a Coalescer with no fetches in flight
+func NewCoalescer() *Coalescer {
+	return &Coalescer{flights: make(map[string]*flight)}
+}
+
+// do calls fetch for key unless a call for key is already in flight, in which
+// case it waits for that call's result instead, reporting it shared
+func (c *Coalescer) do(ctx context.Context, key string, fetch func() (*ContributionGraph, error)) (graph *ContributionGraph, shared bool, err error) {
+	for {
+		c.mu.Lock()
+		f, ok := c.flights[key]
+		if !ok {
+			break
+		}
+		c.mu.Unlock()
+		select {
+		case <-f.done:
+		case <-ctx.Done():
+			return nil, true, ctx.Err()
+		}
+		// The caller that went upstream gave up, not this one, so try again
+		if (errors.Is(f.err, context.Canceled) || errors.Is(f.err, context.DeadlineExceeded)) && ctx.Err() == nil {
+			continue
+		}
+		if f.err != nil {
+			return nil, true, f.err
+		}
+		var copied ContributionGraph
+		if err := json.Unmarshal(f.data, &copied); err != nil {
+			return nil, true, err
+		}
+		return &copied, true, nil
+	}
+	f := &flight{done: make(chan struct{})}
+	c.flights[key] = f
+	c.mu.Unlock()
+
+	graph, err = fetch()
+	if err == nil {
+		// Encoded before anyone can change graph, which is the caller's own
+		f.data, f.err = json.Marshal(graph)
+	} else {
+		f.err = err
+	}
+	c.mu.Lock()
+	delete(c.flights, key)
+	c.mu.Unlock()
+	close(f.done)
+	return graph, false, err
+}
@@ -0,0 +1,135 @@
+package gitgraph
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCoalescer(t *testing.T) {
+	errUpstream := errors.New("upstream failed")
+	for _, tt := range []struct {
+		name    string
+		err     error // returned by the upstream fetch
+		wantErr error // seen by the callers sharing it
+	}{
+		{"shared graph", nil, nil},
+		{"shared error", errUpstream, errUpstream},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCoalescer()
+			release := make(chan struct{})
+			var calls atomic.Int32
+			fetch := func() (*ContributionGraph, error) {
+				calls.Add(1)
+				<-release
+				if tt.err != nil {
+					return nil, tt.err
+				}
+				return &ContributionGraph{Username: "alice", TotalContribs: 3}, nil
+			}
+
+			const callers = 5
+			var wg sync.WaitGroup
+			var sharedCount atomic.Int32
+			graphs := make([]*ContributionGraph, callers)
+			for i := range callers {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					graph, shared, err := c.do(context.Background(), "alice", fetch)
+					if !errors.Is(err, tt.wantErr) {
+						t.Errorf("err = %v, want %v", err, tt.wantErr)
+					}
+					if shared {
+						sharedCount.Add(1)
+					}
+					graphs[i] = graph
+				}()
+			}
+			waitFlight(t, c, "alice")
+			close(release)
+			wg.Wait()
+
+			if calls.Load() != 1 || sharedCount.Load() != callers-1 {
+				t.Errorf("%d fetches, %d shared, want 1 and %d", calls.Load(), sharedCount.Load(), callers-1)
+			}
+			if tt.err != nil {
+				return
+			}
+			for i, graph := range graphs {
+				if graph == nil || graph.TotalContribs != 3 {
+					t.Fatalf("caller %d got %+v", i, graph)
+				}
+				for _, other := range graphs[:i] {
+					if graph == other {
+						t.Error("callers share a graph instead of copies")
+					}
+				}
+			}
+		})
+	}
+}
+
+// waitFlight waits for key's fetch to go upstream, then a little longer for
+// the callers started with it to queue behind it
+func waitFlight(t *testing.T, c *Coalescer, key string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		c.mu.Lock()
+		_, ok := c.flights[key]
+		c.mu.Unlock()
+		if ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("no fetch in flight")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+}
+
+func TestCoalescerRetriesCancelledFlight(t *testing.T) {
+	c := NewCoalescer()
+	release := make(chan struct{})
+	go c.do(context.Background(), "alice", func() (*ContributionGraph, error) {
+		<-release
+		return nil, context.Canceled
+	})
+	waitFlight(t, c, "alice")
+
+	// The first caller giving up sends the waiter upstream itself
+	done := make(chan error)
+	go func() {
+		graph, shared, err := c.do(context.Background(), "alice", func() (*ContributionGraph, error) {
+			return &ContributionGraph{Username: "alice"}, nil
+		})
+		if err == nil && (graph == nil || shared) {
+			err = errors.New("retried fetch not its own")
+		}
+		done <- err
+	}()
+	close(release)
+	if err := <-done; err != nil {
+		t.Error(err)
+	}
+
+	// A waiter that gives up returns at once
+	block := make(chan struct{})
+	defer close(block)
+	go c.do(context.Background(), "bob", func() (*ContributionGraph, error) {
+		<-block
+		return nil, nil
+	})
+	waitFlight(t, c, "bob")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, _, err := c.do(ctx, "bob", nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want the waiter's deadline", err)
+	}
+}
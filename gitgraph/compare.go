@@ -0,0 +1,70 @@
+package gitgraph
+
+import (
+	"sort"
+	"time"
+)
+
+// Comparison lines up several users' graphs over the same period
+type Comparison struct {
+	Usernames []string        `json:"usernames"`
+	Summaries []UserSummary   `json:"summaries"`
+	Days      []ComparisonDay `json:"days"`
+}
+
+// UserSummary holds one user's headline numbers. Deltas are relative to the
+// first user in the comparison.
+type UserSummary struct {
+	Username       string `json:"username"`
+	Total          int    `json:"totalContributions"`
+	TotalDelta     int    `json:"totalDelta"`
+	CurrentStreak  int    `json:"currentStreak"`
+	LongestStreak  int    `json:"longestStreak"`
+	StreakDelta    int    `json:"longestStreakDelta"`
+	BusiestWeekday string `json:"busiestWeekday"`
+}
+
+// ComparisonDay holds each user's count and level for one date, in Usernames order
+type ComparisonDay struct {
+	Date   string `json:"date"`
+	Counts []int  `json:"counts"`
+	Levels []int  `json:"levels"`
+}
+
+// Compare aligns graphs by date and summarises each user against the first
+func Compare(graphs []*ContributionGraph, today time.Time) *Comparison {
+	cmp := &Comparison{Usernames: []string{}, Summaries: []UserSummary{}, Days: []ComparisonDay{}}
+	byDate := make(map[string]*ComparisonDay)
+
+	for i, graph := range graphs {
+		cmp.Usernames = append(cmp.Usernames, graph.Username)
+
+		streaks := ComputeStreaks(graph.Days, today)
+		summary := UserSummary{
+			Username:       graph.Username,
+			Total:          graph.TotalContribs,
+			CurrentStreak:  streaks.Current.Length,
+			LongestStreak:  streaks.Longest.Length,
+			BusiestWeekday: BusiestWeekday(graph.Days).String(),
+		}
+		if i > 0 {
+			summary.TotalDelta = summary.Total - cmp.Summaries[0].Total
+			summary.StreakDelta = summary.LongestStreak - cmp.Summaries[0].LongestStreak
+		}
+		cmp.Summaries = append(cmp.Summaries, summary)
+
+		for _, day := range graph.Days {
+			if _, ok := byDate[day.Date]; !ok {
+				byDate[day.Date] = &ComparisonDay{Date: day.Date, Counts: make([]int, len(graphs)), Levels: make([]int, len(graphs))}
+			}
+			byDate[day.Date].Counts[i] = day.Count
+			byDate[day.Date].Levels[i] = day.Level
+		}
+	}
+
+	for _, day := range byDate {
+		cmp.Days = append(cmp.Days, *day)
+	}
+	sort.Slice(cmp.Days, func(i, j int) bool { return cmp.Days[i].Date < cmp.Days[j].Date })
+	return cmp
+}
@@ -0,0 +1,150 @@
+package gitgraph
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// DefaultValidatorTTL is how long responses are kept for revalidation by default
+const DefaultValidatorTTL = 30 * 24 * time.Hour
+
+// ConditionalTransport remembers the ETag and Last-Modified validators of GET responses
+// and sends them as If-None-Match/If-Modified-Since on later requests, answering a 304
+// with the stored body so callers parse it as if it had been fetched again
+type ConditionalTransport struct {
+	Base  http.RoundTripper
+	Cache Cache
+	TTL   time.Duration
+}
+
+// NewConditionalTransport wraps base (or the NewTransport default) to revalidate through cache
+func NewConditionalTransport(base http.RoundTripper, cache Cache) *ConditionalTransport {
+	if base == nil {
+		base, _ = NewTransport("")
+	}
+	return &ConditionalTransport{Base: base, Cache: cache, TTL: DefaultValidatorTTL}
+}
+
+// validatedResponse is the part of a response needed to replay it after a 304
+type validatedResponse struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+	ContentType  string `json:"contentType,omitempty"`
+	Body         []byte `json:"body"`
+}
+
+func (t *ConditionalTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.Cache == nil || req.Method != http.MethodGet || req.Header.Get("Range") != "" {
+		return t.Base.RoundTrip(req)
+	}
+
+	key := validatorKey(req)
+	var stored validatedResponse
+	cached := false
+	if data, ok := t.Cache.Get(key); ok && json.Unmarshal(data, &stored) == nil {
+		cached = stored.ETag != "" || stored.LastModified != ""
+	}
+	if cached {
+		req = req.Clone(req.Context())
+		if stored.ETag != "" {
+			req.Header.Set("If-None-Match", stored.ETag)
+		}
+		if stored.LastModified != "" {
+			req.Header.Set("If-Modified-Since", stored.LastModified)
+		}
+	}
+
+	resp, err := t.Base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if cached && resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		return stored.response(req, resp), nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+
+	etag, lastModified := resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+	if etag == "" && lastModified == "" {
+		return resp, nil
+	}
+	// The body is stored once the caller has read all of it, so parsing
+	// starts with the first bytes instead of waiting for the last
+	contentType := resp.Header.Get("Content-Type")
+	resp.Body = &teeBody{ReadCloser: resp.Body, done: func(body []byte) {
+		data, err := json.Marshal(validatedResponse{
+			ETag:         etag,
+			LastModified: lastModified,
+			ContentType:  contentType,
+			Body:         body,
+		})
+		if err == nil {
+			t.Cache.Set(key, data, t.TTL)
+		}
+	}}
+	return resp, nil
+}
+
+// teeBody copies a response body as it is read, handing the copy to done on
+// reaching the end. Closing it first reads the rest, as decoders may stop
+// short of the end.
+type teeBody struct {
+	io.ReadCloser
+	buf  bytes.Buffer
+	done func(body []byte)
+}
+
+func (b *teeBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.buf.Write(p[:n])
+	if err == io.EOF && b.done != nil {
+		b.done(b.buf.Bytes())
+		b.done = nil
+	}
+	return n, err
+}
+
+func (b *teeBody) Close() error {
+	if b.done != nil {
+		if _, err := io.Copy(&b.buf, b.ReadCloser); err == nil {
+			b.done(b.buf.Bytes())
+		}
+		b.done = nil
+	}
+	return b.ReadCloser.Close()
+}
+
+// response rebuilds a 200 from the stored body, keeping the headers of the 304
+// so rate limit information stays current
+func (v validatedResponse) response(req *http.Request, notModified *http.Response) *http.Response {
+	header := notModified.Header.Clone()
+	if v.ContentType != "" {
+		header.Set("Content-Type", v.ContentType)
+	}
+	header.Set("Content-Length", strconv.Itoa(len(v.Body)))
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         notModified.Proto,
+		ProtoMajor:    notModified.ProtoMajor,
+		ProtoMinor:    notModified.ProtoMinor,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(v.Body)),
+		ContentLength: int64(len(v.Body)),
+		Request:       req,
+	}
+}
+
+// validatorKey identifies a request by URL and credentials, since responses can differ per token
+func validatorKey(req *http.Request) string {
+	sum := sha256.Sum256([]byte(req.Header.Get("Authorization")))
+	return "http:" + req.URL.String() + ":" + hex.EncodeToString(sum[:8])
+}
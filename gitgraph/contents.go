@@ -0,0 +1,71 @@
+package gitgraph
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// PutFile creates or updates path in repo ("owner/name") with content through the
+// contents API, committing with message. An empty branch uses the default branch.
+func (g *GitHub) PutFile(ctx context.Context, repo, path, branch, message string, content []byte) error {
+	if g.Token == "" {
+		return fmt.Errorf("committing files requires a token")
+	}
+	owner, name, ok := strings.Cut(repo, "/")
+	if !ok || owner == "" || name == "" {
+		return fmt.Errorf("invalid repository %q, expected owner/name", repo)
+	}
+	endpoint := fmt.Sprintf("%s/repos/%s/%s/contents/%s", g.restEndpoint(), url.PathEscape(owner), url.PathEscape(name), strings.TrimLeft(path, "/"))
+
+	// Updating an existing file requires its current blob SHA
+	var existing struct {
+		SHA string `json:"sha"`
+	}
+	lookup := endpoint
+	if branch != "" {
+		lookup += "?ref=" + url.QueryEscape(branch)
+	}
+	var status *StatusError
+	if _, err := getJSON(ctx, g.HTTPClient, g.Token, lookup, &existing); err != nil && !(errors.As(err, &status) && status.Code == http.StatusNotFound) {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	update := map[string]string{
+		"message": message,
+		"content": base64.StdEncoding.EncodeToString(content),
+	}
+	if existing.SHA != "" {
+		update["sha"] = existing.SHA
+	}
+	if branch != "" {
+		update["branch"] = branch
+	}
+	body, err := json.Marshal(update)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, "PUT", endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+g.Token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := g.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return newStatusError(resp)
+	}
+	return nil
+}
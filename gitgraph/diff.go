@@ -0,0 +1,62 @@
+package gitgraph
+
+import (
+	"sort"
+	"time"
+)
+
+// DayChange records a day whose count differs between two snapshots
+type DayChange struct {
+	Date   string `json:"date"`
+	Before int    `json:"before"`
+	After  int    `json:"after"`
+}
+
+// DiffDays lists the days whose counts changed from old to new, in date order.
+// Days missing from one snapshot are treated as having zero contributions.
+func DiffDays(old, new *ContributionGraph) []DayChange {
+	before := make(map[string]int, len(old.Days))
+	for _, day := range old.Days {
+		before[day.Date] = day.Count
+	}
+	after := make(map[string]int, len(new.Days))
+	for _, day := range new.Days {
+		after[day.Date] = day.Count
+	}
+
+	changes := []DayChange{}
+	for date, count := range after {
+		if before[date] != count {
+			changes = append(changes, DayChange{Date: date, Before: before[date], After: count})
+		}
+	}
+	for date, count := range before {
+		if _, ok := after[date]; !ok && count != 0 {
+			changes = append(changes, DayChange{Date: date, Before: count})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Date < changes[j].Date })
+	return changes
+}
+
+// GraphDiff summarises how a graph changed between two snapshots
+type GraphDiff struct {
+	Username      string      `json:"username"`
+	Changes       []DayChange `json:"changes"`
+	TotalBefore   int         `json:"totalBefore"`
+	TotalAfter    int         `json:"totalAfter"`
+	StreaksBefore Streaks     `json:"streaksBefore"`
+	StreaksAfter  Streaks     `json:"streaksAfter"`
+}
+
+// Diff compares two snapshots of a graph, computing streaks as of today
+func Diff(old, new *ContributionGraph, today time.Time) GraphDiff {
+	return GraphDiff{
+		Username:      new.Username,
+		Changes:       DiffDays(old, new),
+		TotalBefore:   old.TotalContribs,
+		TotalAfter:    new.TotalContribs,
+		StreaksBefore: ComputeStreaks(old.Days, today),
+		StreaksAfter:  ComputeStreaks(new.Days, today),
+	}
+}
@@ -0,0 +1,54 @@
+package gitgraph
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Errors that fetches may wrap, so callers can tell failures apart with errors.Is
+var (
+	// ErrUserNotFound means the user, or the organization, doesn't exist upstream
+	ErrUserNotFound = errors.New("user not found")
+	// ErrProfilePrivate means the user exists but keeps their activity private,
+	// so there is no calendar to read
+	ErrProfilePrivate = errors.New("profile is private")
+	// ErrRateLimited means upstream refused the request until a rate limit resets
+	ErrRateLimited = errors.New("rate limited")
+	// ErrParse means the response couldn't be understood, e.g. after a markup change
+	ErrParse = errors.New("unexpected response")
+)
+
+// StatusError is returned for unexpected HTTP response codes
+type StatusError struct {
+	Code int
+	// RateLimited is set for a 429, or a 403 reporting no remaining requests as
+	// GitHub sends when the primary rate limit is exhausted
+	RateLimited bool
+}
+
+func newStatusError(resp *http.Response) *StatusError {
+	limited := resp.StatusCode == http.StatusTooManyRequests ||
+		resp.StatusCode == http.StatusForbidden && resp.Header.Get("X-RateLimit-Remaining") == "0"
+	return &StatusError{Code: resp.StatusCode, RateLimited: limited}
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("HTTP request failed with status code: %d", e.Code)
+}
+
+// Is matches ErrUserNotFound for a 404 and ErrRateLimited for rate limit responses
+func (e *StatusError) Is(target error) bool {
+	switch target {
+	case ErrUserNotFound:
+		return e.Code == http.StatusNotFound
+	case ErrRateLimited:
+		return e.RateLimited
+	}
+	return false
+}
+
+// parseError wraps err as an ErrParse
+func parseError(err error) error {
+	return fmt.Errorf("%w: %v", ErrParse, err)
+}
@@ -0,0 +1,154 @@
+package gitgraph
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"sort"
+	"time"
+)
+
+// Event is one entry of a user's activity as returned by the Events API
+type Event struct {
+	Type string `json:"type"`
+	Repo struct {
+		Name string `json:"name"` // owner/name
+	} `json:"repo"`
+	CreatedAt time.Time `json:"created_at"`
+	Payload   struct {
+		Action string `json:"action"`
+		Size   int    `json:"size"` // commits in a PushEvent
+	} `json:"payload"`
+}
+
+// Contributions is how many contributions the event stands for: the commits
+// of a push, one for opening a pull request or issue or submitting a review,
+// and none for other activity such as stars and comments
+func (e Event) Contributions() int {
+	switch e.Type {
+	case "PushEvent":
+		// Pushes without a size still carried at least one commit
+		return max(e.Payload.Size, 1)
+	case "PullRequestEvent", "IssuesEvent":
+		if e.Payload.Action == "opened" {
+			return 1
+		}
+	case "PullRequestReviewEvent":
+		return 1
+	}
+	return 0
+}
+
+// Events returns username's events since the given time, newest first. The
+// API only keeps the past 90 days, and at most 300 events; with the user's
+// own token private activity is included.
+func (g *GitHub) Events(ctx context.Context, username string, since time.Time) ([]Event, error) {
+	if g.Token == "" {
+		return nil, fmt.Errorf("reading events requires a token")
+	}
+	return g.events(ctx, username, since)
+}
+
+// events is Events for any client, reading only public events without a token
+func (g *GitHub) events(ctx context.Context, username string, since time.Time) ([]Event, error) {
+	var events []Event
+	next := fmt.Sprintf("%s/users/%s/events?per_page=100", g.restEndpoint(), url.PathEscape(username))
+	for next != "" {
+		var page []Event
+		var remaining int
+		var err error
+		if next, remaining, err = getJSONPage(ctx, g.HTTPClient, g.Token, next, &page); err != nil {
+			return nil, err
+		}
+		for _, event := range page {
+			if event.CreatedAt.Before(since) {
+				return events, nil
+			}
+			events = append(events, event)
+		}
+		if next != "" && len(events) > 0 && remaining >= 0 && remaining < eventsRateReserve {
+			slog.WarnContext(ctx, "stopping at older events to stay within the rate limit",
+				"username", username, "remaining", remaining, "oldest", events[len(events)-1].CreatedAt)
+			return events, nil
+		}
+	}
+	return events, nil
+}
+
+// eventsWindow is how far back the Events API keeps events
+const eventsWindow = 90 * 24 * time.Hour
+
+// eventsRange approximates the contributions between from and to from the
+// user's events, dated in g.Location, for when the calendar can't be read.
+// It returns an error when the range ends before the events kept.
+func (g *GitHub) eventsRange(ctx context.Context, username string, from, to time.Time) (*ContributionGraph, error) {
+	loc := orUTC(g.Location)
+	oldest := time.Now().Add(-eventsWindow)
+	if midnight(to, loc).AddDate(0, 0, 1).Before(oldest) {
+		return nil, fmt.Errorf("events only reach back to %s", oldest.In(loc).Format("2006-01-02"))
+	}
+	since := midnight(from, loc)
+	if since.Before(oldest) {
+		since = oldest
+	}
+	events, err := g.events(ctx, username, since)
+	if err != nil {
+		return nil, err
+	}
+	counts := map[string]int{}
+	for _, event := range events {
+		if n := event.Contributions(); n > 0 {
+			counts[event.CreatedAt.In(loc).Format("2006-01-02")] += n
+		}
+	}
+	graph := GraphFromCounts(username, counts, from, to, QuartileThresholds(counts))
+	graph.Approximate = true
+	graph.TimeZone = loc.String()
+	return graph, nil
+}
+
+// eventsRateReserve is how many requests Events leaves in the rate limit for
+// the rest of a command, rather than paging further back
+const eventsRateReserve = 50
+
+// DayActivity is what a day's events touched
+type DayActivity struct {
+	Repositories []string       `json:"repositories"` // owner/name, sorted
+	Events       map[string]int `json:"events"`       // counts by event type, e.g. PushEvent
+}
+
+// Enrich annotates each day from since on with the repositories and types of
+// its events, dated in loc. Days before since are left without activity,
+// telling them apart from days without events.
+func (g *ContributionGraph) Enrich(events []Event, since time.Time, loc *time.Location) {
+	byDate := map[string]*DayActivity{}
+	repos := map[string]map[string]bool{}
+	for _, event := range events {
+		date := event.CreatedAt.In(loc).Format("2006-01-02")
+		activity, ok := byDate[date]
+		if !ok {
+			activity = &DayActivity{Repositories: []string{}, Events: map[string]int{}}
+			byDate[date] = activity
+			repos[date] = map[string]bool{}
+		}
+		activity.Events[event.Type]++
+		if name := event.Repo.Name; name != "" && !repos[date][name] {
+			repos[date][name] = true
+			activity.Repositories = append(activity.Repositories, name)
+		}
+	}
+
+	first := since.In(loc).Format("2006-01-02")
+	for i, day := range g.Days {
+		if day.Date < first {
+			continue
+		}
+		activity, ok := byDate[day.Date]
+		if !ok {
+			activity = &DayActivity{Repositories: []string{}, Events: map[string]int{}}
+		}
+		sort.Strings(activity.Repositories)
+		g.Days[i].Activity = activity
+	}
+}
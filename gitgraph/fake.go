@@ -0,0 +1,128 @@
+package gitgraph
+
+import (
+	"context"
+	"hash/fnv"
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"time"
+)
+
+// Fake makes up contributions instead of fetching them, for demos and for
+// developing themes, renderers and integrations offline. Each day's count
+// only depends on Seed, the username and the date, so a graph comes out the
+// same on every run and overlapping ranges agree. Levels are quartiles of
+// the range fetched, as on GitHub.
+type Fake struct {
+	Seed int64
+	// Now is the current time, after which nothing is made up; nil uses
+	// time.Now. Give it the Client's clock to keep the two in step.
+	Now func() time.Time
+}
+
+// NewFake creates a Fake provider generating from seed
+func NewFake(seed int64) *Fake {
+	return &Fake{Seed: seed}
+}
+
+func (f *Fake) Name() string {
+	return "fake:" + strconv.FormatInt(f.Seed, 10)
+}
+
+func (f *Fake) FetchRange(ctx context.Context, username string, from, to time.Time) (*ContributionGraph, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	habits := f.habits(username)
+	counts := make(map[string]int)
+	// Made-up users start in their first year and don't contribute ahead of time
+	start, end := midnight(from, time.UTC), midnight(to, time.UTC)
+	if first := time.Date(habits.since, 1, 1, 0, 0, 0, 0, time.UTC); start.Before(first) {
+		start = first
+	}
+	if today := midnight(f.now(), time.UTC); end.After(today) {
+		end = today
+	}
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		if count := habits.count(f.rand(username, d, 0), f.rand(username, weekOf(d), 1), d); count > 0 {
+			counts[d.Format("2006-01-02")] = count
+		}
+	}
+	return GraphFromCounts(username, counts, midnight(from, time.UTC), midnight(to, time.UTC), QuartileThresholds(counts)), nil
+}
+
+func (f *Fake) now() time.Time {
+	if f.Now != nil {
+		return f.Now()
+	}
+	return time.Now()
+}
+
+// fakeHabits are how a made-up user works, drawn once per seed and username
+// so each user looks different but consistent
+type fakeHabits struct {
+	rate     float64 // mean contributions of an active day
+	active   float64 // chance a weekday has any
+	weekends float64 // weekend activity relative to weekdays
+	phase    float64 // where in the year the busy season falls
+	since    int     // first year with contributions
+}
+
+func (f *Fake) habits(username string) fakeHabits {
+	r := f.rand(username, time.Time{}, 2)
+	return fakeHabits{
+		rate:     1.5 + r.Float64()*6,
+		active:   0.45 + r.Float64()*0.45,
+		weekends: 0.1 + r.Float64()*0.6,
+		phase:    r.Float64() * 2 * math.Pi,
+		since:    2008 + r.IntN(15),
+	}
+}
+
+// FirstYear returns the year username's made-up contributions start in
+func (f *Fake) FirstYear(username string) int {
+	return f.habits(username).since
+}
+
+// count draws date's contributions, with day and week drawing that day's
+// and its week's randomness. Weeks are quiet, ordinary or busy, and some
+// are holidays without any contributions, so the calendar shows streaks
+// and gaps like a real one.
+func (h fakeHabits) count(day, week *rand.Rand, date time.Time) int {
+	pace := 1.0
+	switch w := week.Float64(); {
+	case w < 0.06:
+		return 0
+	case w < 0.25:
+		pace = 0.4
+	case w > 0.85:
+		pace = 1.8
+	}
+	// Activity rises and falls over the year
+	pace *= 1 + 0.35*math.Sin(2*math.Pi*float64(date.YearDay())/365+h.phase)
+
+	active := h.active * min(pace, 1.5)
+	if weekday := date.Weekday(); weekday == time.Saturday || weekday == time.Sunday {
+		active *= h.weekends
+	}
+	if day.Float64() >= active {
+		return 0
+	}
+	// Active days have a long tail, mostly a few contributions and sometimes many
+	return 1 + int(day.ExpFloat64()*h.rate*pace)
+}
+
+// rand returns the randomness of username at date, for the given use, the
+// same every time it is asked for
+func (f *Fake) rand(username string, date time.Time, use uint64) *rand.Rand {
+	h := fnv.New64a()
+	h.Write([]byte(username))
+	key := h.Sum64() ^ uint64(date.Unix())*0x9e3779b97f4a7c15 ^ use
+	return rand.New(rand.NewPCG(uint64(f.Seed), key))
+}
+
+// weekOf is the Sunday starting date's week
+func weekOf(date time.Time) time.Time {
+	return date.AddDate(0, 0, -int(date.Weekday()))
+}
@@ -0,0 +1,85 @@
+package gitgraph
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+)
+
+// FixtureTransport answers every request with the contents of a saved response,
+// so providers can be run against a recorded page or API reply without network
+// access. A .html fixture replays a scraped contributions page, a .json one an
+// API response.
+type FixtureTransport struct {
+	Path string
+}
+
+func (t *FixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	body, err := os.ReadFile(t.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make(http.Header)
+	if contentType := mime.TypeByExtension(filepath.Ext(t.Path)); contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+	header.Set("Content-Length", strconv.Itoa(len(body)))
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// RecordTransport saves the body of every successful response to Path, so a later
+// run can replay it with FixtureTransport. Each response replaces the previous one.
+type RecordTransport struct {
+	Base http.RoundTripper
+	Path string
+
+	mu sync.Mutex
+}
+
+// NewRecordTransport records the responses of base (or the NewTransport default) to path
+func NewRecordTransport(base http.RoundTripper, path string) *RecordTransport {
+	if base == nil {
+		base, _ = NewTransport("")
+	}
+	return &RecordTransport{Base: base, Path: path}
+}
+
+func (t *RecordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.Base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err := os.WriteFile(t.Path, body, 0o644); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
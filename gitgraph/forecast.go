@@ -0,0 +1,70 @@
+package gitgraph
+
+import (
+	"math"
+	"time"
+)
+
+// Forecast projects the total of a year in progress from its contributions so far
+type Forecast struct {
+	Year            int `json:"year"`
+	YearToDate      int `json:"yearToDate"`
+	DaysElapsed     int `json:"daysElapsed"` // including today
+	DaysRemaining   int `json:"daysRemaining"`
+	Linear          int `json:"linear"`          // at the daily mean so far
+	WeekdayWeighted int `json:"weekdayWeighted"` // at each weekday's mean so far
+	Goal            int `json:"goal,omitempty"`
+	// RequiredPace is the daily count needed over the remaining days to reach Goal
+	RequiredPace float64 `json:"requiredDailyPace,omitempty"`
+}
+
+// ForecastYear projects the total of today's calendar year from the days up to and
+// including today, with the pace needed to reach goal when it is positive. It
+// returns false when days cover none of the year so far.
+func ForecastYear(days []ContributionDay, today time.Time, goal int) (Forecast, bool) {
+	year := today.Year()
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+	todayDate := time.Date(year, today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+
+	f := Forecast{Year: year, Goal: goal}
+	var weekdayTotals [7]int
+	covered := false
+	for _, day := range days {
+		date, err := parseDate(day.Date)
+		if err != nil || date.Before(start) || date.After(todayDate) {
+			continue
+		}
+		covered = true
+		f.YearToDate += day.Count
+		weekdayTotals[date.Weekday()] += day.Count
+	}
+	if !covered {
+		return f, false
+	}
+
+	var weekdaysElapsed [7]int
+	for d := start; !d.After(todayDate); d = d.AddDate(0, 0, 1) {
+		weekdaysElapsed[d.Weekday()]++
+		f.DaysElapsed++
+	}
+	mean := float64(f.YearToDate) / float64(f.DaysElapsed)
+
+	weighted := float64(f.YearToDate)
+	for d := todayDate.AddDate(0, 0, 1); !d.After(end); d = d.AddDate(0, 0, 1) {
+		f.DaysRemaining++
+		// Early in January some weekdays haven't come round yet
+		if n := weekdaysElapsed[d.Weekday()]; n > 0 {
+			weighted += float64(weekdayTotals[d.Weekday()]) / float64(n)
+		} else {
+			weighted += mean
+		}
+	}
+	f.Linear = int(math.Round(mean * float64(f.DaysElapsed+f.DaysRemaining)))
+	f.WeekdayWeighted = int(math.Round(weighted))
+
+	if goal > f.YearToDate && f.DaysRemaining > 0 {
+		f.RequiredPace = float64(goal-f.YearToDate) / float64(f.DaysRemaining)
+	}
+	return f, true
+}
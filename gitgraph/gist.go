@@ -0,0 +1,121 @@
+package gitgraph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Gist is a gist as returned by the gists API
+type Gist struct {
+	ID          string              `json:"id"`
+	Description string              `json:"description"`
+	HTMLURL     string              `json:"html_url"`
+	Files       map[string]GistFile `json:"files"`
+}
+
+// GistFile is one file of a gist
+type GistFile struct {
+	Filename string `json:"filename"`
+	RawURL   string `json:"raw_url"`
+}
+
+// LatestRawURL is the file's raw URL without the revision, which always
+// serves the newest content and so can be hotlinked across updates
+func (f GistFile) LatestRawURL() string {
+	// Raw URLs look like .../<owner>/<id>/raw/<revision>/<filename>
+	prefix, rest, ok := strings.Cut(f.RawURL, "/raw/")
+	if !ok {
+		return f.RawURL
+	}
+	if _, name, ok := strings.Cut(rest, "/"); ok {
+		return prefix + "/raw/" + name
+	}
+	return f.RawURL
+}
+
+// FindGist returns the authenticated user's gist with description, or nil
+// when there is none
+func (g *GitHub) FindGist(ctx context.Context, description string) (*Gist, error) {
+	if g.Token == "" {
+		return nil, fmt.Errorf("finding gists requires a token")
+	}
+	next := g.restEndpoint() + "/gists?per_page=100"
+	for next != "" {
+		var gists []Gist
+		var err error
+		if next, err = getJSON(ctx, g.HTTPClient, g.Token, next, &gists); err != nil {
+			return nil, err
+		}
+		for _, gist := range gists {
+			if gist.Description == description {
+				return &gist, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// PutGist updates the gist id with files, or creates a secret gist with
+// description when id is empty, and returns the gist as stored
+func (g *GitHub) PutGist(ctx context.Context, id, description string, files map[string][]byte) (*Gist, error) {
+	if g.Token == "" {
+		return nil, fmt.Errorf("publishing gists requires a token")
+	}
+	type content struct {
+		Content string `json:"content"`
+	}
+	update := struct {
+		Description string             `json:"description"`
+		Public      bool               `json:"public"`
+		Files       map[string]content `json:"files"`
+	}{Description: description, Files: map[string]content{}}
+	for name, data := range files {
+		update.Files[name] = content{string(data)}
+	}
+	body, err := json.Marshal(update)
+	if err != nil {
+		return nil, err
+	}
+
+	method, endpoint, want := "POST", g.restEndpoint()+"/gists", http.StatusCreated
+	if id != "" {
+		method, endpoint, want = "PATCH", g.restEndpoint()+"/gists/"+url.PathEscape(id), http.StatusOK
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+g.Token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := g.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != want {
+		return nil, newStatusError(resp)
+	}
+	var gist Gist
+	if err := json.NewDecoder(resp.Body).Decode(&gist); err != nil {
+		return nil, fmt.Errorf("decoding gist: %w", err)
+	}
+	return &gist, nil
+}
+
+// FileNames lists the gist's files in order
+func (gist *Gist) FileNames() []string {
+	names := make([]string, 0, len(gist.Files))
+	for name := range gist.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,77 @@
+package gitgraph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultGiteaURL is the base URL of gitea.com
+const DefaultGiteaURL = "https://gitea.com"
+
+// DefaultForgejoURL is the base URL of Codeberg, the largest public Forgejo instance
+const DefaultForgejoURL = "https://codeberg.org"
+
+// Gitea fetches contributions from a Gitea or Forgejo instance's heatmap API.
+// Like the profile heatmap, it only covers about the last year of activity.
+type Gitea struct {
+	HTTPClient *http.Client
+	BaseURL    string
+	Location   *time.Location // zone the heatmap's timestamps are dated in; nil for UTC
+}
+
+// NewGitea creates a Gitea provider for baseURL, defaulting to gitea.com
+func NewGitea(httpClient *http.Client, baseURL string) *Gitea {
+	if baseURL == "" {
+		baseURL = DefaultGiteaURL
+	}
+	return &Gitea{HTTPClient: httpClient, BaseURL: strings.TrimRight(baseURL, "/")}
+}
+
+func (g *Gitea) Name() string {
+	return "gitea:" + g.BaseURL + zoneSuffix(g.Location)
+}
+
+// giteaHeatmapEntry is one bucket of the heatmap, several of which may fall on a day
+type giteaHeatmapEntry struct {
+	Timestamp     int64 `json:"timestamp"`
+	Contributions int   `json:"contributions"`
+}
+
+func (g *Gitea) FetchRange(ctx context.Context, username string, from, to time.Time) (*ContributionGraph, error) {
+	endpoint := fmt.Sprintf("%s/api/v1/users/%s/heatmap", g.BaseURL, url.PathEscape(username))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Accept", "application/json")
+
+	resp, err := g.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newStatusError(resp)
+	}
+
+	var heatmap []giteaHeatmapEntry
+	if err := json.NewDecoder(resp.Body).Decode(&heatmap); err != nil {
+		return nil, parseError(err)
+	}
+
+	loc := orUTC(g.Location)
+	counts := make(map[string]int)
+	for _, entry := range heatmap {
+		counts[time.Unix(entry.Timestamp, 0).In(loc).Format("2006-01-02")] += entry.Contributions
+	}
+	graph := GraphFromCounts(username, counts, from, to, QuartileThresholds(counts))
+	graph.TimeZone = loc.String()
+	return graph, nil
+}
@@ -0,0 +1,171 @@
+package gitgraph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/telemetry"
+)
+
+// DefaultGitHubURL is the web URL of github.com
+const DefaultGitHubURL = "https://github.com"
+
+// GitHub fetches contributions from github.com or a GitHub Enterprise Server
+// instance. With a Token it uses the GraphQL API, falling back to scraping the
+// public contributions page, which is all it uses without one. When neither
+// can be read, the past 90 days are approximated from the user's events.
+type GitHub struct {
+	HTTPClient *http.Client
+	Token      string
+	BaseURL    string // web URL of an Enterprise Server instance; empty for github.com
+	// IncludePrivate adds the private contributions hidden from the calendar to
+	// the total. It requires a Token.
+	IncludePrivate bool
+	// DayTypes splits each day's count by kind of contribution, at the cost of
+	// extra requests. It requires a Token.
+	DayTypes bool
+	// NoEvents fails fetches the calendar can't answer rather than
+	// approximating them from events
+	NoEvents bool
+	// Location is the zone events are dated in when approximating from them;
+	// nil for UTC. The calendar itself is always in UTC.
+	Location *time.Location
+}
+
+// NewGitHub creates a GitHub provider for the instance at baseURL, e.g.
+// https://github.mycompany.com, defaulting to github.com
+func NewGitHub(httpClient *http.Client, baseURL, token string) *GitHub {
+	baseURL = strings.TrimRight(baseURL, "/")
+	if baseURL == DefaultGitHubURL {
+		baseURL = ""
+	}
+	return &GitHub{HTTPClient: httpClient, Token: token, BaseURL: baseURL}
+}
+
+func (g *GitHub) Name() string {
+	name := "github"
+	if g.BaseURL != "" {
+		name += ":" + g.BaseURL
+	}
+	if g.IncludePrivate {
+		name += "+private"
+	}
+	if g.DayTypes {
+		name += "+types"
+	}
+	return name + zoneSuffix(g.Location)
+}
+
+func (g *GitHub) webURL() string {
+	if g.BaseURL != "" {
+		return g.BaseURL
+	}
+	return DefaultGitHubURL
+}
+
+// graphQLEndpoint is api.github.com's GraphQL endpoint, or /api/graphql on Enterprise Server
+func (g *GitHub) graphQLEndpoint() string {
+	if g.BaseURL != "" {
+		return g.BaseURL + "/api/graphql"
+	}
+	return graphQLURL
+}
+
+// restEndpoint is api.github.com, or /api/v3 on Enterprise Server
+func (g *GitHub) restEndpoint() string {
+	if g.BaseURL != "" {
+		return g.BaseURL + "/api/v3"
+	}
+	return restAPIURL
+}
+
+func (g *GitHub) FetchRange(ctx context.Context, username string, from, to time.Time) (*ContributionGraph, error) {
+	if g.Token == "" {
+		if g.IncludePrivate {
+			return nil, fmt.Errorf("including private contributions requires a token")
+		}
+		if g.DayTypes {
+			return nil, fmt.Errorf("splitting days by contribution type requires a token")
+		}
+		return g.withEventsFallback(ctx, username, from, to, g.scrapeRange)
+	}
+	// The scraped page has no private counts or types, so it can't stand in for the API then
+	if g.IncludePrivate || g.DayTypes {
+		return g.fetchGraphQL(ctx, username, from, to)
+	}
+	return g.withEventsFallback(ctx, username, from, to, NewFallback(
+		ProviderFunc("github-graphql", g.fetchGraphQL),
+		ProviderFunc("github-scraper", g.scrapeRange),
+	).FetchRange)
+}
+
+// withEventsFallback calls fetch, approximating the graph from recent events
+// when it fails for reasons other than the user: an unknown user or private
+// profile wouldn't have events to read either
+func (g *GitHub) withEventsFallback(ctx context.Context, username string, from, to time.Time, fetch func(ctx context.Context, username string, from, to time.Time) (*ContributionGraph, error)) (*ContributionGraph, error) {
+	graph, err := fetch(ctx, username, from, to)
+	if err == nil || g.NoEvents || ctx.Err() != nil || errors.Is(err, ErrUserNotFound) || errors.Is(err, ErrProfilePrivate) {
+		return graph, err
+	}
+	approximate, eventsErr := g.eventsRange(ctx, username, from, to)
+	if eventsErr != nil {
+		return nil, errors.Join(err, fmt.Errorf("github-events: %w", eventsErr))
+	}
+	slog.WarnContext(ctx, "approximating contributions from recent events", "username", username, "err", err)
+	return approximate, nil
+}
+
+// scrapeRange fetches and parses the public contributions page
+func (g *GitHub) scrapeRange(ctx context.Context, username string, from, to time.Time) (*ContributionGraph, error) {
+	endpoint := fmt.Sprintf("%s/users/%s/contributions?from=%s&to=%s",
+		g.webURL(), url.PathEscape(username), from.Format("2006-01-02"), to.Format("2006-01-02"))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Add headers to make it look like a browser request
+	req.Header.Add("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36")
+	req.Header.Add("Accept", "text/html,application/xhtml+xml,application/xml")
+
+	resp, err := g.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s", ErrUserNotFound, username)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, newStatusError(resp)
+	}
+
+	parseStart := time.Now()
+	_, span := telemetry.Start(ctx, "gitgraph.parse", telemetry.KindInternal)
+	graph, err := parseContributions(resp.Body)
+	span.End(err)
+	if err != nil {
+		if errors.Is(err, ErrProfilePrivate) {
+			return nil, fmt.Errorf("%w: %s", ErrProfilePrivate, username)
+		}
+		return nil, err
+	}
+	parsed := time.Since(parseStart)
+	DefaultMetrics.ObserveParse(parsed, len(graph.Days))
+	slog.DebugContext(ctx, "parsed contributions page", "user", username, "days", len(graph.Days), "duration", parsed)
+	if len(graph.Days) == 0 {
+		return nil, fmt.Errorf("%w: no calendar cells in the contributions page", ErrParse)
+	}
+	graph.Username = username
+	graph.Years = yearsBetween(from, to)
+	graph.TimeZone = "UTC"
+	return graph, nil
+}
@@ -0,0 +1,64 @@
+package gitgraph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultGitLabURL is the base URL of gitlab.com
+const DefaultGitLabURL = "https://gitlab.com"
+
+// gitlabThresholds mirror the color buckets of GitLab's own activity calendar
+var gitlabThresholds = []int{1, 10, 20, 30}
+
+// GitLab fetches contributions from a GitLab instance's calendar endpoint.
+// GitLab only publishes the last year of activity, so older ranges come back empty.
+type GitLab struct {
+	HTTPClient *http.Client
+	BaseURL    string
+}
+
+// NewGitLab creates a GitLab provider for baseURL, defaulting to gitlab.com
+func NewGitLab(httpClient *http.Client, baseURL string) *GitLab {
+	if baseURL == "" {
+		baseURL = DefaultGitLabURL
+	}
+	return &GitLab{HTTPClient: httpClient, BaseURL: strings.TrimRight(baseURL, "/")}
+}
+
+func (g *GitLab) Name() string {
+	return "gitlab:" + g.BaseURL
+}
+
+func (g *GitLab) FetchRange(ctx context.Context, username string, from, to time.Time) (*ContributionGraph, error) {
+	endpoint := fmt.Sprintf("%s/users/%s/calendar.json", g.BaseURL, url.PathEscape(username))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Accept", "application/json")
+
+	resp, err := g.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newStatusError(resp)
+	}
+
+	// The calendar is a map of YYYY-MM-DD to count, listing only active days
+	var calendar map[string]int
+	if err := json.NewDecoder(resp.Body).Decode(&calendar); err != nil {
+		return nil, parseError(err)
+	}
+
+	return GraphFromCounts(username, calendar, from, to, gitlabThresholds), nil
+}
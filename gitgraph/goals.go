@@ -0,0 +1,53 @@
+package gitgraph
+
+import (
+	"math"
+	"time"
+)
+
+// GoalProgress measures the contributions of a period against a goal for it
+type GoalProgress struct {
+	Period  string  `json:"period"` // e.g. 2024 or 2024-03
+	Goal    int     `json:"goal"`
+	Total   int     `json:"total"`
+	Percent float64 `json:"percent"`
+	// DaysAhead is how many days of the goal's even pace the total is ahead by,
+	// negative when behind
+	DaysAhead float64 `json:"daysAhead"`
+}
+
+// YearProgress measures today's calendar year so far against goal
+func YearProgress(days []ContributionDay, today time.Time, goal int) GoalProgress {
+	start := time.Date(today.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	return periodProgress(days, start, start.AddDate(1, 0, -1), today, goal, start.Format("2006"))
+}
+
+// MonthProgress measures today's calendar month so far against goal
+func MonthProgress(days []ContributionDay, today time.Time, goal int) GoalProgress {
+	start := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return periodProgress(days, start, start.AddDate(0, 1, -1), today, goal, start.Format("2006-01"))
+}
+
+// periodProgress compares the total of start..today with an even pace towards
+// goal over start..end, counting today as elapsed
+func periodProgress(days []ContributionDay, start, end, today time.Time, goal int, name string) GoalProgress {
+	todayDate := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	p := GoalProgress{Period: name, Goal: goal}
+	for _, day := range days {
+		date, err := parseDate(day.Date)
+		if err != nil || date.Before(start) || date.After(todayDate) {
+			continue
+		}
+		p.Total += day.Count
+	}
+	if goal <= 0 {
+		return p
+	}
+
+	p.Percent = math.Round(float64(p.Total)/float64(goal)*1000) / 10
+	length := end.Sub(start).Hours()/24 + 1
+	elapsed := todayDate.Sub(start).Hours()/24 + 1
+	perDay := float64(goal) / length
+	p.DaysAhead = math.Round((float64(p.Total)-perDay*elapsed)/perDay*10) / 10
+	return p
+}
@@ -0,0 +1,118 @@
+// Package gitgraph fetches and parses GitHub contribution graphs.
+package gitgraph
+
+import "time"
+
+// ContributionDay represents a single day in the contribution graph
+type ContributionDay struct {
+	Date       string `json:"date"`
+	Count      int    `json:"count"`
+	Level      int    `json:"level"`
+	DayOfWeek  int    `json:"dayOfWeek"`
+	WeekOfYear int    `json:"weekOfYear"`
+	// GridWeek is the day's column in the graph's calendar as GitHub draws it:
+	// weeks start on Sunday and the week holding the graph's first day is
+	// column 0. A day on its own counts from January 1 of its year.
+	GridWeek     int    `json:"gridWeek"`
+	ContribLevel string `json:"contribLevel"` // none, first_quartile, second_quartile, third_quartile, fourth_quartile
+	// Types splits Count by kind when fetched with GitHub.DayTypes
+	Types *ContributionTypes `json:"types,omitempty"`
+	// Activity lists the repositories and event types of recent days when
+	// enriched with ContributionGraph.Enrich
+	Activity *DayActivity `json:"activity,omitempty"`
+	// Accounts splits Count by account in a graph built with MergeAccounts
+	Accounts map[string]int `json:"accounts,omitempty"`
+}
+
+// ContributionGraph represents the complete contribution data
+type ContributionGraph struct {
+	Username      string `json:"username"`
+	TotalContribs int    `json:"totalContributions"`
+	Years         []int  `json:"years"`
+	// YearTotals subtotals each year of a graph fetched year by year
+	YearTotals []YearTotal       `json:"yearTotals,omitempty"`
+	Days       []ContributionDay `json:"days"`
+	Streaks    *Streaks          `json:"streaks,omitempty"`
+	Summary    *Summary          `json:"summary,omitempty"`
+	Anomalies  *Anomalies        `json:"anomalies,omitempty"`
+	// RollingAverage smooths the daily counts when requested
+	RollingAverage *RollingAverage `json:"rollingAverage,omitempty"`
+	// Types totals contributions by kind; only the GraphQL API reports it
+	Types *ContributionTypes `json:"types,omitempty"`
+	// Repositories breaks commits down by repository; only the GraphQL API reports it
+	Repositories []RepositoryContributions `json:"repositories,omitempty"`
+	// IncludesPrivate marks counts that include private contributions. PrivateContribs
+	// are private contributions counted in TotalContribs but not in any day.
+	IncludesPrivate bool `json:"includesPrivate,omitempty"`
+	PrivateContribs int  `json:"privateContributions,omitempty"`
+	// Stale marks a cached graph served because upstream is unavailable
+	Stale bool `json:"stale,omitempty"`
+	// Approximate marks counts estimated from the past 90 days of events,
+	// because the calendar couldn't be read; earlier days read as none
+	Approximate bool `json:"approximate,omitempty"`
+	// TimeZone is the zone the provider dated days in, e.g. UTC for GitHub's
+	// calendar; empty when the provider doesn't say
+	TimeZone string `json:"timeZone,omitempty"`
+	// FetchedAt is when the graph was fetched from upstream, kept through the cache
+	FetchedAt *time.Time `json:"fetchedAt,omitempty"`
+	// Accounts are the totals of the accounts summed by MergeAccounts
+	Accounts []MemberTotal `json:"accounts,omitempty"`
+}
+
+// RepositoryContributions counts the commits made to one repository in the period
+type RepositoryContributions struct {
+	Repository string `json:"repository"` // owner/name
+	Private    bool   `json:"private,omitempty"`
+	Commits    int    `json:"commits"`
+	// Languages are the repository's largest languages, biggest first
+	Languages []LanguageSize `json:"languages,omitempty"`
+}
+
+// levelNames maps a contribution level (0-4) to its ContribLevel name
+var levelNames = []string{"none", "first_quartile", "second_quartile", "third_quartile", "fourth_quartile"}
+
+// newContributionDay builds a ContributionDay, deriving the calendar fields from date
+func newContributionDay(date time.Time, count, level int) ContributionDay {
+	return makeContributionDay(date, date.Format("2006-01-02"), count, level)
+}
+
+// makeContributionDay is newContributionDay for a date already formatted as
+// YYYY-MM-DD, saving the parser formatting each day again
+func makeContributionDay(date time.Time, formatted string, count, level int) ContributionDay {
+	return ContributionDay{
+		Date:         formatted,
+		Count:        count,
+		Level:        level,
+		DayOfWeek:    int(date.Weekday()),
+		WeekOfYear:   getWeekOfYear(date),
+		GridWeek:     gridWeek(date, yearStart(date), time.Sunday),
+		ContribLevel: levelName(level),
+	}
+}
+
+// levelName returns the ContribLevel name of a 0-4 level, empty when out of range
+func levelName(level int) string {
+	if level < 0 || level >= len(levelNames) {
+		return ""
+	}
+	return levelNames[level]
+}
+
+func getWeekOfYear(date time.Time) int {
+	_, week := date.ISOWeek()
+	return week
+}
+
+// parseDate parses a YYYY-MM-DD day as used in ContributionDay.Date
+func parseDate(date string) (time.Time, error) {
+	return time.Parse("2006-01-02", date)
+}
+
+// NewDay builds a ContributionDay for a YYYY-MM-DD date, e.g. when reloading stored days
+func NewDay(date string, count, level int) (ContributionDay, error) {
+	parsed, err := parseDate(date)
+	if err != nil {
+		return ContributionDay{}, err
+	}
+	return newContributionDay(parsed, count, level), nil
+}
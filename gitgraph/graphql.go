@@ -0,0 +1,244 @@
+package gitgraph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+const graphQLURL = "https://api.github.com/graphql"
+
+const contributionsQuery = `query($login: String!, $from: DateTime!, $to: DateTime!) {
+  viewer {
+    login
+  }
+  user(login: $login) {
+    contributionsCollection(from: $from, to: $to) {
+      restrictedContributionsCount
+      totalCommitContributions
+      totalPullRequestContributions
+      totalIssueContributions
+      totalPullRequestReviewContributions
+      totalRepositoryContributions
+      commitContributionsByRepository(maxRepositories: 100) {
+        repository {
+          nameWithOwner
+          isPrivate
+          languages(first: 10, orderBy: {field: SIZE, direction: DESC}) {
+            edges {
+              size
+              node {
+                name
+                color
+              }
+            }
+          }
+        }
+        contributions {
+          totalCount
+        }
+      }
+      contributionCalendar {
+        totalContributions
+        weeks {
+          contributionDays {
+            date
+            contributionCount
+            contributionLevel
+          }
+        }
+      }
+    }
+  }
+}`
+
+type graphQLRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables"`
+}
+
+type graphQLError struct {
+	Type    string `json:"type"` // e.g. NOT_FOUND or RATE_LIMITED
+	Message string `json:"message"`
+}
+
+type contributionsResponse struct {
+	Data struct {
+		Viewer struct {
+			Login string `json:"login"`
+		} `json:"viewer"`
+		User *struct {
+			ContributionsCollection struct {
+				RestrictedContributionsCount        int `json:"restrictedContributionsCount"`
+				TotalCommitContributions            int `json:"totalCommitContributions"`
+				TotalPullRequestContributions       int `json:"totalPullRequestContributions"`
+				TotalIssueContributions             int `json:"totalIssueContributions"`
+				TotalPullRequestReviewContributions int `json:"totalPullRequestReviewContributions"`
+				TotalRepositoryContributions        int `json:"totalRepositoryContributions"`
+				CommitContributionsByRepository     []struct {
+					Repository struct {
+						NameWithOwner string `json:"nameWithOwner"`
+						IsPrivate     bool   `json:"isPrivate"`
+						Languages     struct {
+							Edges []struct {
+								Size int `json:"size"`
+								Node struct {
+									Name  string `json:"name"`
+									Color string `json:"color"`
+								} `json:"node"`
+							} `json:"edges"`
+						} `json:"languages"`
+					} `json:"repository"`
+					Contributions struct {
+						TotalCount int `json:"totalCount"`
+					} `json:"contributions"`
+				} `json:"commitContributionsByRepository"`
+				ContributionCalendar struct {
+					TotalContributions int `json:"totalContributions"`
+					Weeks              []struct {
+						ContributionDays []struct {
+							Date              string `json:"date"`
+							ContributionCount int    `json:"contributionCount"`
+							ContributionLevel string `json:"contributionLevel"`
+						} `json:"contributionDays"`
+					} `json:"weeks"`
+				} `json:"contributionCalendar"`
+			} `json:"contributionsCollection"`
+		} `json:"user"`
+	} `json:"data"`
+	Errors []graphQLError `json:"errors"`
+}
+
+// fetchGraphQL fetches the contribution calendar through the authenticated GraphQL API
+func (g *GitHub) fetchGraphQL(ctx context.Context, username string, from, to time.Time) (*ContributionGraph, error) {
+	var result contributionsResponse
+	variables := map[string]any{
+		"login": username,
+		"from":  from.Format(time.RFC3339),
+		"to":    to.Add(24*time.Hour - time.Second).Format(time.RFC3339),
+	}
+	if err := g.graphQL(ctx, contributionsQuery, variables, &result); err != nil {
+		return nil, err
+	}
+	if len(result.Errors) > 0 {
+		switch result.Errors[0].Type {
+		case "NOT_FOUND":
+			return nil, fmt.Errorf("%w: %s", ErrUserNotFound, username)
+		case "RATE_LIMITED":
+			return nil, fmt.Errorf("GraphQL error: %s: %w", result.Errors[0].Message, ErrRateLimited)
+		}
+		return nil, fmt.Errorf("GraphQL error: %s", result.Errors[0].Message)
+	}
+	if result.Data.User == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUserNotFound, username)
+	}
+
+	collection := result.Data.User.ContributionsCollection
+	calendar := collection.ContributionCalendar
+	days := []ContributionDay{}
+	for _, week := range calendar.Weeks {
+		for _, d := range week.ContributionDays {
+			date, err := parseDate(d.Date)
+			if err != nil {
+				continue
+			}
+			days = append(days, newContributionDay(date, d.ContributionCount, levelFromName(d.ContributionLevel)))
+		}
+	}
+
+	graph := &ContributionGraph{
+		Username:      username,
+		TotalContribs: calendar.TotalContributions,
+		Years:         yearsBetween(from, to),
+		Days:          days,
+		TimeZone:      "UTC",
+		Types: &ContributionTypes{
+			Commits:      collection.TotalCommitContributions,
+			PullRequests: collection.TotalPullRequestContributions,
+			Issues:       collection.TotalIssueContributions,
+			Reviews:      collection.TotalPullRequestReviewContributions,
+			Repositories: collection.TotalRepositoryContributions,
+		},
+	}
+	for _, repo := range collection.CommitContributionsByRepository {
+		contributions := RepositoryContributions{
+			Repository: repo.Repository.NameWithOwner,
+			Private:    repo.Repository.IsPrivate,
+			Commits:    repo.Contributions.TotalCount,
+		}
+		for _, edge := range repo.Repository.Languages.Edges {
+			contributions.Languages = append(contributions.Languages, LanguageSize{Name: edge.Node.Name, Color: edge.Node.Color, Bytes: edge.Size})
+		}
+		graph.Repositories = append(graph.Repositories, contributions)
+	}
+	sortRepositories(graph.Repositories)
+	// The calendar already counts private work when the token belongs to the user;
+	// otherwise private contributions are only available as an undated total
+	if strings.EqualFold(result.Data.Viewer.Login, username) {
+		graph.IncludesPrivate = true
+	}
+	if g.IncludePrivate && collection.RestrictedContributionsCount > 0 {
+		graph.IncludesPrivate = true
+		graph.PrivateContribs = collection.RestrictedContributionsCount
+		graph.TotalContribs += collection.RestrictedContributionsCount
+	}
+	if g.DayTypes {
+		if err := g.addDayTypes(ctx, username, from, to, graph); err != nil {
+			return nil, err
+		}
+	}
+	return graph, nil
+}
+
+// graphQL posts query with variables and decodes the response into result
+func (g *GitHub) graphQL(ctx context.Context, query string, variables map[string]any, result any) error {
+	payload, err := json.Marshal(graphQLRequest{Query: query, Variables: variables})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", g.graphQLEndpoint(), bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "bearer "+g.Token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := g.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return newStatusError(resp)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return parseError(err)
+	}
+	return nil
+}
+
+// sortRepositories orders repos by commits, most first, then by name
+func sortRepositories(repos []RepositoryContributions) {
+	sort.Slice(repos, func(i, j int) bool {
+		if repos[i].Commits != repos[j].Commits {
+			return repos[i].Commits > repos[j].Commits
+		}
+		return repos[i].Repository < repos[j].Repository
+	})
+}
+
+// levelFromName converts a GraphQL ContributionLevel (e.g. FIRST_QUARTILE) to 0-4
+func levelFromName(name string) int {
+	name = strings.ToLower(name)
+	for level, levelName := range levelNames {
+		if levelName == name {
+			return level
+		}
+	}
+	return 0
+}
@@ -0,0 +1,56 @@
+package gitgraph
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Pinger is implemented by providers that can check their upstream answers
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Ping checks the Client's provider is reachable, for readiness probes.
+// Providers that can't be pinged are taken to be reachable.
+func (c *Client) Ping(ctx context.Context) error {
+	if p, ok := c.provider().(Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+// Ping requests the rate limit status, which doesn't count against the limit
+func (g *GitHub) Ping(ctx context.Context) error {
+	return ping(ctx, g.HTTPClient, g.restEndpoint()+"/rate_limit")
+}
+
+func (g *GitLab) Ping(ctx context.Context) error {
+	return ping(ctx, g.HTTPClient, g.BaseURL+"/api/v4/version")
+}
+
+func (g *Gitea) Ping(ctx context.Context) error {
+	return ping(ctx, g.HTTPClient, g.BaseURL+"/api/v1/version")
+}
+
+func (b *Bitbucket) Ping(ctx context.Context) error {
+	return ping(ctx, b.HTTPClient, b.BaseURL)
+}
+
+// ping GETs endpoint, counting any answer but a server error as reachable:
+// unauthenticated requests may well be refused, but the upstream is there
+func ping(ctx context.Context, httpClient *http.Client, endpoint string) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 500 {
+		return fmt.Errorf("%s answered %s", endpoint, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package gitgraph
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// HedgeTransport sends a second copy of a request that hasn't been answered
+// after Delay, returning whichever response comes first and cancelling the
+// other. It trades some duplicate upstream requests for a shorter tail of slow
+// responses. Only reads are hedged: GETs, HEADs and GraphQL queries.
+type HedgeTransport struct {
+	Base  http.RoundTripper
+	Delay time.Duration // 0 disables hedging
+}
+
+// NewHedgeTransport wraps base (or the NewTransport default) to hedge
+// requests unanswered after delay
+func NewHedgeTransport(base http.RoundTripper, delay time.Duration) *HedgeTransport {
+	if base == nil {
+		base, _ = NewTransport("")
+	}
+	return &HedgeTransport{Base: base, Delay: delay}
+}
+
+// hedgeResult is the outcome of one copy of a hedged request
+type hedgeResult struct {
+	resp  *http.Response
+	err   error
+	hedge bool
+}
+
+// failed reports whether the copy got no useful answer, so the other may
+// still give one
+func (r hedgeResult) failed() bool {
+	return r.err != nil || r.resp.StatusCode >= 500
+}
+
+func (t *HedgeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.Delay <= 0 || !hedgeable(req) {
+		return t.Base.RoundTrip(req)
+	}
+
+	results := make(chan hedgeResult, 2)
+	cancels := map[bool]context.CancelFunc{}
+	send := func(r *http.Request, hedge bool) {
+		ctx, cancel := context.WithCancel(req.Context())
+		cancels[hedge] = cancel
+		go func() {
+			resp, err := t.Base.RoundTrip(r.WithContext(ctx))
+			results <- hedgeResult{resp: resp, err: err, hedge: hedge}
+		}()
+	}
+	send(req, false)
+
+	timer := time.NewTimer(t.Delay)
+	defer timer.Stop()
+	pending, hedged := 1, false
+	var failure *hedgeResult
+	for {
+		select {
+		case <-timer.C:
+			second, err := replay(req)
+			if err != nil {
+				continue
+			}
+			DefaultMetrics.ObserveHedge()
+			slog.DebugContext(req.Context(), "hedging slow request", "url", req.URL.Redacted(), "after", t.Delay)
+			hedged = true
+			pending++
+			send(second, true)
+		case result := <-results:
+			pending--
+			// A failure settles the request only once no other copy is in flight
+			if result.failed() && pending > 0 {
+				failure = &result
+				continue
+			}
+			if failure != nil {
+				discard(*failure, cancels)
+			}
+			if pending > 0 {
+				// The other copy is cancelled, its response closed whenever it comes
+				cancels[!result.hedge]()
+				go func() { discard(<-results, cancels) }()
+			}
+			if hedged && result.hedge && !result.failed() {
+				DefaultMetrics.ObserveHedgeWin()
+			}
+			if result.err != nil {
+				cancels[result.hedge]()
+				return nil, result.err
+			}
+			result.resp.Body = &cancelBody{ReadCloser: result.resp.Body, cancel: cancels[result.hedge]}
+			return result.resp, nil
+		}
+	}
+}
+
+// hedgeable reports whether req only reads, so sending it twice is harmless,
+// and can be sent again. GraphQL is POSTed, but gitgraphed only queries it.
+func hedgeable(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead:
+		return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+	case http.MethodPost:
+		return strings.HasSuffix(req.URL.Path, "/graphql") && req.GetBody != nil
+	}
+	return false
+}
+
+// replay copies req with a fresh body, to be sent again
+func replay(req *http.Request) (*http.Request, error) {
+	r := req.Clone(req.Context())
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		r.Body = body
+	}
+	return r, nil
+}
+
+// discard closes the response of a copy that isn't returned
+func discard(result hedgeResult, cancels map[bool]context.CancelFunc) {
+	if result.resp != nil {
+		result.resp.Body.Close()
+	}
+	cancels[result.hedge]()
+}
+
+// cancelBody releases the context of a request once its response is closed
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
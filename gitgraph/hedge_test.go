@@ -0,0 +1,126 @@
+package gitgraph
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// hedgeAnswer is how the fake upstream answers one copy of a request
+type hedgeAnswer struct {
+	after  time.Duration
+	status int // 0 fails the request instead
+}
+
+// fakeUpstream answers the nth request it gets with answers[n], recording
+// each request's body and whether it was cancelled first
+type fakeUpstream struct {
+	answers []hedgeAnswer
+
+	mu        sync.Mutex
+	bodies    []string
+	cancelled []bool
+}
+
+func (u *fakeUpstream) RoundTrip(req *http.Request) (*http.Response, error) {
+	u.mu.Lock()
+	n := len(u.bodies)
+	var body []byte
+	if req.Body != nil {
+		body, _ = io.ReadAll(req.Body)
+	}
+	u.bodies = append(u.bodies, string(body))
+	u.cancelled = append(u.cancelled, false)
+	u.mu.Unlock()
+
+	answer := u.answers[n]
+	select {
+	case <-time.After(answer.after):
+	case <-req.Context().Done():
+		u.mu.Lock()
+		u.cancelled[n] = true
+		u.mu.Unlock()
+		return nil, req.Context().Err()
+	}
+	if answer.status == 0 {
+		return nil, errors.New("connection reset")
+	}
+	return &http.Response{
+		StatusCode: answer.status,
+		Body:       io.NopCloser(strings.NewReader("copy " + strconv.Itoa(n))),
+		Request:    req,
+	}, nil
+}
+
+func TestHedgeTransport(t *testing.T) {
+	const delay = 20 * time.Millisecond
+	slow, fast := 200*time.Millisecond, time.Millisecond
+	for _, tt := range []struct {
+		name      string
+		delay     time.Duration
+		method    string
+		path      string
+		answers   []hedgeAnswer
+		want      string // the body returned, or "error"
+		status    int
+		copies    int
+		cancelled []bool
+	}{
+		{"disabled", 0, "GET", "/users/alice", []hedgeAnswer{{slow, 200}}, "copy 0", 200, 1, []bool{false}},
+		{"answered in time", delay, "GET", "/users/alice", []hedgeAnswer{{fast, 200}}, "copy 0", 200, 1, []bool{false}},
+		{"hedge wins", delay, "GET", "/users/alice", []hedgeAnswer{{slow, 200}, {fast, 200}}, "copy 1", 200, 2, []bool{true, false}},
+		{"original wins", delay, "GET", "/users/alice", []hedgeAnswer{{2 * delay, 200}, {slow, 200}}, "copy 0", 200, 2, []bool{false, true}},
+		{"failure then hedge", delay, "GET", "/users/alice", []hedgeAnswer{{2 * delay, 502}, {slow / 2, 200}}, "copy 1", 200, 2, []bool{false, false}},
+		{"hedge fails first", delay, "GET", "/users/alice", []hedgeAnswer{{slow / 2, 200}, {fast, 0}}, "copy 0", 200, 2, []bool{false, false}},
+		{"both fail", delay, "GET", "/users/alice", []hedgeAnswer{{2 * delay, 0}, {slow / 2, 503}}, "copy 1", 503, 2, []bool{false, false}},
+		{"graphql", delay, "POST", "/graphql", []hedgeAnswer{{slow, 200}, {fast, 200}}, "copy 1", 200, 2, []bool{true, false}},
+		{"other post", delay, "POST", "/users/alice", []hedgeAnswer{{slow, 200}}, "copy 0", 200, 1, []bool{false}},
+		{"error", delay, "GET", "/users/alice", []hedgeAnswer{{fast, 0}}, "error", 0, 1, []bool{false}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			upstream := &fakeUpstream{answers: tt.answers}
+			transport := NewHedgeTransport(upstream, tt.delay)
+			var body io.Reader
+			if tt.method == "POST" {
+				body = strings.NewReader(`{"query":"{ viewer { login } }"}`)
+			}
+			req, _ := http.NewRequest(tt.method, "https://api.github.com"+tt.path, body)
+
+			resp, err := transport.RoundTrip(req)
+			got := "error"
+			if err == nil {
+				data, _ := io.ReadAll(resp.Body)
+				resp.Body.Close()
+				got = string(data)
+				if resp.StatusCode != tt.status {
+					t.Errorf("status %d, want %d", resp.StatusCode, tt.status)
+				}
+			}
+			if got != tt.want {
+				t.Errorf("got %s (%v), want %s", got, err, tt.want)
+			}
+
+			// Wait for the losing copy to see its cancellation
+			time.Sleep(slow)
+			upstream.mu.Lock()
+			defer upstream.mu.Unlock()
+			if len(upstream.bodies) != tt.copies {
+				t.Fatalf("%d copies sent, want %d", len(upstream.bodies), tt.copies)
+			}
+			for i, cancelled := range upstream.cancelled {
+				if cancelled != tt.cancelled[i] {
+					t.Errorf("copy %d cancelled = %v, want %v", i, cancelled, tt.cancelled[i])
+				}
+				if tt.method == "POST" && upstream.bodies[i] == "" {
+					t.Errorf("copy %d sent without its body", i)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package gitgraph
+
+import "sort"
+
+// LanguageSize is how much of a repository's code is in one language
+type LanguageSize struct {
+	Name  string `json:"name"`
+	Color string `json:"color,omitempty"` // hex, as GitHub shows the language
+	Bytes int    `json:"bytes"`
+}
+
+// LanguageShare is one language's part of the commits of a period
+type LanguageShare struct {
+	Language string  `json:"language"`
+	Color    string  `json:"color,omitempty"`
+	Commits  float64 `json:"commits"`
+	Percent  float64 `json:"percent"`
+}
+
+// LanguageBreakdown splits each repository's commits between its languages in
+// proportion to their size, most committed language first. Repositories
+// without language data are left out.
+func LanguageBreakdown(repos []RepositoryContributions) []LanguageShare {
+	byName := map[string]*LanguageShare{}
+	total := 0.0
+	for _, repo := range repos {
+		size := 0
+		for _, lang := range repo.Languages {
+			size += lang.Bytes
+		}
+		if size == 0 || repo.Commits == 0 {
+			continue
+		}
+		for _, lang := range repo.Languages {
+			share, ok := byName[lang.Name]
+			if !ok {
+				share = &LanguageShare{Language: lang.Name, Color: lang.Color}
+				byName[lang.Name] = share
+			}
+			commits := float64(repo.Commits) * float64(lang.Bytes) / float64(size)
+			share.Commits += commits
+			total += commits
+		}
+	}
+
+	shares := make([]LanguageShare, 0, len(byName))
+	for _, share := range byName {
+		share.Percent = share.Commits / total * 100
+		shares = append(shares, *share)
+	}
+	sort.Slice(shares, func(i, j int) bool {
+		if shares[i].Commits != shares[j].Commits {
+			return shares[i].Commits > shares[j].Commits
+		}
+		return shares[i].Language < shares[j].Language
+	})
+	return shares
+}
@@ -0,0 +1,106 @@
+package gitgraph
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// LeaderboardMetrics are the measures a Leaderboard can rank by
+var LeaderboardMetrics = []string{"total", "streak", "active"}
+
+// Leaderboard ranks users by one metric over the same period
+type Leaderboard struct {
+	Metric  string             `json:"metric"`
+	Entries []LeaderboardEntry `json:"entries"`
+}
+
+// LeaderboardEntry is one user's place on a Leaderboard. Users with the same
+// value share a rank.
+type LeaderboardEntry struct {
+	Rank          int    `json:"rank"`
+	Username      string `json:"username"`
+	Total         int    `json:"totalContributions"`
+	ActiveDays    int    `json:"activeDays"`
+	CurrentStreak int    `json:"currentStreak"`
+	LongestStreak int    `json:"longestStreak"`
+}
+
+// Value returns the entry's measure of metric, as named in LeaderboardMetrics
+func (e LeaderboardEntry) Value(metric string) int {
+	switch metric {
+	case "streak":
+		return e.LongestStreak
+	case "active":
+		return e.ActiveDays
+	}
+	return e.Total
+}
+
+// LeaderboardOptions filter who is ranked
+type LeaderboardOptions struct {
+	Metric        string // one of LeaderboardMetrics, defaults to total
+	ExcludeBots   bool   // skip accounts IsBot recognizes
+	MinTotal      int    // skip users with fewer contributions
+	MinActiveDays int    // skip users active on fewer days
+}
+
+// Rank builds a leaderboard of graphs, highest first, with ties ordered by username
+func Rank(graphs []*ContributionGraph, opts LeaderboardOptions, today time.Time) (*Leaderboard, error) {
+	if opts.Metric == "" {
+		opts.Metric = "total"
+	}
+	known := false
+	for _, name := range LeaderboardMetrics {
+		known = known || name == opts.Metric
+	}
+	if !known {
+		return nil, fmt.Errorf("unknown metric %q, expected one of %s", opts.Metric, strings.Join(LeaderboardMetrics, ", "))
+	}
+
+	board := &Leaderboard{Metric: opts.Metric, Entries: []LeaderboardEntry{}}
+	for _, graph := range graphs {
+		if opts.ExcludeBots && IsBot(graph.Username) {
+			continue
+		}
+		streaks := ComputeStreaks(graph.Days, today)
+		entry := LeaderboardEntry{
+			Username:      graph.Username,
+			Total:         graph.TotalContribs,
+			CurrentStreak: streaks.Current.Length,
+			LongestStreak: streaks.Longest.Length,
+		}
+		for _, day := range graph.Days {
+			if day.Count > 0 {
+				entry.ActiveDays++
+			}
+		}
+		if entry.Total < opts.MinTotal || entry.ActiveDays < opts.MinActiveDays {
+			continue
+		}
+		board.Entries = append(board.Entries, entry)
+	}
+
+	entries := board.Entries
+	sort.Slice(entries, func(i, j int) bool {
+		if a, b := entries[i].Value(opts.Metric), entries[j].Value(opts.Metric); a != b {
+			return a > b
+		}
+		return entries[i].Username < entries[j].Username
+	})
+	for i := range entries {
+		entries[i].Rank = i + 1
+		if i > 0 && entries[i].Value(opts.Metric) == entries[i-1].Value(opts.Metric) {
+			entries[i].Rank = entries[i-1].Rank
+		}
+	}
+	return board, nil
+}
+
+// IsBot reports whether username looks like an automation account, such as
+// dependabot[bot] or a CI user named release-bot
+func IsBot(username string) bool {
+	name := strings.ToLower(username)
+	return strings.HasSuffix(name, "[bot]") || strings.HasSuffix(name, "-bot") || strings.HasSuffix(name, "_bot")
+}
@@ -0,0 +1,258 @@
+package gitgraph
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/telemetry"
+)
+
+// Metrics instruments fetching: upstream latency, parse time, days parsed,
+// cache hits, coalesced fetches, retries and hedged requests. It is safe for
+// concurrent use.
+type Metrics struct {
+	mu          sync.Mutex
+	fetches     map[fetchLabels]*histogram
+	parse       *histogram
+	daysParsed  int64
+	cacheHits   int64
+	cacheMisses int64
+	retries     int64
+	coalesced   int64
+	hedged      int64
+	hedgeWins   int64
+}
+
+// fetchLabels tell apart the upstream fetch latency histograms
+type fetchLabels struct {
+	provider string
+	result   string // ok or error
+}
+
+// DefaultMetrics records every Client's fetches and every RetryTransport's retries
+var DefaultMetrics = NewMetrics()
+
+// fetchBuckets and parseBuckets are histogram upper bounds in seconds
+var (
+	fetchBuckets = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+	parseBuckets = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 1}
+)
+
+// NewMetrics creates empty Metrics
+func NewMetrics() *Metrics {
+	return &Metrics{
+		fetches: make(map[fetchLabels]*histogram),
+		parse:   newHistogram(parseBuckets),
+	}
+}
+
+// ObserveFetch records an upstream fetch from provider taking d
+func (m *Metrics) ObserveFetch(provider string, d time.Duration, err error) {
+	labels := fetchLabels{provider: provider, result: "ok"}
+	if err != nil {
+		labels.result = "error"
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	h, ok := m.fetches[labels]
+	if !ok {
+		h = newHistogram(fetchBuckets)
+		m.fetches[labels] = h
+	}
+	h.observe(d.Seconds())
+}
+
+// ObserveParse records parsing a contributions page of days days taking d
+func (m *Metrics) ObserveParse(d time.Duration, days int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.parse.observe(d.Seconds())
+	m.daysParsed += int64(days)
+}
+
+// ObserveCache records a cache lookup
+func (m *Metrics) ObserveCache(hit bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if hit {
+		m.cacheHits++
+	} else {
+		m.cacheMisses++
+	}
+}
+
+// ObserveRetry records a request being retried
+func (m *Metrics) ObserveRetry() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.retries++
+}
+
+// ObserveHedge records a second copy of a slow request being sent
+func (m *Metrics) ObserveHedge() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hedged++
+}
+
+// ObserveHedgeWin records a hedged copy answering before the original request
+func (m *Metrics) ObserveHedgeWin() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hedgeWins++
+}
+
+// ObserveCoalesced records a fetch answered by another's upstream fetch
+func (m *Metrics) ObserveCoalesced() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.coalesced++
+}
+
+// CacheHitRatio is the share of cache lookups that hit, 0 before any lookup
+func (m *Metrics) CacheHitRatio() float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.cacheHitRatio()
+}
+
+func (m *Metrics) cacheHitRatio() float64 {
+	if lookups := m.cacheHits + m.cacheMisses; lookups > 0 {
+		return float64(m.cacheHits) / float64(lookups)
+	}
+	return 0
+}
+
+// WritePrometheus writes the metrics in the Prometheus text exposition format
+func (m *Metrics) WritePrometheus(w io.Writer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var b strings.Builder
+	b.WriteString("# HELP gitgraphed_upstream_fetch_seconds Latency of fetches from the contribution provider.\n")
+	b.WriteString("# TYPE gitgraphed_upstream_fetch_seconds histogram\n")
+	labels := make([]fetchLabels, 0, len(m.fetches))
+	for l := range m.fetches {
+		labels = append(labels, l)
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		if labels[i].provider != labels[j].provider {
+			return labels[i].provider < labels[j].provider
+		}
+		return labels[i].result < labels[j].result
+	})
+	for _, l := range labels {
+		m.fetches[l].write(&b, "gitgraphed_upstream_fetch_seconds", fmt.Sprintf(`provider="%s",result="%s"`, escapeLabelValue(l.provider), l.result))
+	}
+
+	b.WriteString("# HELP gitgraphed_parse_seconds Time spent parsing contributions pages.\n")
+	b.WriteString("# TYPE gitgraphed_parse_seconds histogram\n")
+	m.parse.write(&b, "gitgraphed_parse_seconds", "")
+
+	for _, c := range []struct {
+		name, kind, help string
+		value            float64
+	}{
+		{"gitgraphed_days_parsed_total", "counter", "Days read from parsed contributions pages.", float64(m.daysParsed)},
+		{"gitgraphed_cache_hits_total", "counter", "Graph lookups answered by the cache.", float64(m.cacheHits)},
+		{"gitgraphed_cache_misses_total", "counter", "Graph lookups that had to be fetched.", float64(m.cacheMisses)},
+		{"gitgraphed_cache_hit_ratio", "gauge", "Share of graph lookups answered by the cache.", m.cacheHitRatio()},
+		{"gitgraphed_coalesced_fetches_total", "counter", "Graph fetches that shared a concurrent fetch's upstream request.", float64(m.coalesced)},
+		{"gitgraphed_retries_total", "counter", "Upstream requests retried after a failure.", float64(m.retries)},
+		{"gitgraphed_hedged_requests_total", "counter", "Upstream requests sent again for being slow to answer.", float64(m.hedged)},
+		{"gitgraphed_hedge_wins_total", "counter", "Hedged upstream requests answered before the original.", float64(m.hedgeWins)},
+	} {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n%s %g\n", c.name, c.help, c.name, c.kind, c.name, c.value)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Telemetry reports the metrics as OpenTelemetry metrics, for a
+// telemetry.Exporter's Metrics
+func (m *Metrics) Telemetry() []telemetry.Metric {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fetch := telemetry.Metric{Name: "gitgraphed.upstream.fetch.duration", Description: "Latency of fetches from the contribution provider.", Unit: "s", Kind: telemetry.Histogram}
+	for l, h := range m.fetches {
+		fetch.Points = append(fetch.Points, h.point(telemetry.String("provider", l.provider), telemetry.String("result", l.result)))
+	}
+	metrics := []telemetry.Metric{
+		fetch,
+		{Name: "gitgraphed.parse.duration", Description: "Time spent parsing contributions pages.", Unit: "s", Kind: telemetry.Histogram, Points: []telemetry.Point{m.parse.point()}},
+	}
+	for _, c := range []struct {
+		name, help string
+		kind       telemetry.MetricKind
+		value      float64
+	}{
+		{"gitgraphed.days_parsed", "Days read from parsed contributions pages.", telemetry.Counter, float64(m.daysParsed)},
+		{"gitgraphed.cache.hits", "Graph lookups answered by the cache.", telemetry.Counter, float64(m.cacheHits)},
+		{"gitgraphed.cache.misses", "Graph lookups that had to be fetched.", telemetry.Counter, float64(m.cacheMisses)},
+		{"gitgraphed.cache.hit_ratio", "Share of graph lookups answered by the cache.", telemetry.Gauge, m.cacheHitRatio()},
+		{"gitgraphed.coalesced_fetches", "Graph fetches that shared a concurrent fetch's upstream request.", telemetry.Counter, float64(m.coalesced)},
+		{"gitgraphed.retries", "Upstream requests retried after a failure.", telemetry.Counter, float64(m.retries)},
+		{"gitgraphed.hedged_requests", "Upstream requests sent again for being slow to answer.", telemetry.Counter, float64(m.hedged)},
+		{"gitgraphed.hedge_wins", "Hedged upstream requests answered before the original.", telemetry.Counter, float64(m.hedgeWins)},
+	} {
+		metrics = append(metrics, telemetry.Metric{Name: c.name, Description: c.help, Unit: "1", Kind: c.kind, Points: []telemetry.Point{{Value: c.value}}})
+	}
+	return metrics
+}
+
+// histogram counts observations into cumulative buckets
+type histogram struct {
+	bounds []float64
+	counts []int64 // per bucket, not cumulative; the last is +Inf
+	sum    float64
+}
+
+func newHistogram(bounds []float64) *histogram {
+	return &histogram{bounds: bounds, counts: make([]int64, len(bounds)+1)}
+}
+
+func (h *histogram) observe(v float64) {
+	i := sort.SearchFloat64s(h.bounds, v)
+	h.counts[i]++
+	h.sum += v
+}
+
+// write writes the histogram's bucket, sum and count series with labels
+func (h *histogram) write(b *strings.Builder, name, labels string) {
+	sep := ""
+	if labels != "" {
+		sep = ","
+	}
+	var cumulative int64
+	for i, bound := range h.bounds {
+		cumulative += h.counts[i]
+		fmt.Fprintf(b, "%s_bucket{%s%sle=\"%g\"} %d\n", name, labels, sep, bound, cumulative)
+	}
+	cumulative += h.counts[len(h.bounds)]
+	fmt.Fprintf(b, "%s_bucket{%s%sle=\"+Inf\"} %d\n", name, labels, sep, cumulative)
+	if labels != "" {
+		labels = "{" + labels + "}"
+	}
+	fmt.Fprintf(b, "%s_sum%s %g\n%s_count%s %d\n", name, labels, h.sum, name, labels, cumulative)
+}
+
+// point is the histogram as a telemetry.Histogram point with attrs
+func (h *histogram) point(attrs ...telemetry.Attr) telemetry.Point {
+	p := telemetry.Point{Attrs: attrs, Bounds: h.bounds, Counts: make([]uint64, len(h.counts)), Sum: h.sum}
+	for i, c := range h.counts {
+		p.Counts[i] = uint64(c)
+		p.Count += uint64(c)
+	}
+	return p
+}
+
+// escapeLabelValue escapes a Prometheus label value
+func escapeLabelValue(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
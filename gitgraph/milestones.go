@@ -0,0 +1,105 @@
+package gitgraph
+
+import (
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Milestone types
+const (
+	MilestoneFirstOfYear   = "first-of-year"  // the year's first day with contributions
+	MilestoneTotal         = "total"          // a round number of contributions reached
+	MilestoneLongestStreak = "longest-streak" // a streak outlasting every earlier one
+	MilestoneBusiestDay    = "busiest-day"    // a day above every earlier one
+)
+
+// Milestone is a notable moment in a contribution history
+type Milestone struct {
+	Type string `json:"type"`
+	Date string `json:"date"` // when it was reached
+	// Value is the total reached, the streak's length, the day's count or
+	// the year's first count
+	Value int `json:"value"`
+}
+
+// Key identifies m across recomputations as days gain contributions: a
+// longer streak or a busier day keeps the key of the milestone it grew from
+func (m Milestone) Key() string {
+	if m.Type == MilestoneTotal {
+		return m.Type + ":" + m.Date + ":" + strconv.Itoa(m.Value)
+	}
+	return m.Type + ":" + m.Date
+}
+
+// MilestoneCrossed returns the highest total milestone in (before, after]:
+// 100, 250, 500, then every 1000
+func MilestoneCrossed(before, after int) (int, bool) {
+	crossed, ok := 0, false
+	for _, m := range []int{100, 250, 500} {
+		if before < m && after >= m {
+			crossed, ok = m, true
+		}
+	}
+	if m := after / 1000 * 1000; m > 0 && before < m {
+		crossed, ok = m, true
+	}
+	return crossed, ok
+}
+
+// FindMilestones walks days in date order, reporting each year's first
+// contribution, each total milestone crossed, and each streak and day that
+// beat every one before it. The first streak and the first active day have
+// nothing to beat, so aren't reported.
+func FindMilestones(days []ContributionDay) []Milestone {
+	sorted := make([]ContributionDay, len(days))
+	copy(sorted, days)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Date < sorted[j].Date })
+
+	milestones := []Milestone{}
+	total, busiest, longest := 0, 0, 0
+	year := 0
+	// The running streak, and its milestone once it beats the record
+	var run int
+	var prev time.Time
+	streakAt := -1
+	for _, day := range sorted {
+		date, err := parseDate(day.Date)
+		if err != nil {
+			continue
+		}
+		if day.Count == 0 {
+			run, streakAt = 0, -1
+			continue
+		}
+		if y := date.Year(); y != year {
+			year = y
+			milestones = append(milestones, Milestone{Type: MilestoneFirstOfYear, Date: day.Date, Value: day.Count})
+		}
+		if crossed, ok := MilestoneCrossed(total, total+day.Count); ok {
+			milestones = append(milestones, Milestone{Type: MilestoneTotal, Date: day.Date, Value: crossed})
+		}
+		total += day.Count
+
+		if busiest > 0 && day.Count > busiest {
+			milestones = append(milestones, Milestone{Type: MilestoneBusiestDay, Date: day.Date, Value: day.Count})
+		}
+		busiest = max(busiest, day.Count)
+
+		if run > 0 && date.Equal(prev.AddDate(0, 0, 1)) {
+			run++
+		} else {
+			run, streakAt = 1, -1
+		}
+		prev = date
+		switch {
+		case streakAt >= 0:
+			milestones[streakAt].Value = run
+		case longest > 0 && run > longest:
+			streakAt = len(milestones)
+			milestones = append(milestones, Milestone{Type: MilestoneLongestStreak, Date: day.Date, Value: run})
+		}
+		longest = max(longest, run)
+	}
+	return milestones
+}
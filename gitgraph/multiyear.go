@@ -0,0 +1,116 @@
+package gitgraph
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// DefaultWorkers bounds how many requests FetchYears and FetchUsers make at once
+const DefaultWorkers = 4
+
+// FetchYears fetches each year concurrently with at most workers requests in flight,
+// merging the results into a single graph covering every year
+func (c *Client) FetchYears(ctx context.Context, username string, years []int, workers int) (*ContributionGraph, error) {
+	graphs := make([]*ContributionGraph, len(years))
+	errs := make([]error, len(years))
+	forEach(len(years), workers, func(i int) {
+		graphs[i], errs[i] = c.Fetch(ctx, username, Options{Year: years[i]})
+	})
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("fetching %d: %w", years[i], err)
+		}
+	}
+	merged := MergeGraphs(graphs...)
+	for i, graph := range graphs {
+		merged.YearTotals = append(merged.YearTotals, YearTotal{Year: years[i], Total: graph.TotalContribs})
+	}
+	sort.Slice(merged.YearTotals, func(i, j int) bool { return merged.YearTotals[i].Year < merged.YearTotals[j].Year })
+	return c.withStreaks(merged), nil
+}
+
+// FetchUsers fetches several users concurrently over the same period, returning
+// graphs in the order of usernames
+func (c *Client) FetchUsers(ctx context.Context, usernames []string, opts Options, workers int) ([]*ContributionGraph, error) {
+	graphs := make([]*ContributionGraph, len(usernames))
+	errs := make([]error, len(usernames))
+	forEach(len(usernames), workers, func(i int) {
+		graphs[i], errs[i] = c.Fetch(ctx, usernames[i], opts)
+	})
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("fetching %s: %w", usernames[i], err)
+		}
+	}
+	return graphs, nil
+}
+
+// MergeGraphs combines graphs of the same user covering different periods.
+// Days are sorted by date; a date present in several graphs keeps the last one seen.
+func MergeGraphs(graphs ...*ContributionGraph) *ContributionGraph {
+	merged := &ContributionGraph{Years: []int{}, Days: []ContributionDay{}}
+	byDate := make(map[string]ContributionDay)
+	seenYears := make(map[int]bool)
+	repos := make(map[string]int)
+
+	for _, graph := range graphs {
+		if graph == nil {
+			continue
+		}
+		if merged.Username == "" {
+			merged.Username = graph.Username
+		}
+		merged.IncludesPrivate = merged.IncludesPrivate || graph.IncludesPrivate
+		merged.Stale = merged.Stale || graph.Stale
+		merged.Approximate = merged.Approximate || graph.Approximate
+		if merged.TimeZone == "" {
+			merged.TimeZone = graph.TimeZone
+		}
+		// A merged graph is as old as its oldest part
+		if graph.FetchedAt != nil && (merged.FetchedAt == nil || graph.FetchedAt.Before(*merged.FetchedAt)) {
+			merged.FetchedAt = graph.FetchedAt
+		}
+		merged.PrivateContribs += graph.PrivateContribs
+		for _, year := range graph.Years {
+			if !seenYears[year] {
+				seenYears[year] = true
+				merged.Years = append(merged.Years, year)
+			}
+		}
+		for _, day := range graph.Days {
+			byDate[day.Date] = day
+		}
+		if graph.Types != nil {
+			if merged.Types == nil {
+				merged.Types = &ContributionTypes{}
+			}
+			merged.Types.add(*graph.Types)
+		}
+		// Windows don't overlap, so each repository's commits add up
+		for _, repo := range graph.Repositories {
+			i, ok := repos[repo.Repository]
+			if !ok {
+				i = len(merged.Repositories)
+				repos[repo.Repository] = i
+				merged.Repositories = append(merged.Repositories, RepositoryContributions{Repository: repo.Repository, Private: repo.Private, Languages: repo.Languages})
+			}
+			merged.Repositories[i].Commits += repo.Commits
+		}
+	}
+	sortRepositories(merged.Repositories)
+
+	// Undated private contributions only show up in the total
+	merged.TotalContribs = merged.PrivateContribs
+	for _, day := range byDate {
+		merged.Days = append(merged.Days, day)
+		merged.TotalContribs += day.Count
+	}
+	sort.Ints(merged.Years)
+	sort.Slice(merged.Days, func(i, j int) bool { return merged.Days[i].Date < merged.Days[j].Date })
+	merged.numberGridWeeks(time.Sunday)
+	return merged
+}
@@ -0,0 +1,65 @@
+package gitgraph
+
+import (
+	"net/http"
+	"time"
+)
+
+// Option configures a Client created by NewClient
+type Option func(*Client)
+
+// WithHTTPClient makes the Client send every request through httpClient, e.g. one
+// instrumented for tracing or backed by a fake transport in tests. Providers
+// built by NewProvider should be given the same client.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.HTTPClient = httpClient
+	}
+}
+
+// WithUserAgent sets the User-Agent header of every request, including the
+// scraper's, which otherwise presents itself as a browser
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithBaseURL points the default GitHub provider at an Enterprise Server
+// instance, e.g. https://github.mycompany.com
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.BaseURL = baseURL
+	}
+}
+
+// WithClock makes the Client take the current time from now, which decides the
+// default year and the day streaks are computed up to
+func WithClock(now func() time.Time) Option {
+	return func(c *Client) {
+		c.Now = now
+	}
+}
+
+// userAgentTransport sets the User-Agent header before passing requests to Base
+type userAgentTransport struct {
+	Base      http.RoundTripper
+	UserAgent string
+}
+
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", t.UserAgent)
+	return t.Base.RoundTrip(req)
+}
+
+// withUserAgent returns a copy of httpClient sending userAgent, leaving the original untouched
+func withUserAgent(httpClient *http.Client, userAgent string) *http.Client {
+	wrapped := *httpClient
+	base := wrapped.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	wrapped.Transport = &userAgentTransport{Base: base, UserAgent: userAgent}
+	return &wrapped
+}
@@ -0,0 +1,167 @@
+package gitgraph
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+)
+
+// OrgGraph aggregates the contributions of an organization's members
+type OrgGraph struct {
+	Org     string             `json:"org"`
+	Graph   *ContributionGraph `json:"graph"`
+	Members []MemberTotal      `json:"members"`
+}
+
+// MemberTotal is one member's share of an OrgGraph
+type MemberTotal struct {
+	Username string `json:"username"`
+	Total    int    `json:"totalContributions"`
+}
+
+// OrgMembers lists the logins of an organization's members. Private members are only
+// included when the token belongs to a member of the organization.
+func (c *Client) OrgMembers(ctx context.Context, org string) ([]string, error) {
+	gh := c.GitHub()
+	if gh.Token == "" {
+		return nil, fmt.Errorf("listing organization members requires a token")
+	}
+
+	members := []string{}
+	next := fmt.Sprintf("%s/orgs/%s/members?per_page=100", gh.restEndpoint(), url.PathEscape(org))
+	for next != "" {
+		var page []struct {
+			Login string `json:"login"`
+		}
+		var err error
+		next, err = getJSON(ctx, gh.HTTPClient, gh.Token, next, &page)
+		if err != nil {
+			return nil, err
+		}
+		for _, member := range page {
+			members = append(members, member.Login)
+		}
+	}
+	return members, nil
+}
+
+// OrgRepo is one of an organization's repositories
+type OrgRepo struct {
+	FullName string `json:"full_name"` // owner/name
+	CloneURL string `json:"clone_url"`
+	Private  bool   `json:"private"`
+	Fork     bool   `json:"fork"`
+	Archived bool   `json:"archived"`
+}
+
+// OrgRepos lists an organization's repositories. Without a token only its
+// public ones are listed; with one, also the private ones it can read.
+func (c *Client) OrgRepos(ctx context.Context, org string) ([]OrgRepo, error) {
+	gh := c.GitHub()
+	repos := []OrgRepo{}
+	next := fmt.Sprintf("%s/orgs/%s/repos?type=all&per_page=100", gh.restEndpoint(), url.PathEscape(org))
+	for next != "" {
+		var page []OrgRepo
+		var err error
+		next, err = getJSON(ctx, gh.HTTPClient, gh.Token, next, &page)
+		if err != nil {
+			return nil, err
+		}
+		repos = append(repos, page...)
+	}
+	return repos, nil
+}
+
+// FetchOrg fetches every member of org and sums their days into one graph.
+// Each member's graph is added to the sum as it arrives and then dropped, so
+// memory stays bounded however large the organization.
+func (c *Client) FetchOrg(ctx context.Context, org string, opts Options, workers int) (*OrgGraph, error) {
+	members, err := c.OrgMembers(ctx, org)
+	if err != nil {
+		return nil, err
+	}
+
+	var mu sync.Mutex
+	sum := newGraphSum()
+	totals := make([]MemberTotal, len(members))
+	errs := make([]error, len(members))
+	forEach(len(members), workers, func(i int) {
+		graph, err := c.Fetch(ctx, members[i], opts)
+		if err != nil {
+			errs[i] = err
+			return
+		}
+		totals[i] = MemberTotal{Username: graph.Username, Total: graph.TotalContribs}
+		mu.Lock()
+		defer mu.Unlock()
+		sum.add(graph)
+	})
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("fetching %s: %w", members[i], err)
+		}
+	}
+
+	result := &OrgGraph{Org: org, Graph: sum.graph(org), Members: totals}
+	sort.SliceStable(result.Members, func(i, j int) bool { return result.Members[i].Total > result.Members[j].Total })
+	return result, nil
+}
+
+// SumGraphs adds up the daily counts of graphs into a single graph named name.
+// Levels are recomputed from the summed counts.
+func SumGraphs(name string, graphs []*ContributionGraph) *ContributionGraph {
+	sum := newGraphSum()
+	for _, graph := range graphs {
+		sum.add(graph)
+	}
+	return sum.graph(name)
+}
+
+// graphSum accumulates the daily counts of graphs added one at a time
+type graphSum struct {
+	counts map[string]int
+	years  map[int]bool
+}
+
+func newGraphSum() *graphSum {
+	return &graphSum{counts: make(map[string]int), years: make(map[int]bool)}
+}
+
+func (s *graphSum) add(graph *ContributionGraph) {
+	for _, year := range graph.Years {
+		s.years[year] = true
+	}
+	for _, day := range graph.Days {
+		s.counts[day.Date] += day.Count
+	}
+}
+
+// graph returns the sum so far as a graph named name
+func (s *graphSum) graph(name string) *ContributionGraph {
+	dates := make([]string, 0, len(s.counts))
+	for date := range s.counts {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	thresholds := QuartileThresholds(s.counts)
+	sum := &ContributionGraph{Username: name, Years: []int{}, Days: []ContributionDay{}}
+	for year := range s.years {
+		sum.Years = append(sum.Years, year)
+	}
+	sort.Ints(sum.Years)
+	for _, date := range dates {
+		day, err := parseDate(date)
+		if err != nil {
+			continue
+		}
+		count := s.counts[date]
+		sum.Days = append(sum.Days, newContributionDay(day, count, levelForCount(count, thresholds)))
+		sum.TotalContribs += count
+	}
+	sum.numberGridWeeks(time.Sunday)
+	return sum
+}
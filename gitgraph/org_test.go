@@ -0,0 +1,77 @@
+package gitgraph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"runtime/metrics"
+	"testing"
+	"time"
+)
+
+// peakHeap runs fn while sampling the heap each garbage collection finds
+// live, returning the most seen
+func peakHeap(fn func()) uint64 {
+	sample := []metrics.Sample{{Name: "/gc/heap/live:bytes"}}
+	done := make(chan struct{})
+	peak := make(chan uint64)
+	runtime.GC()
+	go func() {
+		var most uint64
+		ticker := time.NewTicker(100 * time.Microsecond)
+		defer ticker.Stop()
+		for {
+			metrics.Read(sample)
+			most = max(most, sample[0].Value.Uint64())
+			select {
+			case <-done:
+				peak <- most
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	fn()
+	close(done)
+	return <-peak
+}
+
+// BenchmarkFetchOrg sums ever larger organizations of fake members. Each
+// member's graph is dropped once added, so the live heap stays about the
+// same while allocations grow with the members.
+func BenchmarkFetchOrg(b *testing.B) {
+	for _, size := range []int{100, 1000, 3000} {
+		b.Run(fmt.Sprintf("members=%d", size), func(b *testing.B) {
+			members := make([]map[string]string, size)
+			for i := range members {
+				members[i] = map[string]string{"login": fmt.Sprintf("member%d", i)}
+			}
+			page, err := json.Marshal(members)
+			if err != nil {
+				b.Fatal(err)
+			}
+			api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write(page)
+			}))
+			defer api.Close()
+
+			client := NewClient(nil, WithBaseURL(api.URL))
+			client.Token = "token"
+			client.Provider = NewFake(1)
+			b.ReportAllocs()
+			var peak uint64
+			for i := 0; i < b.N; i++ {
+				peak = max(peak, peakHeap(func() {
+					if _, err := client.FetchOrg(context.Background(), "bench", Options{Year: 2024}, 8); err != nil {
+						b.Fatal(err)
+					}
+				}))
+			}
+			b.ReportMetric(float64(peak), "peak-live-B")
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package gitgraph
+
+import (
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// privateRegex matches the notice shown instead of the calendar of a user who
+// made their profile or activity overview private
+var privateRegex = regexp.MustCompile(`(?i)(profile|activity overview) is private`)
+
+// totalRegex matches the calendar heading, e.g. "1,234 contributions in the last year" or "... in 2023"
+var totalRegex = regexp.MustCompile(`([\d,]+)\s+contributions?\s+in\s`)
+
+// scrapedCell is a calendar cell found while tokenizing the page
+type scrapedCell struct {
+	id    string
+	date  string
+	level int
+	text  []byte // count text inside the cell, used by older markup
+	count int    // data-count of the SVG calendar's <rect> cells, -1 elsewhere
+}
+
+// calendarDays is how many cells a year's calendar has at most, so a page's
+// cells fit without growing
+const calendarDays = 7 * 54
+
+// ParseContributions reads a GitHub contributions page that was fetched by other
+// means, e.g. by a browser through its own proxy, into a graph without a username
+func ParseContributions(r io.Reader) (*ContributionGraph, error) {
+	return parseContributions(r)
+}
+
+// parseContributions extracts the contribution days from a GitHub contributions page.
+// Cells are located by their data-date and data-level attributes. The count is read
+// from the <tool-tip> referencing the cell's id, falling back to the cell's own text.
+// The SVG calendar GitHub drew until 2023, with rect cells holding a data-count,
+// is read too. testdata holds a page in each markup, to replay with --fixture.
+// A page with no cells that says the profile is private returns ErrProfilePrivate.
+//
+// The page is read in one pass over the tokenizer's own buffers: only the
+// attributes and text of cells, tooltips and the heading are copied out, and
+// tooltips are reduced to their counts as they close.
+func parseContributions(r io.Reader) (*ContributionGraph, error) {
+	z := html.NewTokenizer(r)
+
+	cells := make([]scrapedCell, 0, calendarDays)
+	tooltips := make(map[string]int, calendarDays)
+	totalContribs := -1
+
+	current := -1               // index of the cell whose text is being read
+	var tooltipFor string       // id referenced by the open <tool-tip>
+	var tooltip []byte          // its text so far
+	var heading strings.Builder // text of the open <h2>
+	inHeading := false
+	private := false
+
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			if err := z.Err(); err != io.EOF {
+				return nil, err
+			}
+			if private && len(cells) == 0 {
+				return nil, ErrProfilePrivate
+			}
+			return buildGraph(cells, tooltips, totalContribs), nil
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			// Tags are read without building tokens, since only a few matter
+			name, hasAttr := z.TagName()
+			switch {
+			case string(name) == "rect":
+				cell := scrapedCell{count: -1}
+				for hasAttr {
+					var key, val []byte
+					key, val, hasAttr = z.TagAttr()
+					switch string(key) {
+					case "data-date":
+						cell.date = string(val)
+					case "data-level":
+						cell.level = atoi(val)
+					case "data-count":
+						cell.count = atoi(val)
+					}
+				}
+				// Rects also draw the legend, which has no dates
+				if cell.date != "" && cell.count >= 0 {
+					cells = append(cells, cell)
+				}
+			case atom.Lookup(name) == atom.Td:
+				cell := scrapedCell{count: -1}
+				for hasAttr {
+					var key, val []byte
+					key, val, hasAttr = z.TagAttr()
+					switch string(key) {
+					case "id":
+						cell.id = string(val)
+					case "data-date":
+						cell.date = string(val)
+					case "data-level":
+						cell.level = atoi(val)
+					}
+				}
+				if cell.date != "" {
+					current = len(cells)
+					cells = append(cells, cell)
+				}
+			case string(name) == "tool-tip":
+				for hasAttr {
+					var key, val []byte
+					key, val, hasAttr = z.TagAttr()
+					if string(key) == "for" {
+						tooltipFor = string(val)
+					}
+				}
+				tooltip = tooltip[:0]
+			case atom.Lookup(name) == atom.H2:
+				inHeading = true
+				heading.Reset()
+			}
+
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			switch string(name) {
+			case "td":
+				current = -1
+			case "tool-tip":
+				if tooltipFor != "" && len(tooltip) > 0 {
+					tooltips[tooltipFor] = parseCount(tooltip)
+				}
+				tooltipFor = ""
+			case "h2":
+				inHeading = false
+				if m := totalRegex.FindStringSubmatch(heading.String()); m != nil && totalContribs < 0 {
+					totalContribs, _ = strconv.Atoi(strings.ReplaceAll(m[1], ",", ""))
+				}
+			}
+
+		case html.TextToken:
+			text := z.Text()
+			if current < 0 && tooltipFor == "" && !inHeading {
+				// Only a page without a calendar can be a private profile's
+				if len(cells) == 0 && !private {
+					private = privateRegex.Match(text)
+				}
+				continue
+			}
+			switch {
+			case current >= 0:
+				cells[current].text = append(cells[current].text, text...)
+			case tooltipFor != "":
+				tooltip = append(tooltip, text...)
+			}
+			if inHeading {
+				heading.Write(text)
+			}
+		}
+	}
+}
+
+// buildGraph joins cells with their tooltips' counts. A missing heading total
+// is replaced by the sum of the days.
+func buildGraph(cells []scrapedCell, tooltips map[string]int, total int) *ContributionGraph {
+	days := make([]ContributionDay, 0, len(cells))
+	sum := 0
+	for _, cell := range cells {
+		date, err := parseDate(cell.date)
+		if err != nil {
+			continue
+		}
+
+		count := cell.count
+		if count < 0 {
+			var ok bool
+			if count, ok = tooltips[cell.id]; cell.id == "" || !ok {
+				count = parseCount(cell.text)
+			}
+		}
+		sum += count
+		// The date parsed, so it's already written as Date would be
+		days = append(days, makeContributionDay(date, cell.date, count, cell.level))
+	}
+
+	if total < 0 {
+		total = sum
+	}
+	return &ContributionGraph{
+		TotalContribs: total,
+		Days:          days,
+	}
+}
+
+// parseCount reads counts such as "No contributions", "1 contribution on ..." or
+// "1,024 contributions" from the first word of text, 0 when it isn't a number
+func parseCount(text []byte) int {
+	i := 0
+	for i < len(text) && isSpace(text[i]) {
+		i++
+	}
+	count, digits := 0, 0
+	for ; i < len(text) && !isSpace(text[i]); i++ {
+		switch c := text[i]; {
+		case c >= '0' && c <= '9':
+			count = count*10 + int(c-'0')
+			digits++
+		case c != ',':
+			return 0
+		}
+	}
+	if digits == 0 {
+		return 0
+	}
+	return count
+}
+
+// isSpace reports whether c separates words of a page's text
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+// atoi reads an attribute's whole number, 0 when it isn't one
+func atoi(b []byte) int {
+	n, _ := strconv.Atoi(string(b))
+	return n
+}
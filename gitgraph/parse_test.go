@@ -0,0 +1,97 @@
+package gitgraph
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// testdataPages are the saved contributions pages, one in each markup
+func testdataPages(tb testing.TB) []string {
+	tb.Helper()
+	paths, err := filepath.Glob("testdata/*.html")
+	if err != nil || len(paths) == 0 {
+		tb.Fatalf("no testdata pages: %v", err)
+	}
+	return paths
+}
+
+func TestParseContributions(t *testing.T) {
+	// Every page holds the same week, read this way before the parser was
+	// reworked into a single pass
+	want := []struct {
+		date         string
+		count, level int
+	}{
+		{"2024-01-01", 0, 0},
+		{"2024-01-02", 3, 2},
+		{"2024-01-03", 1, 1},
+		{"2024-01-04", 0, 0},
+		{"2024-01-05", 7, 3},
+		{"2024-01-06", 2, 1},
+		{"2024-01-07", 12, 4},
+	}
+	for _, path := range testdataPages(t) {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			graph, err := parseContributions(bytes.NewReader(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if graph.TotalContribs != 25 {
+				t.Errorf("total = %d, want 25", graph.TotalContribs)
+			}
+			if len(graph.Days) != len(want) {
+				t.Fatalf("got %d days, want %d", len(graph.Days), len(want))
+			}
+			for i, day := range graph.Days {
+				// The calendar fields match those of a day built from scratch
+				expected, err := NewDay(want[i].date, want[i].count, want[i].level)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(day, expected) {
+					t.Errorf("day %d = %+v, want %+v", i, day, expected)
+				}
+			}
+		})
+	}
+}
+
+func TestParseCount(t *testing.T) {
+	for text, want := range map[string]int{
+		"":                                0,
+		"No contributions on January 1st": 0,
+		"1 contribution on January 3rd.":  1,
+		"  1,024 contributions":           1024,
+		"12":                              12,
+		"3a contributions":                0,
+	} {
+		if got := parseCount([]byte(text)); got != want {
+			t.Errorf("parseCount(%q) = %d, want %d", text, got, want)
+		}
+	}
+}
+
+func BenchmarkParseContributions(b *testing.B) {
+	for _, path := range testdataPages(b) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(filepath.Base(path), func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(data)))
+			for i := 0; i < b.N; i++ {
+				if _, err := parseContributions(bytes.NewReader(data)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
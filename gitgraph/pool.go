@@ -0,0 +1,27 @@
+package gitgraph
+
+import "sync"
+
+// forEach runs fn for every index in [0, n) using at most workers goroutines
+func forEach(n, workers int, fn func(i int)) {
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers && w < n; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
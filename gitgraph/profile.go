@@ -0,0 +1,94 @@
+package gitgraph
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+)
+
+// ProfileMetrics are the profile counts that can be sampled over time: stars
+// received by the user's own repositories, and followers
+var ProfileMetrics = []string{"stars", "followers"}
+
+// ProfileCounts are a user's profile counts at one point in time
+type ProfileCounts struct {
+	Stars     int `json:"stars"`
+	Followers int `json:"followers"`
+}
+
+// Metric returns the count named by one of ProfileMetrics
+func (p ProfileCounts) Metric(name string) (int, error) {
+	switch name {
+	case "stars":
+		return p.Stars, nil
+	case "followers":
+		return p.Followers, nil
+	}
+	return 0, fmt.Errorf("unknown metric %q (expected one of %v)", name, ProfileMetrics)
+}
+
+// ProfileCounts reads username's follower count and the stars of the
+// repositories they own, forks excluded
+func (g *GitHub) ProfileCounts(ctx context.Context, username string) (*ProfileCounts, error) {
+	var user struct {
+		Followers int `json:"followers"`
+	}
+	endpoint := g.restEndpoint() + "/users/" + url.PathEscape(username)
+	if _, err := getJSON(ctx, g.HTTPClient, g.Token, endpoint, &user); err != nil {
+		return nil, err
+	}
+	counts := &ProfileCounts{Followers: user.Followers}
+
+	next := endpoint + "/repos?type=owner&per_page=100"
+	for next != "" {
+		var repos []struct {
+			Fork       bool `json:"fork"`
+			Stargazers int  `json:"stargazers_count"`
+		}
+		var err error
+		if next, err = getJSON(ctx, g.HTTPClient, g.Token, next, &repos); err != nil {
+			return nil, err
+		}
+		for _, repo := range repos {
+			if !repo.Fork {
+				counts.Stars += repo.Stargazers
+			}
+		}
+	}
+	return counts, nil
+}
+
+// Sample is a profile count as read at a point in time
+type Sample struct {
+	Time  time.Time `json:"time"`
+	Value int       `json:"value"`
+}
+
+// TrendGraph turns samples of a count into a graph of its daily gains over
+// from..to, so the calendar and trend renderers can draw it. A day's gain is
+// its last sample less the last sample before it; losses count as none, since
+// calendars can't show them. Samples are grouped into days in loc.
+func TrendGraph(username string, samples []Sample, from, to time.Time, loc *time.Location) *ContributionGraph {
+	sorted := append([]Sample(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Time.Before(sorted[j].Time) })
+
+	last := map[string]int{}
+	var dates []string
+	for _, sample := range sorted {
+		date := sample.Time.In(loc).Format("2006-01-02")
+		if _, ok := last[date]; !ok {
+			dates = append(dates, date)
+		}
+		last[date] = sample.Value
+	}
+
+	gains := map[string]int{}
+	for i := 1; i < len(dates); i++ {
+		if gain := last[dates[i]] - last[dates[i-1]]; gain > 0 {
+			gains[dates[i]] = gain
+		}
+	}
+	return GraphFromCounts(username, gains, from, to, QuartileThresholds(gains))
+}
@@ -0,0 +1,166 @@
+package gitgraph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Provider fetches contribution days for a user from a code hosting service
+type Provider interface {
+	// Name identifies the provider, e.g. in cache keys
+	Name() string
+	// FetchRange returns the contributions between from and to (inclusive)
+	FetchRange(ctx context.Context, username string, from, to time.Time) (*ContributionGraph, error)
+}
+
+// ProviderFunc adapts fetch into a Provider identified by name
+func ProviderFunc(name string, fetch func(ctx context.Context, username string, from, to time.Time) (*ContributionGraph, error)) Provider {
+	return providerFunc{name: name, fetch: fetch}
+}
+
+type providerFunc struct {
+	name  string
+	fetch func(ctx context.Context, username string, from, to time.Time) (*ContributionGraph, error)
+}
+
+func (p providerFunc) Name() string { return p.name }
+
+func (p providerFunc) FetchRange(ctx context.Context, username string, from, to time.Time) (*ContributionGraph, error) {
+	return p.fetch(ctx, username, from, to)
+}
+
+// Fallback tries each of its providers in order and returns the first graph fetched,
+// so a broken source degrades to the next one instead of failing the fetch
+type Fallback struct {
+	Providers []Provider
+}
+
+// NewFallback creates a Fallback trying providers in the given order
+func NewFallback(providers ...Provider) *Fallback {
+	return &Fallback{Providers: providers}
+}
+
+// Name is the first provider's, as every provider in a chain yields the same calendar
+func (f *Fallback) Name() string {
+	if len(f.Providers) == 0 {
+		return "fallback"
+	}
+	return f.Providers[0].Name()
+}
+
+func (f *Fallback) FetchRange(ctx context.Context, username string, from, to time.Time) (*ContributionGraph, error) {
+	if len(f.Providers) == 0 {
+		return nil, fmt.Errorf("no providers to fetch from")
+	}
+	errs := []error{}
+	for _, provider := range f.Providers {
+		graph, err := provider.FetchRange(ctx, username, from, to)
+		if err == nil {
+			return graph, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", provider.Name(), err))
+	}
+	return nil, errors.Join(errs...)
+}
+
+// zoneSuffix tells apart in provider names, and through them cache keys,
+// graphs bucketed in a zone other than UTC
+func zoneSuffix(loc *time.Location) string {
+	if loc == nil || loc == time.UTC {
+		return ""
+	}
+	return "@" + loc.String()
+}
+
+// orUTC returns loc, or UTC when it is nil
+func orUTC(loc *time.Location) *time.Location {
+	if loc == nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// midnight is the start of date's day in loc
+func midnight(date time.Time, loc *time.Location) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc)
+}
+
+// levelForCount buckets count into a 0-4 level; thresholds holds the minimum count of levels 1-4
+func levelForCount(count int, thresholds []int) int {
+	level := 0
+	for i, min := range thresholds {
+		if count >= min {
+			level = i + 1
+		}
+	}
+	return level
+}
+
+// Relevel recomputes every day's level from its count, replacing the provider's
+// per-user buckets; thresholds holds the minimum count of levels 1-4
+func (g *ContributionGraph) Relevel(thresholds []int) {
+	for i, day := range g.Days {
+		g.Days[i].Level = levelForCount(day.Count, thresholds)
+		g.Days[i].ContribLevel = levelName(g.Days[i].Level)
+	}
+}
+
+// GraphFromCounts builds a graph with one day per date in from..to from sparse
+// YYYY-MM-DD counts; thresholds holds the minimum count of levels 1-4
+func GraphFromCounts(username string, counts map[string]int, from, to time.Time, thresholds []int) *ContributionGraph {
+	graph := &ContributionGraph{
+		Username: username,
+		Years:    yearsBetween(from, to),
+		Days:     []ContributionDay{},
+	}
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		count := counts[d.Format("2006-01-02")]
+		graph.Days = append(graph.Days, newContributionDay(d, count, levelForCount(count, thresholds)))
+		graph.TotalContribs += count
+	}
+	graph.numberGridWeeks(time.Sunday)
+	return graph
+}
+
+// SharedThresholds derives quartile thresholds from the counts of every graph
+// together, so levels mean the same across users
+func SharedThresholds(graphs []*ContributionGraph) []int {
+	counts := map[string]int{}
+	for i, graph := range graphs {
+		for _, day := range graph.Days {
+			counts[fmt.Sprintf("%d %s", i, day.Date)] = day.Count
+		}
+	}
+	return QuartileThresholds(counts)
+}
+
+// QuartileThresholds derives level thresholds from the quartiles of the non-zero
+// counts, approximating how GitHub colors its calendar
+func QuartileThresholds(counts map[string]int) []int {
+	active := []int{}
+	for _, count := range counts {
+		if count > 0 {
+			active = append(active, count)
+		}
+	}
+	if len(active) == 0 {
+		return []int{1, 2, 3, 4}
+	}
+	sort.Ints(active)
+
+	thresholds := []int{1}
+	for _, q := range []int{1, 2, 3} {
+		t := active[(len(active)-1)*q/4]
+		if t <= thresholds[len(thresholds)-1] {
+			t = thresholds[len(thresholds)-1] + 1
+		}
+		thresholds = append(thresholds, t)
+	}
+	return thresholds
+}
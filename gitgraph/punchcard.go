@@ -0,0 +1,63 @@
+package gitgraph
+
+import "time"
+
+// PunchCard counts contributions by weekday and hour of day, showing when
+// someone works rather than only on which days
+type PunchCard struct {
+	Username string `json:"username"`
+	TimeZone string `json:"timeZone"`
+	From     string `json:"from,omitempty"` // date of the oldest event counted
+	To       string `json:"to,omitempty"`   // date of the newest
+	Total    int    `json:"total"`
+	// Hours holds the counts by weekday, Sunday first, then by hour
+	Hours   [7][24]int     `json:"hours"`
+	Busiest *PunchCardSlot `json:"busiest,omitempty"`
+}
+
+// PunchCardSlot is one hour of one weekday
+type PunchCardSlot struct {
+	Weekday string `json:"weekday"`
+	Hour    int    `json:"hour"`
+	Count   int    `json:"count"`
+}
+
+// BuildPunchCard places the contributions of events at their time in loc
+func BuildPunchCard(username string, events []Event, loc *time.Location) *PunchCard {
+	card := &PunchCard{Username: username, TimeZone: loc.String()}
+	if loc == time.Local {
+		// "Local" means nothing to readers elsewhere; name the zone
+		card.TimeZone, _ = time.Now().In(loc).Zone()
+	}
+	var first, last time.Time
+	for _, event := range events {
+		n := event.Contributions()
+		if n == 0 {
+			continue
+		}
+		at := event.CreatedAt.In(loc)
+		card.Hours[at.Weekday()][at.Hour()] += n
+		card.Total += n
+		if first.IsZero() || at.Before(first) {
+			first = at
+		}
+		if at.After(last) {
+			last = at
+		}
+	}
+	if card.Total == 0 {
+		return card
+	}
+	card.From, card.To = first.Format("2006-01-02"), last.Format("2006-01-02")
+
+	busiest := PunchCardSlot{}
+	for weekday, hours := range card.Hours {
+		for hour, count := range hours {
+			if count > busiest.Count {
+				busiest = PunchCardSlot{Weekday: time.Weekday(weekday).String(), Hour: hour, Count: count}
+			}
+		}
+	}
+	card.Busiest = &busiest
+	return card
+}
@@ -0,0 +1,99 @@
+package gitgraph
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// TokenBucket is a rate limiter allowing bursts of up to Burst events, refilled
+// at Rate events per second
+type TokenBucket struct {
+	Rate  float64
+	Burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewTokenBucket creates a full bucket refilled at rate events per second
+func NewTokenBucket(rate float64, burst int) *TokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &TokenBucket{Rate: rate, Burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// refill adds the tokens accrued since the last call, up to Burst. b.mu must be held.
+func (b *TokenBucket) refill(now time.Time) {
+	b.tokens += now.Sub(b.last).Seconds() * b.Rate
+	if b.tokens > b.Burst {
+		b.tokens = b.Burst
+	}
+	b.last = now
+}
+
+// reserve takes a token, returning how long to wait before it may be used
+func (b *TokenBucket) reserve(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill(now)
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.Rate * float64(time.Second))
+}
+
+// Allow takes a token if one is available without waiting
+func (b *TokenBucket) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill(time.Now())
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// Wait blocks until a token is available or ctx is done
+func (b *TokenBucket) Wait(ctx context.Context) error {
+	delay := b.reserve(time.Now())
+	if delay == 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// RateLimitTransport delays requests so they don't exceed the bucket's rate
+type RateLimitTransport struct {
+	Base   http.RoundTripper
+	Bucket *TokenBucket
+}
+
+// NewRateLimitTransport limits base (or the NewTransport default) to perSecond requests
+func NewRateLimitTransport(base http.RoundTripper, perSecond float64) *RateLimitTransport {
+	if base == nil {
+		base, _ = NewTransport("")
+	}
+	return &RateLimitTransport{Base: base, Bucket: NewTokenBucket(perSecond, 1)}
+}
+
+func (t *RateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.Bucket.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	return t.Base.RoundTrip(req)
+}
@@ -0,0 +1,98 @@
+package gitgraph
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ProviderOptions are the settings a ProviderFactory builds a provider from
+type ProviderOptions struct {
+	HTTPClient     *http.Client
+	BaseURL        string // instance URL, empty for the provider's public service
+	Token          string
+	IncludePrivate bool
+	DayTypes       bool // split days by kind of contribution, where the provider can
+	NoEvents       bool // never approximate a graph from events when the calendar fails
+	// Location is the zone timestamped contributions are dated in, for
+	// providers reading timestamps rather than a calendar; nil for UTC
+	Location *time.Location
+	Seed     int64            // of the fake provider's made-up contributions
+	Now      func() time.Time // the fake provider's current time; nil uses time.Now
+}
+
+// ProviderFactory creates a provider from its options
+type ProviderFactory func(opts ProviderOptions) Provider
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]ProviderFactory{
+		"github": func(opts ProviderOptions) Provider {
+			github := NewGitHub(opts.HTTPClient, opts.BaseURL, opts.Token)
+			github.IncludePrivate = opts.IncludePrivate
+			github.DayTypes = opts.DayTypes
+			github.NoEvents = opts.NoEvents
+			github.Location = opts.Location
+			return github
+		},
+		"gitlab": func(opts ProviderOptions) Provider {
+			return NewGitLab(opts.HTTPClient, opts.BaseURL)
+		},
+		"bitbucket": func(opts ProviderOptions) Provider {
+			bitbucket := NewBitbucket(opts.HTTPClient, opts.BaseURL, opts.Token)
+			bitbucket.Location = opts.Location
+			return bitbucket
+		},
+		"gitea": func(opts ProviderOptions) Provider {
+			gitea := NewGitea(opts.HTTPClient, opts.BaseURL)
+			gitea.Location = opts.Location
+			return gitea
+		},
+		"forgejo": func(opts ProviderOptions) Provider {
+			if opts.BaseURL == "" {
+				opts.BaseURL = DefaultForgejoURL
+			}
+			gitea := NewGitea(opts.HTTPClient, opts.BaseURL)
+			gitea.Location = opts.Location
+			return gitea
+		},
+		"fake": func(opts ProviderOptions) Provider {
+			fake := NewFake(opts.Seed)
+			fake.Now = opts.Now
+			return fake
+		},
+	}
+)
+
+// RegisterProvider makes a provider available to NewProvider under name,
+// replacing any provider already registered with it
+func RegisterProvider(name string, factory ProviderFactory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[name] = factory
+}
+
+// NewProvider creates the provider registered under name
+func NewProvider(name string, opts ProviderOptions) (Provider, error) {
+	registryMu.RLock()
+	factory, ok := registry[name]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unsupported provider: %s", name)
+	}
+	return factory(opts), nil
+}
+
+// ProviderNames lists the registered providers in alphabetical order
+func ProviderNames() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
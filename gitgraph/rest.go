@@ -0,0 +1,57 @@
+package gitgraph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strconv"
+)
+
+const restAPIURL = "https://api.github.com"
+
+// nextLinkRegex extracts the next page URL from a REST Link header
+var nextLinkRegex = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// getJSON performs an authenticated REST API GET, decoding the response into v.
+// It returns the URL of the next page when the response is paginated.
+func getJSON(ctx context.Context, httpClient *http.Client, token, url string, v any) (string, error) {
+	next, _, err := getJSONPage(ctx, httpClient, token, url, v)
+	return next, err
+}
+
+// getJSONPage is getJSON also returning the requests left before the rate
+// limit resets, or -1 when the response doesn't say
+func getJSONPage(ctx context.Context, httpClient *http.Client, token, url string, v any) (string, int, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return "", -1, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", -1, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", -1, newStatusError(resp)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return "", -1, parseError(err)
+	}
+
+	next := ""
+	if m := nextLinkRegex.FindStringSubmatch(resp.Header.Get("Link")); m != nil {
+		next = m[1]
+	}
+	remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		remaining = -1
+	}
+	return next, remaining, nil
+}
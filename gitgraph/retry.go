@@ -0,0 +1,119 @@
+package gitgraph
+
+import (
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// DefaultRetries is how many times a failed request is retried by default
+const DefaultRetries = 3
+
+// RetryTransport retries requests that fail with a network error, 429 or 5xx,
+// backing off exponentially with full jitter and honoring Retry-After on 429/503
+type RetryTransport struct {
+	Base          http.RoundTripper
+	MaxRetries    int
+	BaseDelay     time.Duration
+	MaxDelay      time.Duration
+	MaxRetryAfter time.Duration // longer Retry-After values are not waited for
+}
+
+// NewRetryTransport wraps base (or the NewTransport default) to retry up to maxRetries times
+func NewRetryTransport(base http.RoundTripper, maxRetries int) *RetryTransport {
+	if base == nil {
+		base, _ = NewTransport("")
+	}
+	return &RetryTransport{
+		Base:          base,
+		MaxRetries:    maxRetries,
+		BaseDelay:     500 * time.Millisecond,
+		MaxDelay:      30 * time.Second,
+		MaxRetryAfter: 2 * time.Minute,
+	}
+}
+
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := t.Base.RoundTrip(req)
+		if attempt >= t.MaxRetries || !retryable(resp, err) {
+			return resp, err
+		}
+
+		// Requests with a body can only be retried if it can be replayed
+		hasBody := req.Body != nil && req.Body != http.NoBody
+		if hasBody && req.GetBody == nil {
+			return resp, err
+		}
+
+		delay := t.backoff(attempt)
+		if resp != nil {
+			if wait, ok := retryAfter(resp); ok {
+				if wait > t.MaxRetryAfter {
+					return resp, nil
+				}
+				delay = wait
+			}
+			resp.Body.Close()
+		}
+
+		if hasBody {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+
+		DefaultMetrics.ObserveRetry()
+		slog.DebugContext(req.Context(), "retrying request", "url", req.URL.Redacted(), "attempt", attempt+1, "delay", delay)
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns a random delay up to BaseDelay * 2^attempt, capped at MaxDelay
+func (t *RetryTransport) backoff(attempt int) time.Duration {
+	ceiling := t.BaseDelay << attempt
+	if ceiling <= 0 || ceiling > t.MaxDelay {
+		ceiling = t.MaxDelay
+	}
+	return time.Duration(rand.Int63n(int64(ceiling) + 1))
+}
+
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// retryAfter parses the Retry-After header of 429 and 503 responses, in seconds or as an HTTP date
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return 0, false
+	}
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		wait := time.Until(at)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
@@ -0,0 +1,66 @@
+package gitgraph
+
+import (
+	"fmt"
+	"time"
+)
+
+// Bucket aggregates the days of one week or month
+type Bucket struct {
+	Start      string  `json:"start"`
+	End        string  `json:"end"`
+	Sum        int     `json:"sum"`
+	Mean       float64 `json:"mean"`
+	Max        int     `json:"max"`
+	ActiveDays int     `json:"activeDays"`
+	Days       int     `json:"days"`
+}
+
+// Rollup holds a graph's days aggregated into consecutive buckets
+type Rollup struct {
+	Username string   `json:"username"`
+	Period   string   `json:"period"` // week or month
+	Buckets  []Bucket `json:"buckets"`
+}
+
+// RollupGraph aggregates graph's days by period: "week" for Sunday-started weeks,
+// matching the calendar's columns, or "month". Days are assumed to be in date order.
+func RollupGraph(graph *ContributionGraph, period string) (*Rollup, error) {
+	var bucketStart func(time.Time) time.Time
+	switch period {
+	case "week":
+		bucketStart = func(d time.Time) time.Time { return d.AddDate(0, 0, -int(d.Weekday())) }
+	case "month":
+		bucketStart = func(d time.Time) time.Time { return time.Date(d.Year(), d.Month(), 1, 0, 0, 0, 0, d.Location()) }
+	default:
+		return nil, fmt.Errorf("unsupported rollup period: %s", period)
+	}
+
+	rollup := &Rollup{Username: graph.Username, Period: period, Buckets: []Bucket{}}
+	var current time.Time
+	for _, day := range graph.Days {
+		date, err := parseDate(day.Date)
+		if err != nil {
+			continue
+		}
+		if start := bucketStart(date); len(rollup.Buckets) == 0 || !start.Equal(current) {
+			current = start
+			rollup.Buckets = append(rollup.Buckets, Bucket{Start: day.Date})
+		}
+
+		bucket := &rollup.Buckets[len(rollup.Buckets)-1]
+		bucket.End = day.Date
+		bucket.Sum += day.Count
+		bucket.Days++
+		if day.Count > 0 {
+			bucket.ActiveDays++
+		}
+		if day.Count > bucket.Max {
+			bucket.Max = day.Count
+		}
+	}
+	for i := range rollup.Buckets {
+		rollup.Buckets[i].Mean = float64(rollup.Buckets[i].Sum) / float64(rollup.Buckets[i].Days)
+	}
+	return rollup, nil
+}
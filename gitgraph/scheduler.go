@@ -0,0 +1,102 @@
+package gitgraph
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// DefaultMaxParallel bounds the outbound requests in flight at once by default
+const DefaultMaxParallel = 8
+
+// Scheduler bounds the outbound requests of every transport sharing it: at
+// most MaxParallel in flight, started at no more than PerMinute a minute, so
+// however many workers a run asks for it stays clear of GitHub's abuse
+// detection
+type Scheduler struct {
+	slots  chan struct{} // nil for no parallelism bound
+	bucket *TokenBucket  // nil for no rate bound
+}
+
+// NewScheduler creates a scheduler allowing maxParallel requests in flight and
+// perMinute a minute; zero leaves either unbounded
+func NewScheduler(maxParallel int, perMinute float64) *Scheduler {
+	s := &Scheduler{}
+	if maxParallel > 0 {
+		s.slots = make(chan struct{}, maxParallel)
+	}
+	if perMinute > 0 {
+		s.bucket = NewTokenBucket(perMinute/60, max(maxParallel, 1))
+	}
+	return s
+}
+
+// acquire waits for a slot and a token, returning the func releasing the slot
+func (s *Scheduler) acquire(ctx context.Context) (func(), error) {
+	release := func() {}
+	if s.slots != nil {
+		select {
+		case s.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		var once sync.Once
+		release = func() { once.Do(func() { <-s.slots }) }
+	}
+	if s.bucket != nil {
+		if err := s.bucket.Wait(ctx); err != nil {
+			release()
+			return nil, err
+		}
+	}
+	return release, nil
+}
+
+// Transport routes base's requests (or the NewTransport default's) through s
+func (s *Scheduler) Transport(base http.RoundTripper) *SchedulerTransport {
+	if base == nil {
+		base, _ = NewTransport("")
+	}
+	return &SchedulerTransport{Base: base, Scheduler: s}
+}
+
+// SchedulerTransport holds each request to its Scheduler's bounds. A request
+// keeps its slot until its response body is read or closed.
+type SchedulerTransport struct {
+	Base      http.RoundTripper
+	Scheduler *Scheduler
+}
+
+func (t *SchedulerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	release, err := t.Scheduler.acquire(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.Base.RoundTrip(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+// releasingBody frees its request's slot once read to the end or closed
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releasingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err == io.EOF {
+		b.release()
+	}
+	return n, err
+}
+
+func (b *releasingBody) Close() error {
+	b.release()
+	return b.ReadCloser.Close()
+}
@@ -0,0 +1,195 @@
+package gitgraph
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// SchemaVersion is the version of Schema. Within a version fields may be
+// added, but none are removed or change type.
+const SchemaVersion = 1
+
+// Schema is the JSON Schema of a ContributionGraph's JSON
+//
+//go:embed schema.json
+var Schema []byte
+
+// schemaNode is the subset of JSON Schema that Schema uses
+type schemaNode struct {
+	Ref                  string                 `json:"$ref"`
+	Type                 string                 `json:"type"`
+	Properties           map[string]*schemaNode `json:"properties"`
+	Required             []string               `json:"required"`
+	Items                *schemaNode            `json:"items"`
+	AdditionalProperties *schemaNode            `json:"additionalProperties"`
+	Enum                 []string               `json:"enum"`
+	Minimum              *float64               `json:"minimum"`
+	Maximum              *float64               `json:"maximum"`
+	Pattern              string                 `json:"pattern"`
+	Defs                 map[string]*schemaNode `json:"$defs"`
+}
+
+// rootSchema is Schema parsed, with its patterns compiled
+var rootSchema, schemaPatterns = mustParseSchema(Schema)
+
+func mustParseSchema(data []byte) (*schemaNode, map[string]*regexp.Regexp) {
+	var root schemaNode
+	if err := json.Unmarshal(data, &root); err != nil {
+		panic(fmt.Sprintf("parsing JSON schema: %v", err))
+	}
+	patterns := map[string]*regexp.Regexp{}
+	var compile func(n *schemaNode)
+	compile = func(n *schemaNode) {
+		if n == nil {
+			return
+		}
+		if n.Pattern != "" {
+			patterns[n.Pattern] = regexp.MustCompile(n.Pattern)
+		}
+		for _, child := range n.Properties {
+			compile(child)
+		}
+		for _, child := range n.Defs {
+			compile(child)
+		}
+		compile(n.Items)
+		compile(n.AdditionalProperties)
+	}
+	compile(&root)
+	return &root, patterns
+}
+
+// ValidateJSON checks data against Schema, returning every violation found
+// with the path to it, e.g. days[3].level. It returns nil for valid output.
+func ValidateJSON(data []byte) []error {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return []error{err}
+	}
+	var errs []error
+	validateNode(rootSchema, value, "", &errs)
+	return errs
+}
+
+func validateNode(n *schemaNode, value any, path string, errs *[]error) {
+	fail := func(format string, args ...any) {
+		at := path
+		if at == "" {
+			at = "(root)"
+		}
+		*errs = append(*errs, fmt.Errorf("%s: %s", at, fmt.Sprintf(format, args...)))
+	}
+	if name, ok := strings.CutPrefix(n.Ref, "#/$defs/"); ok {
+		def, ok := rootSchema.Defs[name]
+		if !ok {
+			fail("unknown definition %s", name)
+			return
+		}
+		n = def
+	}
+
+	switch n.Type {
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			fail("expected an object, got %s", jsonType(value))
+			return
+		}
+		for _, name := range n.Required {
+			if _, ok := object[name]; !ok {
+				fail("missing required field %s", name)
+			}
+		}
+		names := make([]string, 0, len(object))
+		for name := range object {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			child := n.Properties[name]
+			if child == nil {
+				child = n.AdditionalProperties
+			}
+			// Fields the schema doesn't know are allowed, for newer output
+			if child != nil {
+				validateNode(child, object[name], joinPath(path, name), errs)
+			}
+		}
+	case "array":
+		array, ok := value.([]any)
+		if !ok {
+			fail("expected an array, got %s", jsonType(value))
+			return
+		}
+		if n.Items != nil {
+			for i, item := range array {
+				validateNode(n.Items, item, fmt.Sprintf("%s[%d]", path, i), errs)
+			}
+		}
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			fail("expected a string, got %s", jsonType(value))
+			return
+		}
+		if len(n.Enum) > 0 && !slices.Contains(n.Enum, s) {
+			fail("%q is not one of %s", s, strings.Join(n.Enum, ", "))
+		}
+		if n.Pattern != "" && !schemaPatterns[n.Pattern].MatchString(s) {
+			fail("%q does not match %s", s, n.Pattern)
+		}
+	case "integer", "number":
+		f, ok := value.(float64)
+		if !ok || n.Type == "integer" && f != math.Trunc(f) {
+			expected := "a number"
+			if n.Type == "integer" {
+				expected = "an integer"
+			}
+			fail("expected %s, got %s", expected, jsonType(value))
+			return
+		}
+		if n.Minimum != nil && f < *n.Minimum {
+			fail("%g is below the minimum of %g", f, *n.Minimum)
+		}
+		if n.Maximum != nil && f > *n.Maximum {
+			fail("%g is above the maximum of %g", f, *n.Maximum)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			fail("expected a boolean, got %s", jsonType(value))
+		}
+	}
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// jsonType names the JSON type of a decoded value
+func jsonType(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "a boolean"
+	case float64:
+		if value == math.Trunc(value) {
+			return "an integer"
+		}
+		return "a number"
+	case string:
+		return "a string"
+	case []any:
+		return "an array"
+	}
+	return "an object"
+}
@@ -0,0 +1,110 @@
+package gitgraph
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// SeasonalReport breaks a year down by quarter and by meteorological season,
+// each compared with the same period a year earlier
+type SeasonalReport struct {
+	Username   string        `json:"username"`
+	Year       int           `json:"year"`
+	Hemisphere string        `json:"hemisphere"` // north or south, naming the seasons
+	Quarters   []PeriodStats `json:"quarters"`
+	Seasons    []PeriodStats `json:"seasons"`
+}
+
+// PeriodStats compares one quarter or season with the year before's
+type PeriodStats struct {
+	Period string `json:"period"` // Q1-Q4, or winter, spring, summer and autumn
+	From   string `json:"from"`
+	To     string `json:"to"`
+	// ToDate marks a period still under way: both years are counted up to
+	// today's day of it only
+	ToDate        bool        `json:"toDate,omitempty"`
+	Total         PeriodDelta `json:"total"`
+	ActiveDays    PeriodDelta `json:"activeDays"`
+	LongestStreak PeriodDelta `json:"longestStreak"`
+	// Share is the percent of the year's contributions made in the period
+	Share float64 `json:"share"`
+}
+
+// seasonNames are the seasons starting in December, March, June and September
+var seasonNames = map[string][4]string{
+	"north": {"winter", "spring", "summer", "autumn"},
+	"south": {"summer", "autumn", "winter", "spring"},
+}
+
+// CompareSeasons reports year's quarters and seasons from graph, which must
+// also cover the previous year and the December before it, since winter (or
+// summer in the southern hemisphere) starts in the previous December.
+// Periods starting after today are left out.
+func CompareSeasons(graph *ContributionGraph, year int, hemisphere string, today time.Time) (*SeasonalReport, error) {
+	names, ok := seasonNames[hemisphere]
+	if !ok {
+		return nil, fmt.Errorf("unknown hemisphere %q, expected north or south", hemisphere)
+	}
+	counts := map[string]int{}
+	for _, day := range graph.Days {
+		counts[day.Date] = day.Count
+	}
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	yearEnd := time.Date(year, 12, 31, 0, 0, 0, 0, time.UTC)
+	if today.Before(yearEnd) {
+		yearEnd = today
+	}
+	yearTotal := sumCounts(rangeDays(counts, time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC), yearEnd))
+
+	report := &SeasonalReport{Username: graph.Username, Year: year, Hemisphere: hemisphere, Quarters: []PeriodStats{}, Seasons: []PeriodStats{}}
+	for q := 0; q < 4; q++ {
+		from := time.Date(year, time.Month(3*q+1), 1, 0, 0, 0, 0, time.UTC)
+		if stats, ok := comparePeriod(counts, fmt.Sprintf("Q%d", q+1), from, from.AddDate(0, 3, -1), today, yearTotal); ok {
+			report.Quarters = append(report.Quarters, stats)
+		}
+	}
+	for s, name := range names {
+		from := time.Date(year-1, time.Month(12+3*s), 1, 0, 0, 0, 0, time.UTC)
+		if stats, ok := comparePeriod(counts, name, from, from.AddDate(0, 3, -1), today, yearTotal); ok {
+			report.Seasons = append(report.Seasons, stats)
+		}
+	}
+	return report, nil
+}
+
+// comparePeriod compares from-to with the same dates a year earlier, cutting
+// both at today's date when the period is still under way
+func comparePeriod(counts map[string]int, name string, from, to, today time.Time, yearTotal int) (PeriodStats, bool) {
+	if from.After(today) {
+		return PeriodStats{}, false
+	}
+	stats := PeriodStats{Period: name, From: from.Format("2006-01-02"), To: to.Format("2006-01-02")}
+	if to.After(today) {
+		stats.ToDate, to = true, today
+	}
+	// A year earlier by date, though February 29th has no such day
+	baseFrom := from.AddDate(-1, 0, 0)
+	baseTo := baseFrom.AddDate(0, 3, -1)
+	if stats.ToDate && to.AddDate(-1, 0, 0).Before(baseTo) {
+		baseTo = to.AddDate(-1, 0, 0)
+	}
+	base, current := rangeDays(counts, baseFrom, baseTo), rangeDays(counts, from, to)
+	stats.Total = newPeriodDelta("", sumCounts(base), sumCounts(current))
+	stats.ActiveDays = newPeriodDelta("", Summarize(base).ActiveDays, Summarize(current).ActiveDays)
+	stats.LongestStreak = newPeriodDelta("", ComputeStreaks(base, baseTo).Longest.Length, ComputeStreaks(current, to).Longest.Length)
+	if yearTotal > 0 {
+		stats.Share = math.Round(float64(stats.Total.Current)/float64(yearTotal)*1000) / 10
+	}
+	return stats, true
+}
+
+// rangeDays lists the days from from to to, with counts, zero where counts has none
+func rangeDays(counts map[string]int, from, to time.Time) []ContributionDay {
+	var days []ContributionDay
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		date := d.Format("2006-01-02")
+		days = append(days, ContributionDay{Date: date, Count: counts[date]})
+	}
+	return days
+}
@@ -0,0 +1,235 @@
+package gitgraph
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// WeekdayTotals sums contributions per weekday, indexed by time.Weekday
+func WeekdayTotals(days []ContributionDay) [7]int {
+	var totals [7]int
+	for _, day := range days {
+		date, err := parseDate(day.Date)
+		if err != nil {
+			continue
+		}
+		totals[date.Weekday()] += day.Count
+	}
+	return totals
+}
+
+// BusiestWeekday returns the weekday with the most contributions, preferring the earliest on ties
+func BusiestWeekday(days []ContributionDay) time.Weekday {
+	totals := WeekdayTotals(days)
+	busiest := time.Sunday
+	for wd := time.Sunday; wd <= time.Saturday; wd++ {
+		if totals[wd] > totals[busiest] {
+			busiest = wd
+		}
+	}
+	return busiest
+}
+
+// Summary holds descriptive statistics of a graph's daily counts
+type Summary struct {
+	MeanDaily        float64        `json:"meanDaily"`
+	MedianDaily      float64        `json:"medianDaily"`
+	MaxDay           DayCount       `json:"maxDay"`
+	BusiestWeekday   string         `json:"busiestWeekday"`
+	ActiveDays       int            `json:"activeDays"`
+	ActiveDayPercent float64        `json:"activeDayPercent"`
+	Quarters         []QuarterTotal `json:"quarters"`
+}
+
+// DayCount is a date with its contribution count
+type DayCount struct {
+	Date  string `json:"date"`
+	Count int    `json:"count"`
+}
+
+// QuarterTotal is the contribution total of a calendar quarter, e.g. "2024-Q1"
+type QuarterTotal struct {
+	Quarter string `json:"quarter"`
+	Total   int    `json:"total"`
+}
+
+// UpdateSummary recomputes the graph's Summary from its days
+func (g *ContributionGraph) UpdateSummary() {
+	summary := Summarize(g.Days)
+	g.Summary = &summary
+}
+
+// Summarize computes summary statistics over days, which are assumed to be in date order
+func Summarize(days []ContributionDay) Summary {
+	summary := Summary{
+		BusiestWeekday: BusiestWeekday(days).String(),
+		Quarters:       []QuarterTotal{},
+	}
+	if len(days) == 0 {
+		return summary
+	}
+
+	counts := make([]int, 0, len(days))
+	total := 0
+	for _, day := range days {
+		counts = append(counts, day.Count)
+		total += day.Count
+		if day.Count > 0 {
+			summary.ActiveDays++
+		}
+		if day.Count > summary.MaxDay.Count {
+			summary.MaxDay = DayCount{Date: day.Date, Count: day.Count}
+		}
+
+		date, err := parseDate(day.Date)
+		if err != nil {
+			continue
+		}
+		quarter := fmt.Sprintf("%d-Q%d", date.Year(), (int(date.Month())-1)/3+1)
+		if n := len(summary.Quarters); n == 0 || summary.Quarters[n-1].Quarter != quarter {
+			summary.Quarters = append(summary.Quarters, QuarterTotal{Quarter: quarter})
+		}
+		summary.Quarters[len(summary.Quarters)-1].Total += day.Count
+	}
+
+	sort.Ints(counts)
+	mid := len(counts) / 2
+	if len(counts)%2 == 0 {
+		summary.MedianDaily = float64(counts[mid-1]+counts[mid]) / 2
+	} else {
+		summary.MedianDaily = float64(counts[mid])
+	}
+	summary.MeanDaily = float64(total) / float64(len(days))
+	summary.ActiveDayPercent = float64(summary.ActiveDays) / float64(len(days)) * 100
+	return summary
+}
+
+// FilterWeekdays keeps only the days falling on the given weekdays, with the
+// total recounted from them. Undated private contributions and the period's
+// repository breakdown can't be split by day, so they are dropped; streaks
+// skip over the weekdays left out.
+func (g *ContributionGraph) FilterWeekdays(keep []time.Weekday) {
+	wanted := [7]bool{}
+	for _, wd := range keep {
+		wanted[wd] = true
+	}
+	g.FilterDays(func(_ ContributionDay, date time.Time) bool { return wanted[date.Weekday()] })
+}
+
+// FilterThreshold keeps only the days at minLevel or above with at least
+// minCount contributions, recounting as FilterWeekdays does
+func (g *ContributionGraph) FilterThreshold(minLevel, minCount int) {
+	g.FilterDays(func(day ContributionDay, _ time.Time) bool { return day.Level >= minLevel && day.Count >= minCount })
+}
+
+// FilterDays keeps only the days keep accepts, recounting as FilterWeekdays
+// does. Days with invalid dates are dropped.
+func (g *ContributionGraph) FilterDays(keep func(day ContributionDay, date time.Time) bool) {
+	days := g.Days[:0]
+	total := 0
+	var types *ContributionTypes
+	var yearTotals []YearTotal
+	for _, yt := range g.YearTotals {
+		yearTotals = append(yearTotals, YearTotal{Year: yt.Year})
+	}
+	for _, day := range g.Days {
+		date, err := parseDate(day.Date)
+		if err != nil || !keep(day, date) {
+			continue
+		}
+		days = append(days, day)
+		total += day.Count
+		for i := range g.YearTotals {
+			if g.YearTotals[i].Year == date.Year() {
+				yearTotals[i].Total += day.Count
+			}
+		}
+		if day.Types != nil {
+			if types == nil {
+				types = &ContributionTypes{}
+			}
+			types.add(*day.Types)
+		}
+	}
+	g.Days = days
+	g.TotalContribs = total
+	g.PrivateContribs = 0
+	g.Types = types
+	g.YearTotals = yearTotals
+	g.Repositories = nil
+	g.Summary = nil
+	g.Anomalies = nil
+	g.RollingAverage = nil
+}
+
+// DayOrders are the orders SortDays accepts
+var DayOrders = []string{"date", "count"}
+
+// SortDays returns a copy of days sorted by date or count, ascending unless
+// desc. Days of the same count stay in date order.
+func SortDays(days []ContributionDay, by string, desc bool) ([]ContributionDay, error) {
+	sorted := make([]ContributionDay, len(days))
+	copy(sorted, days)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Date < sorted[j].Date })
+	switch by {
+	case "date":
+		if desc {
+			for i, j := 0, len(sorted)-1; i < j; i, j = i+1, j-1 {
+				sorted[i], sorted[j] = sorted[j], sorted[i]
+			}
+		}
+	case "count":
+		sort.SliceStable(sorted, func(i, j int) bool {
+			if desc {
+				return sorted[i].Count > sorted[j].Count
+			}
+			return sorted[i].Count < sorted[j].Count
+		})
+	default:
+		return nil, fmt.Errorf("unknown order %q, expected date or count", by)
+	}
+	return sorted, nil
+}
+
+// DefaultRollingWindow smooths daily counts over a week
+const DefaultRollingWindow = 7
+
+// RollingAverage is a trailing mean of daily counts
+type RollingAverage struct {
+	Window int          `json:"window"`
+	Days   []DayAverage `json:"days"`
+}
+
+// DayAverage is the mean count of the window of days ending at Date
+type DayAverage struct {
+	Date    string  `json:"date"`
+	Average float64 `json:"average"`
+}
+
+// UpdateRollingAverage recomputes the graph's RollingAverage over window days
+func (g *ContributionGraph) UpdateRollingAverage(window int) {
+	rolling := ComputeRollingAverage(g.Days, window)
+	g.RollingAverage = &rolling
+}
+
+// ComputeRollingAverage averages each day with the window-1 days before it,
+// over fewer days at the start of the period. Days are assumed to be in date
+// order; window defaults to DefaultRollingWindow.
+func ComputeRollingAverage(days []ContributionDay, window int) RollingAverage {
+	if window < 1 {
+		window = DefaultRollingWindow
+	}
+	rolling := RollingAverage{Window: window, Days: make([]DayAverage, 0, len(days))}
+	sum := 0
+	for i, day := range days {
+		sum += day.Count
+		if i >= window {
+			sum -= days[i-window].Count
+		}
+		n := min(i+1, window)
+		rolling.Days = append(rolling.Days, DayAverage{Date: day.Date, Average: math.Round(float64(sum)/float64(n)*100) / 100})
+	}
+	return rolling
+}
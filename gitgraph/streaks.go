@@ -0,0 +1,99 @@
+package gitgraph
+
+import (
+	"sort"
+	"time"
+)
+
+// Streak is a run of consecutive days with at least one contribution
+type Streak struct {
+	Length int    `json:"length"`
+	Start  string `json:"start,omitempty"`
+	End    string `json:"end,omitempty"`
+}
+
+// Streaks holds the streaks derived from a graph's days
+type Streaks struct {
+	Current Streak `json:"current"`
+	Longest Streak `json:"longest"`
+}
+
+// UpdateStreaks recomputes the graph's Streaks as of today
+func (g *ContributionGraph) UpdateStreaks(today time.Time) {
+	streaks := ComputeStreaks(g.Days, today)
+	g.Streaks = &streaks
+}
+
+// ComputeStreaks finds the longest streak in days and the current streak as of today.
+// The current streak may end yesterday, since today can still gain contributions;
+// for periods ending before today it is the streak running into the last day.
+// Weekdays missing from days altogether, as after FilterWeekdays, don't
+// break a streak.
+func ComputeStreaks(days []ContributionDay, today time.Time) Streaks {
+	sorted := make([]ContributionDay, len(days))
+	copy(sorted, days)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Date < sorted[j].Date })
+	present := [7]bool{}
+	for _, day := range sorted {
+		if date, err := parseDate(day.Date); err == nil {
+			present[date.Weekday()] = true
+		}
+	}
+	// next is the day after d, stepping over weekdays with no days at all
+	next := func(d time.Time) time.Time {
+		for i := 0; i < 7; i++ {
+			if d = d.AddDate(0, 0, 1); present[d.Weekday()] {
+				break
+			}
+		}
+		return d
+	}
+
+	var streaks Streaks
+	var run Streak
+	var prev time.Time
+	ref := today.Format("2006-01-02")
+	// The last day that counts before today, skipping weekdays filtered out
+	before := today.AddDate(0, 0, -1)
+	for i := 0; i < 6 && !present[before.Weekday()]; i++ {
+		before = before.AddDate(0, 0, -1)
+	}
+	yesterday := before.Format("2006-01-02")
+
+	for _, day := range sorted {
+		if day.Date > ref {
+			break
+		}
+		date, err := parseDate(day.Date)
+		if err != nil {
+			continue
+		}
+
+		if day.Count == 0 {
+			// An empty today doesn't break the streak yet
+			if day.Date != ref {
+				run = Streak{}
+			}
+		} else {
+			if run.Length == 0 || !date.Equal(next(prev)) {
+				run = Streak{Start: day.Date}
+			}
+			run.Length++
+			run.End = day.Date
+			if run.Length > streaks.Longest.Length {
+				streaks.Longest = run
+			}
+		}
+		prev = date
+	}
+
+	// run is the streak ending at the last day seen, if that day was active
+	last := ""
+	if len(sorted) > 0 {
+		last = sorted[len(sorted)-1].Date
+	}
+	if run.Length > 0 && (run.End == ref || run.End == yesterday || (last < ref && run.End == last)) {
+		streaks.Current = run
+	}
+	return streaks
+}
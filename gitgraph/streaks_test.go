@@ -0,0 +1,90 @@
+package gitgraph
+
+import (
+	"testing"
+	"time"
+)
+
+// daysFrom builds consecutive days from start with counts
+func daysFrom(t *testing.T, start string, counts ...int) []ContributionDay {
+	t.Helper()
+	date, err := parseDate(start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	days := make([]ContributionDay, len(counts))
+	for i, count := range counts {
+		days[i] = newContributionDay(date.AddDate(0, 0, i), count, 0)
+	}
+	return days
+}
+
+func TestComputeStreaks(t *testing.T) {
+	today := time.Date(2024, 3, 10, 15, 0, 0, 0, time.UTC)
+	for _, tt := range []struct {
+		name             string
+		start            string
+		counts           []int
+		current, longest Streak
+	}{
+		{name: "no days"},
+		{name: "none active", start: "2024-03-08", counts: []int{0, 0, 0}},
+		{
+			name:  "running through today",
+			start: "2024-03-06", counts: []int{0, 1, 2, 3, 4},
+			current: Streak{4, "2024-03-07", "2024-03-10"}, longest: Streak{4, "2024-03-07", "2024-03-10"},
+		},
+		{
+			name:  "today still empty",
+			start: "2024-03-07", counts: []int{1, 1, 1, 0},
+			current: Streak{3, "2024-03-07", "2024-03-09"}, longest: Streak{3, "2024-03-07", "2024-03-09"},
+		},
+		{
+			name:  "broken yesterday",
+			start: "2024-03-06", counts: []int{1, 1, 1, 0, 0},
+			longest: Streak{3, "2024-03-06", "2024-03-08"},
+		},
+		{
+			name:  "longest before the current",
+			start: "2024-03-01", counts: []int{1, 1, 1, 1, 0, 0, 0, 0, 2, 2},
+			current: Streak{2, "2024-03-09", "2024-03-10"}, longest: Streak{4, "2024-03-01", "2024-03-04"},
+		},
+		{
+			name:  "period ending before today",
+			start: "2023-12-28", counts: []int{1, 0, 1, 1},
+			current: Streak{2, "2023-12-30", "2023-12-31"}, longest: Streak{2, "2023-12-30", "2023-12-31"},
+		},
+		{
+			name:  "days after today don't count",
+			start: "2024-03-09", counts: []int{1, 1, 5, 5},
+			current: Streak{2, "2024-03-09", "2024-03-10"}, longest: Streak{2, "2024-03-09", "2024-03-10"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var days []ContributionDay
+			if tt.start != "" {
+				days = daysFrom(t, tt.start, tt.counts...)
+			}
+			got := ComputeStreaks(days, today)
+			if got.Current != tt.current || got.Longest != tt.longest {
+				t.Errorf("streaks = %+v, want current %+v and longest %+v", got, tt.current, tt.longest)
+			}
+		})
+	}
+}
+
+func TestComputeStreaksWeekdays(t *testing.T) {
+	// Thursday 2024-02-29 to Tuesday 2024-03-05, active weekdays and idle weekends
+	graph := &ContributionGraph{Days: daysFrom(t, "2024-02-29", 1, 1, 0, 0, 1, 1)}
+	today := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+	if got := ComputeStreaks(graph.Days, today).Current; got.Length != 2 {
+		t.Errorf("current streak with weekends = %+v, want 2 days", got)
+	}
+	// Without the weekends, Friday runs into Monday
+	graph.FilterWeekdays([]time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday})
+	graph.UpdateStreaks(today)
+	want := Streak{4, "2024-02-29", "2024-03-05"}
+	if graph.Streaks.Current != want || graph.Streaks.Longest != want {
+		t.Errorf("streaks on weekdays = %+v, want %+v", *graph.Streaks, want)
+	}
+}
@@ -0,0 +1,34 @@
+package gitgraph
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// NewTransport returns the HTTP transport used for upstream requests: the default
+// transport with a 10s response timeout. It honors HTTP_PROXY, HTTPS_PROXY and
+// NO_PROXY unless proxy is set to an http, https or socks5 proxy URL.
+func NewTransport(proxy string) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = 10 * time.Second
+	if proxy == "" {
+		return transport, nil
+	}
+
+	proxyURL, err := url.Parse(proxy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q, expected http, https or socks5", proxyURL.Scheme)
+	}
+	if proxyURL.Host == "" {
+		return nil, fmt.Errorf("proxy URL %q has no host", proxy)
+	}
+	transport.Proxy = http.ProxyURL(proxyURL)
+	return transport, nil
+}
@@ -0,0 +1,166 @@
+package gitgraph
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ContributionTypes counts contributions by kind. The calendar also counts
+// repositories created, so the kinds add up to the calendar's count.
+type ContributionTypes struct {
+	Commits      int `json:"commits"`
+	PullRequests int `json:"pullRequests"`
+	Issues       int `json:"issues"`
+	Reviews      int `json:"reviews"`
+	Repositories int `json:"repositories,omitempty"`
+}
+
+// ContributionTypeNames are the kinds accepted by OnlyType
+var ContributionTypeNames = []string{"commits", "prs", "issues", "reviews"}
+
+// count returns the contributions of the kind named as in ContributionTypeNames
+func (t ContributionTypes) count(kind string) int {
+	switch kind {
+	case "commits":
+		return t.Commits
+	case "prs":
+		return t.PullRequests
+	case "issues":
+		return t.Issues
+	case "reviews":
+		return t.Reviews
+	}
+	return 0
+}
+
+func (t *ContributionTypes) add(o ContributionTypes) {
+	t.Commits += o.Commits
+	t.PullRequests += o.PullRequests
+	t.Issues += o.Issues
+	t.Reviews += o.Reviews
+	t.Repositories += o.Repositories
+}
+
+// OnlyType narrows the graph to one kind of contribution: each day's count
+// becomes that kind's, with levels recomputed from the quartiles of the new
+// counts. It needs per-day types, which only the GraphQL API provides.
+func (g *ContributionGraph) OnlyType(kind string) error {
+	known := false
+	for _, name := range ContributionTypeNames {
+		known = known || name == kind
+	}
+	if !known {
+		return fmt.Errorf("unknown contribution type %q, expected one of %s", kind, strings.Join(ContributionTypeNames, ", "))
+	}
+	counts := make(map[string]int, len(g.Days))
+	total := 0
+	for i, day := range g.Days {
+		if day.Types == nil {
+			return fmt.Errorf("filtering by contribution type needs per-day types from the GraphQL API, which requires a token")
+		}
+		g.Days[i].Count = day.Types.count(kind)
+		counts[day.Date] = g.Days[i].Count
+		total += g.Days[i].Count
+	}
+	g.Relevel(QuartileThresholds(counts))
+	g.TotalContribs = total
+	g.PrivateContribs = 0
+	return nil
+}
+
+// typeConnections are the contributionsCollection connections listing dated
+// contributions of each kind besides commits, which are what remains of a
+// day's calendar count
+var typeConnections = []struct {
+	Field string
+	Add   func(t *ContributionTypes)
+}{
+	{"pullRequestContributions", func(t *ContributionTypes) { t.PullRequests++ }},
+	{"issueContributions", func(t *ContributionTypes) { t.Issues++ }},
+	{"pullRequestReviewContributions", func(t *ContributionTypes) { t.Reviews++ }},
+	{"repositoryContributions", func(t *ContributionTypes) { t.Repositories++ }},
+}
+
+const typeConnectionQuery = `query($login: String!, $from: DateTime!, $to: DateTime!, $after: String) {
+  user(login: $login) {
+    contributionsCollection(from: $from, to: $to) {
+      %s(first: 100, after: $after) {
+        pageInfo {
+          hasNextPage
+          endCursor
+        }
+        nodes {
+          occurredAt
+        }
+      }
+    }
+  }
+}`
+
+type typeConnectionResponse struct {
+	Data struct {
+		User *struct {
+			ContributionsCollection map[string]struct {
+				PageInfo struct {
+					HasNextPage bool   `json:"hasNextPage"`
+					EndCursor   string `json:"endCursor"`
+				} `json:"pageInfo"`
+				Nodes []struct {
+					OccurredAt time.Time `json:"occurredAt"`
+				} `json:"nodes"`
+			} `json:"contributionsCollection"`
+		} `json:"user"`
+	} `json:"data"`
+	Errors []graphQLError `json:"errors"`
+}
+
+// addDayTypes splits each day of graph by kind, paging through the dated
+// contributions of every kind but commits. Dates are taken in UTC, so work
+// near midnight may land a day off from the calendar, which uses the user's
+// time zone.
+func (g *GitHub) addDayTypes(ctx context.Context, username string, from, to time.Time, graph *ContributionGraph) error {
+	byDate := map[string]*ContributionTypes{}
+	for i := range graph.Days {
+		graph.Days[i].Types = &ContributionTypes{}
+		byDate[graph.Days[i].Date] = graph.Days[i].Types
+	}
+	for _, conn := range typeConnections {
+		var after any // null for the first page
+		for {
+			var result typeConnectionResponse
+			variables := map[string]any{
+				"login": username,
+				"from":  from.Format(time.RFC3339),
+				"to":    to.Add(24*time.Hour - time.Second).Format(time.RFC3339),
+				"after": after,
+			}
+			if err := g.graphQL(ctx, fmt.Sprintf(typeConnectionQuery, conn.Field), variables, &result); err != nil {
+				return err
+			}
+			if len(result.Errors) > 0 {
+				return fmt.Errorf("GraphQL error: %s", result.Errors[0].Message)
+			}
+			if result.Data.User == nil {
+				return fmt.Errorf("%w: %s", ErrUserNotFound, username)
+			}
+			page := result.Data.User.ContributionsCollection[conn.Field]
+			for _, node := range page.Nodes {
+				if types, ok := byDate[node.OccurredAt.UTC().Format("2006-01-02")]; ok {
+					conn.Add(types)
+				}
+			}
+			if !page.PageInfo.HasNextPage {
+				break
+			}
+			after = page.PageInfo.EndCursor
+		}
+	}
+
+	for _, day := range graph.Days {
+		t := day.Types
+		t.Commits = max(day.Count-t.PullRequests-t.Issues-t.Reviews-t.Repositories, 0)
+	}
+	return nil
+}
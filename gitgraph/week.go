@@ -0,0 +1,94 @@
+package gitgraph
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ParseWeekStart parses the first day of the week, sunday or monday
+func ParseWeekStart(name string) (time.Weekday, error) {
+	switch strings.ToLower(name) {
+	case "sunday", "sun":
+		return time.Sunday, nil
+	case "monday", "mon":
+		return time.Monday, nil
+	}
+	return 0, fmt.Errorf("unsupported week start %q, expected sunday or monday", name)
+}
+
+// WeekNumberings name the sources NumberWeeks can fill WeekOfYear from
+var WeekNumberings = []string{"iso", "grid"}
+
+// SetWeekStart renumbers every day's DayOfWeek, WeekOfYear and GridWeek for
+// weeks that begin on start. Monday weeks follow ISO 8601: Monday is day 0 and
+// weeks are ISO weeks. Sunday weeks follow GitHub's calendar: Sunday is day 0
+// and week 1 is the one holding January 1.
+func (g *ContributionGraph) SetWeekStart(start time.Weekday) {
+	for i, day := range g.Days {
+		date, err := parseDate(day.Date)
+		if err != nil {
+			continue
+		}
+		g.Days[i].DayOfWeek = (int(date.Weekday()) - int(start) + 7) % 7
+		g.Days[i].WeekOfYear = gridWeek(date, yearStart(date), start) + 1
+		if start == time.Monday {
+			g.Days[i].WeekOfYear = getWeekOfYear(date)
+		}
+	}
+	g.numberGridWeeks(start)
+}
+
+// numberGridWeeks sets every day's GridWeek to its column in the graph's
+// calendar when weeks begin on start, as GitHub lays out a year of days from
+// the week of the first. A year of days spans columns 0 to 52, or to 53 when
+// a leap year begins on the last day of a week; longer graphs keep counting.
+func (g *ContributionGraph) numberGridWeeks(start time.Weekday) {
+	first := ""
+	for _, day := range g.Days {
+		if first == "" || day.Date < first {
+			first = day.Date
+		}
+	}
+	firstDate, err := parseDate(first)
+	if err != nil {
+		return
+	}
+	for i, day := range g.Days {
+		if date, err := parseDate(day.Date); err == nil {
+			g.Days[i].GridWeek = gridWeek(date, firstDate, start)
+		}
+	}
+}
+
+// NumberWeeks fills every day's WeekOfYear from numbering: iso for the ISO 8601
+// week, or grid to copy GridWeek, the column the day is drawn in
+func (g *ContributionGraph) NumberWeeks(numbering string) error {
+	switch numbering {
+	case "iso":
+		for i, day := range g.Days {
+			if date, err := parseDate(day.Date); err == nil {
+				g.Days[i].WeekOfYear = getWeekOfYear(date)
+			}
+		}
+	case "grid":
+		for i, day := range g.Days {
+			g.Days[i].WeekOfYear = day.GridWeek
+		}
+	default:
+		return fmt.Errorf("unknown week numbering %q, expected one of %s", numbering, strings.Join(WeekNumberings, ", "))
+	}
+	return nil
+}
+
+// gridWeek is the column of date in a calendar beginning at first when weeks
+// begin on start, counting the week holding first as column 0
+func gridWeek(date, first time.Time, start time.Weekday) int {
+	offset := (int(first.Weekday()) - int(start) + 7) % 7
+	return (int(date.Sub(first).Hours()/24) + offset) / 7
+}
+
+// yearStart is January 1 of date's year
+func yearStart(date time.Time) time.Time {
+	return time.Date(date.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,106 @@
+package gitgraph
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGridWeek(t *testing.T) {
+	for _, tt := range []struct {
+		date, first string
+		start       time.Weekday
+		want        int
+	}{
+		// 2024-01-01 is a Monday, in the Sunday week of 2023-12-31
+		{"2024-01-01", "2024-01-01", time.Sunday, 0},
+		{"2024-01-06", "2024-01-01", time.Sunday, 0},
+		{"2024-01-07", "2024-01-01", time.Sunday, 1},
+		{"2024-12-31", "2024-01-01", time.Sunday, 52},
+		{"2024-01-07", "2024-01-01", time.Monday, 0},
+		{"2024-01-08", "2024-01-01", time.Monday, 1},
+		// A leap year starting on a Saturday takes a 54th column
+		{"2028-01-01", "2028-01-01", time.Sunday, 0},
+		{"2028-01-02", "2028-01-01", time.Sunday, 1},
+		{"2028-12-31", "2028-01-01", time.Sunday, 53},
+		// A rolling year counts from its first day, not January 1
+		{"2024-03-05", "2024-03-05", time.Sunday, 0},
+		{"2024-03-10", "2024-03-05", time.Sunday, 1},
+		{"2025-03-04", "2024-03-05", time.Sunday, 52},
+	} {
+		date, _ := parseDate(tt.date)
+		first, _ := parseDate(tt.first)
+		if got := gridWeek(date, first, tt.start); got != tt.want {
+			t.Errorf("gridWeek(%s, %s, %s) = %d, want %d", tt.date, tt.first, tt.start, got, tt.want)
+		}
+	}
+}
+
+func TestGraphGridWeeks(t *testing.T) {
+	from := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+	graph := GraphFromCounts("alice", nil, from, from.AddDate(1, 0, -1), nil)
+	if first, last := graph.Days[0], graph.Days[len(graph.Days)-1]; first.GridWeek != 0 || last.GridWeek != 52 {
+		t.Errorf("rolling year spans columns %d-%d, want 0-52", first.GridWeek, last.GridWeek)
+	}
+
+	// Merging in earlier days moves every column
+	earlier := GraphFromCounts("alice", nil, from.AddDate(0, 0, -7), from.AddDate(0, 0, -1), nil)
+	merged := MergeGraphs(graph, earlier)
+	if first, last := merged.Days[0], merged.Days[len(merged.Days)-1]; first.GridWeek != 0 || last.GridWeek != 53 {
+		t.Errorf("merged graph spans columns %d-%d, want 0-53", first.GridWeek, last.GridWeek)
+	}
+}
+
+func TestSetWeekStart(t *testing.T) {
+	// Sunday 2023-12-31 to Monday 2024-01-08
+	graph := &ContributionGraph{Days: daysFrom(t, "2023-12-31", 0, 0, 0, 0, 0, 0, 0, 0, 0)}
+	for _, tt := range []struct {
+		start                     time.Weekday
+		dayOfWeek, week, gridWeek []int
+	}{
+		{
+			time.Sunday,
+			[]int{0, 1, 2, 3, 4, 5, 6, 0, 1},
+			[]int{53, 1, 1, 1, 1, 1, 1, 2, 2},
+			[]int{0, 0, 0, 0, 0, 0, 0, 1, 1},
+		},
+		{
+			// ISO weeks: 2023-12-31 ends week 52 of 2023
+			time.Monday,
+			[]int{6, 0, 1, 2, 3, 4, 5, 6, 0},
+			[]int{52, 1, 1, 1, 1, 1, 1, 1, 2},
+			[]int{0, 1, 1, 1, 1, 1, 1, 1, 2},
+		},
+	} {
+		graph.SetWeekStart(tt.start)
+		for i, day := range graph.Days {
+			if day.DayOfWeek != tt.dayOfWeek[i] || day.WeekOfYear != tt.week[i] || day.GridWeek != tt.gridWeek[i] {
+				t.Errorf("%s weeks: %s has day %d, week %d, column %d, want %d, %d, %d", tt.start, day.Date,
+					day.DayOfWeek, day.WeekOfYear, day.GridWeek, tt.dayOfWeek[i], tt.week[i], tt.gridWeek[i])
+			}
+		}
+	}
+}
+
+func TestNumberWeeks(t *testing.T) {
+	graph := &ContributionGraph{Days: daysFrom(t, "2023-12-31", 0, 0, 0, 0, 0, 0, 0, 0)}
+	graph.SetWeekStart(time.Sunday)
+	for _, tt := range []struct {
+		numbering string
+		want      []int
+	}{
+		{"iso", []int{52, 1, 1, 1, 1, 1, 1, 1}},
+		{"grid", []int{0, 0, 0, 0, 0, 0, 0, 1}},
+	} {
+		if err := graph.NumberWeeks(tt.numbering); err != nil {
+			t.Fatal(err)
+		}
+		for i, day := range graph.Days {
+			if day.WeekOfYear != tt.want[i] {
+				t.Errorf("%s: %s is week %d, want %d", tt.numbering, day.Date, day.WeekOfYear, tt.want[i])
+			}
+		}
+	}
+	if err := graph.NumberWeeks("us"); err == nil {
+		t.Error("unknown numbering accepted")
+	}
+}
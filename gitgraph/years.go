@@ -0,0 +1,85 @@
+package gitgraph
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// YearTotal is the contribution total of one calendar year of a graph
+type YearTotal struct {
+	Year  int `json:"year"`
+	Total int `json:"total"`
+}
+
+const contributionYearsQuery = `query($login: String!) {
+  user(login: $login) {
+    contributionsCollection {
+      contributionYears
+    }
+  }
+}`
+
+type contributionYearsResponse struct {
+	Data struct {
+		User *struct {
+			ContributionsCollection struct {
+				ContributionYears []int `json:"contributionYears"`
+			} `json:"contributionsCollection"`
+		} `json:"user"`
+	} `json:"data"`
+	Errors []graphQLError `json:"errors"`
+}
+
+// AllYears lists every year from username's first contribution through the
+// current one. It is supported for GitHub and the fake provider.
+func (c *Client) AllYears(ctx context.Context, username string) ([]int, error) {
+	first := 0
+	switch provider := c.Provider.(type) {
+	case nil, *GitHub:
+		var err error
+		if first, err = c.GitHub().FirstYear(ctx, username); err != nil {
+			return nil, err
+		}
+	case *Fake:
+		first = provider.FirstYear(username)
+	default:
+		return nil, fmt.Errorf("%s can't find a user's first year; only GitHub and the fake provider can", c.Provider.Name())
+	}
+	return yearsBetween(time.Date(first, 1, 1, 0, 0, 0, 0, time.UTC), c.now()), nil
+}
+
+// FirstYear returns the earliest year username has contributions in, as listed
+// by the GraphQL API. Without a token the account's creation year stands in.
+func (g *GitHub) FirstYear(ctx context.Context, username string) (int, error) {
+	if g.Token == "" {
+		var user struct {
+			CreatedAt time.Time `json:"created_at"`
+		}
+		if _, err := getJSON(ctx, g.HTTPClient, "", fmt.Sprintf("%s/users/%s", g.restEndpoint(), url.PathEscape(username)), &user); err != nil {
+			return 0, err
+		}
+		return user.CreatedAt.Year(), nil
+	}
+
+	var result contributionYearsResponse
+	if err := g.graphQL(ctx, contributionYearsQuery, map[string]any{"login": username}, &result); err != nil {
+		return 0, err
+	}
+	if len(result.Errors) > 0 {
+		return 0, fmt.Errorf("GraphQL error: %s", result.Errors[0].Message)
+	}
+	if result.Data.User == nil {
+		return 0, fmt.Errorf("%w: %s", ErrUserNotFound, username)
+	}
+	years := result.Data.User.ContributionsCollection.ContributionYears
+	if len(years) == 0 {
+		return time.Now().Year(), nil
+	}
+	first := years[0]
+	for _, year := range years {
+		first = min(first, year)
+	}
+	return first, nil
+}
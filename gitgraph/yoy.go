@@ -0,0 +1,108 @@
+package gitgraph
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// YearOverYear compares one user's contributions in two years
+type YearOverYear struct {
+	Username      string        `json:"username"`
+	BaseYear      int           `json:"baseYear"`
+	Year          int           `json:"year"`
+	Total         PeriodDelta   `json:"total"`
+	ActiveDays    PeriodDelta   `json:"activeDays"`
+	LongestStreak PeriodDelta   `json:"longestStreak"`
+	Weeks         []PeriodDelta `json:"weeks"`
+	Months        []PeriodDelta `json:"months"`
+}
+
+// PeriodDelta holds a value in the base year and the compared year. Percent
+// is omitted when the base value is 0.
+type PeriodDelta struct {
+	Period  string   `json:"period,omitempty"` // ISO week as W01-W53, or month as Jan-Dec
+	Base    int      `json:"base"`
+	Current int      `json:"current"`
+	Delta   int      `json:"delta"`
+	Percent *float64 `json:"percent,omitempty"`
+}
+
+func newPeriodDelta(period string, base, current int) PeriodDelta {
+	d := PeriodDelta{Period: period, Base: base, Current: current, Delta: current - base}
+	if base != 0 {
+		percent := math.Round(float64(d.Delta)/float64(base)*1000) / 10
+		d.Percent = &percent
+	}
+	return d
+}
+
+// ISOYearBounds returns the Monday starting ISO week 1 of year and the Sunday
+// ending its last week; these can fall in the neighbouring calendar years
+func ISOYearBounds(year int) (time.Time, time.Time) {
+	// January 4 is always in week 1
+	jan4 := time.Date(year, 1, 4, 0, 0, 0, 0, time.UTC)
+	start := jan4.AddDate(0, 0, -(int(jan4.Weekday())+6)%7)
+	next := time.Date(year+1, 1, 4, 0, 0, 0, 0, time.UTC)
+	end := next.AddDate(0, 0, -(int(next.Weekday())+6)%7-1)
+	return start, end
+}
+
+// CompareYears lines up base and current, each covering baseYear and year and
+// any days of their ISO weeks outside them. Weeks are compared by ISO week
+// number; totals, months and streaks use calendar years only.
+func CompareYears(base, current *ContributionGraph, baseYear, year int) *YearOverYear {
+	yoy := &YearOverYear{Username: current.Username, BaseYear: baseYear, Year: year, Weeks: []PeriodDelta{}, Months: []PeriodDelta{}}
+	baseDays, baseWeeks, baseMonths := yearBuckets(base, baseYear)
+	days, weeks, months := yearBuckets(current, year)
+
+	yoy.Total = newPeriodDelta("", sumCounts(baseDays), sumCounts(days))
+	yoy.ActiveDays = newPeriodDelta("", Summarize(baseDays).ActiveDays, Summarize(days).ActiveDays)
+	// A past year's streaks are as of its last day
+	baseStreaks := ComputeStreaks(baseDays, time.Date(baseYear, 12, 31, 0, 0, 0, 0, time.UTC))
+	streaks := ComputeStreaks(days, time.Date(year, 12, 31, 0, 0, 0, 0, time.UTC))
+	yoy.LongestStreak = newPeriodDelta("", baseStreaks.Longest.Length, streaks.Longest.Length)
+
+	for week := 1; week <= 53; week++ {
+		b, okBase := baseWeeks[week]
+		c, ok := weeks[week]
+		if !okBase && !ok {
+			continue
+		}
+		yoy.Weeks = append(yoy.Weeks, newPeriodDelta(fmt.Sprintf("W%02d", week), b, c))
+	}
+	for month := time.January; month <= time.December; month++ {
+		yoy.Months = append(yoy.Months, newPeriodDelta(month.String()[:3], baseMonths[month-1], months[month-1]))
+	}
+	return yoy
+}
+
+// yearBuckets splits graph into the days of calendar year, its totals by ISO
+// week of the ISO year of the same number, and its totals by month
+func yearBuckets(graph *ContributionGraph, year int) ([]ContributionDay, map[int]int, [12]int) {
+	var days []ContributionDay
+	weeks := map[int]int{}
+	var months [12]int
+	for _, day := range graph.Days {
+		date, err := parseDate(day.Date)
+		if err != nil {
+			continue
+		}
+		if isoYear, week := date.ISOWeek(); isoYear == year {
+			weeks[week] += day.Count
+		}
+		if date.Year() == year {
+			days = append(days, day)
+			months[date.Month()-1] += day.Count
+		}
+	}
+	return days, weeks, months
+}
+
+func sumCounts(days []ContributionDay) int {
+	total := 0
+	for _, day := range days {
+		total += day.Count
+	}
+	return total
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/JyotinderSingh/gitgraphed/export"
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+// writeInflux POSTs graph's days as line protocol to an InfluxDB write
+// endpoint, e.g. http://localhost:8086/api/v2/write?org=o&bucket=b or a
+// VictoriaMetrics /write. token is sent as InfluxDB 2's Token authorization
+// when set; InfluxDB 1 takes u and p in the URL instead.
+func writeInflux(ctx context.Context, client *http.Client, endpoint, token string, graph *gitgraph.ContributionGraph) error {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("invalid InfluxDB URL %q", endpoint)
+	}
+	// Points are timestamped in seconds
+	query := u.Query()
+	if !query.Has("precision") {
+		query.Set("precision", "s")
+		u.RawQuery = query.Encode()
+	}
+
+	var body bytes.Buffer
+	if err := export.Influx(&body, graph); err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	req.Header.Set("User-Agent", "gitgraphed")
+	if token != "" {
+		req.Header.Set("Authorization", "Token "+token)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		// InfluxDB explains rejected points in the body
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("InfluxDB returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+//go:build !windows
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// keychainService names gitgraphed's entries in the OS keychain
+const keychainService = "gitgraphed"
+
+// errNoKeychain is returned where no OS keychain can be used, so tokens are
+// saved in the credentials file instead
+var errNoKeychain = errors.New("no OS keychain available")
+
+// keychainName describes where keychainSet saves tokens
+func keychainName() string {
+	if runtime.GOOS == "darwin" {
+		return "the macOS Keychain"
+	}
+	return "the Secret Service keyring"
+}
+
+// keychainGet returns the token saved for host in the macOS Keychain, or
+// elsewhere in the Secret Service through secret-tool, empty when there is
+// none
+func keychainGet(host string) (string, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "darwin" {
+		cmd = exec.Command("security", "find-generic-password", "-s", keychainService, "-a", host, "-w")
+	} else {
+		if _, err := exec.LookPath("secret-tool"); err != nil {
+			return "", errNoKeychain
+		}
+		cmd = exec.Command("secret-tool", "lookup", "service", keychainService, "host", host)
+	}
+	out, err := cmd.Output()
+	var exit *exec.ExitError
+	if errors.As(err, &exit) {
+		// Without such an entry security exits with 44, and secret-tool with
+		// 1 and nothing to say
+		stderr := bytes.TrimSpace(exit.Stderr)
+		if runtime.GOOS == "darwin" && exit.ExitCode() == 44 || runtime.GOOS != "darwin" && exit.ExitCode() == 1 && len(stderr) == 0 {
+			return "", nil
+		}
+		return "", fmt.Errorf("%w: %s", keychainError(cmd, err), stderr)
+	}
+	if err != nil {
+		return "", keychainError(cmd, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// keychainSet saves token for host in the OS keychain, replacing the one
+// saved before
+func keychainSet(host, token string) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "darwin" {
+		// Read from stdin, so the token isn't in the arguments other users
+		// can list
+		cmd = exec.Command("security", "-i")
+		cmd.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -U -s %s -a %q -l %q -w %q\n",
+			keychainService, host, keychainService+" ("+host+")", token))
+	} else {
+		if _, err := exec.LookPath("secret-tool"); err != nil {
+			return errNoKeychain
+		}
+		cmd = exec.Command("secret-tool", "store", "--label", keychainService+" ("+host+")", "service", keychainService, "host", host)
+		cmd.Stdin = strings.NewReader(token)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", keychainError(cmd, err), strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func keychainError(cmd *exec.Cmd, err error) error {
+	return fmt.Errorf("%s: %w", cmd.Args[0], err)
+}
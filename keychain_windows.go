@@ -0,0 +1,100 @@
+package main
+
+import (
+	"errors"
+	"syscall"
+	"unsafe"
+)
+
+// keychainService names gitgraphed's entries in Credential Manager
+const keychainService = "gitgraphed"
+
+// errNoKeychain is returned where no OS keychain can be used, so tokens are
+// saved in the credentials file instead
+var errNoKeychain = errors.New("no OS keychain available")
+
+var (
+	advapi32       = syscall.NewLazyDLL("advapi32.dll")
+	procCredReadW  = advapi32.NewProc("CredReadW")
+	procCredWriteW = advapi32.NewProc("CredWriteW")
+	procCredFree   = advapi32.NewProc("CredFree")
+)
+
+const (
+	credTypeGeneric         = 1
+	credPersistLocalMachine = 2
+	errorNotFound           = syscall.Errno(1168)
+)
+
+// credential is the CREDENTIALW structure of wincred.h
+type credential struct {
+	Flags              uint32
+	Type               uint32
+	TargetName         *uint16
+	Comment            *uint16
+	LastWritten        syscall.Filetime
+	CredentialBlobSize uint32
+	CredentialBlob     *byte
+	Persist            uint32
+	AttributeCount     uint32
+	Attributes         uintptr
+	TargetAlias        *uint16
+	UserName           *uint16
+}
+
+// keychainName describes where keychainSet saves tokens
+func keychainName() string {
+	return "Windows Credential Manager"
+}
+
+// keychainTarget is the Credential Manager entry of host's token
+func keychainTarget(host string) (*uint16, error) {
+	return syscall.UTF16PtrFromString(keychainService + ":" + host)
+}
+
+// keychainGet returns the token saved for host in Credential Manager, empty
+// when there is none
+func keychainGet(host string) (string, error) {
+	target, err := keychainTarget(host)
+	if err != nil {
+		return "", err
+	}
+	var cred *credential
+	ret, _, err := procCredReadW.Call(uintptr(unsafe.Pointer(target)), credTypeGeneric, 0, uintptr(unsafe.Pointer(&cred)))
+	if ret == 0 {
+		if errors.Is(err, errorNotFound) {
+			return "", nil
+		}
+		return "", err
+	}
+	defer procCredFree.Call(uintptr(unsafe.Pointer(cred)))
+	return string(unsafe.Slice(cred.CredentialBlob, cred.CredentialBlobSize)), nil
+}
+
+// keychainSet saves token for host in Credential Manager, replacing the one
+// saved before
+func keychainSet(host, token string) error {
+	target, err := keychainTarget(host)
+	if err != nil {
+		return err
+	}
+	user, err := syscall.UTF16PtrFromString(host)
+	if err != nil {
+		return err
+	}
+	blob := []byte(token)
+	cred := credential{
+		Type:               credTypeGeneric,
+		TargetName:         target,
+		CredentialBlobSize: uint32(len(blob)),
+		Persist:            credPersistLocalMachine,
+		UserName:           user,
+	}
+	if len(blob) > 0 {
+		cred.CredentialBlob = &blob[0]
+	}
+	if ret, _, err := procCredWriteW.Call(uintptr(unsafe.Pointer(&cred)), 0); ret == 0 {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// systemdFirstFD is the first descriptor systemd passes to an activated service
+const systemdFirstFD = 3
+
+// listen opens addr: a TCP address such as :8080, unix:/path/to.sock for a
+// unix socket, or systemd, or systemd:name, for a socket passed by systemd
+// socket activation, named as in the socket unit's FileDescriptorName
+func listen(addr string) (net.Listener, error) {
+	if path, ok := strings.CutPrefix(addr, "unix:"); ok {
+		return listenUnix(path)
+	}
+	if addr == "systemd" || strings.HasPrefix(addr, "systemd:") {
+		return systemdListener(strings.TrimPrefix(strings.TrimPrefix(addr, "systemd"), ":"))
+	}
+	return net.Listen("tcp", addr)
+}
+
+// listenUnix listens on a unix socket at path, replacing one left behind by
+// an earlier run. The socket is writable by its group, e.g. a web server's.
+func listenUnix(path string) (net.Listener, error) {
+	if info, err := os.Lstat(path); err == nil && info.Mode().Type() == fs.ModeSocket {
+		os.Remove(path)
+	}
+	lis, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0o660); err != nil {
+		lis.Close()
+		return nil, err
+	}
+	return lis, nil
+}
+
+// systemdListener returns the socket systemd passed under name, or the first
+// one when name is empty, following sd_listen_fds(3)
+func systemdListener(name string) (net.Listener, error) {
+	if pid, _ := strconv.Atoi(os.Getenv("LISTEN_PID")); pid != os.Getpid() {
+		return nil, fmt.Errorf("no sockets passed by systemd (LISTEN_PID is not this process)")
+	}
+	count, _ := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if count < 1 {
+		return nil, fmt.Errorf("no sockets passed by systemd (LISTEN_FDS is unset)")
+	}
+	names := strings.Split(os.Getenv("LISTEN_FDNAMES"), ":")
+	for i := 0; i < count; i++ {
+		if name != "" && (i >= len(names) || names[i] != name) {
+			continue
+		}
+		fd := systemdFirstFD + i
+		file := os.NewFile(uintptr(fd), "systemd:"+strconv.Itoa(fd))
+		lis, err := net.FileListener(file)
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("socket %d passed by systemd: %w", fd, err)
+		}
+		return lis, nil
+	}
+	return nil, fmt.Errorf("no socket named %q passed by systemd (got %s)", name, os.Getenv("LISTEN_FDNAMES"))
+}
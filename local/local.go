@@ -0,0 +1,179 @@
+// Package local builds contribution graphs from commits in local git repositories.
+package local
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+// Options selects whose commits are counted and over which period
+type Options struct {
+	Author string // matched like git log --author
+	// Aliases are other names or emails of the same person, matched alike
+	Aliases []string
+	From    time.Time
+	To      time.Time
+	// ExcludeBots drops commits by bot accounts such as dependabot[bot]
+	ExcludeBots bool
+	// NoMerges drops merge commits
+	NoMerges bool
+	// ExcludePaths drops commits touching only these paths, e.g. vendor
+	ExcludePaths []string
+	// Branches limits counting to commits reachable from these branches
+	// instead of every ref; repositories without any of them are skipped
+	Branches []string
+}
+
+// FindRepos returns root if it is a git repository, otherwise every repository found beneath it
+func FindRepos(root string) ([]string, error) {
+	if isRepo(root) {
+		return []string{root}, nil
+	}
+
+	repos := []string{}
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != root && strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+		if isRepo(path) {
+			repos = append(repos, path)
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	return repos, err
+}
+
+func isRepo(path string) bool {
+	_, err := os.Stat(filepath.Join(path, ".git"))
+	return err == nil
+}
+
+// Analyze counts the author's commits per day across repos and returns them as a graph
+func Analyze(ctx context.Context, repos []string, opts Options) (*gitgraph.ContributionGraph, error) {
+	counts := make(map[string]int)
+	for _, repo := range repos {
+		if err := countCommits(ctx, repo, opts, counts); err != nil {
+			return nil, fmt.Errorf("%s: %w", repo, err)
+		}
+	}
+
+	graph := gitgraph.GraphFromCounts(opts.Author, counts, opts.From, opts.To, gitgraph.QuartileThresholds(counts))
+	graph.UpdateStreaks(time.Now())
+	return graph, nil
+}
+
+// countCommits adds the commits in repo to counts, keyed by author date.
+// Authors are matched after the repository's .mailmap is applied.
+func countCommits(ctx context.Context, repo string, opts Options, counts map[string]int) error {
+	args := []string{"-C", repo, "log", "--use-mailmap", "--format=%ad%x09%aN%x09%aE", "--date=short",
+		"--since=" + opts.From.Format("2006-01-02"),
+		"--until=" + opts.To.Format("2006-01-02") + " 23:59:59"}
+	if opts.Author != "" {
+		// git counts commits matching any of several --author patterns
+		for _, author := range append([]string{opts.Author}, opts.Aliases...) {
+			args = append(args, "--author="+author)
+		}
+	}
+	if opts.NoMerges {
+		args = append(args, "--no-merges")
+	}
+	if len(opts.Branches) == 0 {
+		args = append(args, "--all")
+	} else {
+		branches := existingRefs(ctx, repo, opts.Branches)
+		if len(branches) == 0 {
+			return nil
+		}
+		args = append(args, branches...)
+	}
+	if len(opts.ExcludePaths) > 0 {
+		args = append(args, "--", ".")
+		for _, path := range opts.ExcludePaths {
+			args = append(args, ":(exclude)"+path)
+		}
+	}
+
+	out, err := git(ctx, args...)
+	if err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		date, author, _ := strings.Cut(scanner.Text(), "\t")
+		name, email, _ := strings.Cut(author, "\t")
+		if opts.ExcludeBots && isBot(name, email) {
+			continue
+		}
+		if date >= opts.From.Format("2006-01-02") && date <= opts.To.Format("2006-01-02") {
+			counts[date]++
+		}
+	}
+	return scanner.Err()
+}
+
+// git runs a git command, returning its output or an error carrying its stderr
+func git(ctx context.Context, args ...string) ([]byte, error) {
+	return gitWithEnv(ctx, nil, args...)
+}
+
+// gitWithEnv is git with env added to the environment, which keeps secrets
+// out of the command line
+func gitWithEnv(ctx context.Context, env []string, args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", args...)
+	if env != nil {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s failed: %v: %s", args[2], err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// existingRefs returns the refs of names that resolve to a commit in repo
+func existingRefs(ctx context.Context, repo string, names []string) []string {
+	refs := []string{}
+	for _, name := range names {
+		if _, err := git(ctx, "-C", repo, "rev-parse", "--verify", "--quiet", name+"^{commit}"); err == nil {
+			refs = append(refs, name)
+		}
+	}
+	return refs
+}
+
+// isBot reports whether an author is an automated account, going by GitHub's
+// [bot] suffix and the usual -bot naming
+func isBot(name, email string) bool {
+	name = strings.ToLower(name)
+	return strings.HasSuffix(name, "[bot]") || strings.HasSuffix(name, "-bot") ||
+		strings.Contains(email, "[bot]@")
+}
+
+// DefaultAuthor returns the user.email from git config, which is the usual author identity
+func DefaultAuthor() string {
+	out, err := exec.Command("git", "config", "user.email").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
@@ -0,0 +1,106 @@
+package local
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Remote is a repository to mirror
+type Remote struct {
+	Name string // e.g. owner/name, also its directory beneath the mirrors
+	URL  string // to clone from over HTTPS
+}
+
+// MirrorOptions controls Mirror
+type MirrorOptions struct {
+	Dir     string // holding a directory per remote
+	Token   string // authenticates to the remotes, for private repositories
+	Workers int    // clones and fetches at once, 4 when zero
+}
+
+// Mirror clones each remote into a bare repository under opts.Dir, with its
+// commits and trees but not file contents, or updates the mirror cloned by an
+// earlier run. It returns the paths mirrored, which Analyze reads like any
+// repository, and an error for each remote that failed, so one unreachable
+// repository doesn't stop the rest.
+func Mirror(ctx context.Context, remotes []Remote, opts MirrorOptions) ([]string, []error) {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = 4
+	}
+	var env []string
+	if opts.Token != "" {
+		// Sent as configuration through the environment so it's neither
+		// shown in the process list nor saved in the mirror's config
+		basic := base64.StdEncoding.EncodeToString([]byte("x-access-token:" + opts.Token))
+		env = []string{"GIT_CONFIG_COUNT=1", "GIT_CONFIG_KEY_0=http.extraHeader", "GIT_CONFIG_VALUE_0=Authorization: Basic " + basic}
+	}
+	env = append(env, "GIT_TERMINAL_PROMPT=0")
+
+	paths := make([]string, len(remotes))
+	errs := make([]error, len(remotes))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers && w < len(remotes); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				path := filepath.Join(opts.Dir, filepath.FromSlash(remotes[i].Name)+".git")
+				if err := mirror(ctx, remotes[i].URL, path, env); err != nil {
+					errs[i] = fmt.Errorf("%s: %w", remotes[i].Name, err)
+					continue
+				}
+				paths[i] = path
+			}
+		}()
+	}
+	for i := range remotes {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	mirrored := []string{}
+	failed := []error{}
+	for i := range remotes {
+		if errs[i] != nil {
+			failed = append(failed, errs[i])
+		} else {
+			mirrored = append(mirrored, paths[i])
+		}
+	}
+	return mirrored, failed
+}
+
+// mirror fetches url's branches and tags into the bare repository at path,
+// cloning it first when there is none. Clones are made beside path and moved
+// into place, so an interrupted run doesn't leave one half done.
+func mirror(ctx context.Context, url, path string, env []string) error {
+	refspecs := []string{"+refs/heads/*:refs/heads/*", "+refs/tags/*:refs/tags/*"}
+	if _, err := os.Stat(filepath.Join(path, "HEAD")); err == nil {
+		_, err := gitWithEnv(ctx, env, append([]string{"-C", path, "fetch", "--prune", "--quiet", "--filter=blob:none", url}, refspecs...)...)
+		return err
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	partial := path + ".partial"
+	if err := os.RemoveAll(partial); err != nil {
+		return err
+	}
+	if _, err := gitWithEnv(ctx, env, "-C", filepath.Dir(path), "clone", "--bare", "--quiet", "--filter=blob:none", url, partial); err != nil {
+		os.RemoveAll(partial)
+		return err
+	}
+	return os.Rename(partial, path)
+}
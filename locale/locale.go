@@ -0,0 +1,232 @@
+// Package locale localizes the month and weekday names and date formats used
+// in rendered calendars and reports.
+package locale
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Locale holds the names and date patterns of one language, after CLDR's
+// abbreviated stand-alone names and its yMMMd, MMMd and yMMM skeletons
+type Locale struct {
+	Tag      string
+	Months   [12]string // abbreviated, January first
+	Weekdays [7]string  // abbreviated, Sunday first
+	// Date patterns use CLDR symbols: d day, M month number, MMM month name,
+	// y year, with literal text in single quotes
+	Date      string // e.g. "MMM d, y"
+	MonthDay  string // e.g. "MMM d"
+	YearMonth string // e.g. "MMM y"
+}
+
+// English is the default locale, matching GitHub's own labels
+var English = &Locale{
+	Tag:       "en",
+	Months:    [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+	Weekdays:  [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+	Date:      "MMM d, y",
+	MonthDay:  "MMM d",
+	YearMonth: "MMM y",
+}
+
+// locales are keyed by language, or language-REGION where a region differs
+var locales = map[string]*Locale{
+	"en": English,
+	"en-GB": {
+		Tag:       "en-GB",
+		Months:    English.Months,
+		Weekdays:  English.Weekdays,
+		Date:      "d MMM y",
+		MonthDay:  "d MMM",
+		YearMonth: "MMM y",
+	},
+	"da": {
+		Tag:       "da",
+		Months:    [12]string{"jan", "feb", "mar", "apr", "maj", "jun", "jul", "aug", "sep", "okt", "nov", "dec"},
+		Weekdays:  [7]string{"søn", "man", "tir", "ons", "tor", "fre", "lør"},
+		Date:      "d. MMM y",
+		MonthDay:  "d. MMM",
+		YearMonth: "MMM y",
+	},
+	"de": {
+		Tag:       "de",
+		Months:    [12]string{"Jan", "Feb", "Mär", "Apr", "Mai", "Jun", "Jul", "Aug", "Sep", "Okt", "Nov", "Dez"},
+		Weekdays:  [7]string{"So", "Mo", "Di", "Mi", "Do", "Fr", "Sa"},
+		Date:      "d. MMM y",
+		MonthDay:  "d. MMM",
+		YearMonth: "MMM y",
+	},
+	"es": {
+		Tag:       "es",
+		Months:    [12]string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic"},
+		Weekdays:  [7]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"},
+		Date:      "d MMM y",
+		MonthDay:  "d MMM",
+		YearMonth: "MMM y",
+	},
+	"fi": {
+		Tag:       "fi",
+		Months:    [12]string{"tammi", "helmi", "maalis", "huhti", "touko", "kesä", "heinä", "elo", "syys", "loka", "marras", "joulu"},
+		Weekdays:  [7]string{"su", "ma", "ti", "ke", "to", "pe", "la"},
+		Date:      "d.M.y",
+		MonthDay:  "d.M.",
+		YearMonth: "MMM y",
+	},
+	"fr": {
+		Tag:       "fr",
+		Months:    [12]string{"janv", "févr", "mars", "avr", "mai", "juin", "juil", "août", "sept", "oct", "nov", "déc"},
+		Weekdays:  [7]string{"dim", "lun", "mar", "mer", "jeu", "ven", "sam"},
+		Date:      "d MMM y",
+		MonthDay:  "d MMM",
+		YearMonth: "MMM y",
+	},
+	"it": {
+		Tag:       "it",
+		Months:    [12]string{"gen", "feb", "mar", "apr", "mag", "giu", "lug", "ago", "set", "ott", "nov", "dic"},
+		Weekdays:  [7]string{"dom", "lun", "mar", "mer", "gio", "ven", "sab"},
+		Date:      "d MMM y",
+		MonthDay:  "d MMM",
+		YearMonth: "MMM y",
+	},
+	"nb": {
+		Tag:       "nb",
+		Months:    [12]string{"jan", "feb", "mar", "apr", "mai", "jun", "jul", "aug", "sep", "okt", "nov", "des"},
+		Weekdays:  [7]string{"søn", "man", "tir", "ons", "tor", "fre", "lør"},
+		Date:      "d. MMM y",
+		MonthDay:  "d. MMM",
+		YearMonth: "MMM y",
+	},
+	"nl": {
+		Tag:       "nl",
+		Months:    [12]string{"jan", "feb", "mrt", "apr", "mei", "jun", "jul", "aug", "sep", "okt", "nov", "dec"},
+		Weekdays:  [7]string{"zo", "ma", "di", "wo", "do", "vr", "za"},
+		Date:      "d MMM y",
+		MonthDay:  "d MMM",
+		YearMonth: "MMM y",
+	},
+	"pl": {
+		Tag:       "pl",
+		Months:    [12]string{"sty", "lut", "mar", "kwi", "maj", "cze", "lip", "sie", "wrz", "paź", "lis", "gru"},
+		Weekdays:  [7]string{"niedz", "pon", "wt", "śr", "czw", "pt", "sob"},
+		Date:      "d MMM y",
+		MonthDay:  "d MMM",
+		YearMonth: "MMM y",
+	},
+	"pt": {
+		Tag:       "pt",
+		Months:    [12]string{"jan", "fev", "mar", "abr", "mai", "jun", "jul", "ago", "set", "out", "nov", "dez"},
+		Weekdays:  [7]string{"dom", "seg", "ter", "qua", "qui", "sex", "sáb"},
+		Date:      "d 'de' MMM 'de' y",
+		MonthDay:  "d 'de' MMM",
+		YearMonth: "MMM 'de' y",
+	},
+	"sv": {
+		Tag:       "sv",
+		Months:    [12]string{"jan", "feb", "mars", "apr", "maj", "juni", "juli", "aug", "sep", "okt", "nov", "dec"},
+		Weekdays:  [7]string{"sön", "mån", "tis", "ons", "tors", "fre", "lör"},
+		Date:      "d MMM y",
+		MonthDay:  "d MMM",
+		YearMonth: "MMM y",
+	},
+}
+
+// Tags lists the supported locales
+func Tags() []string {
+	tags := make([]string, 0, len(locales))
+	for tag := range locales {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// Lookup finds the locale of a BCP 47 style tag such as de-DE, de_DE or de,
+// falling back from language-REGION to the language alone
+func Lookup(tag string) (*Locale, error) {
+	parts := strings.FieldsFunc(tag, func(r rune) bool { return r == '-' || r == '_' || r == '.' })
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("empty locale")
+	}
+	lang := strings.ToLower(parts[0])
+	if len(parts) > 1 {
+		if l, ok := locales[lang+"-"+strings.ToUpper(parts[1])]; ok {
+			return l, nil
+		}
+	}
+	if l, ok := locales[lang]; ok {
+		return l, nil
+	}
+	return nil, fmt.Errorf("unsupported locale %q, expected one of %s", tag, strings.Join(Tags(), ", "))
+}
+
+// OrDefault returns l, or English when l is nil
+func (l *Locale) OrDefault() *Locale {
+	if l == nil {
+		return English
+	}
+	return l
+}
+
+// Month returns the abbreviated name of m
+func (l *Locale) Month(m time.Month) string {
+	return l.OrDefault().Months[m-1]
+}
+
+// Weekday returns the abbreviated name of d
+func (l *Locale) Weekday(d time.Weekday) string {
+	return l.OrDefault().Weekdays[d]
+}
+
+// FormatDate formats t as a day, month name and year, e.g. "Jan 2, 2006"
+func (l *Locale) FormatDate(t time.Time) string {
+	return l.format(l.OrDefault().Date, t)
+}
+
+// FormatMonthDay formats t without its year, e.g. "Jan 2"
+func (l *Locale) FormatMonthDay(t time.Time) string {
+	return l.format(l.OrDefault().MonthDay, t)
+}
+
+// FormatYearMonth formats t's month and year, e.g. "Jan 2006"
+func (l *Locale) FormatYearMonth(t time.Time) string {
+	return l.format(l.OrDefault().YearMonth, t)
+}
+
+// format expands a CLDR-style pattern for t
+func (l *Locale) format(pattern string, t time.Time) string {
+	var b strings.Builder
+	for i := 0; i < len(pattern); {
+		c := pattern[i]
+		if c == '\'' {
+			end := strings.IndexByte(pattern[i+1:], '\'')
+			if end < 0 {
+				end = len(pattern) - i - 1
+			}
+			b.WriteString(pattern[i+1 : i+1+end])
+			i += end + 2
+			continue
+		}
+		n := 1
+		for i+n < len(pattern) && pattern[i+n] == c {
+			n++
+		}
+		switch {
+		case c == 'd':
+			b.WriteString(strconv.Itoa(t.Day()))
+		case c == 'y':
+			b.WriteString(strconv.Itoa(t.Year()))
+		case c == 'M' && n >= 3:
+			b.WriteString(l.Month(t.Month()))
+		case c == 'M':
+			b.WriteString(strconv.Itoa(int(t.Month())))
+		default:
+			b.WriteString(pattern[i : i+n])
+		}
+		i += n
+	}
+	return b.String()
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+)
+
+// logLevel is raised by --quiet and lowered by --verbose
+var logLevel = new(slog.LevelVar)
+
+var logOptions = &slog.HandlerOptions{Level: logLevel}
+
+// Logs go to stderr so stdout only ever carries command output
+func init() {
+	setLogHandler(slog.NewTextHandler(os.Stderr, logOptions))
+}
+
+func setLogHandler(handler slog.Handler) {
+	slog.SetDefault(slog.New(handler))
+}
+
+// fatal logs msg with its attributes as an error and exits with status 1
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(exitFailure)
+}
@@ -1,173 +1,126 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
-	"regexp"
-	"strconv"
+	"os/signal"
 	"strings"
-	"time"
+	"syscall"
 )
 
-// ContributionDay represents a single day in the contribution graph
-type ContributionDay struct {
-	Date         string `json:"date"`
-	Count        int    `json:"count"`
-	Level        int    `json:"level"`
-	DayOfWeek    int    `json:"dayOfWeek"`
-	WeekOfYear   int    `json:"weekOfYear"`
-	ContribLevel string `json:"contribLevel"` // none, first_quartile, second_quartile, third_quartile, fourth_quartile
+// command is a gitgraphed subcommand
+type command struct {
+	Name    string
+	Usage   string
+	Summary string
+	Run     func(ctx context.Context, config *Config, args []string)
 }
 
-// ContributionGraph represents the complete contribution data
-type ContributionGraph struct {
-	Username      string            `json:"username"`
-	TotalContribs int               `json:"totalContributions"`
-	Years         []int             `json:"years"`
-	Days          []ContributionDay `json:"days"`
-}
-
-func main() {
-	if len(os.Args) < 2 {
-		fmt.Println("Usage: gitgraphed <username> [year]")
-		os.Exit(1)
-	}
-
-	username := os.Args[1]
-	year := time.Now().Year()
-
-	if len(os.Args) >= 3 {
-		parsedYear, err := strconv.Atoi(os.Args[2])
-		if err == nil {
-			year = parsedYear
-		}
-	}
-
-	graph, err := fetchContributionGraph(username, year)
-	if err != nil {
-		fmt.Printf("Error fetching contribution data: %v\n", err)
-		os.Exit(1)
-	}
-
-	// Output JSON to stdout
-	encoder := json.NewEncoder(os.Stdout)
-	encoder.SetIndent("", "  ")
-	if err := encoder.Encode(graph); err != nil {
-		fmt.Printf("Error encoding JSON: %v\n", err)
-		os.Exit(1)
+// commands is populated in init, since usage refers back to it
+var commands []command
+
+func init() {
+	commands = []command{
+		{"fetch", "fetch [flags] <username> [year|from-to]", "fetch contributions as JSON, CSV or an email digest", runFetch},
+		{"render", "render [flags] <username> [year|from-to]", "render the calendar as SVG, PNG or a terminal heatmap", runRender},
+		{"stats", "stats [flags] <username> [year|from-to]", "print streaks and summary statistics", runStats},
+		{"batch", "batch [flags] -f <file> [year|from-to]", "fetch many users listed in a file, one JSON result per line", runBatch},
+		{"compare", "compare [flags] <username> <username>... | --years <years> <username>", "compare several users over the same year, or a user's years, as JSON or one SVG", runCompare},
+		{"yoy", "yoy [flags] [username] <year> <year>", "compare a user's year against an earlier one, week by week and month by month", runYoY},
+		{"seasons", "seasons [flags] [username] [year]", "compare a year's quarters and seasons with the previous year's", runSeasons},
+		{"punchcard", "punchcard [flags] <username>", "count recent contributions by weekday and hour from the Events API", runPunchCard},
+		{"tui", "tui [flags] [username...]", "browse the calendar interactively, switching between years and users", runTUI},
+		{"leaderboard", "leaderboard [flags] [username...]", "rank a team, an organization or several users by total, streak or active days", runLeaderboard},
+		{"org", "org [flags] <org>", "aggregate the contributions of an organization's members", runOrg},
+		{"widget", "widget [flags] <username>", "render a profile README widget, optionally committing it to a repository", runWidget},
+		{"serve", "serve [flags]", "serve JSON and SVG over HTTP, and optionally gRPC", runServe},
+		{"exporter", "exporter [flags] <username>...", "export Prometheus metrics", runExporter},
+		{"wallpaper", "wallpaper [flags] [username]", "render the rolling year as a desktop wallpaper PNG, optionally setting it", runWallpaper},
+		{"local", "local [flags] [path...] | org [flags] <org>", "graph commits from local git repositories, or from mirrors of an organization's", runLocal},
+		{"daemon", "daemon [flags] [username...]", "fetch, render and notify on a cron schedule", runDaemon},
+		{"remind", "remind [flags] [username]", "show a desktop notification in the evening while today has no contributions", runRemind},
+		{"query", "query [flags] <username> [year|from-to]", "read days saved with --store", runQuery},
+		{"trends", "trends [flags] <username>", "sample follower and star counts into a --store and graph their growth", runTrends},
+		{"sync", "sync [flags] [username...]", "update a --store with only the days since its last snapshot", runSync},
+		{"store", "store prune [flags] [username...]", "drop old snapshots from a --store, keeping a few per day and week", runStore},
+		{"report", "report [flags] [username...]", "summarize last week as an HTML report, optionally emailed, or a year as a PDF", runReport},
+		{"progress", "progress [flags] [username]", "show progress towards yearly and monthly goals", runProgress},
+		{"plan", "plan [flags] <text> | --grid <file>", "compute the dated commits that draw text or a pixel grid on the calendar", runPlan},
+		{"diff", "diff [flags] <old.json> <new.json>", "report days, totals and streaks that changed between snapshots", runDiff},
+		{"login", "login [flags]", "log in to GitHub in the browser and save a token for later commands", runLogin},
+		{"validate", "validate [flags] <file.json>...", "check JSON output against its published schema", runValidate},
+		{"completion", "completion bash|zsh|fish", "write a shell script completing commands, flags, formats and themes", runCompletion},
+		{"update", "update [flags]", "replace this executable with the latest release, after verifying its checksum", runUpdate},
 	}
 }
 
-func fetchContributionGraph(username string, year int) (*ContributionGraph, error) {
-	url := fmt.Sprintf("https://github.com/users/%s/contributions?from=%d-01-01&to=%d-12-31",
-		username, year, year)
-
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
-
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	// Add headers to make it look like a browser request
-	req.Header.Add("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36")
-	req.Header.Add("Accept", "text/html,application/xhtml+xml,application/xml")
+func main() {
+	args := os.Args[1:]
 
-	resp, err := client.Do(req)
+	configPath, explicitConfig := configPathFromArgs(args)
+	config, err := loadConfig(configPath, explicitConfig)
 	if err != nil {
-		return nil, err
+		fatal("loading config", "err", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("HTTP request failed with status code: %d", resp.StatusCode)
+	if config.CacheControl != "" {
+		uploadCacheControl = config.CacheControl
 	}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
+	if len(args) > 0 && (args[0] == "help" || args[0] == "-h" || args[0] == "--help") {
+		usage()
+		return
 	}
 
-	htmlContent := string(body)
-
-	// Extract total contributions
-	totalRegex := regexp.MustCompile(`(\d+) contributions in the last year`)
-	totalMatches := totalRegex.FindStringSubmatch(htmlContent)
-	totalContribs := 0
-	if len(totalMatches) > 1 {
-		totalContribs, _ = strconv.Atoi(totalMatches[1])
+	// SIGINT/SIGTERM cancel in-flight requests so commands can stop cleanly
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	// The completion scripts call back with the words being completed
+	if len(args) > 0 && args[0] == "__complete" {
+		runComplete(ctx, config, args[1:])
+		return
 	}
 
-	// Find all the contribution days
-	dayRegex := regexp.MustCompile(`data-date="([^"]+)"[^>]+data-level="([^"]+)"[^>]*>([^<]*)<\/td>`)
-	dayMatches := dayRegex.FindAllStringSubmatch(htmlContent, -1)
-
-	days := make([]ContributionDay, 0, len(dayMatches))
-
-	for _, match := range dayMatches {
-		dateStr := match[1]
-		levelStr := match[2]
-		countStr := strings.TrimSpace(match[3])
-
-		// Parse date
-		date, err := time.Parse("2006-01-02", dateStr)
-		if err != nil {
-			continue
-		}
-
-		// Parse count (GitHub shows "No contributions" or "X contributions")
-		count := 0
-		if countStr != "No contributions" && countStr != "" {
-			countParts := strings.Fields(countStr)
-			if len(countParts) > 0 {
-				count, _ = strconv.Atoi(countParts[0])
-			}
-		}
-
-		// Parse level
-		level, _ := strconv.Atoi(levelStr)
-
-		// Determine contribution level name
-		var contribLevel string
-		switch level {
-		case 0:
-			contribLevel = "none"
-		case 1:
-			contribLevel = "first_quartile"
-		case 2:
-			contribLevel = "second_quartile"
-		case 3:
-			contribLevel = "third_quartile"
-		case 4:
-			contribLevel = "fourth_quartile"
+	// Without a known command, arguments are passed to fetch as before subcommands existed
+	cmd := commands[0]
+	if len(args) > 0 {
+		if found, ok := findCommand(args[0]); ok {
+			cmd = found
+			args = args[1:]
 		}
+	}
+	cmd.Run(ctx, config, args)
+}
 
-		day := ContributionDay{
-			Date:         dateStr,
-			Count:        count,
-			Level:        level,
-			DayOfWeek:    int(date.Weekday()),
-			WeekOfYear:   getWeekOfYear(date),
-			ContribLevel: contribLevel,
+func findCommand(name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.Name == name {
+			return cmd, true
 		}
-
-		days = append(days, day)
 	}
+	return command{}, false
+}
 
-	return &ContributionGraph{
-		Username:      username,
-		TotalContribs: totalContribs,
-		Years:         []int{year},
-		Days:          days,
-	}, nil
+func usage() {
+	fmt.Println("Usage: gitgraphed <command> [flags] [arguments]")
+	fmt.Println()
+	fmt.Println("Commands:")
+	for _, cmd := range commands {
+		fmt.Printf("  %-10s %s\n", cmd.Name, cmd.Summary)
+	}
+	fmt.Println()
+	fmt.Println("Run 'gitgraphed <command> -h' for the flags of a command.")
+	fmt.Println()
+	fmt.Println("Exit status: 1 on failure, 2 for invalid flags, 3 when the user is not found,")
+	fmt.Println("4 when rate limited, 5 on network errors, 6 for unparseable responses and 7 when")
+	fmt.Println("the user's profile is private.")
 }
 
-func getWeekOfYear(date time.Time) int {
-	_, week := date.ISOWeek()
-	return week
+// commandUsage prints the usage line of the named command followed by its flags
+func commandUsage(name string, printDefaults func()) func() {
+	return func() {
+		cmd, _ := findCommand(name)
+		fmt.Printf("Usage: gitgraphed %s\n\n%s.\n\nFlags:\n", cmd.Usage, strings.ToUpper(cmd.Summary[:1])+cmd.Summary[1:])
+		printDefaults()
+	}
 }
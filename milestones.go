@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+	"github.com/JyotinderSingh/gitgraphed/store"
+)
+
+// milestoneHistory is the days milestones are found in: graph's, preceded by
+// the earlier days kept in st, when there is one
+func milestoneHistory(ctx context.Context, st store.Store, graph *gitgraph.ContributionGraph, now time.Time) ([]gitgraph.ContributionDay, error) {
+	if st == nil {
+		return graph.Days, nil
+	}
+	stored, err := st.Graph(ctx, graph.Username, time.Time{}, now)
+	if err != nil {
+		return nil, err
+	}
+	// The fetched days are fresher than the stored ones
+	fetched := map[string]bool{}
+	for _, day := range graph.Days {
+		fetched[day.Date] = true
+	}
+	days := append([]gitgraph.ContributionDay{}, graph.Days...)
+	for _, day := range stored.Days {
+		if !fetched[day.Date] {
+			days = append(days, day)
+		}
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Date < days[j].Date })
+	return days, nil
+}
+
+// newMilestones returns the milestones of after that before doesn't have
+func newMilestones(before, after []gitgraph.Milestone) []gitgraph.Milestone {
+	seen := map[string]bool{}
+	for _, m := range before {
+		seen[m.Key()] = true
+	}
+	var added []gitgraph.Milestone
+	for _, m := range after {
+		if !seen[m.Key()] {
+			added = append(added, m)
+		}
+	}
+	return added
+}
+
+// milestoneEvent is the watch event announcing m
+func milestoneEvent(m gitgraph.Milestone, total, streak int) watchEvent {
+	return watchEvent{Type: "milestone", Total: total, Streak: streak, Milestone: m.Value, MilestoneType: m.Type, MilestoneDate: m.Date}
+}
+
+// milestoneMessage describes a milestone event in a sentence
+func milestoneMessage(event watchEvent) string {
+	switch event.MilestoneType {
+	case gitgraph.MilestoneFirstOfYear:
+		return fmt.Sprintf("%s made the first contribution of %s on %s", event.Username, event.MilestoneDate[:4], event.MilestoneDate)
+	case gitgraph.MilestoneLongestStreak:
+		return fmt.Sprintf("%s is on a new longest streak: %d days 🔥", event.Username, event.Milestone)
+	case gitgraph.MilestoneBusiestDay:
+		return fmt.Sprintf("%s had the busiest day yet on %s, with %d contributions", event.Username, event.MilestoneDate, event.Milestone)
+	}
+	return fmt.Sprintf("%s reached %d contributions 🎉", event.Username, event.Milestone)
+}
+
+// describeMilestone is m as a line of the stats output
+func describeMilestone(m gitgraph.Milestone) string {
+	switch m.Type {
+	case gitgraph.MilestoneFirstOfYear:
+		return fmt.Sprintf("first contribution of %s", m.Date[:4])
+	case gitgraph.MilestoneLongestStreak:
+		return fmt.Sprintf("new longest streak, %d %s", m.Value, pluralize(m.Value, "day", "days"))
+	case gitgraph.MilestoneBusiestDay:
+		return fmt.Sprintf("busiest day yet, %d %s", m.Value, pluralize(m.Value, "contribution", "contributions"))
+	}
+	return fmt.Sprintf("%dth contribution", m.Value)
+}
+
+// printMilestones lists the latest limit milestones, oldest first
+func printMilestones(milestones []gitgraph.Milestone, limit int) {
+	if len(milestones) == 0 || limit <= 0 {
+		return
+	}
+	fmt.Println("  Milestones:")
+	for _, m := range milestones[max(len(milestones)-limit, 0):] {
+		fmt.Printf("    %s  %s\n", m.Date, describeMilestone(m))
+	}
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+	"github.com/JyotinderSingh/gitgraphed/render"
+)
+
+// notifier delivers watch events to an external target. Add a target by
+// implementing it and appending to newNotifiers.
+type notifier interface {
+	// Name identifies the target in logs
+	Name() string
+	// Wants reports whether events of this type should be delivered
+	Wants(eventType string) bool
+	// Notify delivers event; graph is the latest successful fetch
+	Notify(ctx context.Context, event watchEvent, graph *gitgraph.ContributionGraph) error
+}
+
+// newNotifiers returns a notifier for each target configured in opts
+func newNotifiers(opts watchOptions, client *http.Client) []notifier {
+	var notifiers []notifier
+	if opts.NotifyURL != "" {
+		notifiers = append(notifiers, &webhook{URL: opts.NotifyURL, HTTPClient: client})
+	}
+	if opts.SlackURL != "" {
+		notifiers = append(notifiers, &slack{URL: opts.SlackURL, HTTPClient: client})
+	}
+	if opts.DiscordURL != "" {
+		notifiers = append(notifiers, &discord{URL: opts.DiscordURL, HTTPClient: client})
+	}
+	if opts.Desktop {
+		notifiers = append(notifiers, &desktop{})
+	}
+	return notifiers
+}
+
+// chatEvent reports whether eventType is worth a chat message; changes are
+// too frequent and snapshots and errors are for the local log only
+func chatEvent(eventType string) bool {
+	return eventType == "daily-summary" || eventType == "milestone" || eventType == "streak-at-risk"
+}
+
+// post sends body to url, treating any non-2xx response as a failure of target
+func post(ctx context.Context, client *http.Client, target, url, contentType string, body io.Reader) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("User-Agent", "gitgraphed")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s returned %s", target, resp.Status)
+	}
+	return nil
+}
+
+// webhook POSTs watch events as JSON to a user-configured URL
+type webhook struct {
+	URL        string
+	HTTPClient *http.Client
+}
+
+func (h *webhook) Name() string { return "webhook" }
+
+func (h *webhook) Wants(eventType string) bool {
+	return eventType == "change" || eventType == "streak-at-risk"
+}
+
+// Notify sends event to the webhook as JSON
+func (h *webhook) Notify(ctx context.Context, event watchEvent, _ *gitgraph.ContributionGraph) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return post(ctx, h.HTTPClient, "webhook", h.URL, "application/json", bytes.NewReader(body))
+}
+
+// slack posts watch events as messages to a Slack incoming webhook
+type slack struct {
+	URL        string
+	HTTPClient *http.Client
+}
+
+func (s *slack) Name() string { return "slack" }
+
+func (s *slack) Wants(eventType string) bool { return chatEvent(eventType) }
+
+// Notify sends event's message to the channel of the incoming webhook
+func (s *slack) Notify(ctx context.Context, event watchEvent, _ *gitgraph.ContributionGraph) error {
+	body, err := json.Marshal(map[string]string{"text": eventMessage(event)})
+	if err != nil {
+		return err
+	}
+	return post(ctx, s.HTTPClient, "slack", s.URL, "application/json", bytes.NewReader(body))
+}
+
+// discord posts watch events to a Discord webhook as an embed showing the
+// recent heatmap and the day's stats
+type discord struct {
+	URL        string
+	HTTPClient *http.Client
+}
+
+// discordHeatmapWeeks is how much history the embedded heatmap shows
+const discordHeatmapWeeks = 12
+
+// discordColor is the embed accent, GitHub's brightest green
+const discordColor = 0x216e39
+
+type discordEmbed struct {
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	Color       int            `json:"color"`
+	Fields      []discordField `json:"fields,omitempty"`
+	Image       *discordImage  `json:"image,omitempty"`
+	Timestamp   time.Time      `json:"timestamp"`
+}
+
+type discordField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+type discordImage struct {
+	URL string `json:"url"`
+}
+
+func (d *discord) Name() string { return "discord" }
+
+func (d *discord) Wants(eventType string) bool { return chatEvent(eventType) }
+
+// Notify uploads the heatmap and posts an embed referencing it as an attachment
+func (d *discord) Notify(ctx context.Context, event watchEvent, graph *gitgraph.ContributionGraph) error {
+	embed := discordEmbed{
+		Title:       event.Username + "'s contributions",
+		Description: eventMessage(event),
+		Color:       discordColor,
+		Timestamp:   event.Time,
+		Fields: []discordField{
+			{Name: "Total", Value: fmt.Sprint(event.Total), Inline: true},
+			{Name: "Current streak", Value: fmt.Sprintf("%d %s", event.Streak, pluralize(event.Streak, "day", "days")), Inline: true},
+		},
+	}
+	if event.Day != nil {
+		embed.Fields = append(embed.Fields, discordField{Name: event.Day.Date, Value: fmt.Sprint(event.Day.Count), Inline: true})
+	}
+
+	var heatmap bytes.Buffer
+	if graph != nil {
+		recent := recentWeeks(graph, event.Time, discordHeatmapWeeks)
+		if err := render.PNG(&heatmap, recent, render.PNGOptions{Scale: 2, Theme: render.GitHubTheme}); err != nil {
+			return err
+		}
+		embed.Image = &discordImage{URL: "attachment://heatmap.png"}
+	}
+
+	payload, err := json.Marshal(map[string][]discordEmbed{"embeds": {embed}})
+	if err != nil {
+		return err
+	}
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	if err := form.WriteField("payload_json", string(payload)); err != nil {
+		return err
+	}
+	if heatmap.Len() > 0 {
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Disposition", `form-data; name="files[0]"; filename="heatmap.png"`)
+		header.Set("Content-Type", "image/png")
+		part, err := form.CreatePart(header)
+		if err != nil {
+			return err
+		}
+		if _, err := part.Write(heatmap.Bytes()); err != nil {
+			return err
+		}
+	}
+	if err := form.Close(); err != nil {
+		return err
+	}
+	return post(ctx, d.HTTPClient, "discord", d.URL, form.FormDataContentType(), &body)
+}
+
+// recentWeeks returns graph trimmed to its last weeks weeks up to now
+func recentWeeks(graph *gitgraph.ContributionGraph, now time.Time, weeks int) *gitgraph.ContributionGraph {
+	from := now.AddDate(0, 0, -7*weeks+1).Format("2006-01-02")
+	to := now.Format("2006-01-02")
+	recent := *graph
+	recent.Days = nil
+	for _, day := range graph.Days {
+		if day.Date >= from && day.Date <= to {
+			recent.Days = append(recent.Days, day)
+		}
+	}
+	return &recent
+}
+
+// eventMessage describes a watch event in a sentence for chat notifications
+func eventMessage(event watchEvent) string {
+	switch event.Type {
+	case "daily-summary":
+		return fmt.Sprintf("%s made %d %s on %s, %d in total with a %d-day streak",
+			event.Username, event.Day.Count, pluralize(event.Day.Count, "contribution", "contributions"),
+			event.Day.Date, event.Total, event.Streak)
+	case "streak-at-risk":
+		return fmt.Sprintf("%s has no contributions yet today, and a %d-day streak at stake", event.Username, event.Streak)
+	case "milestone":
+		return milestoneMessage(event)
+	case "change":
+		return fmt.Sprintf("%s's contributions on %s went from %d to %d", event.Username, event.Change.Date, event.Change.Before, event.Change.After)
+	}
+	return fmt.Sprintf("%s: %s %s", event.Username, event.Type, event.Error)
+}
+
+// streakNudge decides when to warn that the current streak is about to break
+type streakNudge struct {
+	Hour     int // local hour from which to nudge; 0 disables nudging
+	lastDate string
+}
+
+// Due reports whether a nudge should be sent at now: past the nudge hour, with
+// a running streak and nothing yet today. It fires at most once per day.
+func (n *streakNudge) Due(now time.Time, todayCount, streak int) bool {
+	if n.Hour <= 0 || now.Hour() < n.Hour || todayCount > 0 || streak == 0 {
+		return false
+	}
+	today := now.Format("2006-01-02")
+	if n.lastDate == today {
+		return false
+	}
+	n.lastDate = today
+	return true
+}
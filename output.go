@@ -0,0 +1,327 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/JyotinderSingh/gitgraphed/api"
+	"github.com/JyotinderSingh/gitgraphed/export"
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+	"github.com/JyotinderSingh/gitgraphed/publish"
+	"github.com/JyotinderSingh/gitgraphed/render"
+)
+
+// outputOptions collects the flags that shape rendered output
+type outputOptions struct {
+	Format string
+	Header bool
+	SVG    render.SVGOptions
+	Term   render.TermOptions
+	PNG    render.PNGOptions
+	GIF    render.GIFOptions
+	MD     render.MarkdownOptions
+	Spark  render.SparkOptions
+	Emoji  render.EmojiOptions
+	Dots   render.BrailleOptions
+	Bitmap render.BitmapOptions
+	TikZ   render.TikZOptions
+	Table  render.TableOptions
+	// Skyline draws svg and png as an isometric 3D view instead of the calendar
+	Skyline *render.SkylineOptions
+	// Months draws svg, png and term as a bar chart of each month's total
+	Months *render.MonthOptions
+	// Poster draws svg and png as every year's calendar, stacked
+	Poster *render.PosterOptions
+	Rollup string // week or month to aggregate days, empty for daily data
+	// Template replaces Format with a user-supplied text/template
+	Template *template.Template
+	// Plugin is the executable producing Format when it isn't built in
+	Plugin string
+	// Fields selects the day fields of json, jsonl and csv, empty for all
+	Fields []string
+	// Shape is one of jsonShapes to reshape json for another consumer
+	Shape string
+	// Copy places the output on the clipboard instead of stdout
+	Copy bool
+	// Order sorts and limits the days of json, jsonl, csv and table, nil
+	// for every day in date order
+	Order *dayOrder
+	// Now is the time anomalies are found as of, the current time when zero
+	Now time.Time
+}
+
+// now is Now, or the current time when it is unset
+func (opts outputOptions) now() time.Time {
+	if opts.Now.IsZero() {
+		return time.Now()
+	}
+	return opts.Now
+}
+
+// dayOrder is how the days of data output are sorted and limited
+type dayOrder struct {
+	By   string // one of gitgraph.DayOrders
+	Desc bool
+	Top  int // days written, 0 for all
+}
+
+// apply returns a copy of graph with its days in order, leaving graph as it
+// is for the totals and summaries worked out in date order
+func (o *dayOrder) apply(graph *gitgraph.ContributionGraph) (*gitgraph.ContributionGraph, error) {
+	if o == nil {
+		return graph, nil
+	}
+	days, err := gitgraph.SortDays(graph.Days, o.By, o.Desc)
+	if err != nil {
+		return nil, err
+	}
+	if o.Top > 0 && o.Top < len(days) {
+		days = days[:o.Top]
+	}
+	ordered := *graph
+	ordered.Days = days
+	return &ordered, nil
+}
+
+// writeOutput writes graph to path (stdout when empty), exiting on failure
+func writeOutput(path string, graph *gitgraph.ContributionGraph, opts outputOptions) {
+	if opts.Copy {
+		copyOutput(path, func(w io.Writer) error { return writeGraph(w, graph, opts) })
+		return
+	}
+	out, err := createOutput(path)
+	if err != nil {
+		fatal("creating output", "err", err)
+	}
+	if err := writeGraph(out, graph, opts); err != nil {
+		fatal("writing output", "format", opts.Format, "err", err)
+	}
+	if err := out.Close(); err != nil {
+		fatal("writing output", "err", err)
+	}
+}
+
+// writeGraph encodes graph to w in opts.Format
+func writeGraph(w io.Writer, graph *gitgraph.ContributionGraph, opts outputOptions) error {
+	if opts.Template != nil {
+		graph.UpdateSummary()
+		return opts.Template.Execute(w, graph)
+	}
+	if opts.Plugin != "" {
+		return runFormatPlugin(w, graph, opts.Plugin, opts.Format, opts.now())
+	}
+	if opts.Rollup != "" {
+		return writeRollup(w, graph, opts)
+	}
+	// json orders its days once the summary is worked out in date order
+	if opts.Order != nil && opts.Format != "json" {
+		var err error
+		if graph, err = opts.Order.apply(graph); err != nil {
+			return err
+		}
+	}
+	switch opts.Format {
+	case "csv":
+		return export.CSVFields(w, graph, opts.Header, opts.Fields)
+	case "table":
+		opts.Table.Header = opts.Header
+		return render.Table(w, graph, opts.Table)
+	case "jsonl":
+		return export.JSONLFields(w, graph, opts.Fields)
+	case "ics":
+		return export.ICS(w, graph)
+	case "parquet":
+		return export.Parquet(w, graph)
+	case "xml":
+		return export.XML(w, graph, time.Now())
+	case "msgpack":
+		return export.MsgPack(w, graph, time.Now())
+	case "influx":
+		return export.Influx(w, graph)
+	case "pb":
+		data, err := proto.Marshal(api.FromGraph(graph))
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	case "svg":
+		if opts.Skyline != nil {
+			return render.SkylineSVG(w, graph, *opts.Skyline)
+		}
+		if opts.Months != nil {
+			return render.MonthsSVG(w, graph, *opts.Months)
+		}
+		if opts.Poster != nil {
+			return render.PosterSVG(w, graph, *opts.Poster)
+		}
+		return render.SVG(w, graph, opts.SVG)
+	case "png":
+		if opts.Skyline != nil {
+			return render.SkylinePNG(w, graph, *opts.Skyline)
+		}
+		if opts.Months != nil {
+			return render.MonthsPNG(w, graph, *opts.Months)
+		}
+		if opts.Poster != nil {
+			return render.PosterPNG(w, graph, *opts.Poster)
+		}
+		return render.PNG(w, graph, opts.PNG)
+	case "gif":
+		return render.GIF(w, graph, opts.GIF)
+	case "term":
+		if opts.Months != nil {
+			return render.MonthsTerminal(w, graph, opts.Term)
+		}
+		return render.Terminal(w, graph, opts.Term)
+	case "md":
+		return render.Markdown(w, graph, opts.MD)
+	case "braille":
+		return render.Braille(w, graph, opts.Dots)
+	case "spark":
+		return render.Sparkline(w, graph, opts.Spark)
+	case "emoji":
+		return render.Emoji(w, graph, opts.Emoji)
+	case "pbm":
+		return render.PBM(w, graph, opts.Bitmap)
+	case "bmp":
+		return render.BMP(w, graph, opts.Bitmap)
+	case "tikz":
+		return render.TikZ(w, graph, opts.TikZ)
+	default:
+		switch opts.Shape {
+		case "graphql":
+			return export.GraphQL(w, graph, render.GitHubTheme.Levels, !compactJSON)
+		case "chartjs":
+			return export.ChartJS(w, graph, render.GitHubTheme.Levels, !compactJSON)
+		case "d3":
+			return export.D3(w, graph, !compactJSON)
+		}
+		graph.UpdateSummary()
+		graph.UpdateAnomalies(opts.now())
+		graph, err := opts.Order.apply(graph)
+		if err != nil {
+			return err
+		}
+		if len(opts.Fields) > 0 {
+			selected, err := export.SelectFields(graph, opts.Fields)
+			if err != nil {
+				return err
+			}
+			return encodeJSON(w, selected)
+		}
+		return encodeJSON(w, graph)
+	}
+}
+
+// writeRollup encodes graph aggregated by opts.Rollup as CSV or JSON
+func writeRollup(w io.Writer, graph *gitgraph.ContributionGraph, opts outputOptions) error {
+	rollup, err := gitgraph.RollupGraph(graph, opts.Rollup)
+	if err != nil {
+		return err
+	}
+	if opts.Format == "csv" {
+		return export.RollupCSV(w, rollup, opts.Header)
+	}
+	return encodeJSON(w, rollup)
+}
+
+// writeJSON outputs v as indented JSON to stdout, exiting on failure
+func writeJSON(v any) {
+	if err := encodeJSON(os.Stdout, v); err != nil {
+		fatal("encoding JSON", "err", err)
+	}
+}
+
+// compactJSON drops the indentation of encodeJSON, set by --compact
+var compactJSON bool
+
+func encodeJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	if !compactJSON {
+		encoder.SetIndent("", "  ")
+	}
+	return encoder.Encode(v)
+}
+
+// jsonArray writes a JSON array one element at a time, so large outputs
+// needn't be held in memory, indented like encodeJSON unless compactJSON
+type jsonArray struct {
+	w io.Writer
+	n int
+}
+
+// Write appends v to the array
+func (a *jsonArray) Write(v any) error {
+	var b bytes.Buffer
+	encoder := json.NewEncoder(&b)
+	sep, open := ",", "["
+	if !compactJSON {
+		encoder.SetIndent("  ", "  ")
+		sep, open = ",\n  ", "[\n  "
+	}
+	if err := encoder.Encode(v); err != nil {
+		return err
+	}
+	if a.n == 0 {
+		sep = open
+	}
+	a.n++
+	_, err := io.WriteString(a.w, sep+strings.TrimSuffix(b.String(), "\n"))
+	return err
+}
+
+// Close ends the array
+func (a *jsonArray) Close() error {
+	end := "]\n"
+	switch {
+	case a.n == 0:
+		end = "[]\n"
+	case !compactJSON:
+		end = "\n]\n"
+	}
+	_, err := io.WriteString(a.w, end)
+	return err
+}
+
+// nopCloser lets stdout be used where the output file would be closed
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+// uploadCacheControl is the Cache-Control of outputs written to object storage
+var uploadCacheControl = publish.DefaultCacheControl
+
+// createOutput opens path for writing, falling back to stdout when path is
+// empty. An s3:// or gs:// path is buffered and uploaded on Close.
+func createOutput(path string) (io.WriteCloser, error) {
+	if path == "" {
+		return nopCloser{os.Stdout}, nil
+	}
+	if publish.IsRemote(path) {
+		return &remoteOutput{dest: path}, nil
+	}
+	return os.Create(path)
+}
+
+// remoteOutput collects output destined for object storage
+type remoteOutput struct {
+	bytes.Buffer
+	dest string
+}
+
+func (o *remoteOutput) Close() error {
+	return publish.Upload(context.Background(), o.dest, publish.Object{Body: o.Bytes(), CacheControl: uploadCacheControl})
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+// formatPluginPrefix names the executables on PATH that provide --format values
+// beyond the built-in ones: --format foo runs gitgraphed-format-foo
+const formatPluginPrefix = "gitgraphed-format-"
+
+// pluginFormatName matches formats that may name a plugin, keeping path
+// separators out of the executable looked up
+var pluginFormatName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// mustFormatPlugin resolves an unknown format to its plugin executable,
+// exiting when there is none
+func mustFormatPlugin(format string) string {
+	if pluginFormatName.MatchString(format) {
+		if path, err := exec.LookPath(formatPluginPrefix + format); err == nil {
+			return path
+		}
+	}
+	fatal("unsupported format, and no "+formatPluginPrefix+format+" plugin on PATH", "format", format)
+	return ""
+}
+
+// runFormatPlugin streams graph as JSON to the plugin's stdin, writing whatever
+// it prints to w. The plugin's stderr passes through, and it is told the format
+// it was run for in $GITGRAPHED_FORMAT. Anomalies are found as of today.
+func runFormatPlugin(w io.Writer, graph *gitgraph.ContributionGraph, path, format string, today time.Time) error {
+	graph.UpdateSummary()
+	graph.UpdateAnomalies(today)
+
+	cmd := exec.Command(path)
+	cmd.Stdout = w
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), "GITGRAPHED_FORMAT="+format)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	// A plugin may stop reading once it has what it needs, so only its exit
+	// status decides whether it failed
+	encodeJSON(stdin, graph)
+	stdin.Close()
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package publish
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// gcsScope allows writing objects
+const gcsScope = "https://www.googleapis.com/auth/devstorage.read_write"
+
+// metadataTokenURL serves the attached service account's token on GCE, Cloud
+// Run and Cloud Functions
+const metadataTokenURL = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+
+// uploadGCS PUTs obj to bucket/key through the Cloud Storage XML API, which
+// takes Content-Type and Cache-Control as plain headers
+func uploadGCS(ctx context.Context, bucket, key string, obj Object) error {
+	endpoint := "https://storage.googleapis.com"
+	token := ""
+	if host := os.Getenv("STORAGE_EMULATOR_HOST"); host != "" {
+		endpoint = strings.TrimSuffix(host, "/")
+		if !strings.Contains(endpoint, "://") {
+			endpoint = "http://" + endpoint
+		}
+	} else {
+		var err error
+		if token, err = gcsToken(ctx); err != nil {
+			return err
+		}
+	}
+
+	u := fmt.Sprintf("%s/%s/%s", endpoint, bucket, s3Escape(key))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u, bytes.NewReader(obj.Body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", obj.ContentType)
+	req.Header.Set("Cache-Control", obj.CacheControl)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return checkResponse("gcs", resp)
+}
+
+// gcsToken returns an OAuth access token from $GOOGLE_OAUTH_ACCESS_TOKEN, the
+// service account key in $GOOGLE_APPLICATION_CREDENTIALS, or the metadata server
+func gcsToken(ctx context.Context) (string, error) {
+	if token := os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN"); token != "" {
+		return token, nil
+	}
+	if path := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS"); path != "" {
+		return serviceAccountToken(ctx, path)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, metadataTokenURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	token, err := requestToken(req)
+	if err != nil {
+		return "", fmt.Errorf("no gcs credentials: set GOOGLE_APPLICATION_CREDENTIALS or run on Google Cloud: %w", err)
+	}
+	return token, nil
+}
+
+// serviceAccount is the part of a service account key file used to sign in
+type serviceAccount struct {
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// serviceAccountToken exchanges a JWT signed with the key file at path for an
+// access token
+func serviceAccountToken(ctx context.Context, path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	var account serviceAccount
+	if err := json.Unmarshal(data, &account); err != nil {
+		return "", fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if account.TokenURI == "" {
+		account.TokenURI = "https://oauth2.googleapis.com/token"
+	}
+	block, _ := pem.Decode([]byte(account.PrivateKey))
+	if block == nil {
+		return "", fmt.Errorf("%s: no private key", path)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", path, err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return "", fmt.Errorf("%s: private key is not RSA", path)
+	}
+
+	now := time.Now()
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	claims, _ := json.Marshal(map[string]any{
+		"iss":   account.ClientEmail,
+		"scope": gcsScope,
+		"aud":   account.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(nil, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+	assertion := unsigned + "." + base64.RawURLEncoding.EncodeToString(signature)
+
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, account.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return requestToken(req)
+}
+
+// requestToken sends req and returns the access_token of its JSON response
+func requestToken(req *http.Request) (string, error) {
+	resp, err := HTTPClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token request returned %s", resp.Status)
+	}
+	var body struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", err
+	}
+	if body.AccessToken == "" {
+		return "", errors.New("token response has no access_token")
+	}
+	return body.AccessToken, nil
+}
@@ -0,0 +1,89 @@
+// Package publish uploads rendered output to object storage: s3:// URLs go to
+// Amazon S3 (or a compatible service) and gs:// URLs to Google Cloud Storage
+package publish
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+// DefaultCacheControl lets CDNs and image proxies hold a graph for a few minutes
+const DefaultCacheControl = "public, max-age=300"
+
+// Object is one upload
+type Object struct {
+	Body         []byte
+	ContentType  string // defaults to the type of the key's extension
+	CacheControl string // defaults to DefaultCacheControl
+}
+
+// HTTPClient carries uploads and credential requests
+var HTTPClient = &http.Client{Timeout: 2 * time.Minute}
+
+// IsRemote reports whether dest is an object storage URL handled by Upload
+func IsRemote(dest string) bool {
+	return strings.HasPrefix(dest, "s3://") || strings.HasPrefix(dest, "gs://")
+}
+
+// Upload writes obj to dest, an s3://bucket/key or gs://bucket/key URL
+func Upload(ctx context.Context, dest string, obj Object) error {
+	scheme, rest, _ := strings.Cut(dest, "://")
+	bucket, key, _ := strings.Cut(rest, "/")
+	if bucket == "" || key == "" {
+		return fmt.Errorf("invalid destination %q, expected %s://bucket/key", dest, scheme)
+	}
+	if obj.ContentType == "" {
+		obj.ContentType = ContentType(key)
+	}
+	if obj.CacheControl == "" {
+		obj.CacheControl = DefaultCacheControl
+	}
+
+	switch scheme {
+	case "s3":
+		return uploadS3(ctx, bucket, key, obj)
+	case "gs":
+		return uploadGCS(ctx, bucket, key, obj)
+	}
+	return fmt.Errorf("unsupported destination %q", dest)
+}
+
+// contentTypes covers the output formats missing from, or ambiguous in, the
+// system MIME tables
+var contentTypes = map[string]string{
+	".svg":   "image/svg+xml",
+	".png":   "image/png",
+	".gif":   "image/gif",
+	".json":  "application/json",
+	".jsonl": "application/x-ndjson",
+	".csv":   "text/csv; charset=utf-8",
+	".ics":   "text/calendar; charset=utf-8",
+	".md":    "text/markdown; charset=utf-8",
+	".html":  "text/html; charset=utf-8",
+	".txt":   "text/plain; charset=utf-8",
+}
+
+// ContentType returns the MIME type for key's extension
+func ContentType(key string) string {
+	ext := strings.ToLower(path.Ext(key))
+	if t, ok := contentTypes[ext]; ok {
+		return t
+	}
+	if t := mime.TypeByExtension(ext); t != "" {
+		return t
+	}
+	return "application/octet-stream"
+}
+
+// checkResponse turns a non-2xx upload response into an error
+func checkResponse(service string, resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+		return nil
+	}
+	return fmt.Errorf("%s upload returned %s", service, resp.Status)
+}
@@ -0,0 +1,150 @@
+package publish
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// s3Credentials are read from the standard AWS environment variables
+type s3Credentials struct {
+	AccessKey    string
+	SecretKey    string
+	SessionToken string
+	Region       string
+	Endpoint     string // custom endpoint for S3-compatible services, addressed path-style
+}
+
+func s3CredentialsFromEnv() (s3Credentials, error) {
+	creds := s3Credentials{
+		AccessKey:    os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretKey:    os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+		Region:       firstEnv("AWS_REGION", "AWS_DEFAULT_REGION"),
+		Endpoint:     strings.TrimSuffix(firstEnv("AWS_ENDPOINT_URL_S3", "AWS_ENDPOINT_URL"), "/"),
+	}
+	if creds.AccessKey == "" || creds.SecretKey == "" {
+		return creds, errors.New("s3 upload needs AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")
+	}
+	if creds.Region == "" {
+		creds.Region = "us-east-1"
+	}
+	return creds, nil
+}
+
+// uploadS3 PUTs obj to bucket/key, signed with AWS Signature Version 4
+func uploadS3(ctx context.Context, bucket, key string, obj Object) error {
+	creds, err := s3CredentialsFromEnv()
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", bucket, creds.Region, s3Escape(key))
+	if creds.Endpoint != "" {
+		url = fmt.Sprintf("%s/%s/%s", creds.Endpoint, bucket, s3Escape(key))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, bytes.NewReader(obj.Body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", obj.ContentType)
+	req.Header.Set("Cache-Control", obj.CacheControl)
+	signS3(req, obj.Body, creds, time.Now().UTC())
+
+	resp, err := HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return checkResponse("s3", resp)
+}
+
+// signS3 adds the SigV4 Authorization header for an S3 request with body
+func signS3(req *http.Request, body []byte, creds s3Credentials, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + creds.Region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	signingKey := []byte("AWS4" + creds.SecretKey)
+	for _, part := range []string{date, creds.Region, "s3", "aws4_request"} {
+		signingKey = hmacSHA256(signingKey, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		creds.AccessKey, scope, signedHeaders, signature))
+}
+
+// s3Escape percent-encodes key as SigV4 expects, keeping its slashes
+func s3Escape(key string) string {
+	var b strings.Builder
+	for _, c := range []byte(key) {
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/':
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// firstEnv returns the first of the named environment variables that is set
+func firstEnv(names ...string) string {
+	for _, name := range names {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,61 @@
+package render
+
+import (
+	"math"
+	"strconv"
+)
+
+// axisSteps is about how many steps an axis is divided into
+const axisSteps = 4
+
+// axis maps counts from 0 to its last tick onto a length, in whatever unit
+// the chart draws in: pixels, points or terminal columns
+type axis struct {
+	Ticks  []int // from 0 up to at least the peak, evenly spaced
+	Length float64
+}
+
+// newAxis scales 0 to at least peak onto length, with ticks at 1, 2 or 5
+// times a power of ten
+func newAxis(peak int, length float64) axis {
+	peak = max(peak, 1)
+	step := 1
+	for magnitude := 1; ; magnitude *= 10 {
+		if step = magnitude; peak <= step*axisSteps {
+			break
+		}
+		if step = 2 * magnitude; peak <= step*axisSteps {
+			break
+		}
+		if step = 5 * magnitude; peak <= step*axisSteps {
+			break
+		}
+	}
+	ticks := []int{0}
+	for ticks[len(ticks)-1] < peak {
+		ticks = append(ticks, ticks[len(ticks)-1]+step)
+	}
+	return axis{Ticks: ticks, Length: length}
+}
+
+// pos is how far along the axis count lies
+func (a axis) pos(count int) float64 {
+	return a.Length * float64(count) / float64(a.Ticks[len(a.Ticks)-1])
+}
+
+// round is pos rounded to whole units, for pixel- and cell-based charts
+func (a axis) round(count int) int {
+	return int(math.Round(a.pos(count)))
+}
+
+// formatCount writes n with thousands separators, e.g. 1,234
+func formatCount(n int) string {
+	s := strconv.Itoa(n)
+	if n < 0 {
+		return "-" + formatCount(-n)
+	}
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
@@ -0,0 +1,159 @@
+package render
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+// BitmapOptions controls 1-bit output for e-paper displays
+type BitmapOptions struct {
+	Width, Height int          // exact size of the display in pixels
+	Theme         Theme        // greys are taken from its colors; defaults to GitHubTheme
+	Threshold     int          // 0 dithers; otherwise pixels darker than this (1-255) are black
+	Caption       bool         // draw the username and total above the calendar when there is room
+	WeekStart     time.Weekday // weekday of the first row, Sunday by default
+}
+
+// DefaultBitmapOptions fit a common 7.5 inch 800x480 panel, dithered
+var DefaultBitmapOptions = BitmapOptions{Width: 800, Height: 480, Theme: GitHubTheme, Caption: true}
+
+const bitmapMargin = 4
+
+// Bitmap draws graph at exactly the requested size in black and white, the
+// cells sized to fill the display rather than scaled so nothing is clipped
+func Bitmap(graph *gitgraph.ContributionGraph, opts BitmapOptions) *image.Paletted {
+	if opts.Width < 1 || opts.Height < 1 {
+		opts.Width, opts.Height = DefaultBitmapOptions.Width, DefaultBitmapOptions.Height
+	}
+	theme := opts.Theme.orDefault(GitHubTheme)
+	grey := image.NewGray(image.Rect(0, 0, opts.Width, opts.Height))
+	background := color.Color(color.White)
+	if theme.Background != "" {
+		background = mustParseHex(theme.Background)
+	}
+	draw.Draw(grey, grey.Bounds(), &image.Uniform{background}, image.Point{}, draw.Src)
+
+	grid := LayoutWeeks(graph.Days, opts.WeekStart)
+	weeks := max(grid.Weeks, 1)
+	top := bitmapMargin
+	caption := graph.Username + " - " + strconv.Itoa(graph.TotalContribs) + " contributions"
+	step := min((opts.Width-2*bitmapMargin)/weeks, (opts.Height-2*bitmapMargin)/7)
+	// The 7x13 font needs its own line, which only pays off if cells stay visible
+	if opts.Caption {
+		if s := min((opts.Width-2*bitmapMargin)/weeks, (opts.Height-2*bitmapMargin-pngCaption)/7); s >= 2 {
+			step = s
+			top += pngCaption
+		} else {
+			opts.Caption = false
+		}
+	}
+	step = max(step, 1)
+	cell := step
+	if step >= 3 {
+		cell = step - max(1, step/6)
+	}
+
+	// Center the calendar in the space left below the caption
+	left := (opts.Width - weeks*step + (step - cell)) / 2
+	top += (opts.Height - top - bitmapMargin - 7*step + (step - cell)) / 2
+	if opts.Caption {
+		drawText(grey, left, top-pngCaption+11, caption, color.Black)
+	}
+	colors := paletteRGBA(theme.Levels)
+	for _, c := range grid.Cells {
+		fill := colors[0]
+		if c.Day.Level >= 0 && c.Day.Level < len(colors) {
+			fill = colors[c.Day.Level]
+		}
+		x, y := left+c.Col*step, top+c.Row*step
+		draw.Draw(grey, image.Rect(x, y, x+cell, y+cell), &image.Uniform{fill}, image.Point{}, draw.Src)
+	}
+
+	out := image.NewPaletted(grey.Bounds(), color.Palette{color.Black, color.White})
+	if opts.Threshold <= 0 {
+		draw.FloydSteinberg.Draw(out, out.Bounds(), grey, image.Point{})
+		return out
+	}
+	for i, v := range grey.Pix {
+		if int(v) >= opts.Threshold {
+			out.Pix[i] = 1
+		}
+	}
+	return out
+}
+
+// PBM writes graph as a binary (P4) portable bitmap
+func PBM(w io.Writer, graph *gitgraph.ContributionGraph, opts BitmapOptions) error {
+	img := Bitmap(graph, opts)
+	size := img.Bounds().Size()
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "P4\n%d %d\n", size.X, size.Y)
+	// PBM rows are packed most significant bit first, 1 for black
+	bw.Write(packRows(img, 0, 0))
+	return bw.Flush()
+}
+
+// BMP writes graph as a 1-bit Windows bitmap, the format most e-paper
+// driver boards and microcontroller libraries load directly
+func BMP(w io.Writer, graph *gitgraph.ContributionGraph, opts BitmapOptions) error {
+	img := Bitmap(graph, opts)
+	size := img.Bounds().Size()
+	// Rows are padded to 4 bytes and stored bottom-up; index 1 of the palette is white
+	stride := (size.X + 31) / 32 * 4
+	rows := packRows(img, 1, 4)
+	pixels := make([]byte, 0, len(rows))
+	for y := size.Y - 1; y >= 0; y-- {
+		pixels = append(pixels, rows[y*stride:(y+1)*stride]...)
+	}
+
+	const headerSize = 14 + 40 + 8
+	header := make([]byte, headerSize)
+	copy(header, "BM")
+	binary.LittleEndian.PutUint32(header[2:], uint32(headerSize+len(pixels)))
+	binary.LittleEndian.PutUint32(header[10:], headerSize)
+	binary.LittleEndian.PutUint32(header[14:], 40)
+	binary.LittleEndian.PutUint32(header[18:], uint32(size.X))
+	binary.LittleEndian.PutUint32(header[22:], uint32(size.Y))
+	binary.LittleEndian.PutUint16(header[26:], 1) // planes
+	binary.LittleEndian.PutUint16(header[28:], 1) // bits per pixel
+	binary.LittleEndian.PutUint32(header[34:], uint32(len(pixels)))
+	binary.LittleEndian.PutUint32(header[38:], 2835) // 72 DPI
+	binary.LittleEndian.PutUint32(header[42:], 2835)
+	binary.LittleEndian.PutUint32(header[46:], 2) // palette entries
+	// Palette, as blue, green, red, reserved: black then white
+	copy(header[58:], []byte{0xff, 0xff, 0xff, 0})
+
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	_, err := w.Write(pixels)
+	return err
+}
+
+// packRows packs img one bit per pixel, setting bits for pixels of palette
+// index set and padding each row to a multiple of align bytes (0 for none)
+func packRows(img *image.Paletted, set uint8, align int) []byte {
+	size := img.Bounds().Size()
+	stride := (size.X + 7) / 8
+	if align > 0 {
+		stride = (stride + align - 1) / align * align
+	}
+	out := make([]byte, stride*size.Y)
+	for y := 0; y < size.Y; y++ {
+		for x := 0; x < size.X; x++ {
+			if img.Pix[y*img.Stride+x] == set {
+				out[y*stride+x/8] |= 0x80 >> (x % 8)
+			}
+		}
+	}
+	return out
+}
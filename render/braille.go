@@ -0,0 +1,100 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+	"github.com/JyotinderSingh/gitgraphed/locale"
+)
+
+// BrailleOptions controls the Braille chart
+type BrailleOptions struct {
+	Height int            // rows of characters, four dots each; defaults to DefaultBrailleHeight
+	Locale *locale.Locale // month names, defaults to locale.English
+}
+
+// DefaultBrailleHeight gives counts 16 steps of resolution
+const DefaultBrailleHeight = 4
+
+// brailleDots are the bits of the dots in a Braille cell by [column][row from top]
+var brailleDots = [2][4]rune{
+	{0x01, 0x02, 0x04, 0x40},
+	{0x08, 0x10, 0x20, 0x80},
+}
+
+// Braille writes daily counts as a bar chart of Braille dots, two days per
+// character, readable in terminals without color
+func Braille(w io.Writer, graph *gitgraph.ContributionGraph, opts BrailleOptions) error {
+	height := opts.Height
+	if height < 1 {
+		height = DefaultBrailleHeight
+	}
+	grid := Layout(graph.Days)
+	if len(grid.Cells) == 0 {
+		_, err := fmt.Fprintf(w, "%d contributions\n", graph.TotalContribs)
+		return err
+	}
+
+	first := grid.Cells[0].Date
+	index := func(cell Cell) int { return int(cell.Date.Sub(first).Hours() / 24) }
+	days := index(grid.Cells[len(grid.Cells)-1]) + 1
+	max := 0
+	for _, cell := range grid.Cells {
+		if cell.Day.Count > max {
+			max = cell.Day.Count
+		}
+	}
+
+	width := (days + 1) / 2
+	rows := make([][]rune, height)
+	for i := range rows {
+		rows[i] = []rune(strings.Repeat("⠀", width))
+	}
+	dots := height * 4
+	for _, cell := range grid.Cells {
+		if cell.Day.Count == 0 {
+			continue
+		}
+		// Round up so any contribution shows at least one dot
+		bar := (cell.Day.Count*dots + max - 1) / max
+		i := index(cell)
+		for d := 0; d < bar; d++ {
+			rows[height-1-d/4][i/2] |= brailleDots[i%2][3-d%4]
+		}
+	}
+
+	axis := len(fmt.Sprint(max))
+	var b strings.Builder
+	for i, row := range rows {
+		label := ""
+		switch i {
+		case 0:
+			label = fmt.Sprint(max)
+		case height - 1:
+			label = "0"
+		}
+		fmt.Fprintf(&b, "%*s %s\n", axis, label, string(row))
+	}
+
+	// Month names go under the character column holding their first day
+	line := []rune(strings.Repeat(" ", axis+1+width+3))
+	last := -4
+	for _, cell := range grid.Cells {
+		if cell.Date.Day() != 1 && cell.Date != first {
+			continue
+		}
+		col := index(cell) / 2
+		if col-last < 4 {
+			continue
+		}
+		copy(line[axis+1+col:], []rune(opts.Locale.Month(cell.Date.Month())))
+		last = col
+	}
+	b.WriteString(strings.TrimRight(string(line), " ") + "\n")
+	fmt.Fprintf(&b, "%s%d contributions\n", strings.Repeat(" ", axis+1), graph.TotalContribs)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,65 @@
+package render
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// parseHex converts "#rrggbb" into a color.RGBA
+func parseHex(hex string) (color.RGBA, error) {
+	hex = strings.TrimPrefix(hex, "#")
+	if len(hex) != 6 {
+		return color.RGBA{}, fmt.Errorf("invalid hex color: %q", hex)
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("invalid hex color: %q", hex)
+	}
+	return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xff}, nil
+}
+
+// mustParseHex is parseHex for built-in palettes that are known to be valid
+func mustParseHex(hex string) color.RGBA {
+	c, err := parseHex(hex)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// xterm256 returns the closest xterm 256-color palette index (from the 6x6x6 cube or grey ramp)
+func xterm256(c color.RGBA) int {
+	cube := func(v uint8) int {
+		if v < 48 {
+			return 0
+		}
+		if v < 115 {
+			return 1
+		}
+		return (int(v) - 35) / 40
+	}
+	levels := []int{0, 95, 135, 175, 215, 255}
+	r, g, b := cube(c.R), cube(c.G), cube(c.B)
+	cubeIdx := 16 + 36*r + 6*g + b
+	cubeDist := sqDist(c, levels[r], levels[g], levels[b])
+
+	avg := (int(c.R) + int(c.G) + int(c.B)) / 3
+	grey := (avg - 3) / 10
+	if grey < 0 {
+		grey = 0
+	} else if grey > 23 {
+		grey = 23
+	}
+	greyLevel := 8 + grey*10
+	if sqDist(c, greyLevel, greyLevel, greyLevel) < cubeDist {
+		return 232 + grey
+	}
+	return cubeIdx
+}
+
+func sqDist(c color.RGBA, r, g, b int) int {
+	dr, dg, db := int(c.R)-r, int(c.G)-g, int(c.B)-b
+	return dr*dr + dg*dg + db*db
+}
@@ -0,0 +1,93 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+// ComparePanel is one calendar of a comparison, under its caption
+type ComparePanel struct {
+	Graph   *gitgraph.ContributionGraph
+	Caption string // e.g. "alice: 1,234 contributions in 2024"
+}
+
+// CompareOptions controls CompareSVG; Trend is not drawn
+type CompareOptions struct {
+	SVGOptions
+	SideBySide bool // place the calendars in a row instead of stacking them
+}
+
+const (
+	svgCaptionHeight = 20
+	svgPanelGap      = 24 // between side-by-side calendars
+)
+
+// CompareSVG writes several calendars, e.g. of a team or of one user's
+// years, in one SVG with a caption each and a legend they share
+func CompareSVG(w io.Writer, panels []ComparePanel, opts CompareOptions) error {
+	if len(panels) == 0 {
+		return errors.New("nothing to compare")
+	}
+	theme := opts.Theme.orDefault(GitHubTheme)
+	step := opts.CellSize + opts.Gap
+	panelHeight := svgCaptionHeight + svgLabelHeight + 7*step
+
+	grids := make([]Grid, len(panels))
+	width, height := 0, 0
+	for i, panel := range panels {
+		grids[i] = LayoutWeeks(panel.Graph.Days, opts.WeekStart)
+		panelWidth := svgLabelWidth + grids[i].Weeks*step
+		if opts.SideBySide {
+			if i > 0 {
+				width += svgPanelGap
+			}
+			width += panelWidth
+			height = panelHeight
+		} else {
+			width = max(width, panelWidth)
+			height += panelHeight
+		}
+	}
+	height += svgLegendSpace
+
+	var b strings.Builder
+	captions := make([]string, len(panels))
+	for i, panel := range panels {
+		captions[i] = panel.Caption
+	}
+	label := html.EscapeString("Contributions compared: " + strings.Join(captions, "; "))
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" role="img" aria-label="%s">`+"\n",
+		width, height, width, height, label)
+	fmt.Fprintf(&b, "<title>%s</title>\n", label)
+	fmt.Fprintf(&b, `<style>text{font:9px -apple-system,BlinkMacSystemFont,"Segoe UI",Helvetica,Arial,sans-serif;fill:%s}.caption{font-size:12px;font-weight:600}</style>`+"\n", theme.Text)
+	if theme.Background != "" {
+		fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", theme.Background)
+	}
+	fill := theme.color
+	if opts.Patterns {
+		writePatterns(&b, theme)
+		fill = patternFill(theme)
+	}
+
+	x, y := 0, 0
+	for i, panel := range panels {
+		fmt.Fprintf(&b, `<text class="caption" x="%d" y="%d">%s</text>`+"\n", x+svgLabelWidth, y+svgCaptionHeight-7, html.EscapeString(panel.Caption))
+		writeCalendar(&b, grids[i], opts.SVGOptions, fill, x, y+svgCaptionHeight)
+		if opts.SideBySide {
+			x += svgLabelWidth + grids[i].Weeks*step + svgPanelGap
+		} else {
+			y += panelHeight
+		}
+	}
+
+	writeLegend(&b, theme, opts.SVGOptions, fill, width, height-svgLegendSpace+5)
+	b.WriteString("</svg>\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,79 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+// EmojiOptions controls emoji heatmap output
+type EmojiOptions struct {
+	Weeks     int          // most recent weeks to show; 0 shows them all
+	Dark      bool         // draw empty days as ⬛, for chats in a dark theme
+	WeekStart time.Weekday // weekday of the first row, Sunday by default
+}
+
+// emojiPad fills the rows before the first day and after today. An ideographic
+// space is about as wide as an emoji and, unlike a plain space, isn't collapsed
+// by Mastodon and other HTML renderings.
+const emojiPad = "　"
+
+// Emoji writes the calendar as plain rows of emoji blocks, one per weekday,
+// under a caption, for pasting into Slack, Discord or Mastodon where neither
+// ANSI colors nor images show
+func Emoji(w io.Writer, graph *gitgraph.ContributionGraph, opts EmojiOptions) error {
+	levels := emojiLevels
+	if opts.Dark {
+		levels = append([]string{"⬛"}, emojiLevels[1:]...)
+	}
+
+	// Days after today, as in the rest of this year, are left out
+	today := time.Now()
+	grid := LayoutWeeks(graph.Days, opts.WeekStart)
+	var cells []Cell
+	weeks, total := 0, 0
+	for _, cell := range grid.Cells {
+		if cell.Date.After(today) {
+			break
+		}
+		cells = append(cells, cell)
+		weeks = cell.Col + 1
+	}
+	first := 0
+	if opts.Weeks > 0 && opts.Weeks < weeks {
+		first = weeks - opts.Weeks
+	}
+
+	rows := make([][]string, 7)
+	for i := range rows {
+		rows[i] = make([]string, weeks-first)
+		for j := range rows[i] {
+			rows[i][j] = emojiPad
+		}
+	}
+	for _, cell := range cells {
+		if cell.Col < first {
+			continue
+		}
+		level := min(max(cell.Day.Level, 0), len(levels)-1)
+		rows[cell.Row][cell.Col-first] = levels[level]
+		total += cell.Day.Count
+	}
+
+	var b strings.Builder
+	noun := "contributions"
+	if total == 1 {
+		noun = "contribution"
+	}
+	fmt.Fprintf(&b, "%s: %d %s\n", graph.Username, total, noun)
+	for _, row := range rows {
+		b.WriteString(strings.TrimRight(strings.Join(row, ""), emojiPad))
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "Less %s More\n", strings.Join(levels, ""))
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,81 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"io"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+// GIFOptions controls animated output
+type GIFOptions struct {
+	PNG        PNGOptions    // raster settings shared with PNG output
+	FrameDelay time.Duration // time each week is shown
+	Hold       time.Duration // time the final, complete frame is shown
+}
+
+// DefaultGIFOptions shows a week every 100ms and holds the full year for 3s
+var DefaultGIFOptions = GIFOptions{PNG: DefaultPNGOptions, FrameDelay: 100 * time.Millisecond, Hold: 3 * time.Second}
+
+// GIF writes an animation of the calendar filling in week by week, drawn by the
+// same raster renderer as PNG so both share a layout
+func GIF(w io.Writer, graph *gitgraph.ContributionGraph, opts GIFOptions) error {
+	grid := LayoutWeeks(graph.Days, opts.PNG.WeekStart)
+	palette := gifPalette(opts.PNG.Theme.orDefault(GitHubTheme))
+
+	anim := &gif.GIF{}
+	for week := 0; week < grid.Weeks; week++ {
+		frame := RasterImage(graphThroughWeek(graph, grid, week), opts.PNG)
+		paletted := image.NewPaletted(frame.Bounds(), palette)
+		draw.Draw(paletted, frame.Bounds(), frame, image.Point{}, draw.Src)
+
+		delay := opts.FrameDelay
+		if week == grid.Weeks-1 {
+			delay = opts.Hold
+		}
+		anim.Image = append(anim.Image, paletted)
+		anim.Delay = append(anim.Delay, int(delay/(10*time.Millisecond)))
+	}
+	return gif.EncodeAll(w, anim)
+}
+
+// graphThroughWeek copies graph with every day after column week emptied, so the
+// layout stays that of the full graph while the caption shows the running total
+func graphThroughWeek(graph *gitgraph.ContributionGraph, grid Grid, week int) *gitgraph.ContributionGraph {
+	visible := make(map[string]bool, len(grid.Cells))
+	for _, cell := range grid.Cells {
+		if cell.Col <= week {
+			visible[cell.Day.Date] = true
+		}
+	}
+
+	frame := *graph
+	frame.TotalContribs = 0
+	frame.Days = make([]gitgraph.ContributionDay, len(graph.Days))
+	for i, day := range graph.Days {
+		if !visible[day.Date] {
+			day.Count, day.Level = 0, 0
+		}
+		frame.Days[i] = day
+		frame.TotalContribs += day.Count
+	}
+	return &frame
+}
+
+// gifPalette holds exactly the colors RasterImage draws with; the bitmap font
+// isn't antialiased, so no other colors occur
+func gifPalette(theme Theme) color.Palette {
+	palette := color.Palette{color.RGBA{0xff, 0xff, 0xff, 0xff}}
+	if theme.Background != "" {
+		palette[0] = mustParseHex(theme.Background)
+	}
+	palette = append(palette, mustParseHex(theme.Text))
+	for _, c := range paletteRGBA(theme.Levels) {
+		palette = append(palette, c)
+	}
+	return palette
+}
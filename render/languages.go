@@ -0,0 +1,80 @@
+package render
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+const (
+	languageBarWidth  = 400
+	languageBarHeight = 10
+	languageRowHeight = 16
+	languageColumns   = 3
+	// languageLimit is how many languages are named before the rest are
+	// grouped as Other
+	languageLimit = 8
+)
+
+// languageOtherColor fills languages without a color of their own
+const languageOtherColor = "#8b949e"
+
+// LanguagesSVG draws shares as one stacked bar with a legend of each
+// language's percentage, as on GitHub's repository pages
+func LanguagesSVG(w io.Writer, shares []gitgraph.LanguageShare, opts SVGOptions) error {
+	theme := opts.Theme.orDefault(GitHubTheme)
+	if len(shares) > languageLimit {
+		other := gitgraph.LanguageShare{Language: "Other"}
+		for _, share := range shares[languageLimit-1:] {
+			other.Commits += share.Commits
+			other.Percent += share.Percent
+		}
+		shares = append(shares[:languageLimit-1:languageLimit-1], other)
+	}
+	rows := (len(shares) + languageColumns - 1) / languageColumns
+	height := languageBarHeight + 8 + rows*languageRowHeight
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		languageBarWidth, height, languageBarWidth, height)
+	fmt.Fprintf(&b, `<style>text{font:9px -apple-system,BlinkMacSystemFont,"Segoe UI",Helvetica,Arial,sans-serif;fill:%s}</style>`+"\n", theme.Text)
+	if theme.Background != "" {
+		fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", theme.Background)
+	}
+	if len(shares) == 0 {
+		fmt.Fprintf(&b, `<rect width="%d" height="%d" rx="%d" fill="%s"/>`+"\n", languageBarWidth, languageBarHeight, languageBarHeight/2, theme.color(0))
+	}
+
+	b.WriteString(`<clipPath id="bar">`)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" rx="%d"/>`, languageBarWidth, languageBarHeight, languageBarHeight/2)
+	b.WriteString("</clipPath>\n<g clip-path=\"url(#bar)\">\n")
+	x := 0.0
+	for _, share := range shares {
+		width := share.Percent / 100 * languageBarWidth
+		fmt.Fprintf(&b, `<rect x="%.1f" width="%.1f" height="%d" fill="%s"/>`+"\n", x, width, languageBarHeight, languageColor(share))
+		x += width
+	}
+	b.WriteString("</g>\n")
+
+	columnWidth := languageBarWidth / languageColumns
+	for i, share := range shares {
+		cx := i % languageColumns * columnWidth
+		cy := languageBarHeight + 8 + i/languageColumns*languageRowHeight
+		fmt.Fprintf(&b, `<circle cx="%d" cy="%d" r="4" fill="%s"/>`+"\n", cx+4, cy+4, languageColor(share))
+		fmt.Fprintf(&b, `<text x="%d" y="%d">%s %.1f%%</text>`+"\n", cx+12, cy+7, html.EscapeString(share.Language), share.Percent)
+	}
+	b.WriteString("</svg>\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func languageColor(share gitgraph.LanguageShare) string {
+	if share.Color == "" {
+		return languageOtherColor
+	}
+	return share.Color
+}
@@ -0,0 +1,93 @@
+// Package render draws contribution graphs as images and terminal output.
+package render
+
+import (
+	"sort"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+// Cell is a single day positioned on the calendar grid
+type Cell struct {
+	Day  gitgraph.ContributionDay
+	Date time.Time
+	Col  int // week column, starting at 0
+	Row  int // weekday row, 0 = the grid's WeekStart
+}
+
+// MonthLabel marks the column where a month starts
+type MonthLabel struct {
+	Month time.Month
+	Col   int
+}
+
+// Grid lays days out in week columns like GitHub's calendar
+type Grid struct {
+	Cells     []Cell
+	Weeks     int
+	Months    []MonthLabel
+	WeekStart time.Weekday // weekday of row 0
+}
+
+// Weekday returns the day of the week shown in row
+func (g Grid) Weekday(row int) time.Weekday {
+	return (g.WeekStart + time.Weekday(row)) % 7
+}
+
+// labelRows are the rows named beside the calendar: Monday, Wednesday and
+// Friday, as on GitHub, wherever the week start puts them
+func (g Grid) labelRows() []int {
+	var rows []int
+	for row := 0; row < 7; row++ {
+		switch g.Weekday(row) {
+		case time.Monday, time.Wednesday, time.Friday:
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// Layout arranges days into a Grid, one column per Sunday-started week
+func Layout(days []gitgraph.ContributionDay) Grid {
+	return LayoutWeeks(days, time.Sunday)
+}
+
+// LayoutWeeks arranges days into a Grid, one column per week beginning on start
+func LayoutWeeks(days []gitgraph.ContributionDay, start time.Weekday) Grid {
+	cells := make([]Cell, 0, len(days))
+	for _, day := range days {
+		date, err := time.Parse("2006-01-02", day.Date)
+		if err != nil {
+			continue
+		}
+		cells = append(cells, Cell{Day: day, Date: date})
+	}
+	sort.Slice(cells, func(i, j int) bool { return cells[i].Date.Before(cells[j].Date) })
+
+	grid := Grid{Cells: cells, WeekStart: start}
+	if len(cells) == 0 {
+		return grid
+	}
+
+	row := func(date time.Time) int { return (int(date.Weekday()) - int(start) + 7) % 7 }
+	first := cells[0].Date.AddDate(0, 0, -row(cells[0].Date))
+	for i := range cells {
+		cells[i].Col = int(cells[i].Date.Sub(first).Hours()/24) / 7
+		cells[i].Row = row(cells[i].Date)
+		if cells[i].Col+1 > grid.Weeks {
+			grid.Weeks = cells[i].Col + 1
+		}
+
+		// Label a month at its first day shown, skipping labels that would overlap the previous one
+		if i == 0 || cells[i].Date.Month() != cells[i-1].Date.Month() {
+			label := MonthLabel{Month: cells[i].Date.Month(), Col: cells[i].Col}
+			if n := len(grid.Months); n > 0 && label.Col-grid.Months[n-1].Col < 3 {
+				grid.Months[n-1] = label
+				continue
+			}
+			grid.Months = append(grid.Months, label)
+		}
+	}
+	return grid
+}
@@ -0,0 +1,137 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+	"github.com/JyotinderSingh/gitgraphed/locale"
+)
+
+// MarkdownOptions controls Markdown summary output
+type MarkdownOptions struct {
+	Heatmap   bool           // append an emoji-block heatmap of the calendar
+	Locale    *locale.Locale // month names and dates, defaults to locale.English
+	WeekStart time.Weekday   // weekday of the heatmap's first row, Sunday by default
+}
+
+// emojiLevels are the heatmap blocks for levels 0-4
+var emojiLevels = []string{"⬜", "🟨", "🟧", "🟥", "🟪"}
+
+// Markdown writes a summary of graph suitable for READMEs and reports: totals,
+// streaks and a month-by-month table, optionally followed by an emoji heatmap
+func Markdown(w io.Writer, graph *gitgraph.ContributionGraph, opts MarkdownOptions) error {
+	streaks := gitgraph.ComputeStreaks(graph.Days, time.Now())
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "## %s's contributions%s\n\n", graph.Username, markdownPeriod(graph.Days, opts.Locale))
+	fmt.Fprintf(&b, "- **Total:** %d contributions\n", graph.TotalContribs)
+	fmt.Fprintf(&b, "- **Current streak:** %s\n", markdownStreak(streaks.Current))
+	fmt.Fprintf(&b, "- **Longest streak:** %s\n", markdownStreak(streaks.Longest))
+	fmt.Fprintf(&b, "- **Busiest weekday:** %s\n", gitgraph.BusiestWeekday(graph.Days))
+
+	b.WriteString("\n| Month | Contributions | Active days | Best day |\n")
+	b.WriteString("| --- | ---: | ---: | --- |\n")
+	for _, month := range monthSummaries(graph.Days, opts.Locale) {
+		best := "-"
+		if month.Best.Count > 0 {
+			best = fmt.Sprintf("%s (%d)", month.Best.Date, month.Best.Count)
+		}
+		fmt.Fprintf(&b, "| %s | %d | %d | %s |\n", month.Name, month.Total, month.Active, best)
+	}
+
+	if opts.Heatmap {
+		b.WriteString("\n")
+		b.WriteString(emojiHeatmap(graph.Days, opts.WeekStart))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// monthSummary aggregates the days of one calendar month
+type monthSummary struct {
+	Name   string
+	Total  int
+	Active int
+	Best   gitgraph.ContributionDay
+}
+
+// monthSummaries groups days by month in date order
+func monthSummaries(days []gitgraph.ContributionDay, loc *locale.Locale) []monthSummary {
+	var months []monthSummary
+	for _, cell := range Layout(days).Cells {
+		name := loc.FormatYearMonth(cell.Date)
+		if len(months) == 0 || months[len(months)-1].Name != name {
+			months = append(months, monthSummary{Name: name})
+		}
+		month := &months[len(months)-1]
+		month.Total += cell.Day.Count
+		if cell.Day.Count > 0 {
+			month.Active++
+		}
+		if cell.Day.Count > month.Best.Count {
+			month.Best = cell.Day
+		}
+	}
+	return months
+}
+
+// emojiHeatmap draws the calendar as rows of emoji blocks inside a code fence,
+// which keeps the rows from being joined into one paragraph
+func emojiHeatmap(days []gitgraph.ContributionDay, start time.Weekday) string {
+	grid := LayoutWeeks(days, start)
+	rows := make([][]string, 7)
+	for i := range rows {
+		rows[i] = make([]string, grid.Weeks)
+		for j := range rows[i] {
+			rows[i][j] = "  "
+		}
+	}
+	for _, cell := range grid.Cells {
+		rows[cell.Row][cell.Col] = levelEmoji(cell.Day.Level)
+	}
+
+	var b strings.Builder
+	b.WriteString("```\n")
+	for _, row := range rows {
+		b.WriteString(strings.TrimRight(strings.Join(row, ""), " "))
+		b.WriteString("\n")
+	}
+	b.WriteString("```\n\n")
+	fmt.Fprintf(&b, "Less %s More\n", strings.Join(emojiLevels, ""))
+	return b.String()
+}
+
+func levelEmoji(level int) string {
+	if level < 0 {
+		level = 0
+	}
+	if level >= len(emojiLevels) {
+		level = len(emojiLevels) - 1
+	}
+	return emojiLevels[level]
+}
+
+// markdownPeriod describes the span covered by days, e.g. " (Jan 1, 2024 - Dec 31, 2024)"
+func markdownPeriod(days []gitgraph.ContributionDay, loc *locale.Locale) string {
+	cells := Layout(days).Cells
+	if len(cells) == 0 {
+		return ""
+	}
+	first, last := cells[0].Date, cells[len(cells)-1].Date
+	return fmt.Sprintf(" (%s - %s)", loc.FormatDate(first), loc.FormatDate(last))
+}
+
+func markdownStreak(s gitgraph.Streak) string {
+	switch s.Length {
+	case 0:
+		return "0 days"
+	case 1:
+		return fmt.Sprintf("1 day (%s)", s.Start)
+	default:
+		return fmt.Sprintf("%d days (%s to %s)", s.Length, s.Start, s.End)
+	}
+}
@@ -0,0 +1,285 @@
+package render
+
+import (
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+	"github.com/JyotinderSingh/gitgraphed/locale"
+)
+
+// MonthOptions controls the bar chart of contributions per month
+type MonthOptions struct {
+	Theme   Theme          // defaults to GitHubTheme
+	Locale  *locale.Locale // month names, defaults to locale.English
+	Caption bool           // write the username and total above the chart
+	Scale   int            // PNG pixel multiplier
+}
+
+// DefaultMonthOptions draw PNGs at 2x in the GitHub theme with a caption
+var DefaultMonthOptions = MonthOptions{Theme: GitHubTheme, Caption: true, Scale: 2}
+
+// Unscaled bar chart geometry, in pixels
+const (
+	monthMargin      = 16
+	monthCaption     = 22
+	monthAxisWidth   = 40 // tick labels left of the bars
+	monthSlot        = 40 // per month, the bar centered in it
+	monthBarWidth    = 26
+	monthChartHeight = 140
+	monthValueSpace  = 14 // above the tallest bar for its count
+	monthLabelSpace  = 30 // below the bars for month names and years
+)
+
+// monthTotal is the contributions of one calendar month
+type monthTotal struct {
+	Month time.Time
+	Total int
+}
+
+// monthTotals sums days by calendar month, in order; days are assumed to be
+// in date order
+func monthTotals(days []gitgraph.ContributionDay) []monthTotal {
+	var months []monthTotal
+	for _, day := range days {
+		date, err := time.Parse("2006-01-02", day.Date)
+		if err != nil {
+			continue
+		}
+		month := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+		if n := len(months); n == 0 || !months[n-1].Month.Equal(month) {
+			months = append(months, monthTotal{Month: month})
+		}
+		months[len(months)-1].Total += day.Count
+	}
+	return months
+}
+
+// monthBar is one month's bar with the labels around it
+type monthBar struct {
+	X, Y, Width, Height int
+	Center              int    // of the bar, where its labels are centered
+	Value               string // count drawn above the bar, empty for none
+	Label               string // month name drawn below
+	Year                string // beneath the label, at the first month and each January
+	Title               string // e.g. "Jan 2024: 42"
+}
+
+// monthTick is a ruled line across the chart at a count
+type monthTick struct {
+	Y     int
+	Label string
+}
+
+// monthChart is the laid out bar chart and the image size
+type monthChart struct {
+	Bars          []monthBar
+	Ticks         []monthTick
+	Left, Right   int // ends of the ruled lines
+	Bottom        int // of the bars
+	Width, Height int
+}
+
+// layoutMonths places a bar per month of graph over a ruled axis. It is
+// shared by the SVG and PNG bar chart renderers.
+func layoutMonths(graph *gitgraph.ContributionGraph, opts MonthOptions) monthChart {
+	months := monthTotals(graph.Days)
+	top := monthMargin + monthValueSpace
+	if opts.Caption {
+		top += monthCaption
+	}
+	chart := monthChart{
+		Left:   monthMargin + monthAxisWidth,
+		Bottom: top + monthChartHeight,
+	}
+	chart.Right = chart.Left + max(len(months), 1)*monthSlot
+	chart.Width = chart.Right + monthMargin
+	chart.Height = chart.Bottom + monthLabelSpace + monthMargin
+
+	peak := 0
+	for _, month := range months {
+		peak = max(peak, month.Total)
+	}
+	scale := newAxis(peak, monthChartHeight)
+	for _, tick := range scale.Ticks {
+		chart.Ticks = append(chart.Ticks, monthTick{Y: chart.Bottom - scale.round(tick), Label: formatCount(tick)})
+	}
+	for i, month := range months {
+		height := scale.round(month.Total)
+		bar := monthBar{
+			X:      chart.Left + i*monthSlot + (monthSlot-monthBarWidth)/2,
+			Y:      chart.Bottom - height,
+			Width:  monthBarWidth,
+			Height: height,
+			Center: chart.Left + i*monthSlot + monthSlot/2,
+			Label:  opts.Locale.Month(month.Month.Month()),
+			Title:  fmt.Sprintf("%s: %d", opts.Locale.FormatYearMonth(month.Month), month.Total),
+		}
+		if month.Total > 0 {
+			bar.Value = formatCount(month.Total)
+		}
+		if i == 0 || month.Month.Month() == time.January {
+			bar.Year = strconv.Itoa(month.Month.Year())
+		}
+		chart.Bars = append(chart.Bars, bar)
+	}
+	return chart
+}
+
+// MonthsSVG writes a bar chart of graph's contributions per month in SVG
+func MonthsSVG(w io.Writer, graph *gitgraph.ContributionGraph, opts MonthOptions) error {
+	theme := opts.Theme.orDefault(GitHubTheme)
+	chart := layoutMonths(graph, opts)
+
+	var b strings.Builder
+	label := html.EscapeString(fmt.Sprintf("%s: %d contributions by month", graph.Username, graph.TotalContribs))
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" role="img" aria-label="%s">`+"\n",
+		chart.Width, chart.Height, chart.Width, chart.Height, label)
+	fmt.Fprintf(&b, "<title>%s</title>\n", label)
+	fmt.Fprintf(&b, `<style>text{font:9px -apple-system,BlinkMacSystemFont,"Segoe UI",Helvetica,Arial,sans-serif;fill:%s}.caption{font-size:12px;font-weight:600}</style>`+"\n", theme.Text)
+	if theme.Background != "" {
+		fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", theme.Background)
+	}
+	if opts.Caption {
+		fmt.Fprintf(&b, `<text class="caption" x="%d" y="%d">%s - %d contributions</text>`+"\n",
+			monthMargin, monthMargin+12, html.EscapeString(graph.Username), graph.TotalContribs)
+	}
+	for _, tick := range chart.Ticks {
+		fmt.Fprintf(&b, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s"/>`+"\n", chart.Left, tick.Y, chart.Right, tick.Y, theme.color(0))
+		fmt.Fprintf(&b, `<text x="%d" y="%d" text-anchor="end">%s</text>`+"\n", chart.Left-4, tick.Y+3, tick.Label)
+	}
+	for _, bar := range chart.Bars {
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"><title>%s</title></rect>`+"\n",
+			bar.X, bar.Y, bar.Width, bar.Height, theme.color(3), html.EscapeString(bar.Title))
+		if bar.Value != "" {
+			fmt.Fprintf(&b, `<text x="%d" y="%d" text-anchor="middle">%s</text>`+"\n", bar.Center, bar.Y-3, bar.Value)
+		}
+		fmt.Fprintf(&b, `<text x="%d" y="%d" text-anchor="middle">%s</text>`+"\n", bar.Center, chart.Bottom+12, html.EscapeString(bar.Label))
+		if bar.Year != "" {
+			fmt.Fprintf(&b, `<text x="%d" y="%d" text-anchor="middle">%s</text>`+"\n", bar.Center, chart.Bottom+24, bar.Year)
+		}
+	}
+	b.WriteString("</svg>\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// MonthsPNG writes a bar chart of graph's contributions per month in PNG
+func MonthsPNG(w io.Writer, graph *gitgraph.ContributionGraph, opts MonthOptions) error {
+	if opts.Scale < 1 {
+		opts.Scale = 1
+	}
+	theme := opts.Theme.orDefault(GitHubTheme)
+	chart := layoutMonths(graph, opts)
+
+	img := image.NewRGBA(image.Rect(0, 0, chart.Width, chart.Height))
+	background := color.RGBA{0xff, 0xff, 0xff, 0xff}
+	if theme.Background != "" {
+		background = mustParseHex(theme.Background)
+	}
+	draw.Draw(img, img.Bounds(), &image.Uniform{background}, image.Point{}, draw.Src)
+	text := mustParseHex(theme.Text)
+	// The bitmap font is 7 pixels a character
+	centered := func(x, y int, s string) {
+		drawText(img, x-len([]rune(asciiFold.Replace(s)))*7/2, y, s, text)
+	}
+
+	if opts.Caption {
+		drawText(img, monthMargin, monthMargin+11, fmt.Sprintf("%s - %d contributions", graph.Username, graph.TotalContribs), text)
+	}
+	rule := &image.Uniform{mustParseHex(theme.color(0))}
+	for _, tick := range chart.Ticks {
+		draw.Draw(img, image.Rect(chart.Left, tick.Y, chart.Right, tick.Y+1), rule, image.Point{}, draw.Src)
+		drawText(img, chart.Left-4-len(tick.Label)*7, tick.Y+4, tick.Label, text)
+	}
+	fill := &image.Uniform{mustParseHex(theme.color(3))}
+	for _, bar := range chart.Bars {
+		draw.Draw(img, image.Rect(bar.X, bar.Y, bar.X+bar.Width, bar.Y+bar.Height), fill, image.Point{}, draw.Src)
+		if bar.Value != "" {
+			centered(bar.Center, bar.Y-3, bar.Value)
+		}
+		centered(bar.Center, chart.Bottom+13, bar.Label)
+		if bar.Year != "" {
+			centered(bar.Center, chart.Bottom+26, bar.Year)
+		}
+	}
+	return png.Encode(w, upscale(img, opts.Scale))
+}
+
+// Terminal bar chart geometry, in columns
+const (
+	termMonthLabel = 9 // e.g. "Jan 2024 "
+	termMonthBars  = 48
+)
+
+// termEighths are the partial blocks ending a bar, an eighth of a column each
+var termEighths = []string{"", "▏", "▎", "▍", "▌", "▋", "▊", "▉"}
+
+// MonthsTerminal writes graph's contributions per month as a horizontal bar
+// each, on an axis along the bottom
+func MonthsTerminal(w io.Writer, graph *gitgraph.ContributionGraph, opts TermOptions) error {
+	theme := opts.Theme.orDefault(GitHubDarkTheme)
+	months := monthTotals(graph.Days)
+	peak := 0
+	for _, month := range months {
+		peak = max(peak, month.Total)
+	}
+	scale := newAxis(peak, termMonthBars)
+
+	var b strings.Builder
+	for i, month := range months {
+		label := opts.Locale.Month(month.Month.Month())
+		if i == 0 || month.Month.Month() == time.January {
+			label += " " + strconv.Itoa(month.Month.Year())
+		}
+		fmt.Fprintf(&b, "%s%s", label, strings.Repeat(" ", max(termMonthLabel-len([]rune(label)), 1)))
+		b.WriteString(termBar(theme, scale.pos(month.Total), opts))
+		fmt.Fprintf(&b, " %s\n", formatCount(month.Total))
+	}
+
+	// Tick labels start at their tick, skipping any that would run into the last
+	line := []rune(strings.Repeat(" ", termMonthLabel+termMonthBars+8))
+	end := 0
+	for _, tick := range scale.Ticks {
+		at := termMonthLabel + scale.round(tick)
+		if at < end {
+			continue
+		}
+		label := []rune(formatCount(tick))
+		copy(line[at:], label)
+		end = at + len(label) + 1
+	}
+	b.WriteString(strings.TrimRight(string(line), " "))
+	fmt.Fprintf(&b, "\n\n%d contributions\n", graph.TotalContribs)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// termBar draws a bar columns long in the theme's level 3 color, in eighths
+// of a column, or in #s without color
+func termBar(theme Theme, columns float64, opts TermOptions) string {
+	if opts.NoColor {
+		return strings.Repeat("#", int(math.Round(columns)))
+	}
+	eighths := int(math.Round(columns * 8))
+	bar := strings.Repeat("█", eighths/8) + termEighths[eighths%8]
+	if bar == "" {
+		return ""
+	}
+	c := mustParseHex(theme.color(3))
+	if opts.TrueColor {
+		return fmt.Sprintf("\x1b[38;2;%d;%d;%dm%s\x1b[0m", c.R, c.G, c.B, bar)
+	}
+	return fmt.Sprintf("\x1b[38;5;%dm%s\x1b[0m", xterm256(c), bar)
+}
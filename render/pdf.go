@@ -0,0 +1,174 @@
+package render
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A4 portrait, in points
+const (
+	pdfPageWidth  = 595.0
+	pdfPageHeight = 842.0
+)
+
+// pdfDocument is a PDF of vector pages drawn in the standard Helvetica
+// fonts, which every reader has, so nothing needs embedding
+type pdfDocument struct {
+	Title string
+	pages []*pdfPage
+}
+
+// pdfPage collects the drawing operators of one page. Its methods take y
+// downwards from the top edge, as the other renderers do, and flip it into
+// PDF's upward coordinates.
+type pdfPage struct {
+	content bytes.Buffer
+}
+
+func (d *pdfDocument) newPage() *pdfPage {
+	page := &pdfPage{}
+	d.pages = append(d.pages, page)
+	return page
+}
+
+// fillColor sets the color of the fills and text that follow
+func (p *pdfPage) fillColor(hex string) {
+	c := mustParseHex(hex)
+	fmt.Fprintf(&p.content, "%s %s %s rg\n", pdfNumber(float64(c.R)/255), pdfNumber(float64(c.G)/255), pdfNumber(float64(c.B)/255))
+}
+
+func (p *pdfPage) rect(x, y, w, h float64, hex string) {
+	p.fillColor(hex)
+	fmt.Fprintf(&p.content, "%s %s %s %s re f\n", pdfNumber(x), pdfNumber(pdfPageHeight-y-h), pdfNumber(w), pdfNumber(h))
+}
+
+func (p *pdfPage) line(x1, y1, x2, y2, width float64, hex string) {
+	c := mustParseHex(hex)
+	fmt.Fprintf(&p.content, "%s %s %s RG %s w %s %s m %s %s l S\n",
+		pdfNumber(float64(c.R)/255), pdfNumber(float64(c.G)/255), pdfNumber(float64(c.B)/255), pdfNumber(width),
+		pdfNumber(x1), pdfNumber(pdfPageHeight-y1), pdfNumber(x2), pdfNumber(pdfPageHeight-y2))
+}
+
+// text draws s with its baseline at y
+func (p *pdfPage) text(x, y, size float64, bold bool, hex, s string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	p.fillColor(hex)
+	fmt.Fprintf(&p.content, "BT /%s %s Tf %s %s Td (%s) Tj ET\n", font, pdfNumber(size), pdfNumber(x), pdfNumber(pdfPageHeight-y), pdfString(s))
+}
+
+// textRight draws s ending at x, for columns of numbers
+func (p *pdfPage) textRight(x, y, size float64, bold bool, hex, s string) {
+	p.text(x-textWidth(s, size), y, size, bold, hex, s)
+}
+
+// write writes the document: catalog, page tree, fonts, then each page and
+// its compressed content stream, followed by the cross-reference table
+func (d *pdfDocument) write(w io.Writer) error {
+	var b bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, b.Len())
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	b.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 6+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	object(fmt.Sprintf("<< /Title (%s) /Producer (gitgraphed) >>", pdfString(d.Title)))
+	for i, page := range d.pages {
+		var stream bytes.Buffer
+		zw := zlib.NewWriter(&stream)
+		if _, err := zw.Write(page.content.Bytes()); err != nil {
+			return err
+		}
+		if err := zw.Close(); err != nil {
+			return err
+		}
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pdfNumber(pdfPageWidth), pdfNumber(pdfPageHeight), 7+2*i))
+		object(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", stream.Len(), stream.Bytes()))
+	}
+
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// pdfNumber formats n with at most two decimals, trimming trailing zeros
+func pdfNumber(n float64) string {
+	s := strings.TrimRight(fmt.Sprintf("%.2f", n), "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// pdfString escapes s for a literal string in WinAnsiEncoding. Characters
+// beyond Latin-1 have no glyph in the standard fonts and print as ?.
+func pdfString(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 0x20 && r < 0x7f:
+			b.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// helveticaWidths are the advance widths of ASCII 32-126 in Helvetica, in
+// thousandths of the font size; Helvetica-Bold is close enough for layout
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+// textWidth is how wide s is set in Helvetica at size, in points
+func textWidth(s string, size float64) float64 {
+	width := 0
+	for _, r := range s {
+		if r >= 32 && r <= 126 {
+			width += helveticaWidths[r-32]
+		} else {
+			width += 556
+		}
+	}
+	return float64(width) * size / 1000
+}
+
+// truncateText shortens s with an ellipsis to fit within width
+func truncateText(s string, size, width float64) string {
+	if textWidth(s, size) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && textWidth(string(runes)+"...", size) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
@@ -0,0 +1,146 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+	"github.com/JyotinderSingh/gitgraphed/locale"
+)
+
+// PNGOptions controls raster output
+type PNGOptions struct {
+	Scale     int            // pixel multiplier applied to the whole image
+	Theme     Theme          // defaults to GitHubTheme
+	Caption   bool           // draw the username and total above the calendar
+	Locale    *locale.Locale // month and weekday names, defaults to locale.English
+	WeekStart time.Weekday   // weekday of the first row, Sunday by default
+}
+
+// DefaultPNGOptions renders at 2x in the GitHub theme with a caption
+var DefaultPNGOptions = PNGOptions{Scale: 2, Theme: GitHubTheme, Caption: true}
+
+// Unscaled raster geometry, in pixels
+const (
+	pngCell        = 10
+	pngGap         = 3
+	pngMargin      = 10
+	pngLabelWidth  = 28
+	pngLabelHeight = 15
+	pngCaption     = 18
+)
+
+// PNG writes a raster heatmap of graph to w
+func PNG(w io.Writer, graph *gitgraph.ContributionGraph, opts PNGOptions) error {
+	return png.Encode(w, RasterImage(graph, opts))
+}
+
+// RasterImage draws graph onto a new image
+func RasterImage(graph *gitgraph.ContributionGraph, opts PNGOptions) *image.RGBA {
+	if opts.Scale < 1 {
+		opts.Scale = 1
+	}
+	theme := opts.Theme.orDefault(GitHubTheme)
+	colors := paletteRGBA(theme.Levels)
+	background := color.RGBA{0xff, 0xff, 0xff, 0xff}
+	if theme.Background != "" {
+		background = mustParseHex(theme.Background)
+	}
+
+	grid := LayoutWeeks(graph.Days, opts.WeekStart)
+	step := pngCell + pngGap
+	top := pngMargin + pngLabelHeight
+	if opts.Caption {
+		top += pngCaption
+	}
+	left := pngMargin + pngLabelWidth
+	width := left + grid.Weeks*step + pngMargin
+	height := top + 7*step + pngMargin
+
+	// Draw at 1x, then upscale so text and cells stay crisp
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{background}, image.Point{}, draw.Src)
+
+	text := mustParseHex(theme.Text)
+	if opts.Caption {
+		caption := graph.Username + " - " + strconv.Itoa(graph.TotalContribs) + " contributions"
+		drawText(img, pngMargin, pngMargin+11, caption, text)
+	}
+	drawGrid(img, grid, left, top, colors, text, opts.Locale)
+
+	return upscale(img, opts.Scale)
+}
+
+// drawGrid draws grid's cells with their top-left corner at left, top, and
+// its month and weekday labels above and left of them
+func drawGrid(img draw.Image, grid Grid, left, top int, colors []color.RGBA, text color.Color, loc *locale.Locale) {
+	step := pngCell + pngGap
+	for _, month := range grid.Months {
+		drawText(img, left+month.Col*step, top-4, loc.Month(month.Month), text)
+	}
+	for _, row := range grid.labelRows() {
+		drawText(img, left-pngLabelWidth, top+row*step+pngCell-1, loc.Weekday(grid.Weekday(row)), text)
+	}
+
+	for _, cell := range grid.Cells {
+		x := left + cell.Col*step
+		y := top + cell.Row*step
+		c := colors[0]
+		if cell.Day.Level >= 0 && cell.Day.Level < len(colors) {
+			c = colors[cell.Day.Level]
+		}
+		draw.Draw(img, image.Rect(x, y, x+pngCell, y+pngCell), &image.Uniform{c}, image.Point{}, draw.Src)
+	}
+}
+
+// drawText draws s with its baseline at (x, y) using the built-in bitmap font
+func drawText(img draw.Image, x, y int, s string, c color.Color) {
+	d := font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(c),
+		Face: basicfont.Face7x13,
+		Dot:  fixed.P(x, y),
+	}
+	d.DrawString(asciiFold.Replace(s))
+}
+
+// asciiFold drops the accents the bitmap font, which only has ASCII, can't draw
+var asciiFold = strings.NewReplacer(
+	"á", "a", "ä", "a", "å", "a", "à", "a", "â", "a", "ã", "a", "ç", "c", "é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ï", "i", "ł", "l", "ñ", "n", "ń", "n", "ó", "o", "ö", "o", "ø", "o", "ô", "o", "ś", "s",
+	"ú", "u", "ü", "u", "û", "u", "ź", "z", "ż", "z", "Ä", "A", "Ö", "O", "Ü", "U", "ß", "ss",
+)
+
+// upscale enlarges img by an integer factor with nearest-neighbour sampling
+func upscale(img *image.RGBA, scale int) *image.RGBA {
+	if scale == 1 {
+		return img
+	}
+	b := img.Bounds()
+	out := image.NewRGBA(image.Rect(0, 0, b.Dx()*scale, b.Dy()*scale))
+	for y := 0; y < out.Bounds().Dy(); y++ {
+		for x := 0; x < out.Bounds().Dx(); x++ {
+			out.SetRGBA(x, y, img.RGBAAt(x/scale, y/scale))
+		}
+	}
+	return out
+}
+
+// paletteRGBA converts a hex palette into colors
+func paletteRGBA(hexes []string) []color.RGBA {
+	colors := make([]color.RGBA, len(hexes))
+	for i, hex := range hexes {
+		colors[i] = mustParseHex(hex)
+	}
+	return colors
+}
@@ -0,0 +1,187 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+// PosterOptions controls the poster of every year of a graph, stacked
+type PosterOptions struct {
+	SVGOptions      // cell geometry, theme, locale and week start; Trend is not drawn
+	Scale      int  // PNG pixel multiplier
+	Caption    bool // write the username and the total of all years above them
+}
+
+// DefaultPosterOptions draw GitHub-sized cells, PNGs at 2x, with a caption
+var DefaultPosterOptions = PosterOptions{SVGOptions: DefaultSVGOptions, Scale: 2, Caption: true}
+
+// posterYear is one year's calendar on a poster
+type posterYear struct {
+	Year  int
+	Total int
+	Grid  Grid
+}
+
+// posterYears splits graph into its calendar years, oldest first. Totals
+// come from the graph's year totals when it has them, which count private
+// contributions, and from its days otherwise.
+func posterYears(graph *gitgraph.ContributionGraph, opts PosterOptions) []posterYear {
+	var years []posterYear
+	var days [][]gitgraph.ContributionDay
+	for _, day := range graph.Days {
+		year, err := strconv.Atoi(day.Date[:min(4, len(day.Date))])
+		if err != nil {
+			continue
+		}
+		if n := len(years); n == 0 || years[n-1].Year != year {
+			years = append(years, posterYear{Year: year})
+			days = append(days, nil)
+		}
+		years[len(years)-1].Total += day.Count
+		days[len(days)-1] = append(days[len(days)-1], day)
+	}
+	for i := range years {
+		for _, total := range graph.YearTotals {
+			if total.Year == years[i].Year {
+				years[i].Total = total.Total
+			}
+		}
+		years[i].Grid = LayoutWeeks(days[i], opts.WeekStart)
+	}
+	return years
+}
+
+// posterCaption sums up the poster, e.g. "alice: 12,345 contributions, 2015-2024"
+func posterCaption(graph *gitgraph.ContributionGraph, years []posterYear) string {
+	total := 0
+	for _, year := range years {
+		total += year.Total
+	}
+	span := strconv.Itoa(years[0].Year)
+	if last := years[len(years)-1].Year; last != years[0].Year {
+		span += "-" + strconv.Itoa(last)
+	}
+	return fmt.Sprintf("%s: %s %s, %s", graph.Username, formatCount(total), contributionNoun(total), span)
+}
+
+// yearHeading labels a year's calendar, e.g. "2024: 1,234 contributions"
+func yearHeading(year posterYear) string {
+	return fmt.Sprintf("%d: %s %s", year.Year, formatCount(year.Total), contributionNoun(year.Total))
+}
+
+// contributionNoun is "contribution" or "contributions", whichever fits n
+func contributionNoun(n int) string {
+	if n == 1 {
+		return "contribution"
+	}
+	return "contributions"
+}
+
+// PosterSVG writes every year of graph as a calendar under its own heading,
+// stacked oldest first in one tall SVG with a shared legend, for printing a
+// whole history
+func PosterSVG(w io.Writer, graph *gitgraph.ContributionGraph, opts PosterOptions) error {
+	years := posterYears(graph, opts)
+	if len(years) == 0 {
+		return errors.New("no days to draw")
+	}
+	theme := opts.Theme.orDefault(GitHubTheme)
+	step := opts.CellSize + opts.Gap
+	yearHeight := svgCaptionHeight + svgLabelHeight + 7*step
+
+	top := 0
+	if opts.Caption {
+		top = svgCaptionHeight + 8
+	}
+	width := 0
+	for _, year := range years {
+		width = max(width, svgLabelWidth+year.Grid.Weeks*step)
+	}
+	height := top + len(years)*yearHeight + svgLegendSpace
+
+	var b strings.Builder
+	caption := posterCaption(graph, years)
+	label := html.EscapeString(caption)
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" role="img" aria-label="%s">`+"\n",
+		width, height, width, height, label)
+	fmt.Fprintf(&b, "<title>%s</title>\n", label)
+	fmt.Fprintf(&b, `<style>text{font:9px -apple-system,BlinkMacSystemFont,"Segoe UI",Helvetica,Arial,sans-serif;fill:%s}.caption{font-size:14px;font-weight:600}.year{font-size:12px;font-weight:600}</style>`+"\n", theme.Text)
+	if theme.Background != "" {
+		fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", theme.Background)
+	}
+	fill := theme.color
+	if opts.Patterns {
+		writePatterns(&b, theme)
+		fill = patternFill(theme)
+	}
+
+	if opts.Caption {
+		fmt.Fprintf(&b, `<text class="caption" x="%d" y="%d">%s</text>`+"\n", svgLabelWidth, svgCaptionHeight-5, label)
+	}
+	for i, year := range years {
+		y := top + i*yearHeight
+		fmt.Fprintf(&b, `<text class="year" x="%d" y="%d">%s</text>`+"\n", svgLabelWidth, y+svgCaptionHeight-7, html.EscapeString(yearHeading(year)))
+		writeCalendar(&b, year.Grid, opts.SVGOptions, fill, 0, y+svgCaptionHeight)
+	}
+
+	writeLegend(&b, theme, opts.SVGOptions, fill, width, height-svgLegendSpace+5)
+	b.WriteString("</svg>\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// PosterPNG writes the poster of PosterSVG as a raster image, cells sized
+// as in PNG output
+func PosterPNG(w io.Writer, graph *gitgraph.ContributionGraph, opts PosterOptions) error {
+	years := posterYears(graph, opts)
+	if len(years) == 0 {
+		return errors.New("no days to draw")
+	}
+	if opts.Scale < 1 {
+		opts.Scale = 1
+	}
+	theme := opts.Theme.orDefault(GitHubTheme)
+	colors := paletteRGBA(theme.Levels)
+	background := color.RGBA{0xff, 0xff, 0xff, 0xff}
+	if theme.Background != "" {
+		background = mustParseHex(theme.Background)
+	}
+
+	step := pngCell + pngGap
+	yearHeight := pngCaption + pngLabelHeight + 7*step + pngGap
+	top := pngMargin
+	if opts.Caption {
+		top += pngCaption + 4
+	}
+	left := pngMargin + pngLabelWidth
+	width := 0
+	for _, year := range years {
+		width = max(width, left+year.Grid.Weeks*step+pngMargin)
+	}
+	height := top + len(years)*yearHeight + pngMargin
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{background}, image.Point{}, draw.Src)
+	text := mustParseHex(theme.Text)
+	if opts.Caption {
+		drawText(img, pngMargin, pngMargin+11, posterCaption(graph, years), text)
+	}
+	for i, year := range years {
+		y := top + i*yearHeight
+		drawText(img, left, y+pngCaption-5, yearHeading(year), text)
+		drawGrid(img, year.Grid, left, y+pngCaption+pngLabelHeight, colors, text, opts.Locale)
+	}
+
+	return png.Encode(w, upscale(img, opts.Scale))
+}
@@ -0,0 +1,71 @@
+package render
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+const (
+	punchCardStep  = 24 // spacing of the hour columns and weekday rows
+	punchCardLabel = 36 // width of the weekday labels
+)
+
+// PunchCardSVG draws card as a weekday by hour grid of dots, each dot's area
+// proportional to its count. CellSize, Gap and Radius of opts are ignored.
+func PunchCardSVG(w io.Writer, card *gitgraph.PunchCard, opts SVGOptions) error {
+	theme := opts.Theme.orDefault(GitHubTheme)
+	width := punchCardLabel + 24*punchCardStep
+	height := svgLabelHeight + 7*punchCardStep + svgLegendSpace
+
+	peak := 0
+	for _, hours := range card.Hours {
+		for _, count := range hours {
+			peak = max(peak, count)
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		width, height, width, height)
+	fmt.Fprintf(&b, `<style>text{font:9px -apple-system,BlinkMacSystemFont,"Segoe UI",Helvetica,Arial,sans-serif;fill:%s}</style>`+"\n", theme.Text)
+	if theme.Background != "" {
+		fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", theme.Background)
+	}
+
+	for hour := 0; hour < 24; hour += 3 {
+		fmt.Fprintf(&b, `<text x="%d" y="%d" text-anchor="middle">%02d:00</text>`+"\n",
+			punchCardLabel+hour*punchCardStep+punchCardStep/2, svgLabelHeight-5, hour)
+	}
+	for row := 0; row < 7; row++ {
+		weekday := time.Weekday((int(opts.WeekStart) + row) % 7)
+		y := svgLabelHeight + row*punchCardStep + punchCardStep/2
+		fmt.Fprintf(&b, `<text x="0" y="%d" dominant-baseline="middle">%s</text>`+"\n", y, opts.Locale.Weekday(weekday))
+		fmt.Fprintf(&b, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s"/>`+"\n",
+			punchCardLabel, y, width, y, theme.color(0))
+		for hour, count := range card.Hours[weekday] {
+			if count == 0 {
+				continue
+			}
+			r := math.Sqrt(float64(count)/float64(peak)) * (punchCardStep/2 - 1)
+			fmt.Fprintf(&b, `<circle cx="%d" cy="%d" r="%.1f" fill="%s" data-weekday="%s" data-hour="%d" data-count="%d"/>`+"\n",
+				punchCardLabel+hour*punchCardStep+punchCardStep/2, y, max(r, 1.5), theme.color(len(theme.Levels)-1),
+				weekday, hour, count)
+		}
+	}
+
+	caption := fmt.Sprintf("%s: %d contributions, %s time", card.Username, card.Total, card.TimeZone)
+	if card.From != "" {
+		caption = fmt.Sprintf("%s: %d contributions from %s to %s, %s time", card.Username, card.Total, card.From, card.To, card.TimeZone)
+	}
+	fmt.Fprintf(&b, `<text x="%d" y="%d">%s</text>`+"\n", punchCardLabel, height-6, html.EscapeString(caption))
+	b.WriteString("</svg>\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,247 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+	"github.com/JyotinderSingh/gitgraphed/locale"
+)
+
+// ReviewOptions controls ReviewPDF
+type ReviewOptions struct {
+	Theme     Theme          // defaults to GitHubTheme
+	Locale    *locale.Locale // month and weekday names and dates, defaults to locale.English
+	WeekStart time.Weekday   // weekday of the calendar's first row, Sunday by default
+	Repos     int            // repositories listed, 10 when zero
+}
+
+// Year-in-review page geometry, in points
+const (
+	reviewMargin     = 40.0
+	reviewLabelWidth = 24.0 // weekday labels left of the calendar
+	reviewRow        = 16.0 // table row height
+	reviewChart      = 110.0
+)
+
+// ReviewPDF writes a year-in-review PDF with a page per graph: its calendar,
+// a bar chart of its months, a table of statistics and its top repositories
+func ReviewPDF(w io.Writer, graphs []*gitgraph.ContributionGraph, opts ReviewOptions) error {
+	if len(graphs) == 0 {
+		return errors.New("no graphs to review")
+	}
+	if opts.Repos <= 0 {
+		opts.Repos = 10
+	}
+	theme := opts.Theme.orDefault(GitHubTheme)
+	doc := &pdfDocument{Title: "Year in review"}
+	if len(graphs) == 1 {
+		doc.Title = graphs[0].Username + ": year in review"
+	}
+	for _, graph := range graphs {
+		reviewPage(doc.newPage(), graph, theme, opts)
+	}
+	return doc.write(w)
+}
+
+// reviewStrong is the color of headings and values, darker than the theme's
+// label color on light pages and lighter on dark ones
+func reviewStrong(theme Theme) string {
+	if theme.Background == "" || mustParseHex(theme.Background).R > 0x80 {
+		return "#24292f"
+	}
+	return "#e6edf3"
+}
+
+func reviewPage(page *pdfPage, graph *gitgraph.ContributionGraph, theme Theme, opts ReviewOptions) {
+	if theme.Background != "" {
+		page.rect(0, 0, pdfPageWidth, pdfPageHeight, theme.Background)
+	}
+	strong := reviewStrong(theme)
+	grid := LayoutWeeks(graph.Days, opts.WeekStart)
+	months := monthTotals(graph.Days)
+
+	page.text(reviewMargin, 64, 22, true, strong, graph.Username)
+	subtitle := fmt.Sprintf("%s contributions", formatCount(graph.TotalContribs))
+	if n := len(grid.Cells); n > 0 {
+		subtitle = opts.Locale.FormatDate(grid.Cells[0].Date) + " - " + opts.Locale.FormatDate(grid.Cells[n-1].Date) + "  ·  " + subtitle
+	}
+	page.text(reviewMargin, 84, 11, false, theme.Text, subtitle)
+
+	y := reviewHeading(page, 116, "Contributions", strong, theme)
+	y = reviewCalendar(page, grid, theme, opts, y)
+	y = reviewHeading(page, y+28, "By month", strong, theme)
+	y = reviewMonths(page, months, theme, opts, y)
+	y = reviewHeading(page, y+28, "Statistics", strong, theme)
+	y = reviewStats(page, graph, grid, months, strong, theme, opts, y)
+	y = reviewHeading(page, y+28, "Top repositories", strong, theme)
+	reviewRepos(page, graph.Repositories, strong, theme, opts, y)
+}
+
+// reviewHeading draws a section heading ruled beneath at y, returning where
+// the section's content starts
+func reviewHeading(page *pdfPage, y float64, title, strong string, theme Theme) float64 {
+	page.text(reviewMargin, y, 13, true, strong, title)
+	page.line(reviewMargin, y+6, pdfPageWidth-reviewMargin, y+6, 0.5, theme.color(0))
+	return y + 22
+}
+
+// reviewCalendar draws the calendar filling the page's width with a legend
+// beneath, returning the y below it
+func reviewCalendar(page *pdfPage, grid Grid, theme Theme, opts ReviewOptions, y float64) float64 {
+	width := pdfPageWidth - 2*reviewMargin - reviewLabelWidth
+	step := math.Min(12, width/float64(max(grid.Weeks, 1)))
+	cell := step * 0.8
+	left := reviewMargin + reviewLabelWidth
+	top := y + 10
+
+	for _, month := range grid.Months {
+		page.text(left+float64(month.Col)*step, y+4, 7, false, theme.Text, opts.Locale.Month(month.Month))
+	}
+	for _, row := range grid.labelRows() {
+		page.text(reviewMargin, top+float64(row)*step+cell-1, 7, false, theme.Text, opts.Locale.Weekday(grid.Weekday(row)))
+	}
+	for _, c := range grid.Cells {
+		page.rect(left+float64(c.Col)*step, top+float64(c.Row)*step, cell, cell, theme.color(c.Day.Level))
+	}
+
+	legend := top + 7*step + 6
+	x := pdfPageWidth - reviewMargin - float64(len(theme.Levels))*step - textWidth("More", 7) - 3
+	page.textRight(x-3, legend+cell-1, 7, false, theme.Text, "Less")
+	for i := range theme.Levels {
+		page.rect(x+float64(i)*step, legend, cell, cell, theme.color(i))
+	}
+	page.text(x+float64(len(theme.Levels))*step+2, legend+cell-1, 7, false, theme.Text, "More")
+	return legend + cell
+}
+
+// reviewMonths draws a bar per month on a ruled axis, returning the y below
+// the month labels
+func reviewMonths(page *pdfPage, months []monthTotal, theme Theme, opts ReviewOptions, y float64) float64 {
+	peak := 0
+	for _, month := range months {
+		peak = max(peak, month.Total)
+	}
+	scale := newAxis(peak, reviewChart)
+	left := reviewMargin + reviewLabelWidth
+	width := pdfPageWidth - reviewMargin - left
+	bottom := y + reviewChart
+
+	for _, tick := range scale.Ticks {
+		ty := bottom - scale.pos(tick)
+		page.line(left, ty, left+width, ty, 0.5, theme.color(0))
+		page.textRight(left-4, ty+2.5, 7, false, theme.Text, formatCount(tick))
+	}
+	slot := width / float64(max(len(months), 1))
+	bar := math.Min(slot*0.7, 28)
+	for i, month := range months {
+		x := left + float64(i)*slot + (slot-bar)/2
+		height := scale.pos(month.Total)
+		page.rect(x, bottom-height, bar, height, theme.color(3))
+		center := x + bar/2
+		if month.Total > 0 {
+			label := formatCount(month.Total)
+			page.text(center-textWidth(label, 7)/2, bottom-height-3, 7, false, theme.Text, label)
+		}
+		name := opts.Locale.Month(month.Month.Month())
+		page.text(center-textWidth(name, 7)/2, bottom+10, 7, false, theme.Text, name)
+	}
+	return bottom + 10
+}
+
+// reviewStats draws the statistics in two columns of label and value,
+// returning the y below them
+func reviewStats(page *pdfPage, graph *gitgraph.ContributionGraph, grid Grid, months []monthTotal, strong string, theme Theme, opts ReviewOptions, y float64) float64 {
+	loc := opts.Locale
+	summary := gitgraph.Summarize(graph.Days)
+	streaks := gitgraph.Streaks{}
+	if graph.Streaks != nil {
+		streaks = *graph.Streaks
+	} else if n := len(grid.Cells); n > 0 {
+		streaks = gitgraph.ComputeStreaks(graph.Days, grid.Cells[n-1].Date)
+	}
+	streak := func(s gitgraph.Streak) string {
+		days := strconv.Itoa(s.Length) + " days"
+		if s.Length == 1 {
+			days = "1 day"
+		}
+		start, err1 := time.Parse("2006-01-02", s.Start)
+		end, err2 := time.Parse("2006-01-02", s.End)
+		if s.Length == 0 || err1 != nil || err2 != nil {
+			return days
+		}
+		return days + ", " + loc.FormatMonthDay(start) + " - " + loc.FormatMonthDay(end)
+	}
+
+	rows := [][2]string{
+		{"Contributions", formatCount(graph.TotalContribs)},
+		{"Active days", fmt.Sprintf("%d of %d (%.0f%%)", summary.ActiveDays, len(graph.Days), summary.ActiveDayPercent)},
+		{"Current streak", streak(streaks.Current)},
+		{"Longest streak", streak(streaks.Longest)},
+		{"Daily mean / median", fmt.Sprintf("%.2f / %g", summary.MeanDaily, summary.MedianDaily)},
+		{"Busiest weekday", loc.Weekday(gitgraph.BusiestWeekday(graph.Days))},
+	}
+	if best, err := time.Parse("2006-01-02", summary.MaxDay.Date); err == nil && summary.MaxDay.Count > 0 {
+		rows = append(rows, [2]string{"Best day", fmt.Sprintf("%s (%d)", loc.FormatDate(best), summary.MaxDay.Count)})
+	}
+	busiest := monthTotal{}
+	for _, month := range months {
+		if month.Total > busiest.Total {
+			busiest = month
+		}
+	}
+	if busiest.Total > 0 {
+		rows = append(rows, [2]string{"Busiest month", fmt.Sprintf("%s (%s)", loc.FormatYearMonth(busiest.Month), formatCount(busiest.Total))})
+	}
+	if t := graph.Types; t != nil {
+		rows = append(rows,
+			[2]string{"Commits", formatCount(t.Commits)},
+			[2]string{"Pull requests", formatCount(t.PullRequests)},
+			[2]string{"Issues", formatCount(t.Issues)},
+			[2]string{"Reviews", formatCount(t.Reviews)},
+		)
+	}
+
+	column := (pdfPageWidth - 2*reviewMargin) / 2
+	half := (len(rows) + 1) / 2
+	for i, row := range rows {
+		x := reviewMargin + float64(i/half)*column
+		ry := y + float64(i%half)*reviewRow + 8
+		page.text(x, ry, 9, false, theme.Text, row[0])
+		page.textRight(x+column-16, ry, 9, true, strong, truncateText(row[1], 9, column-120))
+	}
+	return y + float64(half)*reviewRow
+}
+
+// reviewRepos lists the repositories committed to most with their share of
+// the commits
+func reviewRepos(page *pdfPage, repos []gitgraph.RepositoryContributions, strong string, theme Theme, opts ReviewOptions, y float64) {
+	if len(repos) == 0 {
+		page.text(reviewMargin, y+8, 9, false, theme.Text, "No per-repository breakdown; it needs a token for GitHub's GraphQL API")
+		return
+	}
+	total := 0
+	for _, repo := range repos {
+		total += repo.Commits
+	}
+	// Leave a row for the count of those left out, within the bottom margin
+	limit := min(opts.Repos, int((pdfPageHeight-reviewMargin-y)/reviewRow)-1)
+	right := pdfPageWidth - reviewMargin
+	for i, repo := range repos[:min(limit, len(repos))] {
+		ry := y + float64(i)*reviewRow + 8
+		name := repo.Repository
+		if repo.Private {
+			name += " (private)"
+		}
+		page.text(reviewMargin, ry, 9, false, strong, truncateText(name, 9, right-reviewMargin-150))
+		page.textRight(right-60, ry, 9, true, strong, formatCount(repo.Commits))
+		page.textRight(right, ry, 9, false, theme.Text, fmt.Sprintf("%.1f%%", float64(repo.Commits)/float64(max(total, 1))*100))
+	}
+	if rest := len(repos) - limit; rest > 0 {
+		page.text(reviewMargin, y+float64(limit)*reviewRow+8, 9, false, theme.Text, fmt.Sprintf("and %d more", rest))
+	}
+}
@@ -0,0 +1,180 @@
+package render
+
+import (
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/image/vector"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+// SkylineOptions controls the isometric 3D view
+type SkylineOptions struct {
+	Cell      float64      // edge of a day's base tile, in pixels
+	MaxHeight float64      // height of the busiest day's bar, in pixels
+	Theme     Theme        // defaults to GitHubTheme
+	Caption   bool         // write the username and total above the skyline
+	WeekStart time.Weekday // weekday of the first row, Sunday by default
+}
+
+// DefaultSkylineOptions suit a year of data at about 800 pixels wide
+var DefaultSkylineOptions = SkylineOptions{Cell: 12, MaxHeight: 90, Theme: GitHubTheme, Caption: true}
+
+const skylineMargin = 16
+
+// point is a projected position in image coordinates
+type point struct{ X, Y float64 }
+
+// face is one filled quadrilateral of the skyline
+type face struct {
+	Points [4]point
+	Color  color.RGBA
+}
+
+// skyline is the projected scene: faces in drawing order and the image size
+type skyline struct {
+	Faces         []face
+	Width, Height float64
+	Top           float64 // where the scene starts below the caption
+}
+
+// Shading of the two visible sides relative to a bar's top
+const (
+	skylineLeftShade  = 0.8
+	skylineRightShade = 0.6
+)
+
+// layoutSkyline projects a bar per day, weeks running right and down, weekdays
+// left and down, back to front so nearer bars paint over farther ones. It is
+// shared by the SVG and PNG skyline renderers.
+func layoutSkyline(graph *gitgraph.ContributionGraph, opts SkylineOptions) skyline {
+	if opts.Cell <= 0 {
+		opts.Cell = DefaultSkylineOptions.Cell
+	}
+	if opts.MaxHeight <= 0 {
+		opts.MaxHeight = DefaultSkylineOptions.MaxHeight
+	}
+	theme := opts.Theme.orDefault(GitHubTheme)
+	colors := paletteRGBA(theme.Levels)
+	grid := LayoutWeeks(graph.Days, opts.WeekStart)
+
+	max := 0
+	for _, cell := range grid.Cells {
+		if cell.Day.Count > max {
+			max = cell.Day.Count
+		}
+	}
+
+	// Isometric axes: x along weeks, y along weekdays, z up
+	a := opts.Cell * math.Cos(math.Pi/6)
+	b := opts.Cell * math.Sin(math.Pi/6)
+	top := float64(skylineMargin)
+	if opts.Caption {
+		top += 20
+	}
+	originX := skylineMargin + 7*a
+	originY := top + opts.MaxHeight
+	project := func(x, y, z float64) point {
+		return point{originX + (x-y)*a, originY + (x+y)*b - z}
+	}
+
+	cells := append([]Cell(nil), grid.Cells...)
+	sort.SliceStable(cells, func(i, j int) bool { return cells[i].Col+cells[i].Row < cells[j].Col+cells[j].Row })
+
+	scene := skyline{
+		Width:  2*skylineMargin + float64(grid.Weeks+7)*a,
+		Height: originY + float64(grid.Weeks+7)*b + skylineMargin,
+		Top:    top,
+	}
+	for _, cell := range cells {
+		c := colors[0]
+		if cell.Day.Level >= 0 && cell.Day.Level < len(colors) {
+			c = colors[cell.Day.Level]
+		}
+		x, y := float64(cell.Col), float64(cell.Row)
+		h := 0.0
+		if max > 0 {
+			h = float64(cell.Day.Count) / float64(max) * opts.MaxHeight
+		}
+		if h > 0 {
+			scene.Faces = append(scene.Faces,
+				face{[4]point{project(x, y+1, h), project(x+1, y+1, h), project(x+1, y+1, 0), project(x, y+1, 0)}, shade(c, skylineLeftShade)},
+				face{[4]point{project(x+1, y, h), project(x+1, y+1, h), project(x+1, y+1, 0), project(x+1, y, 0)}, shade(c, skylineRightShade)},
+			)
+		}
+		scene.Faces = append(scene.Faces, face{[4]point{project(x, y, h), project(x+1, y, h), project(x+1, y+1, h), project(x, y+1, h)}, c})
+	}
+	return scene
+}
+
+// shade darkens c by factor
+func shade(c color.RGBA, factor float64) color.RGBA {
+	return color.RGBA{uint8(float64(c.R) * factor), uint8(float64(c.G) * factor), uint8(float64(c.B) * factor), c.A}
+}
+
+// SkylineSVG writes graph as an isometric 3D skyline in SVG
+func SkylineSVG(w io.Writer, graph *gitgraph.ContributionGraph, opts SkylineOptions) error {
+	theme := opts.Theme.orDefault(GitHubTheme)
+	scene := layoutSkyline(graph, opts)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f">`+"\n",
+		scene.Width, scene.Height, scene.Width, scene.Height)
+	if theme.Background != "" {
+		fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", theme.Background)
+	}
+	if opts.Caption {
+		fmt.Fprintf(&b, `<text x="%d" y="%d" font-family="sans-serif" font-size="14" fill="%s">%s - %d contributions</text>`+"\n",
+			skylineMargin, skylineMargin+12, theme.Text, html.EscapeString(graph.Username), graph.TotalContribs)
+	}
+	for _, f := range scene.Faces {
+		fmt.Fprintf(&b, `<polygon points="%.1f,%.1f %.1f,%.1f %.1f,%.1f %.1f,%.1f" fill="#%02x%02x%02x"/>`+"\n",
+			f.Points[0].X, f.Points[0].Y, f.Points[1].X, f.Points[1].Y,
+			f.Points[2].X, f.Points[2].Y, f.Points[3].X, f.Points[3].Y,
+			f.Color.R, f.Color.G, f.Color.B)
+	}
+	b.WriteString("</svg>\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// SkylinePNG writes graph as an isometric 3D skyline in PNG, antialiased
+func SkylinePNG(w io.Writer, graph *gitgraph.ContributionGraph, opts SkylineOptions) error {
+	theme := opts.Theme.orDefault(GitHubTheme)
+	scene := layoutSkyline(graph, opts)
+
+	width, height := int(math.Ceil(scene.Width)), int(math.Ceil(scene.Height))
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	background := color.RGBA{0xff, 0xff, 0xff, 0xff}
+	if theme.Background != "" {
+		background = mustParseHex(theme.Background)
+	}
+	draw.Draw(img, img.Bounds(), &image.Uniform{background}, image.Point{}, draw.Src)
+	if opts.Caption {
+		caption := fmt.Sprintf("%s - %d contributions", graph.Username, graph.TotalContribs)
+		drawText(img, skylineMargin, skylineMargin+11, caption, mustParseHex(theme.Text))
+	}
+
+	r := vector.NewRasterizer(width, height)
+	for _, f := range scene.Faces {
+		r.Reset(width, height)
+		r.MoveTo(float32(f.Points[0].X), float32(f.Points[0].Y))
+		for _, p := range f.Points[1:] {
+			r.LineTo(float32(p.X), float32(p.Y))
+		}
+		r.ClosePath()
+		r.Draw(img, img.Bounds(), &image.Uniform{f.Color}, image.Point{})
+	}
+	return png.Encode(w, img)
+}
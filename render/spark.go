@@ -0,0 +1,64 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+// SparkOptions controls sparkline output
+type SparkOptions struct {
+	Weeks     int          // most recent weeks to show; 0 shows them all
+	WeekStart time.Weekday // first day of each week, Sunday by default
+}
+
+// sparkBlocks are the bar heights from no contributions to the busiest week
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline writes one line of weekly totals as block characters followed by
+// the number of contributions they cover, for shell prompts and status bars
+func Sparkline(w io.Writer, graph *gitgraph.ContributionGraph, opts SparkOptions) error {
+	// Weeks after the current one, as in the rest of this year, are left out
+	today := time.Now()
+	var weeks []int
+	for _, cell := range LayoutWeeks(graph.Days, opts.WeekStart).Cells {
+		if cell.Date.After(today) {
+			break
+		}
+		for len(weeks) <= cell.Col {
+			weeks = append(weeks, 0)
+		}
+		weeks[cell.Col] += cell.Day.Count
+	}
+	total := graph.TotalContribs
+	if opts.Weeks > 0 && opts.Weeks < len(weeks) {
+		weeks = weeks[len(weeks)-opts.Weeks:]
+		total = 0
+		for _, count := range weeks {
+			total += count
+		}
+	}
+
+	max := 0
+	for _, count := range weeks {
+		if count > max {
+			max = count
+		}
+	}
+	line := make([]rune, len(weeks))
+	for i, count := range weeks {
+		idx := 0
+		if max > 0 {
+			idx = count * (len(sparkBlocks) - 1) / max
+		}
+		// Any activity stands above an empty week
+		if count > 0 && idx == 0 {
+			idx = 1
+		}
+		line[i] = sparkBlocks[idx]
+	}
+	_, err := fmt.Fprintf(w, "%s %d\n", string(line), total)
+	return err
+}
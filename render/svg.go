@@ -0,0 +1,200 @@
+package render
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+	"github.com/JyotinderSingh/gitgraphed/locale"
+)
+
+// SVGOptions controls the calendar geometry and colors
+type SVGOptions struct {
+	CellSize  int
+	Gap       int
+	Radius    int
+	Theme     Theme          // defaults to GitHubTheme
+	Trend     int            // days in a rolling average drawn as a line below the calendar, 0 for none
+	Locale    *locale.Locale // month and weekday names, defaults to locale.English
+	WeekStart time.Weekday   // weekday of the first row, Sunday by default
+	// Patterns dots each active cell, the dots growing with its level, so
+	// levels can be told apart without their colors
+	Patterns bool
+}
+
+// DefaultSVGOptions matches the proportions of GitHub's profile calendar
+var DefaultSVGOptions = SVGOptions{CellSize: 10, Gap: 3, Radius: 2}
+
+const (
+	svgLabelWidth  = 28
+	svgLabelHeight = 15
+	svgLegendSpace = 20
+	svgTrendHeight = 40
+	svgPatternSize = 4 // side of a pattern tile, holding one dot
+)
+
+// SVG writes a self-contained SVG heatmap of graph to w
+func SVG(w io.Writer, graph *gitgraph.ContributionGraph, opts SVGOptions) error {
+	theme := opts.Theme.orDefault(GitHubTheme)
+	grid := LayoutWeeks(graph.Days, opts.WeekStart)
+	step := opts.CellSize + opts.Gap
+
+	width := svgLabelWidth + grid.Weeks*step
+	trendHeight := 0
+	if opts.Trend > 0 {
+		trendHeight = svgTrendHeight
+	}
+	height := svgLabelHeight + 7*step + trendHeight + svgLegendSpace
+
+	var b strings.Builder
+	label := html.EscapeString(svgLabel(graph))
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" role="img" aria-label="%s">`+"\n",
+		width, height, width, height, label)
+	fmt.Fprintf(&b, "<title>%s</title>\n", label)
+	fmt.Fprintf(&b, `<style>text{font:9px -apple-system,BlinkMacSystemFont,"Segoe UI",Helvetica,Arial,sans-serif;fill:%s}</style>`+"\n", theme.Text)
+	if theme.Background != "" {
+		fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", theme.Background)
+	}
+	fill := theme.color
+	if opts.Patterns {
+		writePatterns(&b, theme)
+		fill = patternFill(theme)
+	}
+
+	writeCalendar(&b, grid, opts, fill, 0, 0)
+
+	if opts.Trend > 0 {
+		writeTrend(&b, grid, opts, theme, svgLabelHeight+7*step)
+	}
+
+	writeLegend(&b, theme, opts, fill, width, svgLabelHeight+7*step+trendHeight+5)
+	b.WriteString("</svg>\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeCalendar draws grid's month and weekday labels and cells, its top-left
+// corner at x, y
+func writeCalendar(b *strings.Builder, grid Grid, opts SVGOptions, fill func(level int) string, x, y int) {
+	step := opts.CellSize + opts.Gap
+	for _, month := range grid.Months {
+		fmt.Fprintf(b, `<text x="%d" y="%d">%s</text>`+"\n", x+svgLabelWidth+month.Col*step, y+svgLabelHeight-5, opts.Locale.Month(month.Month))
+	}
+	for _, row := range grid.labelRows() {
+		fmt.Fprintf(b, `<text x="%d" y="%d">%s</text>`+"\n", x, y+svgLabelHeight+row*step+opts.CellSize-1, opts.Locale.Weekday(grid.Weekday(row)))
+	}
+
+	for _, cell := range grid.Cells {
+		fmt.Fprintf(b, `<rect x="%d" y="%d" width="%d" height="%d" rx="%d" ry="%d" fill="%s" data-date="%s" data-count="%d"><title>%s</title></rect>`+"\n",
+			x+svgLabelWidth+cell.Col*step, y+svgLabelHeight+cell.Row*step, opts.CellSize, opts.CellSize,
+			opts.Radius, opts.Radius, fill(cell.Day.Level), cell.Day.Date, cell.Day.Count, cellTitle(cell.Day, opts.Locale))
+	}
+}
+
+// writeLegend draws the Less-More legend at y in the bottom-right corner of
+// a drawing width wide, as on GitHub
+func writeLegend(b *strings.Builder, theme Theme, opts SVGOptions, fill func(level int) string, width, y int) {
+	step := opts.CellSize + opts.Gap
+	x := width - len(theme.Levels)*step - 30
+	fmt.Fprintf(b, `<text x="%d" y="%d" text-anchor="end">Less</text>`+"\n", x-4, y+opts.CellSize-1)
+	for i := range theme.Levels {
+		fmt.Fprintf(b, `<rect x="%d" y="%d" width="%d" height="%d" rx="%d" ry="%d" fill="%s"/>`+"\n",
+			x+i*step, y, opts.CellSize, opts.CellSize, opts.Radius, opts.Radius, fill(i))
+	}
+	fmt.Fprintf(b, `<text x="%d" y="%d">More</text>`+"\n", x+len(theme.Levels)*step+2, y+opts.CellSize-1)
+}
+
+// svgLabel describes the whole calendar for screen readers
+func svgLabel(graph *gitgraph.ContributionGraph) string {
+	if len(graph.Days) == 0 {
+		return graph.Username + "'s contributions"
+	}
+	return fmt.Sprintf("%s's contributions: %d from %s to %s", graph.Username, graph.TotalContribs,
+		graph.Days[0].Date, graph.Days[len(graph.Days)-1].Date)
+}
+
+// cellTitle is the tooltip and accessible name of a day's cell, worded as
+// GitHub's own
+func cellTitle(day gitgraph.ContributionDay, loc *locale.Locale) string {
+	date := day.Date
+	if t, err := time.Parse("2006-01-02", day.Date); err == nil {
+		date = loc.FormatDate(t)
+	}
+	switch day.Count {
+	case 0:
+		return "No contributions on " + date
+	case 1:
+		return "1 contribution on " + date
+	}
+	return fmt.Sprintf("%d contributions on %s", day.Count, date)
+}
+
+// writePatterns defines a pattern per active level: its color dotted in the
+// empty cells' color, the dots larger at each level
+func writePatterns(b *strings.Builder, theme Theme) {
+	b.WriteString("<defs>\n")
+	for level := 1; level < len(theme.Levels); level++ {
+		radius := 0.2 + 0.3*float64(level)
+		fmt.Fprintf(b, `<pattern id="gg-level-%d" width="%d" height="%d" patternUnits="userSpaceOnUse"><rect width="%d" height="%d" fill="%s"/><circle cx="%g" cy="%g" r="%.1f" fill="%s"/></pattern>`+"\n",
+			level, svgPatternSize, svgPatternSize, svgPatternSize, svgPatternSize, theme.color(level),
+			svgPatternSize/2.0, svgPatternSize/2.0, radius, theme.color(0))
+	}
+	b.WriteString("</defs>\n")
+}
+
+// patternFill returns the fill of each level once writePatterns has defined
+// them; the empty level keeps its plain color
+func patternFill(theme Theme) func(level int) string {
+	return func(level int) string {
+		if level <= 0 || level >= len(theme.Levels) {
+			return theme.color(0)
+		}
+		return fmt.Sprintf("url(#gg-level-%d)", level)
+	}
+}
+
+// writeTrend draws the rolling average of grid's counts as a line in the band
+// starting at top, each day placed along its week's column
+func writeTrend(b *strings.Builder, grid Grid, opts SVGOptions, theme Theme, top int) {
+	days := make([]gitgraph.ContributionDay, len(grid.Cells))
+	for i, cell := range grid.Cells {
+		days[i] = cell.Day
+	}
+	rolling := gitgraph.ComputeRollingAverage(days, opts.Trend)
+	peak := 0.0
+	for _, day := range rolling.Days {
+		peak = max(peak, day.Average)
+	}
+	if len(rolling.Days) == 0 || peak == 0 {
+		return
+	}
+
+	step := float64(opts.CellSize + opts.Gap)
+	var points []string
+	for i, day := range rolling.Days {
+		cell := grid.Cells[i]
+		x := float64(svgLabelWidth) + (float64(cell.Col)+float64(cell.Row)/7)*step + step/14
+		y := float64(top+svgTrendHeight-2) - day.Average/peak*float64(svgTrendHeight-20)
+		points = append(points, fmt.Sprintf("%.1f,%.1f", x, y))
+	}
+	fmt.Fprintf(b, `<text x="%d" y="%d">%d-day average</text>`+"\n", svgLabelWidth, top+14, rolling.Window)
+	fmt.Fprintf(b, `<text x="%d" y="%d" text-anchor="end">peak %.1f</text>`+"\n", svgLabelWidth+grid.Weeks*opts.CellSize+(grid.Weeks-1)*opts.Gap, top+14, peak)
+	fmt.Fprintf(b, `<polyline points="%s" fill="none" stroke="%s" stroke-width="1.5" stroke-linejoin="round"/>`+"\n",
+		strings.Join(points, " "), theme.color(len(theme.Levels)-1))
+}
+
+// LevelColor returns the hex color of a 0-4 level in theme, defaulting to GitHubTheme
+func LevelColor(level int, theme Theme) string {
+	return theme.orDefault(GitHubTheme).color(level)
+}
+
+func levelColor(palette []string, level int) string {
+	if level < 0 || level >= len(palette) {
+		return palette[0]
+	}
+	return palette[level]
+}
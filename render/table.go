@@ -0,0 +1,78 @@
+package render
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+	"github.com/JyotinderSingh/gitgraphed/locale"
+)
+
+// TableOptions controls Table output
+type TableOptions struct {
+	Header bool           // write a row naming the columns
+	Months bool           // head each month's days with its name and year
+	Locale *locale.Locale // weekday and month names, defaults to locale.English
+}
+
+// Table writes a row per day of its date, weekday, count and a bar as long
+// as its level, in aligned columns for reading in a terminal. Month headings
+// go wherever the month changes, so they group the days in date order.
+func Table(w io.Writer, graph *gitgraph.ContributionGraph, opts TableOptions) error {
+	rows := make([][]string, 0, len(graph.Days))
+	months := make([]time.Time, 0, len(graph.Days))
+	for _, day := range graph.Days {
+		date, err := time.Parse("2006-01-02", day.Date)
+		if err != nil {
+			continue
+		}
+		level := min(max(day.Level, 0), 4)
+		rows = append(rows, []string{day.Date, opts.Locale.Weekday(date.Weekday()), strconv.Itoa(day.Count), strings.Repeat("■", level) + strings.Repeat("·", 4-level)})
+		months = append(months, time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC))
+	}
+	header := []string{"Date", "Day", "Count", "Level"}
+	widths := make([]int, len(header))
+	for _, row := range append(rows, header) {
+		for i, cell := range row {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+	// Counts are right-aligned, the other columns left-aligned
+	line := func(b *strings.Builder, row []string) {
+		for i, cell := range row {
+			pad := strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell))
+			switch {
+			case i == 2:
+				b.WriteString(pad + cell)
+			case i == len(row)-1:
+				b.WriteString(cell)
+			default:
+				b.WriteString(cell + pad)
+			}
+			if i < len(row)-1 {
+				b.WriteString("  ")
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	var b strings.Builder
+	if opts.Header {
+		line(&b, header)
+	}
+	for i, row := range rows {
+		if opts.Months && (i == 0 || !months[i].Equal(months[i-1])) {
+			if i > 0 || opts.Header {
+				b.WriteString("\n")
+			}
+			b.WriteString(opts.Locale.FormatYearMonth(months[i]) + "\n")
+		}
+		line(&b, row)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,109 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+	"github.com/JyotinderSingh/gitgraphed/locale"
+)
+
+// TermOptions controls terminal heatmap output
+type TermOptions struct {
+	TrueColor bool           // use 24-bit colors instead of the xterm 256-color palette
+	Theme     Theme          // defaults to GitHubDarkTheme, which suits most terminals
+	Locale    *locale.Locale // month and weekday names, defaults to locale.English
+	WeekStart time.Weekday   // weekday of the first row, Sunday by default
+	Cursor    string         // date of a cell to mark, for interactive views
+	// NoColor draws levels as ASCII shades instead of ANSI colors, for pipes,
+	// files and $NO_COLOR
+	NoColor bool
+}
+
+// termShades draw levels 0-4 without color, denser at each level
+var termShades = []string{"..", "--", "++", "**", "##"}
+
+// DetectTrueColor reports whether the terminal advertises 24-bit color support
+func DetectTrueColor() bool {
+	colorTerm := os.Getenv("COLORTERM")
+	return colorTerm == "truecolor" || colorTerm == "24bit"
+}
+
+const termCellWidth = 2
+
+// Terminal writes the calendar as ANSI-colored blocks with month labels and a legend
+func Terminal(w io.Writer, graph *gitgraph.ContributionGraph, opts TermOptions) error {
+	theme := opts.Theme.orDefault(GitHubDarkTheme)
+	grid := LayoutWeeks(graph.Days, opts.WeekStart)
+
+	rows := make([][]string, 7)
+	for i := range rows {
+		rows[i] = make([]string, grid.Weeks)
+		for j := range rows[i] {
+			rows[i][j] = strings.Repeat(" ", termCellWidth)
+		}
+	}
+	for _, cell := range grid.Cells {
+		rows[cell.Row][cell.Col] = termBlock(theme, cell.Day.Level, opts)
+		if cell.Day.Date == opts.Cursor {
+			rows[cell.Row][cell.Col] = termCursor(theme, cell.Day.Level, opts)
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(termMonthLine(grid, opts.Locale))
+	labels := make([]string, 7)
+	for _, row := range grid.labelRows() {
+		labels[row] = opts.Locale.Weekday(grid.Weekday(row))
+	}
+	for i, row := range rows {
+		fmt.Fprintf(&b, "%-4s%s\n", labels[i], strings.Join(row, ""))
+	}
+
+	b.WriteString("\n    Less ")
+	for level := range theme.Levels {
+		b.WriteString(termBlock(theme, level, opts))
+		b.WriteString(" ")
+	}
+	fmt.Fprintf(&b, "More    %d contributions\n", graph.TotalContribs)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// termBlock renders one cell of level as background-colored spaces
+func termBlock(theme Theme, level int, opts TermOptions) string {
+	if opts.NoColor {
+		return termShades[min(max(level, 0), len(termShades)-1)]
+	}
+	c := mustParseHex(theme.color(level))
+	cell := strings.Repeat(" ", termCellWidth)
+	if opts.TrueColor {
+		return fmt.Sprintf("\x1b[48;2;%d;%d;%dm%s\x1b[0m", c.R, c.G, c.B, cell)
+	}
+	return fmt.Sprintf("\x1b[48;5;%dm%s\x1b[0m", xterm256(c), cell)
+}
+
+// termCursor renders the cursor's cell as brackets over its color
+func termCursor(theme Theme, level int, opts TermOptions) string {
+	if opts.NoColor {
+		return "[]"
+	}
+	c := mustParseHex(theme.color(level))
+	if opts.TrueColor {
+		return fmt.Sprintf("\x1b[48;2;%d;%d;%dm\x1b[1;97m[]\x1b[0m", c.R, c.G, c.B)
+	}
+	return fmt.Sprintf("\x1b[48;5;%dm\x1b[1;97m[]\x1b[0m", xterm256(c))
+}
+
+// termMonthLine places month names above their starting columns
+func termMonthLine(grid Grid, loc *locale.Locale) string {
+	line := []rune(strings.Repeat(" ", 4+grid.Weeks*termCellWidth+6))
+	for _, month := range grid.Months {
+		copy(line[4+month.Col*termCellWidth:], []rune(loc.Month(month.Month)))
+	}
+	return strings.TrimRight(string(line), " ") + "\n"
+}
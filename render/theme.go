@@ -0,0 +1,97 @@
+package render
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Theme is the set of colors every renderer draws with
+type Theme struct {
+	Name       string
+	Levels     []string // hex colors for levels 0-4
+	Background string   // hex page color; empty is transparent in SVG and white in PNG
+	Text       string   // hex color of labels
+}
+
+// githubColors are GitHub's light-mode calendar colors for levels 0-4
+var githubColors = []string{"#ebedf0", "#9be9a8", "#40c463", "#30a14e", "#216e39"}
+
+// githubDarkColors are GitHub's dark-mode calendar colors
+var githubDarkColors = []string{"#161b22", "#0e4429", "#006d32", "#26a641", "#39d353"}
+
+// Built-in themes
+var (
+	GitHubTheme     = Theme{Name: "github", Levels: githubColors, Text: "#767676"}
+	GitHubDarkTheme = Theme{Name: "github-dark", Levels: githubDarkColors, Background: "#0d1117", Text: "#8b949e"}
+	HalloweenTheme  = Theme{Name: "halloween", Levels: []string{"#ebedf0", "#ffee4a", "#ffc501", "#fe9600", "#03001c"}, Text: "#767676"}
+	// ColorblindTheme is a single-hue blue ramp, distinguishable with any form of color blindness
+	ColorblindTheme = Theme{Name: "colorblind", Levels: []string{"#ebedf0", "#c6dbef", "#6baed6", "#2171b5", "#08306b"}, Text: "#767676"}
+	// ColorblindDarkTheme is the blue ramp for dark backgrounds, brightening with activity
+	ColorblindDarkTheme = Theme{Name: "colorblind-dark", Levels: []string{"#161b22", "#0c2d6b", "#1f6feb", "#58a6ff", "#cae8ff"}, Background: "#0d1117", Text: "#8b949e"}
+	// ViridisTheme follows the viridis color map, whose levels differ in
+	// lightness as well as hue and so survive color blindness and greyscale
+	ViridisTheme = Theme{Name: "viridis", Levels: []string{"#ebedf0", "#fde725", "#35b779", "#31688e", "#440154"}, Text: "#767676"}
+)
+
+// themes indexes the built-in themes by name
+var themes = map[string]Theme{
+	GitHubTheme.Name:         GitHubTheme,
+	GitHubDarkTheme.Name:     GitHubDarkTheme,
+	HalloweenTheme.Name:      HalloweenTheme,
+	ColorblindTheme.Name:     ColorblindTheme,
+	ColorblindDarkTheme.Name: ColorblindDarkTheme,
+	ViridisTheme.Name:        ViridisTheme,
+}
+
+// LookupTheme returns the built-in theme called name
+func LookupTheme(name string) (Theme, bool) {
+	theme, ok := themes[name]
+	return theme, ok
+}
+
+// ThemeNames lists the built-in themes alphabetically
+func ThemeNames() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewTheme builds a custom theme from hex colors, validating them. Text and
+// background default to those of base.
+func NewTheme(name string, levels []string, background, text string, base Theme) (Theme, error) {
+	if len(levels) != 5 {
+		return Theme{}, fmt.Errorf("theme %s: expected 5 level colors, got %d", name, len(levels))
+	}
+	theme := Theme{Name: name, Levels: levels, Background: base.Background, Text: base.Text}
+	if background != "" {
+		theme.Background = background
+	}
+	if text != "" {
+		theme.Text = text
+	}
+	for _, hex := range append([]string{theme.Background, theme.Text}, levels...) {
+		if hex == "" {
+			continue
+		}
+		if _, err := parseHex(hex); err != nil {
+			return Theme{}, fmt.Errorf("theme %s: %w", name, err)
+		}
+	}
+	return theme, nil
+}
+
+// orDefault returns t, or fallback when t is the zero Theme
+func (t Theme) orDefault(fallback Theme) Theme {
+	if len(t.Levels) == 0 {
+		return fallback
+	}
+	return t
+}
+
+// color returns the hex color of level
+func (t Theme) color(level int) string {
+	return levelColor(t.Levels, level)
+}
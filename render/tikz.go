@@ -0,0 +1,144 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+	"github.com/JyotinderSingh/gitgraphed/locale"
+)
+
+// TeXLength is a length in one of TeX's absolute units, e.g. 2.5mm
+type TeXLength struct {
+	Value float64
+	Unit  string // pt, mm, cm, in, bp or pc
+}
+
+// texUnits are the points (TeX's pt) in each absolute unit
+var texUnits = map[string]float64{
+	"pt": 1,
+	"mm": 72.27 / 25.4,
+	"cm": 72.27 / 2.54,
+	"in": 72.27,
+	"bp": 72.27 / 72,
+	"pc": 12,
+}
+
+// ParseTeXLength parses a positive length such as 2.5mm or 7pt. Font-relative
+// units like em aren't accepted, as cells and gaps are added together.
+func ParseTeXLength(s string) (TeXLength, error) {
+	s = strings.TrimSpace(s)
+	if len(s) < 3 {
+		return TeXLength{}, fmt.Errorf("invalid length %q, expected a number and a unit such as 2.5mm", s)
+	}
+	unit := s[len(s)-2:]
+	if _, ok := texUnits[unit]; !ok {
+		return TeXLength{}, fmt.Errorf("invalid length %q, expected a unit of pt, mm, cm, in, bp or pc", s)
+	}
+	value, err := strconv.ParseFloat(strings.TrimSpace(s[:len(s)-2]), 64)
+	if err != nil || value <= 0 || math.IsInf(value, 0) {
+		return TeXLength{}, fmt.Errorf("invalid length %q, expected a positive number and a unit such as 2.5mm", s)
+	}
+	return TeXLength{Value: value, Unit: unit}, nil
+}
+
+// in converts l to unit
+func (l TeXLength) in(unit string) float64 {
+	return l.Value * texUnits[l.Unit] / texUnits[unit]
+}
+
+func (l TeXLength) String() string {
+	return texNumber(l.Value) + l.Unit
+}
+
+// TikZOptions controls TikZ output
+type TikZOptions struct {
+	CellSize  TeXLength
+	Gap       TeXLength
+	Theme     Theme          // defaults to GitHubTheme
+	Locale    *locale.Locale // month and weekday names, defaults to locale.English
+	WeekStart time.Weekday   // weekday of the first row, Sunday by default
+}
+
+// DefaultTikZOptions fit a year into 16cm, about the text width of an A4 page
+var DefaultTikZOptions = TikZOptions{CellSize: TeXLength{2.5, "mm"}, Gap: TeXLength{0.5, "mm"}}
+
+// TikZ writes the calendar as a TikZ picture for LaTeX documents, its colors
+// defined with xcolor as gitgraphed0 to gitgraphed4. Coordinates count weeks
+// and weekdays, so the picture scales with the cell size alone.
+func TikZ(w io.Writer, graph *gitgraph.ContributionGraph, opts TikZOptions) error {
+	theme := opts.Theme.orDefault(GitHubTheme)
+	grid := LayoutWeeks(graph.Days, opts.WeekStart)
+	if opts.CellSize.Value <= 0 {
+		opts.CellSize = DefaultTikZOptions.CellSize
+	}
+	if opts.Gap.Unit == "" {
+		opts.Gap = DefaultTikZOptions.Gap
+	}
+	unit := opts.CellSize.Unit
+	step := opts.CellSize.Value + opts.Gap.in(unit)
+	cell := texNumber(opts.CellSize.Value / step)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%% %s: %d contributions, drawn by gitgraphed\n", graph.Username, graph.TotalContribs)
+	b.WriteString("% Needs \\usepackage{tikz} in the preamble\n")
+	for level := range theme.Levels {
+		fmt.Fprintf(&b, "\\definecolor{gitgraphed%d}{HTML}{%s}\n", level, texHex(theme.color(level)))
+	}
+	fmt.Fprintf(&b, "\\definecolor{gitgraphedtext}{HTML}{%s}\n", texHex(theme.Text))
+	if theme.Background != "" {
+		fmt.Fprintf(&b, "\\definecolor{gitgraphedbg}{HTML}{%s}\n", texHex(theme.Background))
+	}
+	fmt.Fprintf(&b, "\\begin{tikzpicture}[x=%s%s, y=-%s%s, gitgraphed label/.style={font=\\tiny, text=gitgraphedtext, inner sep=0pt}]\n",
+		texNumber(step), unit, texNumber(step), unit)
+	if theme.Background != "" {
+		fmt.Fprintf(&b, "\\fill[gitgraphedbg] (-2.5,-1.5) rectangle (%d,9);\n", grid.Weeks+3)
+	}
+	for _, month := range grid.Months {
+		fmt.Fprintf(&b, "\\node[gitgraphed label, anchor=south west] at (%d,-0.3) {%s};\n", month.Col, texEscape(opts.Locale.Month(month.Month)))
+	}
+	for _, row := range grid.labelRows() {
+		fmt.Fprintf(&b, "\\node[gitgraphed label, anchor=east] at (-0.3,%s) {%s};\n", texNumber(float64(row)+(opts.CellSize.Value/step)/2), texEscape(opts.Locale.Weekday(grid.Weekday(row))))
+	}
+	for _, c := range grid.Cells {
+		fmt.Fprintf(&b, "\\fill[gitgraphed%d] (%d,%d) rectangle ++(%s,%s);\n", min(max(c.Day.Level, 0), len(theme.Levels)-1), c.Col, c.Row, cell, cell)
+	}
+
+	// Less-More legend under the last columns
+	x := grid.Weeks - len(theme.Levels)
+	middle := texNumber(7.5 + (opts.CellSize.Value/step)/2)
+	fmt.Fprintf(&b, "\\node[gitgraphed label, anchor=east] at (%s,%s) {Less};\n", texNumber(float64(x)-0.3), middle)
+	for level := range theme.Levels {
+		fmt.Fprintf(&b, "\\fill[gitgraphed%d] (%d,7.5) rectangle ++(%s,%s);\n", level, x+level, cell, cell)
+	}
+	fmt.Fprintf(&b, "\\node[gitgraphed label, anchor=west] at (%s,%s) {More};\n", texNumber(float64(grid.Weeks)+0.1), middle)
+	b.WriteString("\\end{tikzpicture}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// texNumber formats n with at most four decimals, trimming trailing zeros
+func texNumber(n float64) string {
+	return strconv.FormatFloat(math.Round(n*1e4)/1e4, 'f', -1, 64)
+}
+
+// texHex is a color as xcolor's HTML model wants it, e.g. 40C463
+func texHex(hex string) string {
+	c := mustParseHex(hex)
+	return fmt.Sprintf("%02X%02X%02X", c.R, c.G, c.B)
+}
+
+// texEscaper escapes the characters LaTeX treats specially in text
+var texEscaper = strings.NewReplacer(
+	`\`, `\textbackslash{}`, "&", `\&`, "%", `\%`, "$", `\$`, "#", `\#`, "_", `\_`,
+	"{", `\{`, "}", `\}`, "~", `\textasciitilde{}`, "^", `\textasciicircum{}`,
+)
+
+func texEscape(s string) string {
+	return texEscaper.Replace(s)
+}
@@ -0,0 +1,63 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+// WallpaperOptions controls desktop wallpaper output
+type WallpaperOptions struct {
+	Width, Height int          // resolution of the wallpaper in pixels
+	Theme         Theme        // defaults to GitHubDarkTheme; its background fills the screen
+	Fill          float64      // fraction of the width the calendar may take, defaulting to 0.6
+	Caption       bool         // draw the username and total above the calendar
+	WeekStart     time.Weekday // weekday of the first row, Sunday by default
+}
+
+// DefaultWallpaperOptions fit a 1440p display in the GitHub dark theme
+var DefaultWallpaperOptions = WallpaperOptions{Width: 2560, Height: 1440, Theme: GitHubDarkTheme, Fill: 0.6, Caption: true}
+
+// Wallpaper writes graph as a PNG of the given resolution with the calendar
+// centered, upscaled by the largest whole factor that fits
+func Wallpaper(w io.Writer, graph *gitgraph.ContributionGraph, opts WallpaperOptions) error {
+	return png.Encode(w, WallpaperImage(graph, opts))
+}
+
+// WallpaperImage draws the wallpaper onto a new image
+func WallpaperImage(graph *gitgraph.ContributionGraph, opts WallpaperOptions) *image.RGBA {
+	if opts.Width < 1 || opts.Height < 1 {
+		opts.Width, opts.Height = DefaultWallpaperOptions.Width, DefaultWallpaperOptions.Height
+	}
+	if opts.Fill <= 0 || opts.Fill > 1 {
+		opts.Fill = DefaultWallpaperOptions.Fill
+	}
+	theme := opts.Theme.orDefault(GitHubDarkTheme)
+	background := color.RGBA{0xff, 0xff, 0xff, 0xff}
+	if theme.Background != "" {
+		background = mustParseHex(theme.Background)
+	}
+
+	calendar := RasterImage(graph, PNGOptions{Scale: 1, Theme: theme, Caption: opts.Caption, WeekStart: opts.WeekStart})
+	size := calendar.Bounds().Size()
+	scale := int(float64(opts.Width) * opts.Fill / float64(size.X))
+	if limit := opts.Height / size.Y; scale > limit {
+		scale = limit
+	}
+	if scale < 1 {
+		scale = 1
+	}
+	calendar = upscale(calendar, scale)
+
+	img := image.NewRGBA(image.Rect(0, 0, opts.Width, opts.Height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{background}, image.Point{}, draw.Src)
+	size = calendar.Bounds().Size()
+	at := image.Pt((opts.Width-size.X)/2, (opts.Height-size.Y)/2)
+	draw.Draw(img, image.Rectangle{at, at.Add(size)}, calendar, image.Point{}, draw.Src)
+	return img
+}
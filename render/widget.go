@@ -0,0 +1,60 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+// WidgetOptions controls the profile README widget
+type WidgetOptions struct {
+	SVG SVGOptions // calendar geometry and theme
+}
+
+const widgetHeader = 44
+
+// Widget writes an SVG card for profile READMEs: the calendar topped by the
+// user's total and streaks
+func Widget(w io.Writer, graph *gitgraph.ContributionGraph, opts WidgetOptions) error {
+	theme := opts.SVG.Theme.orDefault(GitHubTheme)
+	streaks := gitgraph.ComputeStreaks(graph.Days, time.Now())
+
+	var calendar bytes.Buffer
+	if err := SVG(&calendar, graph, opts.SVG); err != nil {
+		return err
+	}
+	grid := LayoutWeeks(graph.Days, opts.SVG.WeekStart)
+	step := opts.SVG.CellSize + opts.SVG.Gap
+	width := svgLabelWidth + grid.Weeks*step
+	height := widgetHeader + svgLabelHeight + 7*step + svgLegendSpace
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		width, height, width, height)
+	if theme.Background != "" {
+		fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", theme.Background)
+	}
+	fmt.Fprintf(&b, `<g font-family="-apple-system,BlinkMacSystemFont,'Segoe UI',Helvetica,Arial,sans-serif" fill="%s">`+"\n", theme.Text)
+	fmt.Fprintf(&b, `<text x="0" y="16" font-size="14" font-weight="600">%s · %d contributions</text>`+"\n",
+		html.EscapeString(graph.Username), graph.TotalContribs)
+	fmt.Fprintf(&b, `<text x="0" y="34" font-size="11">Current streak %d %s · Longest %d %s</text>`+"\n",
+		streaks.Current.Length, dayWord(streaks.Current.Length), streaks.Longest.Length, dayWord(streaks.Longest.Length))
+	b.WriteString("</g>\n")
+	fmt.Fprintf(&b, `<g transform="translate(0 %d)">`+"\n", widgetHeader)
+	b.Write(calendar.Bytes())
+	b.WriteString("</g>\n</svg>\n")
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+func dayWord(n int) string {
+	if n == 1 {
+		return "day"
+	}
+	return "days"
+}
@@ -0,0 +1,92 @@
+package render
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+const yoyRowHeader = 18
+
+// YearOverYearSVG draws base's baseYear above current's year, both laid out by
+// ISO week with Monday rows so each column is the same week number
+func YearOverYearSVG(w io.Writer, base, current *gitgraph.ContributionGraph, baseYear, year int, opts SVGOptions) error {
+	theme := opts.Theme.orDefault(GitHubTheme)
+	step := opts.CellSize + opts.Gap
+	block := yoyRowHeader + 7*step
+	width := svgLabelWidth + 53*step
+	height := svgLabelHeight + 2*block + svgLegendSpace
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		width, height, width, height)
+	fmt.Fprintf(&b, `<style>text{font:9px -apple-system,BlinkMacSystemFont,"Segoe UI",Helvetica,Arial,sans-serif;fill:%s}</style>`+"\n", theme.Text)
+	if theme.Background != "" {
+		fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", theme.Background)
+	}
+
+	// Month labels follow the ISO weeks of the later year
+	start, _ := gitgraph.ISOYearBounds(year)
+	last := -3
+	for month := time.January; month <= time.December; month++ {
+		_, week := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC).ISOWeek()
+		col := week - 1
+		if month == time.January && time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC).Before(start) {
+			col = 0
+		}
+		if col-last < 3 {
+			continue
+		}
+		fmt.Fprintf(&b, `<text x="%d" y="%d">%s</text>`+"\n", svgLabelWidth+col*step, svgLabelHeight-5, opts.Locale.Month(month))
+		last = col
+	}
+
+	for i, row := range []struct {
+		graph *gitgraph.ContributionGraph
+		year  int
+	}{{base, baseYear}, {current, year}} {
+		top := svgLabelHeight + i*block
+		total := 0
+		var cells strings.Builder
+		for _, day := range row.graph.Days {
+			date, err := time.Parse("2006-01-02", day.Date)
+			if err != nil {
+				continue
+			}
+			if date.Year() == row.year {
+				total += day.Count
+			}
+			isoYear, week := date.ISOWeek()
+			if isoYear != row.year {
+				continue
+			}
+			weekday := (int(date.Weekday()) + 6) % 7
+			fmt.Fprintf(&cells, `<rect x="%d" y="%d" width="%d" height="%d" rx="%d" ry="%d" fill="%s" data-date="%s" data-count="%d"/>`+"\n",
+				svgLabelWidth+(week-1)*step, top+yoyRowHeader+weekday*step, opts.CellSize, opts.CellSize,
+				opts.Radius, opts.Radius, theme.color(day.Level), day.Date, day.Count)
+		}
+		fmt.Fprintf(&b, `<text x="%d" y="%d" font-weight="600">%s %d · %d contributions</text>`+"\n",
+			svgLabelWidth, top+yoyRowHeader-6, html.EscapeString(row.graph.Username), row.year, total)
+		for r, day := range []time.Weekday{time.Monday, time.Wednesday, time.Friday} {
+			fmt.Fprintf(&b, `<text x="0" y="%d">%s</text>`+"\n", top+yoyRowHeader+r*2*step+opts.CellSize-1, opts.Locale.Weekday(day))
+		}
+		b.WriteString(cells.String())
+	}
+
+	legendY := svgLabelHeight + 2*block + 5
+	legendX := width - len(theme.Levels)*step - 30
+	fmt.Fprintf(&b, `<text x="%d" y="%d" text-anchor="end">Less</text>`+"\n", legendX-4, legendY+opts.CellSize-1)
+	for i, color := range theme.Levels {
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" rx="%d" ry="%d" fill="%s"/>`+"\n",
+			legendX+i*step, legendY, opts.CellSize, opts.CellSize, opts.Radius, opts.Radius, color)
+	}
+	fmt.Fprintf(&b, `<text x="%d" y="%d">More</text>`+"\n", legendX+len(theme.Levels)*step+2, legendY+opts.CellSize-1)
+	b.WriteString("</svg>\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"io"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+	"github.com/JyotinderSingh/gitgraphed/locale"
+	"github.com/JyotinderSingh/gitgraphed/render"
+)
+
+// reportHeatmapWeeks is how much history each user's report heatmap shows
+const reportHeatmapWeeks = 12
+
+// weeklyReport summarises a Monday-Sunday week for a team of users
+type weeklyReport struct {
+	From   time.Time
+	To     time.Time
+	Users  []userReport
+	Locale *locale.Locale // weekday names and dates
+}
+
+// userReport is one user's section of a weekly report
+type userReport struct {
+	Username string
+	Total    int
+	Previous int
+	Delta    string
+	Current  int // streak length at the end of the week
+	Longest  int // longest streak of the past year
+	Daily    []reportDay
+	Heatmap  []byte       // PNG of the weeks leading up to the report
+	ImageSrc template.URL // where the HTML finds Heatmap, set before rendering
+}
+
+// reportDay is one day's count in a weekly report
+type reportDay struct {
+	Weekday string
+	Count   int
+}
+
+// lastWeek returns the Monday and Sunday of the week before the one containing now
+func lastWeek(now time.Time) (time.Time, time.Time) {
+	today := truncateDay(now)
+	monday := today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+	return monday.AddDate(0, 0, -7), monday.AddDate(0, 0, -1)
+}
+
+// buildWeeklyReport fetches the year up to the end of last week for each user
+// and summarises that week, naming days and dates in loc
+func buildWeeklyReport(ctx context.Context, client *gitgraph.Client, usernames []string, now time.Time, workers int, loc *locale.Locale) (*weeklyReport, error) {
+	from, to := lastWeek(now)
+	graphs, err := client.FetchUsers(ctx, usernames, gitgraph.Options{From: to.AddDate(-1, 0, 1), To: to}, workers)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &weeklyReport{From: from, To: to, Locale: loc}
+	for _, graph := range graphs {
+		counts := countsByDate(graph.Days)
+		week := windowCounts(counts, from, to)
+		previous := sum(windowCounts(counts, from.AddDate(0, 0, -7), from.AddDate(0, 0, -1)))
+		streaks := gitgraph.ComputeStreaks(graph.Days, to)
+
+		user := userReport{
+			Username: graph.Username,
+			Total:    sum(week),
+			Previous: previous,
+			Delta:    formatDelta(sum(week), previous),
+			Current:  streaks.Current.Length,
+			Longest:  streaks.Longest.Length,
+		}
+		for i, count := range week {
+			user.Daily = append(user.Daily, reportDay{Weekday: loc.Weekday(from.AddDate(0, 0, i).Weekday()), Count: count})
+		}
+		var heatmap bytes.Buffer
+		if err := render.PNG(&heatmap, recentWeeks(graph, to, reportHeatmapWeeks), render.PNGOptions{Scale: 2, Theme: render.GitHubTheme, Locale: loc}); err != nil {
+			return nil, err
+		}
+		user.Heatmap = heatmap.Bytes()
+		report.Users = append(report.Users, user)
+	}
+	return report, nil
+}
+
+// Subject is the email subject line for the report
+func (r *weeklyReport) Subject() string {
+	return fmt.Sprintf("Contributions for the week of %s", r.Locale.FormatDate(r.From))
+}
+
+// embedImages points each heatmap at a data URI, for reports opened as a file
+func (r *weeklyReport) embedImages() {
+	for i := range r.Users {
+		r.Users[i].ImageSrc = template.URL("data:image/png;base64," + base64.StdEncoding.EncodeToString(r.Users[i].Heatmap))
+	}
+}
+
+// heatmapCID is the Content-ID of user i's heatmap in an emailed report
+func heatmapCID(i int) string {
+	return fmt.Sprintf("heatmap-%d@gitgraphed", i)
+}
+
+// HTML writes the report as an HTML document with inline styles, as email
+// clients ignore stylesheets
+func (r *weeklyReport) HTML(w io.Writer) error {
+	return reportTemplate.Execute(w, r)
+}
+
+var reportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<body style="margin:0;padding:24px;background:#f6f8fa;font-family:-apple-system,Segoe UI,Helvetica,Arial,sans-serif;color:#24292f">
+<h1 style="font-size:20px;margin:0 0 4px">{{.Subject}}</h1>
+<p style="margin:0 0 24px;color:#57606a">{{.From.Format "Mon Jan 2"}} – {{.To.Format "Mon Jan 2, 2006"}}</p>
+{{range .Users}}
+<div style="background:#fff;border:1px solid #d0d7de;border-radius:6px;padding:16px;margin-bottom:16px">
+<h2 style="font-size:16px;margin:0 0 12px">{{.Username}}</h2>
+<table style="border-collapse:collapse;margin-bottom:12px">
+<tr><td style="padding:2px 16px 2px 0;color:#57606a">Contributions</td><td><b>{{.Total}}</b> ({{.Delta}} vs the week before)</td></tr>
+<tr><td style="padding:2px 16px 2px 0;color:#57606a">Current streak</td><td>{{.Current}} {{if eq .Current 1}}day{{else}}days{{end}}</td></tr>
+<tr><td style="padding:2px 16px 2px 0;color:#57606a">Longest streak</td><td>{{.Longest}} {{if eq .Longest 1}}day{{else}}days{{end}}</td></tr>
+</table>
+<table style="border-collapse:collapse;margin-bottom:12px;text-align:center">
+<tr>{{range .Daily}}<td style="padding:2px 8px;color:#57606a;font-size:12px">{{.Weekday}}</td>{{end}}</tr>
+<tr>{{range .Daily}}<td style="padding:2px 8px">{{.Count}}</td>{{end}}</tr>
+</table>
+<img src="{{.ImageSrc}}" alt="{{.Username}}'s recent contributions" style="display:block;max-width:100%">
+</div>
+{{end}}
+</body>
+</html>
+`))
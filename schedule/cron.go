@@ -0,0 +1,193 @@
+// Package schedule parses cron expressions and computes their run times
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed five-field cron expression: minute, hour, day of month,
+// month and day of week
+type Schedule struct {
+	spec   string
+	minute uint64
+	hour   uint64
+	dom    uint64
+	month  uint64
+	dow    uint64
+	anyDom bool
+	anyDow bool
+}
+
+// shortcuts are the @-prefixed aliases accepted by Parse
+var shortcuts = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// field bounds, in Schedule field order
+var bounds = []struct {
+	name     string
+	min, max int
+}{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// Parse reads a cron expression such as "0 8 * * 1-5" or "@daily". Fields
+// accept *, lists, ranges and steps; Sunday is 0 or 7. As in cron, when both
+// day of month and day of week are restricted a day matching either runs.
+func Parse(spec string) (*Schedule, error) {
+	expr := strings.TrimSpace(spec)
+	if alias, ok := shortcuts[expr]; ok {
+		expr = alias
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != len(bounds) {
+		return nil, fmt.Errorf("cron expression %q: expected 5 fields, got %d", spec, len(fields))
+	}
+
+	s := &Schedule{spec: spec}
+	sets := []*uint64{&s.minute, &s.hour, &s.dom, &s.month, &s.dow}
+	for i, field := range fields {
+		set, err := parseField(field, bounds[i].min, bounds[i].max)
+		if err != nil {
+			return nil, fmt.Errorf("cron expression %q: %s: %w", spec, bounds[i].name, err)
+		}
+		*sets[i] = set
+	}
+	// 7 is another name for Sunday
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	s.anyDom = strings.HasPrefix(fields[2], "*")
+	s.anyDow = strings.HasPrefix(fields[4], "*")
+	return s, nil
+}
+
+// parseField returns the bitset of values matched by a comma-separated field
+func parseField(field string, min, max int) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if before, after, ok := strings.Cut(part, "/"); ok {
+			n, err := strconv.Atoi(after)
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("invalid step %q", after)
+			}
+			rangePart, step = before, n
+		}
+
+		lo, hi := min, max
+		if rangePart != "*" {
+			var err error
+			if before, after, ok := strings.Cut(rangePart, "-"); ok {
+				if lo, err = strconv.Atoi(before); err != nil {
+					return 0, fmt.Errorf("invalid value %q", before)
+				}
+				if hi, err = strconv.Atoi(after); err != nil {
+					return 0, fmt.Errorf("invalid value %q", after)
+				}
+			} else {
+				if lo, err = strconv.Atoi(rangePart); err != nil {
+					return 0, fmt.Errorf("invalid value %q", rangePart)
+				}
+				hi = lo
+				// A bare value with a step, like 5/15, runs from the value to the maximum
+				if step > 1 {
+					hi = max
+				}
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("%q out of range %d-%d", rangePart, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			set |= 1 << v
+		}
+	}
+	return set, nil
+}
+
+// String returns the expression the schedule was parsed from
+func (s *Schedule) String() string {
+	return s.spec
+}
+
+// maxSearch bounds Next and Prev; every valid expression matches within a few
+// years, except impossible dates like February 30th
+const maxSearch = 5 * 366 * 24 * time.Hour
+
+// Next returns the first run time strictly after t, in t's location, or the
+// zero time if the expression never matches
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(maxSearch)
+	for t.Before(limit) {
+		switch {
+		case !has(s.month, int(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case !has(s.hour, t.Hour()):
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case !has(s.minute, t.Minute()):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// Prev returns the last run time at or before t, or the zero time if there is
+// none within the search window
+func (s *Schedule) Prev(t time.Time) time.Time {
+	t = t.Truncate(time.Minute)
+	limit := t.Add(-maxSearch)
+	for t.After(limit) {
+		switch {
+		case !has(s.month, int(t.Month())):
+			// Last minute of the previous month
+			t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location()).Add(-time.Minute)
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location()).Add(-time.Minute)
+		case !has(s.hour, t.Hour()):
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location()).Add(-time.Minute)
+		case !has(s.minute, t.Minute()):
+			t = t.Add(-time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// dayMatches applies cron's rule for combining day of month and day of week
+func (s *Schedule) dayMatches(t time.Time) bool {
+	dom := has(s.dom, t.Day())
+	dow := has(s.dow, int(t.Weekday()))
+	switch {
+	case s.anyDom && s.anyDow:
+		return true
+	case s.anyDom:
+		return dow
+	case s.anyDow:
+		return dom
+	}
+	return dom || dow
+}
+
+func has(set uint64, v int) bool {
+	return set&(1<<v) != 0
+}
@@ -0,0 +1,107 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+// admin guards an operator endpoint: it answers 404 unless AdminKeys are set,
+// so the endpoints aren't there at all otherwise, and 401 without one of them
+func (s *Server) admin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if len(s.AdminKeys) == 0 {
+			http.NotFound(w, r)
+			return
+		}
+		if !s.AdminKeys[requestAPIKey(r)] {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="gitgraphed admin"`)
+			http.Error(w, "admin key required", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// handleCacheList serves GET /admin/cache, listing the cached graphs, only
+// those of ?username= when given
+func (s *Server) handleCacheList(w http.ResponseWriter, r *http.Request) {
+	graphs, err := s.Client.CachedGraphs()
+	if err != nil {
+		cacheAdminError(w, err)
+		return
+	}
+	if username := r.URL.Query().Get("username"); username != "" {
+		matching := []gitgraph.CachedGraph{}
+		for _, graph := range graphs {
+			if strings.EqualFold(graph.Username, username) {
+				matching = append(matching, graph)
+			}
+		}
+		graphs = matching
+	}
+	size := 0
+	for _, graph := range graphs {
+		size += graph.Size
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"graphs": graphs, "count": len(graphs), "bytes": size})
+}
+
+// handleCacheInvalidate serves DELETE /admin/cache/{username} and
+// /admin/cache/{username}/{year}, dropping the user's cached graphs
+func (s *Server) handleCacheInvalidate(w http.ResponseWriter, r *http.Request) {
+	year := 0
+	if yearStr := r.PathValue("year"); yearStr != "" {
+		var err error
+		if year, err = strconv.Atoi(yearStr); err != nil {
+			http.Error(w, "invalid year", http.StatusBadRequest)
+			return
+		}
+	}
+	username := r.PathValue("username")
+	dropped, err := s.Client.Invalidate(username, year)
+	if err != nil {
+		cacheAdminError(w, err)
+		return
+	}
+	slog.Info("invalidated cached graphs", "username", username, "year", year, "graphs", dropped)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"deleted": dropped})
+}
+
+// cacheAdminError answers 501 when the cache can't be administered and 500
+// when it fails
+func cacheAdminError(w http.ResponseWriter, err error) {
+	if errors.Is(err, gitgraph.ErrCacheNotListable) {
+		http.Error(w, "the cache is disabled or can't list its entries", http.StatusNotImplemented)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
+
+// Warm fetches the current year of each of usernames, as /{username}.svg
+// serves by default, so their first requests are answered from the cache.
+// Failures are logged and don't stop the rest.
+func (s *Server) Warm(ctx context.Context, usernames []string) {
+	start := time.Now()
+	warmed := 0
+	for _, username := range usernames {
+		if _, err := s.Client.Fetch(ctx, username, gitgraph.Options{Year: time.Now().Year()}); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			slog.Warn("warming the cache", "username", username, "err", err)
+			continue
+		}
+		warmed++
+	}
+	slog.Info("warmed the cache", "users", warmed, "failed", len(usernames)-warmed, "duration", time.Since(start).Round(time.Millisecond))
+}
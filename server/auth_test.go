@@ -0,0 +1,92 @@
+package server
+
+import (
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAuthenticatorMiddleware(t *testing.T) {
+	auth := &Authenticator{
+		APIKeys:     map[string]bool{"static": true},
+		SigningKeys: map[string][]byte{"signer": []byte("secret")},
+		Required:    true,
+	}
+	now := time.Now().Unix()
+	const body = `{"query":"{ user(username: \"alice\") { total } }"}`
+	signature := func(method, uri string, timestamp int64, body string) string {
+		return hex.EncodeToString(Sign([]byte("secret"), method, uri, timestamp, []byte(body)))
+	}
+
+	for _, tt := range []struct {
+		name      string
+		key       string
+		bearer    string
+		timestamp int64
+		signature string
+		body      string
+		want      int
+	}{
+		{name: "no key", want: http.StatusUnauthorized},
+		{name: "static key", key: "static", want: http.StatusOK},
+		{name: "bearer token", bearer: "static", want: http.StatusOK},
+		{name: "unknown key", key: "nope", want: http.StatusUnauthorized},
+		{name: "signed", key: "signer", timestamp: now, signature: signature("POST", "/graphql", now, body), body: body, want: http.StatusOK},
+		{name: "signed without a signature", key: "signer", timestamp: now, body: body, want: http.StatusUnauthorized},
+		{name: "another body", key: "signer", timestamp: now, signature: signature("POST", "/graphql", now, body), body: `{"query":"{ other }"}`, want: http.StatusUnauthorized},
+		{name: "another path", key: "signer", timestamp: now, signature: signature("POST", "/graphql?x=1", now, body), body: body, want: http.StatusUnauthorized},
+		{name: "stale", key: "signer", timestamp: now - 600, signature: signature("POST", "/graphql", now-600, body), body: body, want: http.StatusUnauthorized},
+		{name: "ahead", key: "signer", timestamp: now + 600, signature: signature("POST", "/graphql", now+600, body), body: body, want: http.StatusUnauthorized},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			handler := auth.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// The body survives being read for the signature
+				b, _ := io.ReadAll(r.Body)
+				got = string(b)
+			}))
+			r := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(tt.body))
+			if tt.key != "" {
+				r.Header.Set("X-API-Key", tt.key)
+			}
+			if tt.bearer != "" {
+				r.Header.Set("Authorization", "Bearer "+tt.bearer)
+			}
+			if tt.timestamp != 0 {
+				r.Header.Set("X-Timestamp", strconv.FormatInt(tt.timestamp, 10))
+			}
+			if tt.signature != "" {
+				r.Header.Set("X-Signature", tt.signature)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d", w.Code, tt.want)
+			}
+			if w.Code == http.StatusOK && got != tt.body {
+				t.Errorf("handler read body %q, want %q", got, tt.body)
+			}
+		})
+	}
+}
+
+func TestAuthenticatorOptional(t *testing.T) {
+	auth := &Authenticator{APIKeys: map[string]bool{"static": true}}
+	handler := auth.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	for key, want := range map[string]int{"": http.StatusOK, "static": http.StatusOK, "nope": http.StatusUnauthorized} {
+		r := httptest.NewRequest(http.MethodGet, "/alice.svg", nil)
+		if key != "" {
+			r.Header.Set("X-API-Key", key)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if w.Code != want {
+			t.Errorf("key %q: status = %d, want %d", key, w.Code, want)
+		}
+	}
+}
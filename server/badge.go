@@ -0,0 +1,96 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+	"github.com/JyotinderSingh/gitgraphed/telemetry"
+)
+
+// badge is a shields.io endpoint response (https://shields.io/badges/endpoint-badge)
+type badge struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+}
+
+// handleBadge serves /badge/{username}/{metric} as shields.io endpoint JSON,
+// or as a rendered SVG badge when metric has an .svg suffix
+func (s *Server) handleBadge(w http.ResponseWriter, r *http.Request) {
+	metric, asSVG := strings.CutSuffix(r.PathValue("metric"), ".svg")
+	if metric != "streak" && metric != "total" {
+		http.NotFound(w, r)
+		return
+	}
+
+	// Badges cover the past year, like the profile calendar
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	graph, err := s.Client.Fetch(r.Context(), r.PathValue("username"), gitgraph.Options{From: today.AddDate(-1, 0, 1), To: today})
+	if err != nil {
+		upstreamError(w, err)
+		return
+	}
+	markStale(w, graph)
+	if s.cacheHeaders(w, r, graph) {
+		return
+	}
+
+	b := badge{SchemaVersion: 1, Color: "brightgreen"}
+	switch metric {
+	case "streak":
+		length := gitgraph.ComputeStreaks(graph.Days, today).Current.Length
+		b.Label = "streak"
+		b.Message = fmt.Sprintf("%d days", length)
+		if length == 1 {
+			b.Message = "1 day"
+		}
+		if length == 0 {
+			b.Color = "lightgrey"
+		}
+	case "total":
+		b.Label = "contributions"
+		b.Message = fmt.Sprintf("%d in the last year", graph.TotalContribs)
+	}
+
+	if asSVG {
+		w.Header().Set("Content-Type", "image/svg+xml")
+		_, span := telemetry.Start(r.Context(), "render.badge", telemetry.KindInternal)
+		writeBadgeSVG(w, b)
+		span.End(nil)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(b)
+}
+
+// badgeColors maps the shields.io color names used here to hex values
+var badgeColors = map[string]string{
+	"brightgreen": "#4c1",
+	"lightgrey":   "#9f9f9f",
+}
+
+// writeBadgeSVG draws a flat two-part badge in the shields.io style. Text width
+// is estimated per character since the badge font isn't measured.
+func writeBadgeSVG(w io.Writer, b badge) {
+	const charWidth, padding = 7, 10
+	labelWidth := len(b.Label)*charWidth + padding
+	messageWidth := len(b.Message)*charWidth + padding
+	width := labelWidth + messageWidth
+
+	fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s: %s">`,
+		width, html.EscapeString(b.Label), html.EscapeString(b.Message))
+	fmt.Fprintf(w, `<rect width="%d" height="20" rx="3" fill="#555"/>`, width)
+	fmt.Fprintf(w, `<rect x="%d" width="%d" height="20" rx="3" fill="%s"/>`, labelWidth, messageWidth, badgeColors[b.Color])
+	fmt.Fprintf(w, `<rect x="%d" width="4" height="20" fill="%s"/>`, labelWidth, badgeColors[b.Color])
+	fmt.Fprint(w, `<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">`)
+	fmt.Fprintf(w, `<text x="%d" y="14">%s</text>`, labelWidth/2, html.EscapeString(b.Label))
+	fmt.Fprintf(w, `<text x="%d" y="14">%s</text>`, labelWidth+messageWidth/2, html.EscapeString(b.Message))
+	fmt.Fprint(w, "</g></svg>\n")
+}
@@ -0,0 +1,80 @@
+package server
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+// staleMaxAge is how long a response built from a stale graph may be cached,
+// so clients come back soon for a fresh one once upstream recovers
+const staleMaxAge = time.Minute
+
+// cacheHeaders sets Cache-Control, ETag and Last-Modified on a response built
+// from graph, and answers 304 Not Modified when the request's validators show
+// the client already has it. It reports whether the response was written.
+func (s *Server) cacheHeaders(w http.ResponseWriter, r *http.Request, graph *gitgraph.ContributionGraph) bool {
+	if graph.FetchedAt == nil {
+		w.Header().Set("Cache-Control", "no-cache")
+		return false
+	}
+	fetchedAt := *graph.FetchedAt
+	now := time.Now()
+
+	// Fresh until the cached graph would be fetched again
+	maxAge := s.Client.CacheTTL - now.Sub(fetchedAt)
+	if graph.Stale {
+		maxAge = staleMaxAge
+	}
+	maxAge = max(maxAge, 0)
+	etag := graphETag(r, fetchedAt, now)
+	scope := "public"
+	if s.Private {
+		scope = "private"
+		w.Header().Add("Vary", "Authorization, X-API-Key")
+	}
+	w.Header().Set("Cache-Control", fmt.Sprintf("%s, max-age=%d", scope, int(maxAge.Seconds())))
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Last-Modified", fetchedAt.Format(http.TimeFormat))
+
+	if !notModified(r, etag, fetchedAt) {
+		return false
+	}
+	// A 304 carries no body, so the headers describing one go
+	w.Header().Del("Content-Type")
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// graphETag tags a response by the snapshot it was built from. Streaks and
+// anomalies are counted up to today, so it also changes each day. It is weak
+// since equal tags promise the same content, not the same bytes.
+func graphETag(r *http.Request, fetchedAt, now time.Time) string {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%s\n%d\n%s", r.URL.RequestURI(), fetchedAt.Unix(), now.UTC().Format("2006-01-02"))
+	return fmt.Sprintf(`W/"%x"`, h.Sum64())
+}
+
+// notModified evaluates If-None-Match, or If-Modified-Since without it, as
+// RFC 9110 section 13.2.2 orders them
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+	if header := r.Header.Get("If-None-Match"); header != "" {
+		for _, tag := range strings.Split(header, ",") {
+			tag = strings.TrimSpace(tag)
+			// Weak comparison: W/ prefixes are ignored on either side
+			if tag == "*" || strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+		return false
+	}
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	return err == nil && !lastModified.After(since)
+}
@@ -0,0 +1,102 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+func TestCacheHeaders(t *testing.T) {
+	fetchedAt := time.Now().Add(-10 * time.Minute).UTC().Truncate(time.Second)
+	client := gitgraph.NewClient(nil)
+	client.CacheTTL = time.Hour
+
+	for _, tt := range []struct {
+		name    string
+		graph   gitgraph.ContributionGraph
+		private bool
+		scope   string // of Cache-Control, with max-age unless no-cache
+		maxAge  int
+		vary    string
+	}{
+		{name: "uncached", graph: gitgraph.ContributionGraph{}, scope: "no-cache"},
+		{name: "fresh", graph: gitgraph.ContributionGraph{FetchedAt: &fetchedAt}, scope: "public", maxAge: 3000},
+		{name: "stale", graph: gitgraph.ContributionGraph{FetchedAt: &fetchedAt, Stale: true}, scope: "public", maxAge: 60},
+		{name: "private", graph: gitgraph.ContributionGraph{FetchedAt: &fetchedAt}, private: true, scope: "private", maxAge: 3000, vary: "Authorization, X-API-Key"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{Client: client, Private: tt.private}
+			w := httptest.NewRecorder()
+			if s.cacheHeaders(w, httptest.NewRequest(http.MethodGet, "/alice.svg", nil), &tt.graph) {
+				t.Fatal("answered without validators")
+			}
+			want := tt.scope
+			if tt.maxAge > 0 {
+				want = fmt.Sprintf("%s, max-age=%d", tt.scope, tt.maxAge)
+			}
+			// max-age counts down while the test runs, so a second less will do
+			got := w.Header().Get("Cache-Control")
+			if got != want && got != fmt.Sprintf("%s, max-age=%d", tt.scope, tt.maxAge-1) {
+				t.Errorf("Cache-Control = %q, want %q", got, want)
+			}
+			if vary := w.Header().Get("Vary"); vary != tt.vary {
+				t.Errorf("Vary = %q, want %q", vary, tt.vary)
+			}
+			if tt.graph.FetchedAt != nil && w.Header().Get("Last-Modified") != fetchedAt.Format(http.TimeFormat) {
+				t.Errorf("Last-Modified = %q", w.Header().Get("Last-Modified"))
+			}
+		})
+	}
+}
+
+func TestCacheHeadersNotModified(t *testing.T) {
+	fetchedAt := time.Now().Add(-10 * time.Minute).UTC().Truncate(time.Second)
+	s := &Server{Client: gitgraph.NewClient(nil)}
+	graph := &gitgraph.ContributionGraph{FetchedAt: &fetchedAt}
+
+	first := httptest.NewRecorder()
+	s.cacheHeaders(first, httptest.NewRequest(http.MethodGet, "/alice.svg", nil), graph)
+	etag := first.Header().Get("ETag")
+	if !strings.HasPrefix(etag, `W/"`) {
+		t.Fatalf("ETag = %q, want a weak tag", etag)
+	}
+
+	for _, tt := range []struct {
+		name, method, target string
+		headers              map[string]string
+		want                 bool
+	}{
+		{"matching tag", "GET", "/alice.svg", map[string]string{"If-None-Match": etag}, true},
+		{"strong form of the tag", "GET", "/alice.svg", map[string]string{"If-None-Match": strings.TrimPrefix(etag, "W/")}, true},
+		{"one of several tags", "GET", "/alice.svg", map[string]string{"If-None-Match": `W/"other", ` + etag}, true},
+		{"any tag", "HEAD", "/alice.svg", map[string]string{"If-None-Match": "*"}, true},
+		{"other tag", "GET", "/alice.svg", map[string]string{"If-None-Match": `W/"other"`}, false},
+		// Tags cover the URI, query included
+		{"other URI", "GET", "/alice.svg?theme=dark", map[string]string{"If-None-Match": etag}, false},
+		{"modified since", "GET", "/alice.svg", map[string]string{"If-Modified-Since": fetchedAt.Add(-time.Second).Format(http.TimeFormat)}, false},
+		{"not modified since", "GET", "/alice.svg", map[string]string{"If-Modified-Since": fetchedAt.Format(http.TimeFormat)}, true},
+		// If-None-Match wins over If-Modified-Since
+		{"tag over date", "GET", "/alice.svg", map[string]string{"If-None-Match": `W/"other"`, "If-Modified-Since": fetchedAt.Format(http.TimeFormat)}, false},
+		{"POST", "POST", "/alice.svg", map[string]string{"If-None-Match": etag}, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, tt.target, nil)
+			for key, value := range tt.headers {
+				r.Header.Set(key, value)
+			}
+			w := httptest.NewRecorder()
+			w.Header().Set("Content-Type", "image/svg+xml")
+			if got := s.cacheHeaders(w, r, graph); got != tt.want {
+				t.Fatalf("cacheHeaders = %v, want %v", got, tt.want)
+			}
+			if tt.want && (w.Code != http.StatusNotModified || w.Header().Get("Content-Type") != "") {
+				t.Errorf("status %d with Content-Type %q, want 304 without", w.Code, w.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package server
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// DefaultCORSMethods are the methods allowed from the browser when none are configured
+var DefaultCORSMethods = []string{http.MethodGet, http.MethodPost, http.MethodOptions}
+
+// CORS lets pages on the allowed origins call next from the browser with the
+// allowed methods, DefaultCORSMethods when empty; "*" allows every origin.
+// Preflight requests are answered without reaching next.
+func CORS(allowedOrigins, allowedMethods []string, next http.Handler) http.Handler {
+	anyOrigin := slices.Contains(allowedOrigins, "*")
+	if len(allowedMethods) == 0 {
+		allowedMethods = DefaultCORSMethods
+	}
+	methods := strings.ToUpper(strings.Join(allowedMethods, ", "))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" || !anyOrigin && !slices.Contains(allowedOrigins, origin) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Add("Vary", "Origin")
+		if anyOrigin {
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		} else {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+		}
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			w.Header().Set("Access-Control-Allow-Methods", methods)
+			w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type, X-API-Key, X-Timestamp, X-Signature, If-None-Match, If-Modified-Since")
+			w.Header().Set("Access-Control-Max-Age", "86400")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		// Let scripts read the validators to make conditional requests
+		w.Header().Set("Access-Control-Expose-Headers", "ETag, Last-Modified, X-Stale")
+		next.ServeHTTP(w, r)
+	})
+}
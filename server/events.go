@@ -0,0 +1,233 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+// Users with open /events streams are each polled upstream, so how many can
+// be watched at once, and how many streams one client can hold, are capped
+const (
+	DefaultMaxWatches       = 100
+	DefaultMaxClientStreams = 4
+)
+
+var (
+	errTooManyWatches = errors.New("too many users are being watched, try again later")
+	errTooManyStreams = errors.New("too many open event streams from this client")
+)
+
+// sseKeepalive is how often an idle event stream gets a comment, so proxies
+// don't close it between polls
+const sseKeepalive = 30 * time.Second
+
+// dayEvent is one Server-Sent Event of /events/{username}
+type dayEvent struct {
+	Type     string    `json:"type"` // snapshot, change or error
+	Time     time.Time `json:"time"`
+	Username string    `json:"username"`
+	Total    int       `json:"totalContributions,omitempty"`
+	// Days are every day of the rolling year in a snapshot, and the days
+	// whose count changed in a change
+	Days  []gitgraph.ContributionDay `json:"days,omitempty"`
+	Error string                     `json:"error,omitempty"`
+}
+
+// userWatch polls one user for every stream subscribed to them
+type userWatch struct {
+	subscribers map[chan dayEvent]bool
+	latest      *gitgraph.ContributionGraph // from the last successful poll
+	stop        context.CancelFunc
+}
+
+// handleEvents serves /events/{username}, streaming the user's rolling year as
+// Server-Sent Events: a snapshot first, then a change event whenever a poll
+// finds days whose count changed
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	username := r.PathValue("username")
+	if err := validUsername(username); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	events, unsubscribe, err := s.subscribe(username, clientAddr(r.Header.Get("X-Forwarded-For"), r.RemoteAddr, s.TrustProxy))
+	switch {
+	case errors.Is(err, errTooManyStreams):
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+		return
+	case err != nil:
+		w.Header().Set("Retry-After", "60")
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	// Ask nginx not to buffer the stream
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepalive := time.NewTicker(sseKeepalive)
+	defer keepalive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event := <-events:
+			data, err := json.Marshal(event)
+			if err != nil {
+				slog.Error("encoding event", "err", err)
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+				return
+			}
+		case <-keepalive.C:
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// subscribe returns a channel of username's events for a stream from client,
+// starting to poll them unless another stream already is. A stream joining
+// late gets a snapshot of the last poll. unsubscribe stops the polling once no
+// stream is left. It fails once client has MaxClientStreams open, or when
+// username would be one watch more than MaxWatches.
+func (s *Server) subscribe(username, client string) (events <-chan dayEvent, unsubscribe func(), err error) {
+	ch := make(chan dayEvent, 16)
+	s.watchMu.Lock()
+	defer s.watchMu.Unlock()
+	if s.streams[client] >= orDefault(s.MaxClientStreams, DefaultMaxClientStreams) {
+		return nil, nil, errTooManyStreams
+	}
+	watch, ok := s.watches[username]
+	if !ok && len(s.watches) >= orDefault(s.MaxWatches, DefaultMaxWatches) {
+		return nil, nil, errTooManyWatches
+	}
+	if !ok {
+		ctx, stop := context.WithCancel(context.Background())
+		watch = &userWatch{subscribers: map[chan dayEvent]bool{}, stop: stop}
+		s.watches[username] = watch
+		go s.poll(ctx, username, watch)
+	}
+	watch.subscribers[ch] = true
+	s.streams[client]++
+	if watch.latest != nil {
+		ch <- snapshotEvent(watch.latest)
+	}
+	return ch, func() {
+		s.watchMu.Lock()
+		defer s.watchMu.Unlock()
+		delete(watch.subscribers, ch)
+		if len(watch.subscribers) == 0 {
+			watch.stop()
+			delete(s.watches, username)
+		}
+		if s.streams[client]--; s.streams[client] <= 0 {
+			delete(s.streams, client)
+		}
+	}, nil
+}
+
+// orDefault is n, or fallback when n isn't positive
+func orDefault(n, fallback int) int {
+	if n > 0 {
+		return n
+	}
+	return fallback
+}
+
+// poll fetches username's rolling year every WatchInterval until stopped,
+// broadcasting what changed to the watch's subscribers
+func (s *Server) poll(ctx context.Context, username string, watch *userWatch) {
+	interval := s.WatchInterval
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	// Every poll must reach upstream, without changing the shared client
+	client := *s.Client
+	client.Refresh = true
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		today := time.Now().UTC().Truncate(24 * time.Hour)
+		graph, err := client.Fetch(ctx, username, gitgraph.Options{From: today.AddDate(-1, 0, 1), To: today})
+		if graph != nil && graph.Stale {
+			// Report the outage rather than diff a cached graph
+			graph, err = nil, gitgraph.ErrCircuitOpen
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		s.watchMu.Lock()
+		var event *dayEvent
+		switch {
+		case err != nil:
+			event = &dayEvent{Type: "error", Error: err.Error()}
+		case watch.latest == nil:
+			snapshot := snapshotEvent(graph)
+			event = &snapshot
+		default:
+			if days := changedDays(watch.latest, graph); len(days) > 0 {
+				event = &dayEvent{Type: "change", Total: graph.TotalContribs, Days: days}
+			}
+		}
+		if graph != nil {
+			watch.latest = graph
+		}
+		if event != nil {
+			event.Time, event.Username = time.Now(), username
+			for ch := range watch.subscribers {
+				// A stream too slow to take it misses the event rather than hold up the rest
+				select {
+				case ch <- *event:
+				default:
+				}
+			}
+		}
+		s.watchMu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func snapshotEvent(graph *gitgraph.ContributionGraph) dayEvent {
+	return dayEvent{Type: "snapshot", Time: time.Now(), Username: graph.Username, Total: graph.TotalContribs, Days: graph.Days}
+}
+
+// changedDays are the days of current whose count differs from previous
+func changedDays(previous, current *gitgraph.ContributionGraph) []gitgraph.ContributionDay {
+	changed := map[string]bool{}
+	for _, change := range gitgraph.DiffDays(previous, current) {
+		changed[change.Date] = true
+	}
+	var days []gitgraph.ContributionDay
+	for _, day := range current.Days {
+		if changed[day.Date] {
+			days = append(days, day)
+		}
+	}
+	return days
+}
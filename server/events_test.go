@@ -0,0 +1,61 @@
+package server
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+func TestSubscribeLimits(t *testing.T) {
+	client := gitgraph.NewClient(nil)
+	client.Provider = gitgraph.NewFake(1)
+	s := New(client)
+	s.MaxWatches, s.MaxClientStreams = 2, 2
+
+	var unsubscribes []func()
+	defer func() {
+		for _, unsubscribe := range unsubscribes {
+			unsubscribe()
+		}
+	}()
+	for i, step := range []struct {
+		username, client string
+		want             error
+	}{
+		{"alice", "10.0.0.1", nil},
+		// Streams of a user already watched share its watch
+		{"alice", "10.0.0.2", nil},
+		{"bob", "10.0.0.2", nil},
+		{"carol", "10.0.0.3", errTooManyWatches},
+		{"alice", "10.0.0.2", errTooManyStreams},
+		{"bob", "10.0.0.1", nil},
+	} {
+		_, unsubscribe, err := s.subscribe(step.username, step.client)
+		if !errors.Is(err, step.want) {
+			t.Fatalf("step %d: subscribe(%s, %s) = %v, want %v", i, step.username, step.client, err, step.want)
+		}
+		if err == nil {
+			unsubscribes = append(unsubscribes, unsubscribe)
+		}
+	}
+
+	// Closing streams frees their client's slots, and the watches nobody streams
+	unsubscribes[0]()
+	if _, ok := s.watches["alice"]; !ok {
+		t.Error("alice's watch stopped while a stream was open")
+	}
+	unsubscribes[1]()
+	if _, ok := s.watches["alice"]; ok {
+		t.Error("alice's watch kept polling after the last stream closed")
+	}
+	unsubscribes = unsubscribes[2:]
+	_, unsubscribe, err := s.subscribe("carol", "10.0.0.1")
+	if err != nil {
+		t.Fatalf("subscribe after closing streams: %v", err)
+	}
+	unsubscribes = append(unsubscribes, unsubscribe)
+	if len(s.streams) != 2 || s.streams["10.0.0.1"] != 2 || s.streams["10.0.0.2"] != 1 {
+		t.Errorf("streams = %v, want 10.0.0.1:2 and 10.0.0.2:1", s.streams)
+	}
+}
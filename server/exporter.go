@@ -0,0 +1,140 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+// Exporter periodically fetches users' graphs and serves them as Prometheus metrics
+type Exporter struct {
+	Client    *gitgraph.Client
+	Usernames []string
+	Interval  time.Duration
+
+	mu      sync.RWMutex
+	samples map[string]userSample
+}
+
+// userSample is the latest successful fetch for one user
+type userSample struct {
+	total   int
+	today   int
+	streak  int
+	success bool
+}
+
+// NewExporter creates an Exporter for usernames refreshed every interval
+func NewExporter(client *gitgraph.Client, usernames []string, interval time.Duration) *Exporter {
+	return &Exporter{
+		Client:    client,
+		Usernames: usernames,
+		Interval:  interval,
+		samples:   make(map[string]userSample),
+	}
+}
+
+// Run refreshes the metrics immediately and then every Interval until ctx is done
+func (e *Exporter) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.Interval)
+	defer ticker.Stop()
+	for {
+		e.refresh(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *Exporter) refresh(ctx context.Context) {
+	now := time.Now()
+	today := now.Format("2006-01-02")
+
+	for _, username := range e.Usernames {
+		graph, err := e.Client.Fetch(ctx, username, gitgraph.Options{Year: now.Year()})
+
+		e.mu.Lock()
+		if err != nil {
+			// Keep the last known values, but flag the failure
+			sample := e.samples[username]
+			sample.success = false
+			e.samples[username] = sample
+			e.mu.Unlock()
+			continue
+		}
+
+		sample := userSample{total: graph.TotalContribs, success: true}
+		for _, day := range graph.Days {
+			if day.Date == today {
+				sample.today = day.Count
+			}
+		}
+		if graph.Streaks != nil {
+			sample.streak = graph.Streaks.Current.Length
+		}
+		e.samples[username] = sample
+		e.mu.Unlock()
+	}
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.mu.RLock()
+	usernames := make([]string, 0, len(e.samples))
+	for username := range e.samples {
+		usernames = append(usernames, username)
+	}
+	sort.Strings(usernames)
+	samples := make([]userSample, len(usernames))
+	for i, username := range usernames {
+		samples[i] = e.samples[username]
+	}
+	e.mu.RUnlock()
+
+	metrics := []struct {
+		name, help string
+		value      func(userSample) int
+	}{
+		{"gitgraphed_contributions_total", "Contributions so far this year.", func(s userSample) int { return s.total }},
+		{"gitgraphed_contributions_today", "Contributions made today.", func(s userSample) int { return s.today }},
+		{"gitgraphed_current_streak_days", "Length of the current contribution streak in days.", func(s userSample) int { return s.streak }},
+		{"gitgraphed_last_fetch_success", "Whether the most recent fetch succeeded (1) or failed (0).", func(s userSample) int {
+			if s.success {
+				return 1
+			}
+			return 0
+		}},
+	}
+
+	var b strings.Builder
+	for _, m := range metrics {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s gauge\n", m.name, m.help, m.name)
+		for i, username := range usernames {
+			fmt.Fprintf(&b, "%s{username=\"%s\"} %d\n", m.name, escapeLabel(username), m.value(samples[i]))
+		}
+	}
+	gitgraph.DefaultMetrics.WritePrometheus(&b)
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	fmt.Fprint(w, b.String())
+}
+
+// handleMetrics serves the fetch, parse, cache and retry metrics of the
+// server's requests in the Prometheus text exposition format
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	gitgraph.DefaultMetrics.WritePrometheus(w)
+}
+
+// escapeLabel escapes a Prometheus label value
+func escapeLabel(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
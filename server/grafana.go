@@ -0,0 +1,184 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+// grafanaMetrics are the series a Grafana target can ask of a user, as
+// username:metric; a bare username is its count
+var grafanaMetrics = []string{"count", "level", "average"}
+
+// grafanaAverageDays is the window of the average metric's rolling average
+const grafanaAverageDays = 7
+
+// grafanaSearch is the body of POST /grafana/search
+type grafanaSearch struct {
+	Target string `json:"target"`
+}
+
+// grafanaQuery is the body of POST /grafana/query
+type grafanaQuery struct {
+	Range struct {
+		From time.Time `json:"from"`
+		To   time.Time `json:"to"`
+	} `json:"range"`
+	Targets []struct {
+		Target string `json:"target"`
+		RefID  string `json:"refId"`
+		Type   string `json:"type"` // timeserie or table
+	} `json:"targets"`
+}
+
+// grafanaSeries is a timeserie target's response: values with Unix
+// milliseconds, in that order
+type grafanaSeries struct {
+	Target     string       `json:"target"`
+	Datapoints [][2]float64 `json:"datapoints"`
+}
+
+type grafanaTable struct {
+	Type    string          `json:"type"`
+	Columns []grafanaColumn `json:"columns"`
+	Rows    [][]any         `json:"rows"`
+}
+
+type grafanaColumn struct {
+	Text string `json:"text"`
+	Type string `json:"type"`
+}
+
+// handleGrafanaTest answers the connection test of a Grafana JSON datasource
+// whose URL is /grafana
+func (s *Server) handleGrafanaTest(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleGrafanaSearch serves /grafana/search, suggesting the metrics of the
+// username typed so far
+func (s *Server) handleGrafanaSearch(w http.ResponseWriter, r *http.Request) {
+	var req grafanaSearch
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && r.ContentLength != 0 {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	targets := []string{}
+	// Nothing is suggested for what can't be a username
+	if username, _, _ := strings.Cut(req.Target, ":"); validUsername(username) == nil {
+		for _, metric := range grafanaMetrics {
+			targets = append(targets, username+":"+metric)
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(targets)
+}
+
+// handleGrafanaQuery serves /grafana/query: each target's days within the
+// range as a time series, or a table of dates, counts and levels
+func (s *Server) handleGrafanaQuery(w http.ResponseWriter, r *http.Request) {
+	var req grafanaQuery
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	from, to := req.Range.From.UTC().Truncate(24*time.Hour), req.Range.To.UTC().Truncate(24*time.Hour)
+	if from.Before(earliestDay) {
+		from = earliestDay
+	}
+	if req.Range.To.IsZero() || to.After(today) {
+		to = today
+	}
+
+	results := []any{}
+	for _, target := range req.Targets {
+		username, metric, _ := strings.Cut(target.Target, ":")
+		if metric == "" {
+			metric = "count"
+		}
+		if validUsername(username) != nil || !slices.Contains(grafanaMetrics, metric) {
+			http.Error(w, fmt.Sprintf("invalid target %q, expected username or username:%s", target.Target, strings.Join(grafanaMetrics, "|")), http.StatusBadRequest)
+			return
+		}
+		if to.Before(from) {
+			results = append(results, grafanaSeries{Target: target.Target, Datapoints: [][2]float64{}})
+			continue
+		}
+		graph, err := s.Client.Fetch(r.Context(), username, gitgraph.Options{From: from, To: to})
+		if err != nil {
+			upstreamError(w, err)
+			return
+		}
+		markStale(w, graph)
+		first, last := from.Format("2006-01-02"), to.Format("2006-01-02")
+		if target.Type == "table" {
+			results = append(results, grafanaDayTable(graph, first, last))
+		} else {
+			results = append(results, grafanaDaySeries(target.Target, graph, metric, first, last))
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(results)
+}
+
+// grafanaDaySeries is metric of each of graph's days from first to last, at
+// the midnight the day starts in the graph's time zone
+func grafanaDaySeries(target string, graph *gitgraph.ContributionGraph, metric, first, last string) grafanaSeries {
+	series := grafanaSeries{Target: target, Datapoints: [][2]float64{}}
+	loc := graphLocation(graph)
+	if metric == "average" {
+		for _, day := range gitgraph.ComputeRollingAverage(graph.Days, grafanaAverageDays).Days {
+			if day.Date < first || day.Date > last {
+				continue
+			}
+			if t, err := time.ParseInLocation("2006-01-02", day.Date, loc); err == nil {
+				series.Datapoints = append(series.Datapoints, [2]float64{day.Average, float64(t.UnixMilli())})
+			}
+		}
+		return series
+	}
+	for _, day := range graph.Days {
+		t, err := time.ParseInLocation("2006-01-02", day.Date, loc)
+		if err != nil || day.Date < first || day.Date > last {
+			continue
+		}
+		value := day.Count
+		if metric == "level" {
+			value = day.Level
+		}
+		series.Datapoints = append(series.Datapoints, [2]float64{float64(value), float64(t.UnixMilli())})
+	}
+	return series
+}
+
+func grafanaDayTable(graph *gitgraph.ContributionGraph, first, last string) grafanaTable {
+	table := grafanaTable{
+		Type:    "table",
+		Columns: []grafanaColumn{{"Time", "time"}, {"Count", "number"}, {"Level", "number"}},
+		Rows:    [][]any{},
+	}
+	loc := graphLocation(graph)
+	for _, day := range graph.Days {
+		if day.Date < first || day.Date > last {
+			continue
+		}
+		if t, err := time.ParseInLocation("2006-01-02", day.Date, loc); err == nil {
+			table.Rows = append(table.Rows, []any{t.UnixMilli(), day.Count, day.Level})
+		}
+	}
+	return table
+}
+
+// graphLocation is the time zone graph's days are dated in, UTC when unknown
+func graphLocation(graph *gitgraph.ContributionGraph) *time.Location {
+	if loc, err := time.LoadLocation(graph.TimeZone); graph.TimeZone != "" && err == nil {
+		return loc
+	}
+	return time.UTC
+}
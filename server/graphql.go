@@ -0,0 +1,255 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+// graphQLRequest is the body of a POST /graphql request
+type graphQLRequest struct {
+	Query         string         `json:"query"`
+	Variables     map[string]any `json:"variables"`
+	OperationName string         `json:"operationName"`
+}
+
+// handleGraphQL serves /graphql, taking the query from a JSON body or a ?query= parameter
+func (s *Server) handleGraphQL(w http.ResponseWriter, r *http.Request) {
+	var req graphQLRequest
+	if r.Method == http.MethodPost {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+	} else {
+		req.Query = r.URL.Query().Get("query")
+		req.OperationName = r.URL.Query().Get("operationName")
+		if vars := r.URL.Query().Get("variables"); vars != "" {
+			if err := json.Unmarshal([]byte(vars), &req.Variables); err != nil {
+				http.Error(w, "invalid variables", http.StatusBadRequest)
+				return
+			}
+		}
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:         s.schema,
+		RequestString:  req.Query,
+		VariableValues: req.Variables,
+		OperationName:  req.OperationName,
+		Context:        r.Context(),
+	})
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// newSchema builds the GraphQL schema: user, days and stats queries, each
+// taking a username and a calendar year or from/to range
+func (s *Server) newSchema() (graphql.Schema, error) {
+	day := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Day",
+		Fields: graphql.Fields{
+			"date":         &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"count":        &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"level":        &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"dayOfWeek":    &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"weekOfYear":   &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"contribLevel": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		},
+	})
+	streak := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Streak",
+		Fields: graphql.Fields{
+			"length": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"start":  &graphql.Field{Type: graphql.String},
+			"end":    &graphql.Field{Type: graphql.String},
+		},
+	})
+	streaks := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Streaks",
+		Fields: graphql.Fields{
+			"current": &graphql.Field{Type: graphql.NewNonNull(streak)},
+			"longest": &graphql.Field{Type: graphql.NewNonNull(streak)},
+		},
+	})
+	dayCount := graphql.NewObject(graphql.ObjectConfig{
+		Name: "DayCount",
+		Fields: graphql.Fields{
+			"date":  &graphql.Field{Type: graphql.String},
+			"count": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		},
+	})
+	quarter := graphql.NewObject(graphql.ObjectConfig{
+		Name: "QuarterTotal",
+		Fields: graphql.Fields{
+			"quarter": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"total":   &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		},
+	})
+	stats := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Stats",
+		Fields: graphql.Fields{
+			"meanDaily":        &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"medianDaily":      &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"maxDay":           &graphql.Field{Type: graphql.NewNonNull(dayCount)},
+			"busiestWeekday":   &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"activeDays":       &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"activeDayPercent": &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"quarters":         &graphql.Field{Type: graphql.NewList(graphql.NewNonNull(quarter))},
+		},
+	})
+
+	dayFilterArgs := graphql.FieldConfigArgument{
+		"from":     &graphql.ArgumentConfig{Type: graphql.String, Description: "first day to include (YYYY-MM-DD)"},
+		"to":       &graphql.ArgumentConfig{Type: graphql.String, Description: "last day to include (YYYY-MM-DD)"},
+		"minLevel": &graphql.ArgumentConfig{Type: graphql.Int, Description: "lowest level to include (0-4)"},
+		"maxLevel": &graphql.ArgumentConfig{Type: graphql.Int, Description: "highest level to include (0-4)"},
+	}
+	user := graphql.NewObject(graphql.ObjectConfig{
+		Name: "User",
+		Fields: graphql.Fields{
+			"username":             &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"totalContributions":   &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"privateContributions": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"years":                &graphql.Field{Type: graphql.NewList(graphql.NewNonNull(graphql.Int))},
+			"streaks":              &graphql.Field{Type: streaks},
+			"days": &graphql.Field{
+				Type: graphql.NewList(graphql.NewNonNull(day)),
+				Args: dayFilterArgs,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return filterDays(p.Source.(*gitgraph.ContributionGraph).Days, p.Args)
+				},
+			},
+			"stats": &graphql.Field{
+				Type: graphql.NewNonNull(stats),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return gitgraph.Summarize(p.Source.(*gitgraph.ContributionGraph).Days), nil
+				},
+			},
+		},
+	})
+
+	periodArgs := func(extra graphql.FieldConfigArgument) graphql.FieldConfigArgument {
+		args := graphql.FieldConfigArgument{
+			"username": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+			"year":     &graphql.ArgumentConfig{Type: graphql.Int, Description: "calendar year, defaults to the current one"},
+			"from":     &graphql.ArgumentConfig{Type: graphql.String, Description: "first day to fetch (YYYY-MM-DD), instead of a year"},
+			"to":       &graphql.ArgumentConfig{Type: graphql.String, Description: "last day to fetch (YYYY-MM-DD), instead of a year"},
+		}
+		for name, arg := range extra {
+			args[name] = arg
+		}
+		return args
+	}
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"user": &graphql.Field{
+				Type: user,
+				Args: periodArgs(nil),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return s.resolveGraph(p)
+				},
+			},
+			"days": &graphql.Field{
+				Type: graphql.NewList(graphql.NewNonNull(day)),
+				Args: periodArgs(graphql.FieldConfigArgument{
+					"level":    &graphql.ArgumentConfig{Type: graphql.Int, Description: "only days at this level (0-4)"},
+					"minLevel": dayFilterArgs["minLevel"],
+					"maxLevel": dayFilterArgs["maxLevel"],
+				}),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					graph, err := s.resolveGraph(p)
+					if err != nil {
+						return nil, err
+					}
+					filter := map[string]any{"minLevel": p.Args["minLevel"], "maxLevel": p.Args["maxLevel"]}
+					if level, ok := p.Args["level"]; ok {
+						filter["minLevel"], filter["maxLevel"] = level, level
+					}
+					return filterDays(graph.Days, filter)
+				},
+			},
+			"stats": &graphql.Field{
+				Type: stats,
+				Args: periodArgs(nil),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					graph, err := s.resolveGraph(p)
+					if err != nil {
+						return nil, err
+					}
+					return gitgraph.Summarize(graph.Days), nil
+				},
+			},
+		},
+	})
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query})
+}
+
+// resolveGraph fetches the graph selected by a query's username, year, from and to arguments
+func (s *Server) resolveGraph(p graphql.ResolveParams) (*gitgraph.ContributionGraph, error) {
+	username, _ := p.Args["username"].(string)
+	if err := validUsername(username); err != nil {
+		return nil, err
+	}
+	opts := gitgraph.Options{}
+	if year, ok := p.Args["year"].(int); ok {
+		opts.Year = year
+	}
+	fromStr, hasFrom := p.Args["from"].(string)
+	toStr, hasTo := p.Args["to"].(string)
+	if hasFrom || hasTo {
+		if !hasTo {
+			toStr = time.Now().UTC().Format("2006-01-02")
+		}
+		from, err := time.Parse("2006-01-02", fromStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid from: %q", fromStr)
+		}
+		to, err := time.Parse("2006-01-02", toStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid to: %q", toStr)
+		}
+		if from, to, err = clampRange(from, to); err != nil {
+			return nil, err
+		}
+		opts = gitgraph.Options{From: from, To: to}
+	}
+	return s.Client.Fetch(p.Context, username, opts)
+}
+
+// filterDays keeps the days within the optional from, to, minLevel and maxLevel arguments
+func filterDays(days []gitgraph.ContributionDay, args map[string]any) ([]gitgraph.ContributionDay, error) {
+	from, _ := args["from"].(string)
+	to, _ := args["to"].(string)
+	for _, date := range []string{from, to} {
+		if _, err := time.Parse("2006-01-02", date); date != "" && err != nil {
+			return nil, fmt.Errorf("invalid date: %q", date)
+		}
+	}
+	minLevel, ok := args["minLevel"].(int)
+	if !ok {
+		minLevel = 0
+	}
+	maxLevel, ok := args["maxLevel"].(int)
+	if !ok {
+		maxLevel = 4
+	}
+
+	filtered := []gitgraph.ContributionDay{}
+	for _, day := range days {
+		if (from != "" && day.Date < from) || (to != "" && day.Date > to) {
+			continue
+		}
+		if day.Level < minLevel || day.Level > maxLevel {
+			continue
+		}
+		filtered = append(filtered, day)
+	}
+	return filtered, nil
+}
@@ -0,0 +1,260 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/JyotinderSingh/gitgraphed/api"
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+// Watch intervals requested over gRPC default to DefaultWatchInterval and can't
+// go below MinWatchInterval, so one client can't make the server hammer upstream
+const (
+	DefaultWatchInterval = 15 * time.Minute
+	MinWatchInterval     = time.Minute
+)
+
+// GRPC implements the GitGraphed gRPC service on top of a Client
+type GRPC struct {
+	api.UnimplementedGitGraphedServer
+	Client *gitgraph.Client
+}
+
+// NewGRPC creates a gRPC server with the GitGraphed service fetching through
+// client. Calls are checked by auth and limited by limiter, either of which
+// may be nil, as the HTTP API's requests are by their middleware.
+func NewGRPC(client *gitgraph.Client, auth *Authenticator, limiter *RateLimiter) *grpc.Server {
+	guard := grpcGuard{auth: auth, limiter: limiter}
+	srv := grpc.NewServer(grpc.ChainUnaryInterceptor(guard.unary), grpc.ChainStreamInterceptor(guard.stream))
+	api.RegisterGitGraphedServer(srv, &GRPC{Client: client})
+	return srv
+}
+
+// grpcGuard authenticates and rate limits gRPC calls from their metadata
+type grpcGuard struct {
+	auth    *Authenticator
+	limiter *RateLimiter
+}
+
+func (g grpcGuard) unary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if err := g.check(ctx, info.FullMethod, req); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (g grpcGuard) stream(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &guardedStream{ServerStream: stream, guard: g, method: info.FullMethod})
+}
+
+// guardedStream checks a streaming call once its request arrives, since the
+// signature covers the request message. The service's streams all take one
+// request, received before anything is sent.
+type guardedStream struct {
+	grpc.ServerStream
+	guard   grpcGuard
+	method  string
+	checked bool
+}
+
+func (s *guardedStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if s.checked {
+		return nil
+	}
+	s.checked = true
+	return s.guard.check(s.Context(), s.method, m)
+}
+
+// check admits a call to method with req, answering Unauthenticated for a
+// missing or bad key and ResourceExhausted once the caller's bucket runs dry
+func (g grpcGuard) check(ctx context.Context, method string, req any) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	get := func(key string) string {
+		if values := md.Get(key); len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+	key := get("x-api-key")
+	if key == "" {
+		key, _ = strings.CutPrefix(get("authorization"), "Bearer ")
+	}
+
+	if g.auth != nil {
+		// gRPC calls are POSTs to the method's full name
+		err := g.auth.authorize(key, func(secret []byte) bool {
+			msg, ok := req.(proto.Message)
+			if !ok {
+				return false
+			}
+			body, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+			if err != nil {
+				return false
+			}
+			return g.auth.validSignature(secret, "POST", method, get("x-timestamp"), get("x-signature"), body)
+		})
+		if err != nil {
+			return status.Error(codes.Unauthenticated, err.Error())
+		}
+	}
+	if g.limiter != nil {
+		remote := ""
+		if p, ok := peer.FromContext(ctx); ok {
+			remote = p.Addr.String()
+		}
+		if _, err := g.limiter.allow(key, g.limiter.clientIP(get("x-forwarded-for"), remote)); err != nil {
+			if errors.Is(err, errUnknownKey) {
+				return status.Error(codes.Unauthenticated, err.Error())
+			}
+			return status.Error(codes.ResourceExhausted, err.Error())
+		}
+	}
+	return nil
+}
+
+func (g *GRPC) GetGraph(ctx context.Context, req *api.GraphRequest) (*api.ContributionGraph, error) {
+	graph, err := g.fetch(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return api.FromGraph(graph), nil
+}
+
+func (g *GRPC) StreamDays(req *api.GraphRequest, stream grpc.ServerStreamingServer[api.ContributionDay]) error {
+	graph, err := g.fetch(stream.Context(), req)
+	if err != nil {
+		return err
+	}
+	for _, day := range graph.Days {
+		if err := stream.Send(api.FromDay(day)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WatchUser polls the rolling year of the user until the client goes away,
+// sending a snapshot first and then one event per day whose count changed
+func (g *GRPC) WatchUser(req *api.WatchRequest, stream grpc.ServerStreamingServer[api.WatchEvent]) error {
+	if req.GetUsername() == "" {
+		return status.Error(codes.InvalidArgument, "username is required")
+	}
+	if err := validUsername(req.GetUsername()); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	interval := DefaultWatchInterval
+	if req.GetIntervalSeconds() > 0 {
+		interval = max(time.Duration(req.GetIntervalSeconds())*time.Second, MinWatchInterval)
+	}
+
+	// Every poll must reach upstream, without changing the shared client
+	client := *g.Client
+	client.Refresh = true
+
+	ctx := stream.Context()
+	send := func(event *api.WatchEvent) error {
+		event.TimeUnix = time.Now().Unix()
+		event.Username = req.GetUsername()
+		return stream.Send(event)
+	}
+
+	var previous *gitgraph.ContributionGraph
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		today := time.Now().UTC().Truncate(24 * time.Hour)
+		graph, err := client.Fetch(ctx, req.GetUsername(), gitgraph.Options{From: today.AddDate(-1, 0, 1), To: today})
+		switch {
+		case err != nil:
+			if ctx.Err() != nil {
+				return nil
+			}
+			err = send(&api.WatchEvent{Type: api.WatchEvent_TYPE_ERROR, Error: err.Error()})
+		case previous == nil:
+			err = send(&api.WatchEvent{Type: api.WatchEvent_TYPE_SNAPSHOT, TotalContributions: int32(graph.TotalContribs)})
+		default:
+			for _, change := range gitgraph.DiffDays(previous, graph) {
+				err = send(&api.WatchEvent{
+					Type:               api.WatchEvent_TYPE_CHANGE,
+					TotalContributions: int32(graph.TotalContribs),
+					Change: &api.DayChange{
+						Date:   change.Date,
+						Before: int32(change.Before),
+						After:  int32(change.After),
+					},
+				})
+				if err != nil {
+					break
+				}
+			}
+		}
+		if err != nil {
+			return err
+		}
+		if graph != nil {
+			previous = graph
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// fetch resolves a GraphRequest's period and fetches it, mapping failures to gRPC codes
+func (g *GRPC) fetch(ctx context.Context, req *api.GraphRequest) (*gitgraph.ContributionGraph, error) {
+	if req.GetUsername() == "" {
+		return nil, status.Error(codes.InvalidArgument, "username is required")
+	}
+	if err := validUsername(req.GetUsername()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	opts := gitgraph.Options{Year: int(req.GetYear())}
+	if req.GetFrom() != "" || req.GetTo() != "" {
+		from, err := time.Parse("2006-01-02", req.GetFrom())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid from: %v", err)
+		}
+		to, err := time.Parse("2006-01-02", req.GetTo())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid to: %v", err)
+		}
+		if from, to, err = clampRange(from, to); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		opts = gitgraph.Options{From: from, To: to}
+	}
+
+	graph, err := g.Client.Fetch(ctx, req.GetUsername(), opts)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
+		switch {
+		case errors.Is(err, gitgraph.ErrUserNotFound):
+			return nil, status.Error(codes.NotFound, err.Error())
+		case errors.Is(err, gitgraph.ErrProfilePrivate):
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		case errors.Is(err, gitgraph.ErrRateLimited):
+			return nil, status.Error(codes.ResourceExhausted, err.Error())
+		}
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	return graph, nil
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+// probePaths are the endpoints polled by orchestrators such as Kubernetes
+var probePaths = []string{"/healthz", "/readyz"}
+
+// readyKey is the cache entry readiness probes write and read back
+const readyKey = "readyz"
+
+// readyTimeout bounds each readiness check, since probes time out themselves
+const readyTimeout = 5 * time.Second
+
+// handleHealthz serves /healthz, answering as long as the process serves requests
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Write([]byte("ok\n"))
+}
+
+// handleReadyz serves /readyz, checking the upstream provider answers and the
+// cache stores and returns entries. It answers 503 when either fails.
+func (s *Server) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), readyTimeout)
+	defer cancel()
+
+	checks := map[string]string{"upstream": "ok", "cache": "disabled"}
+	ready := true
+	if err := s.Client.Ping(ctx); err != nil {
+		checks["upstream"], ready = err.Error(), false
+	}
+	if cache := s.Client.Cache; cache != nil {
+		checks["cache"] = "ok"
+		// Every probe reuses one entry, a fresh value telling it was just written
+		value := strconv.FormatInt(time.Now().UnixNano(), 36)
+		cache.Set(readyKey, []byte(value), time.Minute)
+		if stored, ok := cache.Get(readyKey); !ok || string(stored) != value {
+			checks["cache"], ready = "entries aren't stored", false
+		}
+		if listable, ok := cache.(gitgraph.ListableCache); ok {
+			listable.Delete(readyKey)
+		}
+	}
+
+	status, code := "ok", http.StatusOK
+	if !ready {
+		status, code = "unavailable", http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]any{"status": status, "checks": checks})
+}
+
+// buildVersion describes the running binary, from the module version stamped
+// by go install and the VCS details stamped by go build
+type buildVersion struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	Time      string `json:"time,omitempty"`
+	Modified  bool   `json:"modified,omitempty"`
+	GoVersion string `json:"goVersion"`
+}
+
+// handleVersion serves /version
+func (s *Server) handleVersion(w http.ResponseWriter, r *http.Request) {
+	v := buildVersion{Version: "(devel)", GoVersion: runtime.Version()}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		v.Version = info.Main.Version
+		for _, setting := range info.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				v.Commit = setting.Value
+			case "vcs.time":
+				v.Time = setting.Value
+			case "vcs.modified":
+				v.Modified = setting.Value == "true"
+			}
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// isProbe reports whether r is a liveness or readiness probe
+func isProbe(r *http.Request) bool {
+	return slices.Contains(probePaths, r.URL.Path)
+}
@@ -0,0 +1,217 @@
+package server
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+// openAPISpec describes the JSON, SVG and badge endpoints, served at
+// /openapi.json for generating clients and checked against every request
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
+// apiParameter is an OpenAPI parameter with the parts of its schema that
+// requests are validated against
+type apiParameter struct {
+	Ref      string `json:"$ref"`
+	Name     string `json:"name"`
+	In       string `json:"in"`
+	Required bool   `json:"required"`
+	Schema   struct {
+		Type    string   `json:"type"`
+		Pattern string   `json:"pattern"`
+		Enum    []string `json:"enum"`
+		Minimum *float64 `json:"minimum"`
+		Maximum *float64 `json:"maximum"`
+	} `json:"schema"`
+	pattern *regexp.Regexp
+}
+
+// apiRoute is one operation of the spec, matched by method and path template
+type apiRoute struct {
+	method     string
+	path       *regexp.Regexp // path template with a group per path parameter
+	names      []string       // of the path parameters, in the order of path's groups
+	parameters []apiParameter
+}
+
+// apiRoutes are the operations of openAPISpec
+var apiRoutes = mustParseRoutes(openAPISpec)
+
+// mustParseRoutes compiles the operations of an OpenAPI document, resolving
+// parameters that refer to components
+func mustParseRoutes(spec []byte) []apiRoute {
+	var doc struct {
+		Paths      map[string]map[string]struct{ Parameters []apiParameter } `json:"paths"`
+		Components struct {
+			Parameters map[string]apiParameter `json:"parameters"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		panic(fmt.Sprintf("parsing OpenAPI spec: %v", err))
+	}
+
+	var routes []apiRoute
+	for template, operations := range doc.Paths {
+		path, names := compileTemplate(template)
+		for method, op := range operations {
+			route := apiRoute{method: strings.ToUpper(method), path: path, names: names}
+			for _, p := range op.Parameters {
+				if name, ok := strings.CutPrefix(p.Ref, "#/components/parameters/"); ok {
+					p = doc.Components.Parameters[name]
+				}
+				if p.Schema.Pattern != "" {
+					p.pattern = regexp.MustCompile(p.Schema.Pattern)
+				}
+				route.parameters = append(route.parameters, p)
+			}
+			routes = append(routes, route)
+		}
+	}
+	return routes
+}
+
+// usernameParameter is the spec's username parameter, whose pattern every
+// API holds usernames to, whichever way they arrive
+var usernameParameter = mustParseParameter(openAPISpec, "username")
+
+// mustParseParameter compiles the named parameter of an OpenAPI document's
+// components
+func mustParseParameter(spec []byte, name string) *apiParameter {
+	var doc struct {
+		Components struct {
+			Parameters map[string]apiParameter `json:"parameters"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		panic(fmt.Sprintf("parsing OpenAPI spec: %v", err))
+	}
+	p, ok := doc.Components.Parameters[name]
+	if !ok || p.Schema.Pattern == "" {
+		panic(fmt.Sprintf("OpenAPI spec has no %s parameter with a pattern", name))
+	}
+	p.pattern = regexp.MustCompile(p.Schema.Pattern)
+	return &p
+}
+
+// validUsername checks a username given to GraphQL, gRPC or Grafana as the
+// REST routes check theirs
+func validUsername(username string) error {
+	if err := usernameParameter.validate(username); err != nil {
+		return fmt.Errorf("invalid username: %w", err)
+	}
+	return nil
+}
+
+// templateParameter matches the {name} placeholders of a path template
+var templateParameter = regexp.MustCompile(`\{([^}]+)\}`)
+
+// compileTemplate turns a path template into a regexp with a group for each
+// placeholder, which matches one path segment or part of one as in
+// {year}.json, and returns the placeholders' names in order
+func compileTemplate(template string) (*regexp.Regexp, []string) {
+	var expr strings.Builder
+	var names []string
+	last := 0
+	for _, m := range templateParameter.FindAllStringSubmatchIndex(template, -1) {
+		expr.WriteString(regexp.QuoteMeta(template[last:m[0]]))
+		expr.WriteString(`([^/]+?)`)
+		names = append(names, template[m[2]:m[3]])
+		last = m[1]
+	}
+	expr.WriteString(regexp.QuoteMeta(template[last:]))
+	return regexp.MustCompile("^" + expr.String() + "$"), names
+}
+
+// validateRequest checks r's parameters against the spec operation it
+// matches. Requests outside the spec, like GraphQL's, aren't checked. Routes
+// are matched against the escaped path, segment by segment as ServeMux
+// routes it, so an escaped slash stays inside the parameter it's part of.
+func validateRequest(r *http.Request) error {
+	for _, route := range apiRoutes {
+		if route.method != r.Method {
+			continue
+		}
+		match := route.path.FindStringSubmatch(r.URL.EscapedPath())
+		if match == nil {
+			continue
+		}
+		for _, p := range route.parameters {
+			var value string
+			present := false
+			switch p.In {
+			case "path":
+				i := slices.Index(route.names, p.Name)
+				if i < 0 {
+					continue
+				}
+				var err error
+				if value, err = url.PathUnescape(match[i+1]); err != nil {
+					return fmt.Errorf("invalid %s parameter %s: %w", p.In, p.Name, err)
+				}
+				present = true
+			case "query":
+				present = r.URL.Query().Has(p.Name)
+				value = r.URL.Query().Get(p.Name)
+			default:
+				continue
+			}
+			if !present {
+				if p.Required {
+					return fmt.Errorf("missing %s parameter %s", p.In, p.Name)
+				}
+				continue
+			}
+			if err := p.validate(value); err != nil {
+				return fmt.Errorf("invalid %s parameter %s: %w", p.In, p.Name, err)
+			}
+		}
+		return nil
+	}
+	return nil
+}
+
+// validate checks value against the parameter's schema
+func (p *apiParameter) validate(value string) error {
+	if p.Schema.Type == "integer" {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%q is not an integer", value)
+		}
+		if p.Schema.Minimum != nil && float64(n) < *p.Schema.Minimum {
+			return fmt.Errorf("%d is below the minimum of %g", n, *p.Schema.Minimum)
+		}
+		if p.Schema.Maximum != nil && float64(n) > *p.Schema.Maximum {
+			return fmt.Errorf("%d is above the maximum of %g", n, *p.Schema.Maximum)
+		}
+	}
+	if len(p.Schema.Enum) > 0 && !slices.Contains(p.Schema.Enum, value) {
+		return fmt.Errorf("%q is not one of %s", value, strings.Join(p.Schema.Enum, ", "))
+	}
+	if p.pattern != nil && !p.pattern.MatchString(value) {
+		return fmt.Errorf("%q does not match %s", value, p.Schema.Pattern)
+	}
+	return nil
+}
+
+// handleSchema serves /schema.json, the JSON Schema of /api/v1 responses
+func (s *Server) handleSchema(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/schema+json")
+	w.Write(gitgraph.Schema)
+}
+
+// handleOpenAPI serves /openapi.json
+func (s *Server) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(openAPISpec)
+}
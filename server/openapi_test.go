@@ -0,0 +1,65 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestValidateRequest(t *testing.T) {
+	for _, tt := range []struct {
+		method, target string
+		valid          bool
+	}{
+		{"GET", "/api/v1/alice/2024.json", true},
+		{"GET", "/api/v1/alice/2007.json", false},
+		{"GET", "/api/v1/alice/year.json", false},
+		{"GET", "/api/v1/-alice/2024.json", false},
+		// An escaped slash is part of the username, as ServeMux routes it
+		{"GET", "/api/v1/a%2F..%3Fx/2024.json", false},
+		{"GET", "/a%2Fb.svg", false},
+		{"GET", "/events/a%2F..", false},
+		{"GET", "/alice.svg?year=2024&theme=dark", true},
+		{"GET", "/alice.svg?theme=Dark!", false},
+		{"GET", "/badge/alice/total", true},
+		{"GET", "/badge/alice/nonsense", false},
+		{"GET", "/events/alice", true},
+		// Requests outside the spec aren't checked
+		{"POST", "/graphql", true},
+		{"GET", "/healthz", true},
+	} {
+		err := validateRequest(httptest.NewRequest(tt.method, tt.target, nil))
+		if (err == nil) != tt.valid {
+			t.Errorf("%s %s: err = %v, want valid %v", tt.method, tt.target, err, tt.valid)
+		}
+	}
+}
+
+func TestServeHTTPRejectsEscapedSlash(t *testing.T) {
+	s := New(nil)
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/a%2F..%3Fx/2024.json", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestValidUsername(t *testing.T) {
+	for username, valid := range map[string]bool{
+		"alice":                  true,
+		"a":                      true,
+		"alice-bob.c_d":          true,
+		"":                       false,
+		"-alice":                 false,
+		"a/../b":                 false,
+		"alice?x=1":              false,
+		"alice bob":              false,
+		strings.Repeat("a", 100): true,
+		strings.Repeat("a", 101): false,
+	} {
+		if err := validUsername(username); (err == nil) != valid {
+			t.Errorf("validUsername(%q) = %v, want valid %v", username, err, valid)
+		}
+	}
+}
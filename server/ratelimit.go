@@ -0,0 +1,147 @@
+package server
+
+import (
+	"errors"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+// RateLimiter gives every client a token bucket of its own, answering 429 once
+// it runs dry. Clients are told apart by API key when they send a known one,
+// and by IP address otherwise.
+type RateLimiter struct {
+	Rate     float64 // requests per second per IP address
+	Burst    int
+	KeyRate  float64 // requests per second per API key
+	KeyBurst int
+	APIKeys  map[string]bool
+	// TrustProxy takes the client address from the last X-Forwarded-For entry,
+	// for deployments behind a reverse proxy
+	TrustProxy bool
+
+	mu        sync.Mutex
+	buckets   map[string]*gitgraph.TokenBucket
+	lastSeen  map[string]time.Time
+	lastSweep time.Time
+}
+
+// NewRateLimiter limits each IP address to rate requests per second with bursts of up to burst
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	return &RateLimiter{
+		Rate:     rate,
+		Burst:    burst,
+		KeyRate:  rate,
+		KeyBurst: burst,
+		buckets:  make(map[string]*gitgraph.TokenBucket),
+		lastSeen: make(map[string]time.Time),
+	}
+}
+
+// Middleware rate limits requests before passing them to next. Requests with an
+// API key that isn't known are rejected with 401. Health probes pass unlimited.
+func (l *RateLimiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isProbe(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		wait, err := l.allow(requestAPIKey(r), l.clientIP(r.Header.Get("X-Forwarded-For"), r.RemoteAddr))
+		switch {
+		case errors.Is(err, errUnknownKey):
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		case err != nil:
+			w.Header().Set("Retry-After", strconv.Itoa(wait))
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+var errRateLimited = errors.New("rate limit exceeded")
+
+// allow takes a token from the bucket of the client with apiKey, or from
+// ip's without one, returning how many seconds to wait when it's empty
+func (l *RateLimiter) allow(apiKey, ip string) (int, error) {
+	key, rate, burst := "ip:"+ip, l.Rate, l.Burst
+	if apiKey != "" {
+		if !l.APIKeys[apiKey] {
+			return 0, errUnknownKey
+		}
+		key, rate, burst = "key:"+apiKey, l.KeyRate, l.KeyBurst
+	}
+	if !l.bucket(key, rate, burst).Allow() {
+		return int(math.Ceil(1 / rate)), errRateLimited
+	}
+	return 0, nil
+}
+
+// bucket returns the client's bucket, creating it full on first use
+func (l *RateLimiter) bucket(key string, rate float64, burst int) *gitgraph.TokenBucket {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.sweep(now)
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = gitgraph.NewTokenBucket(rate, burst)
+		l.buckets[key] = bucket
+	}
+	l.lastSeen[key] = now
+	return bucket
+}
+
+// sweep forgets clients idle long enough for their bucket to have refilled,
+// since a new full bucket behaves the same. l.mu must be held.
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < time.Minute {
+		return
+	}
+	l.lastSweep = now
+	for key, seen := range l.lastSeen {
+		bucket := l.buckets[key]
+		if now.Sub(seen).Seconds()*bucket.Rate >= bucket.Burst {
+			delete(l.buckets, key)
+			delete(l.lastSeen, key)
+		}
+	}
+}
+
+// clientIP is the address of a client connecting from remoteAddr, or the
+// last hop of forwarded when proxies are trusted
+func (l *RateLimiter) clientIP(forwarded, remoteAddr string) string {
+	return clientAddr(forwarded, remoteAddr, l.TrustProxy)
+}
+
+// clientAddr is clientIP, trusting proxies as told
+func clientAddr(forwarded, remoteAddr string, trustProxy bool) string {
+	if trustProxy && forwarded != "" {
+		hops := strings.Split(forwarded, ",")
+		return strings.TrimSpace(hops[len(hops)-1])
+	}
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return remoteAddr
+	}
+	return host
+}
+
+// requestAPIKey reads the key from an X-API-Key header or a bearer token
+func requestAPIKey(r *http.Request) string {
+	if key := r.Header.Get("X-API-Key"); key != "" {
+		return key
+	}
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return token
+	}
+	return ""
+}
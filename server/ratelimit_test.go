@@ -0,0 +1,101 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterMiddleware(t *testing.T) {
+	// A slow refill keeps buckets from topping up mid-test
+	limiter := NewRateLimiter(0.01, 2)
+	limiter.KeyBurst = 3
+	limiter.APIKeys = map[string]bool{"key": true}
+	handler := limiter.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	for i, step := range []struct {
+		remote, forwarded, key string
+		want                   int
+	}{
+		{remote: "10.0.0.1:1000", want: http.StatusOK},
+		{remote: "10.0.0.1:1001", want: http.StatusOK},
+		{remote: "10.0.0.1:1002", want: http.StatusTooManyRequests},
+		// Another address has a bucket of its own
+		{remote: "10.0.0.2:1000", want: http.StatusOK},
+		// Forwarded addresses aren't trusted without TrustProxy
+		{remote: "10.0.0.1:1003", forwarded: "192.0.2.1", want: http.StatusTooManyRequests},
+		// A known key has its own, larger bucket, wherever it comes from
+		{remote: "10.0.0.1:1004", key: "key", want: http.StatusOK},
+		{remote: "10.0.0.3:1000", key: "key", want: http.StatusOK},
+		{remote: "10.0.0.4:1000", key: "key", want: http.StatusOK},
+		{remote: "10.0.0.5:1000", key: "key", want: http.StatusTooManyRequests},
+		{remote: "10.0.0.6:1000", key: "unknown", want: http.StatusUnauthorized},
+	} {
+		r := httptest.NewRequest(http.MethodGet, "/alice.svg", nil)
+		r.RemoteAddr = step.remote
+		if step.forwarded != "" {
+			r.Header.Set("X-Forwarded-For", step.forwarded)
+		}
+		if step.key != "" {
+			r.Header.Set("X-API-Key", step.key)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if w.Code != step.want {
+			t.Fatalf("step %d: status = %d, want %d", i, w.Code, step.want)
+		}
+		if w.Code == http.StatusTooManyRequests && w.Header().Get("Retry-After") != "100" {
+			t.Errorf("step %d: Retry-After = %q, want 100", i, w.Header().Get("Retry-After"))
+		}
+	}
+
+	// Probes are never limited
+	for i := 0; i < 3; i++ {
+		r := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+		r.RemoteAddr = "10.0.0.1:1005"
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if w.Code != http.StatusOK {
+			t.Fatalf("probe %d: status = %d, want %d", i, w.Code, http.StatusOK)
+		}
+	}
+}
+
+func TestClientAddr(t *testing.T) {
+	for _, tt := range []struct {
+		forwarded, remote string
+		trust             bool
+		want              string
+	}{
+		{"", "10.0.0.1:1234", false, "10.0.0.1"},
+		{"", "[2001:db8::1]:1234", false, "2001:db8::1"},
+		{"", "unix-socket", false, "unix-socket"},
+		{"192.0.2.1", "10.0.0.1:1234", false, "10.0.0.1"},
+		{"192.0.2.1", "10.0.0.1:1234", true, "192.0.2.1"},
+		// The last hop is the one the trusted proxy added
+		{"198.51.100.7, 192.0.2.1", "10.0.0.1:1234", true, "192.0.2.1"},
+	} {
+		if got := clientAddr(tt.forwarded, tt.remote, tt.trust); got != tt.want {
+			t.Errorf("clientAddr(%q, %q, %v) = %q, want %q", tt.forwarded, tt.remote, tt.trust, got, tt.want)
+		}
+	}
+}
+
+func TestRateLimiterSweep(t *testing.T) {
+	limiter := NewRateLimiter(1, 2)
+	limiter.allow("", "10.0.0.1")
+	limiter.allow("", "10.0.0.2")
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+	// Buckets idle long enough to have refilled are forgotten
+	limiter.lastSeen["ip:10.0.0.1"] = time.Now().Add(-time.Hour)
+	limiter.lastSweep = time.Time{}
+	limiter.sweep(time.Now())
+	if _, ok := limiter.buckets["ip:10.0.0.1"]; ok {
+		t.Error("idle bucket kept")
+	}
+	if len(limiter.buckets) != 1 || len(limiter.lastSeen) != 1 {
+		t.Errorf("kept %d buckets and %d last seen, want 1 each", len(limiter.buckets), len(limiter.lastSeen))
+	}
+}
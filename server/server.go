@@ -0,0 +1,216 @@
+// Package server exposes contribution graphs over HTTP.
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+	"github.com/JyotinderSingh/gitgraphed/render"
+	"github.com/JyotinderSingh/gitgraphed/telemetry"
+)
+
+// Server serves contribution graphs as JSON, SVG and GraphQL, badges for
+// READMEs, and a datasource for Grafana's JSON and Infinity plugins
+type Server struct {
+	Client *gitgraph.Client
+	// WatchInterval is how often users with open /events streams are polled,
+	// DefaultWatchInterval when zero
+	WatchInterval time.Duration
+	// AdminKeys authorize the /admin endpoints, which aren't served without any
+	AdminKeys map[string]bool
+	// MaxWatches caps the users polled for /events streams at once, and
+	// MaxClientStreams the streams one client address may keep open; zero
+	// uses DefaultMaxWatches and DefaultMaxClientStreams
+	MaxWatches       int
+	MaxClientStreams int
+	// TrustProxy takes a stream's client address from X-Forwarded-For
+	TrustProxy bool
+	// Private marks responses as only for the client asking, for servers
+	// behind an Authenticator, so shared caches never answer anyone else
+	Private bool
+
+	mux     *http.ServeMux
+	schema  graphql.Schema
+	watchMu sync.Mutex
+	watches map[string]*userWatch
+	streams map[string]int // open /events streams by client address
+}
+
+// New creates a Server fetching through client
+func New(client *gitgraph.Client) *Server {
+	s := &Server{Client: client, mux: http.NewServeMux(), watches: make(map[string]*userWatch), streams: make(map[string]int)}
+	schema, err := s.newSchema()
+	if err != nil {
+		panic(fmt.Sprintf("building GraphQL schema: %v", err))
+	}
+	s.schema = schema
+	s.mux.HandleFunc("GET /graphql", s.handleGraphQL)
+	s.mux.HandleFunc("POST /graphql", s.handleGraphQL)
+	s.mux.HandleFunc("GET /api/v1/{username}/{year}", s.handleJSON)
+	s.mux.HandleFunc("GET /badge/{username}/{metric}", s.handleBadge)
+	s.mux.HandleFunc("GET /events/{username}", s.handleEvents)
+	s.mux.HandleFunc("GET /grafana/{$}", s.handleGrafanaTest)
+	s.mux.HandleFunc("POST /grafana/search", s.handleGrafanaSearch)
+	s.mux.HandleFunc("POST /grafana/query", s.handleGrafanaQuery)
+	s.mux.HandleFunc("GET /openapi.json", s.handleOpenAPI)
+	s.mux.HandleFunc("GET /schema.json", s.handleSchema)
+	s.mux.HandleFunc("GET /healthz", s.handleHealthz)
+	s.mux.HandleFunc("GET /readyz", s.handleReadyz)
+	s.mux.HandleFunc("GET /version", s.handleVersion)
+	s.mux.HandleFunc("GET /metrics", s.handleMetrics)
+	s.mux.HandleFunc("GET /admin/cache", s.admin(s.handleCacheList))
+	s.mux.HandleFunc("DELETE /admin/cache/{username}", s.admin(s.handleCacheInvalidate))
+	s.mux.HandleFunc("DELETE /admin/cache/{username}/{year}", s.admin(s.handleCacheInvalidate))
+	s.mux.HandleFunc("GET /{$}", s.handleUI)
+	s.mux.HandleFunc("GET /{file}", s.handleSVG)
+	return s
+}
+
+// ServeHTTP rejects requests whose parameters don't match the OpenAPI spec
+// with 400, before routing the rest
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := validateRequest(r); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// handleJSON serves /api/v1/{username}/{year}.json
+func (s *Server) handleJSON(w http.ResponseWriter, r *http.Request) {
+	yearStr, ok := strings.CutSuffix(r.PathValue("year"), ".json")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	year, err := strconv.Atoi(yearStr)
+	if err != nil {
+		http.Error(w, "invalid year", http.StatusBadRequest)
+		return
+	}
+
+	graph, err := s.Client.Fetch(r.Context(), r.PathValue("username"), gitgraph.Options{Year: year})
+	if err != nil {
+		upstreamError(w, err)
+		return
+	}
+	markStale(w, graph)
+	if s.cacheHeaders(w, r, graph) {
+		return
+	}
+
+	graph.UpdateSummary()
+	graph.UpdateAnomalies(time.Now())
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(graph)
+}
+
+// handleSVG serves /{username}.svg, optionally for ?year=YYYY and in a built-in ?theme=
+func (s *Server) handleSVG(w http.ResponseWriter, r *http.Request) {
+	username, ok := strings.CutSuffix(r.PathValue("file"), ".svg")
+	if !ok || username == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	year := time.Now().Year()
+	if yearStr := r.URL.Query().Get("year"); yearStr != "" {
+		parsed, err := strconv.Atoi(yearStr)
+		if err != nil {
+			http.Error(w, "invalid year", http.StatusBadRequest)
+			return
+		}
+		year = parsed
+	}
+
+	opts := render.DefaultSVGOptions
+	if name := r.URL.Query().Get("theme"); name != "" {
+		theme, ok := render.LookupTheme(name)
+		if !ok {
+			http.Error(w, "unknown theme", http.StatusBadRequest)
+			return
+		}
+		opts.Theme = theme
+	}
+	opts.Patterns = r.URL.Query().Get("patterns") == "1"
+
+	graph, err := s.Client.Fetch(r.Context(), username, gitgraph.Options{Year: year})
+	if err != nil {
+		upstreamError(w, err)
+		return
+	}
+	markStale(w, graph)
+	if s.cacheHeaders(w, r, graph) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/svg+xml")
+	_, span := telemetry.Start(r.Context(), "render.SVG", telemetry.KindInternal, telemetry.Int("days", len(graph.Days)))
+	err = render.SVG(w, graph, opts)
+	span.End(err)
+	if err != nil {
+		slog.Error("rendering SVG", "username", username, "err", err)
+	}
+}
+
+// upstreamError reports a failed fetch: 404 for unknown users, 403 for
+// private profiles, 503 while the circuit breaker holds fetches back and 502
+// otherwise
+func upstreamError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, gitgraph.ErrUserNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, gitgraph.ErrProfilePrivate):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, gitgraph.ErrCircuitOpen):
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+	default:
+		http.Error(w, err.Error(), http.StatusBadGateway)
+	}
+}
+
+// markStale flags a response built from a stale cached graph with X-Stale,
+// and one estimated from events with X-Approximate
+func markStale(w http.ResponseWriter, graph *gitgraph.ContributionGraph) {
+	if graph.Stale {
+		w.Header().Set("X-Stale", "true")
+	}
+	if graph.Approximate {
+		w.Header().Set("X-Approximate", "true")
+	}
+}
+
+// maxRangeYears bounds the span of a from/to range one request may fetch,
+// since each year it touches is an upstream fetch of its own
+const maxRangeYears = 5
+
+// earliestDay is the first day a requested range is clamped to, GitHub's launch
+var earliestDay = time.Date(2008, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// clampRange bounds a requested range to earliestDay..today, failing when it
+// ends before it starts or spans more than maxRangeYears
+func clampRange(from, to time.Time) (time.Time, time.Time, error) {
+	if from.Before(earliestDay) {
+		from = earliestDay
+	}
+	if today := time.Now().UTC().Truncate(24 * time.Hour); to.After(today) {
+		to = today
+	}
+	if to.Before(from) {
+		return from, to, fmt.Errorf("range ends before it starts, or before %s", earliestDay.Format("2006-01-02"))
+	}
+	if to.Year()-from.Year() >= maxRangeYears {
+		return from, to, fmt.Errorf("range spans more than %d years", maxRangeYears)
+	}
+	return from, to, nil
+}
@@ -0,0 +1,31 @@
+package server
+
+import (
+	_ "embed"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/render"
+)
+
+// uiPage is the web UI served at /, a form showing a user's calendar and
+// stats through the SVG and JSON endpoints
+//
+//go:embed ui.html
+var uiPage string
+
+var uiTemplate = template.Must(template.New("ui").Parse(uiPage))
+
+// handleUI serves the web UI at /
+func (s *Server) handleUI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	data := struct {
+		Year   int
+		Themes []string
+	}{time.Now().Year(), render.ThemeNames()}
+	if err := uiTemplate.Execute(w, data); err != nil {
+		slog.Error("rendering web UI", "err", err)
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// statsdClient sends metrics over UDP to a StatsD server or Datadog agent
+type statsdClient struct {
+	conn   net.Conn
+	prefix string
+	// dogstatsd tags metrics with the user, DogStatsD style; plain StatsD has
+	// no tags, so the user goes in the metric name instead
+	dogstatsd bool
+}
+
+// newStatsD connects to the StatsD server at addr, e.g. localhost:8125.
+// Being UDP, this only fails on an invalid address.
+func newStatsD(addr, prefix string, dogstatsd bool) (*statsdClient, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return &statsdClient{conn: conn, prefix: strings.TrimSuffix(prefix, "."), dogstatsd: dogstatsd}, nil
+}
+
+// statsdMetric is one line of a StatsD packet
+type statsdMetric struct {
+	Name  string
+	Value int
+	Type  string // g for a gauge, c for a counter
+}
+
+// send writes metrics of username in one packet. Delivery isn't confirmed,
+// so errors are only those writing to the socket.
+func (c *statsdClient) send(username string, metrics []statsdMetric) error {
+	var b strings.Builder
+	for _, m := range metrics {
+		name := c.prefix + "." + m.Name
+		if !c.dogstatsd {
+			name = c.prefix + "." + statsdName(username) + "." + m.Name
+		}
+		fmt.Fprintf(&b, "%s:%d|%s", name, m.Value, m.Type)
+		if c.dogstatsd {
+			fmt.Fprintf(&b, "|#user:%s", statsdTagEscaper.Replace(username))
+		}
+		b.WriteString("\n")
+	}
+	_, err := c.conn.Write([]byte(strings.TrimSuffix(b.String(), "\n")))
+	return err
+}
+
+// statsdName keeps a username from breaking the line format, in which '.'
+// separates name segments and ':', '|', '#' and ',' delimit the rest
+func statsdName(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '.', ':', '|', '#', ',', '@', ' ', '\n':
+			return '_'
+		}
+		return r
+	}, s)
+}
+
+// statsdTagEscaper replaces the characters ending a DogStatsD tag value
+var statsdTagEscaper = strings.NewReplacer("|", "_", ",", "_", "#", "_", " ", "_", "\n", "_")
+
+func (c *statsdClient) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,60 @@
+package store
+
+import (
+	"fmt"
+	"time"
+)
+
+// Retention bounds how many snapshots and samples a store keeps; zero fields
+// don't limit. The latest of each user, or of each user's metric, is always
+// kept, so the latest days still read the same.
+type Retention struct {
+	PerDay  int           // the latest snapshots kept of each day (UTC)
+	PerWeek int           // the latest kept of each ISO week, for those over a week old
+	MaxAge  time.Duration // older snapshots are dropped
+}
+
+// IsZero reports whether r keeps everything
+func (r Retention) IsZero() bool {
+	return r.PerDay <= 0 && r.PerWeek <= 0 && r.MaxAge <= 0
+}
+
+// Pruned counts what Prune removed
+type Pruned struct {
+	Snapshots int `json:"snapshots"`
+	Samples   int `json:"samples"`
+}
+
+// keep reports which of times, oldest first, r retains as of now. Counting
+// from the newest, so the latest of each day and week survive, a time is
+// kept while its day is under PerDay and, once over a week old, its week
+// under PerWeek.
+func (r Retention) keep(times []time.Time, now time.Time) []bool {
+	kept := make([]bool, len(times))
+	days := map[string]int{}
+	weeks := map[string]int{}
+	recent := now.AddDate(0, 0, -7)
+	for i := len(times) - 1; i >= 0; i-- {
+		t := times[i].UTC()
+		if i == len(times)-1 {
+			kept[i] = true
+		} else if r.MaxAge > 0 && now.Sub(t) > r.MaxAge {
+			continue
+		}
+		day := t.Format("2006-01-02")
+		if r.PerDay > 0 && days[day] >= r.PerDay && !kept[i] {
+			continue
+		}
+		days[day]++
+		if t.Before(recent) {
+			year, week := t.ISOWeek()
+			key := fmt.Sprintf("%d-W%02d", year, week)
+			if r.PerWeek > 0 && weeks[key] >= r.PerWeek && !kept[i] {
+				continue
+			}
+			weeks[key]++
+		}
+		kept[i] = true
+	}
+	return kept
+}
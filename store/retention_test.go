@@ -0,0 +1,35 @@
+package store
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRetentionKeep(t *testing.T) {
+	now := time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC)
+	at := func(days, hours int) time.Time {
+		return now.AddDate(0, 0, -days).Add(time.Duration(-hours) * time.Hour)
+	}
+	// Two a day for the last two days, after three days in each of two earlier weeks
+	times := []time.Time{at(16, 0), at(15, 0), at(14, 0), at(10, 0), at(9, 0), at(8, 0), at(1, 2), at(1, 1), at(0, 2), at(0, 1)}
+	for _, tt := range []struct {
+		name string
+		r    Retention
+		want []bool
+	}{
+		{"everything", Retention{}, []bool{true, true, true, true, true, true, true, true, true, true}},
+		{"per day", Retention{PerDay: 1}, []bool{true, true, true, true, true, true, false, true, false, true}},
+		// 2024-03-15 to 17 fall in ISO week 11, 03-21 to 03-23 in week 12
+		{"per week", Retention{PerWeek: 1}, []bool{false, false, true, false, false, true, true, true, true, true}},
+		{"max age", Retention{MaxAge: 10 * 24 * time.Hour}, []bool{false, false, false, true, true, true, true, true, true, true}},
+		{"combined", Retention{PerDay: 1, PerWeek: 1, MaxAge: 15 * 24 * time.Hour}, []bool{false, false, true, false, false, true, false, true, false, true}},
+		{"latest always", Retention{MaxAge: time.Hour}, []bool{false, false, false, false, false, false, false, false, false, true}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.r.keep(times, now); !slices.Equal(got, tt.want) {
+				t.Errorf("keep = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
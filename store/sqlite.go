@@ -0,0 +1,379 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+
+	_ "modernc.org/sqlite" // registers the "sqlite" driver
+)
+
+// sqliteSchema keeps the latest count per day, a row per fetch, and a row
+// per change of a day's count for reading the days as of a past snapshot,
+// plus samples of profile counts such as followers
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS days (
+	username   TEXT    NOT NULL,
+	date       TEXT    NOT NULL,
+	count      INTEGER NOT NULL,
+	level      INTEGER NOT NULL,
+	first_seen TEXT    NOT NULL,
+	fetched_at TEXT    NOT NULL,
+	PRIMARY KEY (username, date)
+);
+CREATE TABLE IF NOT EXISTS snapshots (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	username   TEXT    NOT NULL,
+	fetched_at TEXT    NOT NULL,
+	first_day  TEXT    NOT NULL,
+	last_day   TEXT    NOT NULL,
+	total      INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS snapshots_username ON snapshots (username, fetched_at);
+CREATE TABLE IF NOT EXISTS day_history (
+	username   TEXT    NOT NULL,
+	date       TEXT    NOT NULL,
+	count      INTEGER NOT NULL,
+	level      INTEGER NOT NULL,
+	fetched_at TEXT    NOT NULL,
+	PRIMARY KEY (username, date, fetched_at)
+);
+CREATE TABLE IF NOT EXISTS profile_samples (
+	username   TEXT    NOT NULL,
+	metric     TEXT    NOT NULL,
+	value      INTEGER NOT NULL,
+	sampled_at TEXT    NOT NULL,
+	PRIMARY KEY (username, metric, sampled_at)
+);
+`
+
+// sqliteBackfill seeds day_history from databases created before it existed,
+// knowing only each day's latest count
+const sqliteBackfill = `
+INSERT OR IGNORE INTO day_history (username, date, count, level, fetched_at)
+SELECT username, date, count, level, fetched_at FROM days
+WHERE NOT EXISTS (SELECT 1 FROM day_history)`
+
+// SQLite is a Store backed by a local SQLite database file
+type SQLite struct {
+	db *sql.DB
+}
+
+// OpenSQLite opens (creating if needed) the database at path
+func OpenSQLite(path string) (*SQLite, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+	// SQLite allows a single writer; serialise rather than fail with SQLITE_BUSY
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(sqliteSchema + ";" + sqliteBackfill); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("initialising %s: %w", path, err)
+	}
+	return &SQLite{db: db}, nil
+}
+
+func (s *SQLite) Save(ctx context.Context, graph *gitgraph.ContributionGraph, fetchedAt time.Time) error {
+	if len(graph.Days) == 0 {
+		return nil
+	}
+	stamp := formatStamp(fetchedAt)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	upsert, err := tx.PrepareContext(ctx, `
+		INSERT INTO days (username, date, count, level, first_seen, fetched_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (username, date) DO UPDATE SET
+			count = excluded.count, level = excluded.level, fetched_at = excluded.fetched_at`)
+	if err != nil {
+		return err
+	}
+	defer upsert.Close()
+
+	// Days whose count is new or changed since the last fetch get a history row
+	record, err := tx.PrepareContext(ctx, `
+		INSERT OR REPLACE INTO day_history (username, date, count, level, fetched_at)
+		SELECT ?, ?, ?, ?, ?
+		WHERE NOT EXISTS (SELECT 1 FROM days WHERE username = ? AND date = ? AND count = ?)`)
+	if err != nil {
+		return err
+	}
+	defer record.Close()
+
+	for _, day := range graph.Days {
+		if _, err := record.ExecContext(ctx, graph.Username, day.Date, day.Count, day.Level, stamp, graph.Username, day.Date, day.Count); err != nil {
+			return fmt.Errorf("saving %s: %w", day.Date, err)
+		}
+		if _, err := upsert.ExecContext(ctx, graph.Username, day.Date, day.Count, day.Level, stamp, stamp); err != nil {
+			return fmt.Errorf("saving %s: %w", day.Date, err)
+		}
+	}
+
+	first, last := graph.Days[0].Date, graph.Days[len(graph.Days)-1].Date
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO snapshots (username, fetched_at, first_day, last_day, total) VALUES (?, ?, ?, ?, ?)`,
+		graph.Username, stamp, first, last, graph.TotalContribs); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *SQLite) Graph(ctx context.Context, username string, from, to time.Time) (*gitgraph.ContributionGraph, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT date, count, level FROM days WHERE username = ? AND date BETWEEN ? AND ? ORDER BY date`,
+		username, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	return readGraph(username, rows)
+}
+
+func (s *SQLite) GraphAsOf(ctx context.Context, username string, from, to, asOf time.Time) (*gitgraph.ContributionGraph, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT date, count, level FROM day_history AS h
+		WHERE username = ? AND date BETWEEN ? AND ? AND fetched_at <= ?
+		AND fetched_at = (
+			SELECT MAX(fetched_at) FROM day_history
+			WHERE username = h.username AND date = h.date AND fetched_at <= ?)
+		ORDER BY date`,
+		username, from.Format("2006-01-02"), to.Format("2006-01-02"), formatStamp(asOf), formatStamp(asOf))
+	if err != nil {
+		return nil, err
+	}
+	return readGraph(username, rows)
+}
+
+// formatStamp formats t as fetched_at columns store it, which sorts chronologically
+func formatStamp(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// readGraph assembles a graph from rows of date, count and level
+func readGraph(username string, rows *sql.Rows) (*gitgraph.ContributionGraph, error) {
+	defer rows.Close()
+
+	days := []gitgraph.ContributionDay{}
+	for rows.Next() {
+		var date string
+		var count, level int
+		if err := rows.Scan(&date, &count, &level); err != nil {
+			return nil, err
+		}
+		day, err := gitgraph.NewDay(date, count, level)
+		if err != nil {
+			return nil, fmt.Errorf("stored day %q: %w", date, err)
+		}
+		days = append(days, day)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return graphFromDays(username, days), nil
+}
+
+func (s *SQLite) LastSnapshot(ctx context.Context, username string) (time.Time, error) {
+	var stamp sql.NullString
+	err := s.db.QueryRowContext(ctx,
+		`SELECT MAX(fetched_at) FROM snapshots WHERE username = ?`, username).Scan(&stamp)
+	if err != nil || !stamp.Valid {
+		return time.Time{}, err
+	}
+	return time.Parse(time.RFC3339, stamp.String)
+}
+
+func (s *SQLite) SaveSample(ctx context.Context, username, metric string, value int, sampledAt time.Time) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT OR REPLACE INTO profile_samples (username, metric, value, sampled_at) VALUES (?, ?, ?, ?)`,
+		username, metric, value, formatStamp(sampledAt))
+	return err
+}
+
+func (s *SQLite) Samples(ctx context.Context, username, metric string, from, to time.Time) ([]gitgraph.Sample, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT sampled_at, value FROM profile_samples
+		WHERE username = ? AND metric = ? AND sampled_at BETWEEN ? AND ? ORDER BY sampled_at`,
+		username, metric, formatStamp(from), formatStamp(to))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	samples := []gitgraph.Sample{}
+	for rows.Next() {
+		var stamp string
+		var sample gitgraph.Sample
+		if err := rows.Scan(&stamp, &sample.Value); err != nil {
+			return nil, err
+		}
+		if sample.Time, err = time.Parse(time.RFC3339, stamp); err != nil {
+			return nil, fmt.Errorf("stored sample time %q: %w", stamp, err)
+		}
+		samples = append(samples, sample)
+	}
+	return samples, rows.Err()
+}
+
+func (s *SQLite) Close() error {
+	return s.db.Close()
+}
+
+// sqliteCollapse moves the day_history rows of dropped snapshots to the next
+// snapshot kept, listed in kept_stamps, first dropping those it overrides:
+// rows followed by a later one for the same day before that snapshot
+var sqliteCollapse = []string{`
+DELETE FROM day_history AS h
+WHERE username = ? AND fetched_at NOT IN (SELECT stamp FROM kept_stamps)
+AND EXISTS (
+	SELECT 1 FROM day_history AS n
+	WHERE n.username = h.username AND n.date = h.date AND n.fetched_at > h.fetched_at
+	AND n.fetched_at <= (SELECT MIN(stamp) FROM kept_stamps WHERE stamp >= h.fetched_at))`, `
+UPDATE day_history AS h SET fetched_at = (SELECT MIN(stamp) FROM kept_stamps WHERE stamp >= h.fetched_at)
+WHERE username = ? AND fetched_at NOT IN (SELECT stamp FROM kept_stamps)
+AND EXISTS (SELECT 1 FROM kept_stamps WHERE stamp >= h.fetched_at)`,
+}
+
+func (s *SQLite) Prune(ctx context.Context, username string, r Retention, now time.Time) (Pruned, error) {
+	var pruned Pruned
+	if r.IsZero() {
+		return pruned, nil
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return pruned, err
+	}
+	defer tx.Rollback()
+
+	usernames := []string{username}
+	if username == "" {
+		if usernames, err = queryStrings(ctx, tx, `SELECT username FROM snapshots UNION SELECT username FROM profile_samples`); err != nil {
+			return pruned, err
+		}
+	}
+	for _, username := range usernames {
+		n, err := pruneSnapshots(ctx, tx, username, r, now)
+		if err != nil {
+			return pruned, fmt.Errorf("pruning snapshots of %s: %w", username, err)
+		}
+		pruned.Snapshots += n
+		metrics, err := queryStrings(ctx, tx, `SELECT DISTINCT metric FROM profile_samples WHERE username = ?`, username)
+		if err != nil {
+			return pruned, err
+		}
+		for _, metric := range metrics {
+			n, err := pruneSamples(ctx, tx, username, metric, r, now)
+			if err != nil {
+				return pruned, fmt.Errorf("pruning %s samples of %s: %w", metric, username, err)
+			}
+			pruned.Samples += n
+		}
+	}
+	return pruned, tx.Commit()
+}
+
+// pruneSnapshots drops the snapshots of username r doesn't keep, collapsing
+// their day history into the snapshots kept
+func pruneSnapshots(ctx context.Context, tx *sql.Tx, username string, r Retention, now time.Time) (int, error) {
+	rows, err := tx.QueryContext(ctx, `SELECT id, fetched_at FROM snapshots WHERE username = ? ORDER BY fetched_at, id`, username)
+	if err != nil {
+		return 0, err
+	}
+	var ids []int64
+	var stamps []string
+	var times []time.Time
+	for rows.Next() {
+		var id int64
+		var stamp string
+		if err := rows.Scan(&id, &stamp); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		t, err := time.Parse(time.RFC3339, stamp)
+		if err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("stored snapshot time %q: %w", stamp, err)
+		}
+		ids, stamps, times = append(ids, id), append(stamps, stamp), append(times, t)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	kept := r.keep(times, now)
+	if _, err := tx.ExecContext(ctx, `CREATE TEMP TABLE IF NOT EXISTS kept_stamps (stamp TEXT PRIMARY KEY); DELETE FROM kept_stamps`); err != nil {
+		return 0, err
+	}
+	dropped := 0
+	for i, id := range ids {
+		if kept[i] {
+			_, err = tx.ExecContext(ctx, `INSERT OR IGNORE INTO kept_stamps (stamp) VALUES (?)`, stamps[i])
+		} else {
+			_, err = tx.ExecContext(ctx, `DELETE FROM snapshots WHERE id = ?`, id)
+			dropped++
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	if dropped == 0 {
+		return 0, nil
+	}
+	for _, query := range sqliteCollapse {
+		if _, err := tx.ExecContext(ctx, query, username); err != nil {
+			return 0, err
+		}
+	}
+	return dropped, nil
+}
+
+// pruneSamples drops the samples of username's metric r doesn't keep
+func pruneSamples(ctx context.Context, tx *sql.Tx, username, metric string, r Retention, now time.Time) (int, error) {
+	stamps, err := queryStrings(ctx, tx, `SELECT sampled_at FROM profile_samples WHERE username = ? AND metric = ? ORDER BY sampled_at`, username, metric)
+	if err != nil {
+		return 0, err
+	}
+	times := make([]time.Time, len(stamps))
+	for i, stamp := range stamps {
+		if times[i], err = time.Parse(time.RFC3339, stamp); err != nil {
+			return 0, fmt.Errorf("stored sample time %q: %w", stamp, err)
+		}
+	}
+	dropped := 0
+	for i, keep := range r.keep(times, now) {
+		if keep {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM profile_samples WHERE username = ? AND metric = ? AND sampled_at = ?`, username, metric, stamps[i]); err != nil {
+			return 0, err
+		}
+		dropped++
+	}
+	return dropped, nil
+}
+
+// queryStrings reads a column of strings
+func queryStrings(ctx context.Context, tx *sql.Tx, query string, args ...any) ([]string, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	values := []string{}
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, rows.Err()
+}
@@ -0,0 +1,147 @@
+package store
+
+import (
+	"context"
+	"maps"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+// openTestStore opens an empty SQLite store in a temporary directory
+func openTestStore(t *testing.T) *SQLite {
+	t.Helper()
+	s, err := OpenSQLite(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+// saveCounts saves a snapshot of alice's first days of March 2024
+func saveCounts(t *testing.T, s *SQLite, fetchedAt time.Time, counts ...int) {
+	t.Helper()
+	graph := &gitgraph.ContributionGraph{Username: "alice"}
+	for i, count := range counts {
+		day, err := gitgraph.NewDay(time.Date(2024, 3, 1+i, 0, 0, 0, 0, time.UTC).Format("2006-01-02"), count, min(count, 4))
+		if err != nil {
+			t.Fatal(err)
+		}
+		graph.Days = append(graph.Days, day)
+		graph.TotalContribs += count
+	}
+	if err := s.Save(context.Background(), graph, fetchedAt); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// storedCounts returns alice's counts by date as of asOf, or the latest if zero
+func storedCounts(t *testing.T, s *SQLite, asOf time.Time) map[string]int {
+	t.Helper()
+	from, to := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)
+	var graph *gitgraph.ContributionGraph
+	var err error
+	if asOf.IsZero() {
+		graph, err = s.Graph(context.Background(), "alice", from, to)
+	} else {
+		graph, err = s.GraphAsOf(context.Background(), "alice", from, to, asOf)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := map[string]int{}
+	for _, day := range graph.Days {
+		counts[day.Date] = day.Count
+	}
+	return counts
+}
+
+var (
+	firstFetch  = time.Date(2024, 3, 2, 10, 0, 0, 0, time.UTC)
+	secondFetch = time.Date(2024, 3, 2, 12, 0, 0, 0, time.UTC)
+	thirdFetch  = time.Date(2024, 3, 3, 10, 0, 0, 0, time.UTC)
+)
+
+// saveHistory saves three snapshots, the first two on the same day
+func saveHistory(t *testing.T, s *SQLite) {
+	saveCounts(t, s, firstFetch, 1, 0)
+	saveCounts(t, s, secondFetch, 2, 0)
+	saveCounts(t, s, thirdFetch, 3, 0, 5)
+}
+
+func TestSQLiteGraphAsOf(t *testing.T) {
+	s := openTestStore(t)
+	saveHistory(t, s)
+	for _, tt := range []struct {
+		name string
+		asOf time.Time
+		want map[string]int
+	}{
+		{"before", firstFetch.Add(-time.Second), map[string]int{}},
+		{"first", firstFetch, map[string]int{"2024-03-01": 1, "2024-03-02": 0}},
+		{"between", firstFetch.Add(time.Hour), map[string]int{"2024-03-01": 1, "2024-03-02": 0}},
+		{"second", secondFetch, map[string]int{"2024-03-01": 2, "2024-03-02": 0}},
+		{"third", thirdFetch, map[string]int{"2024-03-01": 3, "2024-03-02": 0, "2024-03-03": 5}},
+		{"latest", time.Time{}, map[string]int{"2024-03-01": 3, "2024-03-02": 0, "2024-03-03": 5}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := storedCounts(t, s, tt.asOf); !maps.Equal(got, tt.want) {
+				t.Errorf("counts = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSQLitePrune(t *testing.T) {
+	s := openTestStore(t)
+	saveHistory(t, s)
+	ctx := context.Background()
+	for i, value := range []int{10, 11, 12} {
+		if err := s.SaveSample(ctx, "alice", "followers", value, []time.Time{firstFetch, secondFetch, thirdFetch}[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pruned, err := s.Prune(ctx, "", Retention{PerDay: 1}, thirdFetch.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pruned != (Pruned{Snapshots: 1, Samples: 1}) {
+		t.Errorf("pruned %+v, want the first snapshot and sample", pruned)
+	}
+
+	// Days the first snapshot changed now read as of the second
+	for _, tt := range []struct {
+		name string
+		asOf time.Time
+		want map[string]int
+	}{
+		{"dropped", firstFetch, map[string]int{}},
+		{"kept", secondFetch, map[string]int{"2024-03-01": 2, "2024-03-02": 0}},
+		{"latest", thirdFetch, map[string]int{"2024-03-01": 3, "2024-03-02": 0, "2024-03-03": 5}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := storedCounts(t, s, tt.asOf); !maps.Equal(got, tt.want) {
+				t.Errorf("counts = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	samples, err := s.Samples(ctx, "alice", "followers", firstFetch, thirdFetch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 2 || samples[0].Value != 11 || samples[1].Value != 12 {
+		t.Errorf("samples = %+v, want 11 and 12", samples)
+	}
+	if last, err := s.LastSnapshot(ctx, "alice"); err != nil || !last.Equal(thirdFetch) {
+		t.Errorf("last snapshot = %v, %v, want %v", last, err, thirdFetch)
+	}
+
+	// Pruning again drops nothing
+	if pruned, err := s.Prune(ctx, "alice", Retention{PerDay: 1}, thirdFetch.Add(time.Hour)); err != nil || pruned != (Pruned{}) {
+		t.Errorf("second prune = %+v, %v, want nothing", pruned, err)
+	}
+}
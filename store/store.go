@@ -0,0 +1,65 @@
+// Package store persists fetched contribution days for historical queries.
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+)
+
+// Store records fetched graphs and reads stored days back
+type Store interface {
+	// Save upserts graph's days, recording fetchedAt as a snapshot
+	Save(ctx context.Context, graph *gitgraph.ContributionGraph, fetchedAt time.Time) error
+	// Graph returns the stored days of username between from and to (inclusive)
+	Graph(ctx context.Context, username string, from, to time.Time) (*gitgraph.ContributionGraph, error)
+	// GraphAsOf returns the days of username between from and to as they were
+	// stored by the snapshots taken up to asOf
+	GraphAsOf(ctx context.Context, username string, from, to, asOf time.Time) (*gitgraph.ContributionGraph, error)
+	// LastSnapshot returns when username was last saved, or the zero time if never
+	LastSnapshot(ctx context.Context, username string) (time.Time, error)
+	// SaveSample records value as username's profile metric at sampledAt
+	SaveSample(ctx context.Context, username, metric string, value int, sampledAt time.Time) error
+	// Samples returns username's samples of metric taken between from and to
+	// (inclusive), oldest first
+	Samples(ctx context.Context, username, metric string, from, to time.Time) ([]gitgraph.Sample, error)
+	// Prune drops the snapshots and samples of username, or of every user
+	// when empty, that r doesn't keep as of now. Days a dropped snapshot
+	// changed read as of the next snapshot kept.
+	Prune(ctx context.Context, username string, r Retention, now time.Time) (Pruned, error)
+	Close() error
+}
+
+// Open opens the store described by spec, e.g. "sqlite:history.db"
+func Open(spec string) (Store, error) {
+	kind, path, ok := strings.Cut(spec, ":")
+	if !ok || path == "" {
+		return nil, fmt.Errorf("invalid store %q, expected e.g. sqlite:history.db", spec)
+	}
+	switch kind {
+	case "sqlite":
+		return OpenSQLite(path)
+	default:
+		return nil, fmt.Errorf("unsupported store type: %s", kind)
+	}
+}
+
+// graphFromDays assembles a graph from stored days sorted by date
+func graphFromDays(username string, days []gitgraph.ContributionDay) *gitgraph.ContributionGraph {
+	graph := &gitgraph.ContributionGraph{Username: username, Years: []int{}, Days: days}
+	for _, day := range days {
+		graph.TotalContribs += day.Count
+		date, err := time.Parse("2006-01-02", day.Date)
+		if err != nil {
+			continue
+		}
+		if n := len(graph.Years); n == 0 || graph.Years[n-1] != date.Year() {
+			graph.Years = append(graph.Years, date.Year())
+		}
+	}
+	graph.UpdateStreaks(time.Now())
+	return graph
+}
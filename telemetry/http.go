@@ -0,0 +1,112 @@
+package telemetry
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Middleware traces each request to next as a server span, continuing the
+// caller's trace and named after the route that served it, and records its
+// duration in the http.server.request.duration histogram
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !Enabled() {
+			next.ServeHTTP(w, r)
+			return
+		}
+		start := time.Now()
+		ctx, span := Start(Extract(r.Context(), r.Header), r.Method, KindServer,
+			String("http.request.method", r.Method),
+			String("url.path", r.URL.Path),
+			String("user_agent.original", r.UserAgent()),
+		)
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		r = r.WithContext(ctx)
+		next.ServeHTTP(rec, r)
+
+		// The mux sets the pattern it matched on r, e.g. GET /badge/{username}/{metric}
+		_, route, _ := strings.Cut(r.Pattern, " ")
+		if route != "" {
+			span.SetName(r.Method + " " + route)
+			span.SetAttributes(String("http.route", route))
+		}
+		span.SetAttributes(Int("http.response.status_code", rec.status))
+		var err error
+		if rec.status >= 500 {
+			err = fmt.Errorf("%d %s", rec.status, http.StatusText(rec.status))
+		}
+		span.End(err)
+		observeRequest(r.Method, route, rec.status, time.Since(start))
+	})
+}
+
+// statusRecorder remembers the status code a handler responds with
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	wrote  bool
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	if !r.wrote {
+		r.status, r.wrote = code, true
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Write(p []byte) (int, error) {
+	r.wrote = true
+	return r.ResponseWriter.Write(p)
+}
+
+// Flush keeps event streams flowing through the recorder
+func (r *statusRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		r.wrote = true
+		flusher.Flush()
+	}
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// Transport traces requests as client spans, children of the span in each
+// request's context, and propagates the trace to the server with traceparent
+type Transport struct {
+	Base http.RoundTripper
+}
+
+// NewTransport wraps base, http.DefaultTransport when nil
+func NewTransport(base http.RoundTripper) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{Base: base}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := Start(req.Context(), req.Method, KindClient,
+		String("http.request.method", req.Method),
+		String("server.address", req.URL.Hostname()),
+		// Without the query, which may carry credentials
+		String("url.full", req.URL.Scheme+"://"+req.URL.Host+req.URL.Path),
+	)
+	if span == nil {
+		return t.Base.RoundTrip(req)
+	}
+	req = req.Clone(ctx)
+	Inject(ctx, req.Header)
+	resp, err := t.Base.RoundTrip(req)
+	spanErr := err
+	if err == nil {
+		span.SetAttributes(Int("http.response.status_code", resp.StatusCode))
+		if resp.StatusCode >= 400 {
+			spanErr = fmt.Errorf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+		}
+	}
+	span.End(spanErr)
+	return resp, err
+}
@@ -0,0 +1,96 @@
+package telemetry
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// MetricKind is how a Metric's points aggregate
+type MetricKind int
+
+const (
+	// Counter points are totals since the process started
+	Counter MetricKind = iota
+	Gauge
+	// Histogram points count observations into buckets since the process started
+	Histogram
+)
+
+// Metric is a named series of points, exported with each batch
+type Metric struct {
+	Name        string
+	Description string
+	Unit        string
+	Kind        MetricKind
+	Points      []Point
+}
+
+// Point is one attribute set's value of a Metric. Histogram points set
+// Bounds, Counts (per bucket, not cumulative, the last above every bound),
+// Sum and Count instead of Value.
+type Point struct {
+	Attrs  []Attr
+	Value  float64
+	Bounds []float64
+	Counts []uint64
+	Sum    float64
+	Count  uint64
+}
+
+// MetricSource reports the current value of metrics kept elsewhere, e.g.
+// gitgraph.Metrics
+type MetricSource func() []Metric
+
+// requestBuckets are the upper bounds in seconds of request durations,
+// those recommended by OpenTelemetry's HTTP semantic conventions
+var requestBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.075, 0.1, 0.25, 0.5, 0.75, 1, 2.5, 5, 7.5, 10}
+
+// requestLabels tell apart the request duration histograms
+type requestLabels struct {
+	method string
+	route  string
+	status int
+}
+
+var (
+	requestsMu sync.Mutex
+	requests   = map[requestLabels]*Point{}
+)
+
+// observeRequest records a served request taking d
+func observeRequest(method, route string, status int, d time.Duration) {
+	labels := requestLabels{method, route, status}
+	requestsMu.Lock()
+	defer requestsMu.Unlock()
+	p, ok := requests[labels]
+	if !ok {
+		p = &Point{Bounds: requestBuckets, Counts: make([]uint64, len(requestBuckets)+1)}
+		attrs := []Attr{String("http.request.method", method), Int("http.response.status_code", status)}
+		if route != "" {
+			attrs = append(attrs, String("http.route", route))
+		}
+		p.Attrs = attrs
+		requests[labels] = p
+	}
+	seconds := d.Seconds()
+	p.Counts[sort.SearchFloat64s(requestBuckets, seconds)]++
+	p.Sum += seconds
+	p.Count++
+}
+
+// requestMetrics reports the durations recorded by Middleware
+func requestMetrics() []Metric {
+	requestsMu.Lock()
+	defer requestsMu.Unlock()
+	if len(requests) == 0 {
+		return nil
+	}
+	metric := Metric{Name: "http.server.request.duration", Description: "Duration of HTTP server requests.", Unit: "s", Kind: Histogram}
+	for _, p := range requests {
+		point := *p
+		point.Counts = append([]uint64(nil), p.Counts...)
+		metric.Points = append(metric.Points, point)
+	}
+	return []Metric{metric}
+}
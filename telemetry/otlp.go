@@ -0,0 +1,335 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultInterval is how often an Exporter sends its batch
+const DefaultInterval = 10 * time.Second
+
+// maxQueued bounds the spans held between exports; later ones are dropped
+// while the collector can't keep up
+const maxQueued = 4096
+
+// scopeName is the instrumentation scope of every span and metric
+const scopeName = "github.com/JyotinderSingh/gitgraphed"
+
+// Exporter batches ended spans and sends them, with the metrics of its
+// sources, to an OpenTelemetry collector as OTLP/HTTP JSON
+type Exporter struct {
+	// TracesURL and MetricsURL receive the batches, e.g.
+	// http://localhost:4318/v1/traces; metrics aren't sent without MetricsURL
+	TracesURL  string
+	MetricsURL string
+	// Headers are sent with each batch, e.g. a vendor's API key
+	Headers map[string]string
+	// Resource describes the process, e.g. service.name
+	Resource []Attr
+	// Interval is how often batches are sent, DefaultInterval when zero
+	Interval time.Duration
+	// Metrics are reported with each batch, besides the request durations
+	// recorded by Middleware
+	Metrics    []MetricSource
+	HTTPClient *http.Client
+
+	mu      sync.Mutex
+	spans   []*Span
+	dropped int
+	started time.Time
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// NewExporter creates an Exporter sending to the OTLP/HTTP collector at
+// endpoint, e.g. http://localhost:4318
+func NewExporter(endpoint string) *Exporter {
+	endpoint = strings.TrimRight(endpoint, "/")
+	return &Exporter{
+		TracesURL:  endpoint + "/v1/traces",
+		MetricsURL: endpoint + "/v1/metrics",
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// ExporterFromEnv creates an Exporter configured by the standard
+// OTEL_EXPORTER_OTLP_ENDPOINT, OTEL_EXPORTER_OTLP_TRACES_ENDPOINT,
+// OTEL_EXPORTER_OTLP_METRICS_ENDPOINT, OTEL_EXPORTER_OTLP_HEADERS and
+// OTEL_SERVICE_NAME variables, with endpoint taking the place of the first.
+// It returns nil when no endpoint is set.
+func ExporterFromEnv(endpoint, service, version string) (*Exporter, error) {
+	if endpoint == "" {
+		endpoint = os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+	}
+	traces, metrics := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"), os.Getenv("OTEL_EXPORTER_OTLP_METRICS_ENDPOINT")
+	if endpoint == "" && traces == "" && metrics == "" {
+		return nil, nil
+	}
+	e := NewExporter(endpoint)
+	if endpoint == "" {
+		e.TracesURL, e.MetricsURL = "", ""
+	}
+	// Signal endpoints are full URLs, used as they are
+	if traces != "" {
+		e.TracesURL = traces
+	}
+	if metrics != "" {
+		e.MetricsURL = metrics
+	}
+	for _, u := range []string{e.TracesURL, e.MetricsURL} {
+		if parsed, err := url.Parse(u); u != "" && (err != nil || parsed.Host == "") {
+			return nil, fmt.Errorf("invalid OTLP endpoint %q", u)
+		}
+	}
+
+	if headers := os.Getenv("OTEL_EXPORTER_OTLP_HEADERS"); headers != "" {
+		e.Headers = map[string]string{}
+		for _, pair := range strings.Split(headers, ",") {
+			key, value, ok := strings.Cut(pair, "=")
+			if !ok {
+				return nil, errors.New("invalid OTEL_EXPORTER_OTLP_HEADERS, expected key=value pairs")
+			}
+			value, _ = url.QueryUnescape(strings.TrimSpace(value))
+			e.Headers[strings.TrimSpace(key)] = value
+		}
+	}
+	if name := os.Getenv("OTEL_SERVICE_NAME"); name != "" {
+		service = name
+	}
+	e.Resource = []Attr{String("service.name", service), String("service.version", version)}
+	return e, nil
+}
+
+// Start makes spans record to e and sends batches every Interval until Shutdown
+func (e *Exporter) Start() {
+	interval := e.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	e.started = time.Now()
+	e.stop, e.done = make(chan struct{}), make(chan struct{})
+	active.Store(e)
+	go func() {
+		defer close(e.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-e.stop:
+				return
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), interval)
+				if err := e.Flush(ctx); err != nil {
+					slog.Warn("exporting telemetry", "err", err)
+				}
+				cancel()
+			}
+		}
+	}()
+}
+
+// Shutdown stops recording spans and sends the last batch
+func (e *Exporter) Shutdown(ctx context.Context) error {
+	active.CompareAndSwap(e, nil)
+	close(e.stop)
+	<-e.done
+	return e.Flush(ctx)
+}
+
+// queue holds span for the next batch
+func (e *Exporter) queue(span *Span) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if len(e.spans) >= maxQueued {
+		e.dropped++
+		return
+	}
+	e.spans = append(e.spans, span)
+}
+
+// Flush sends the queued spans and the current metrics
+func (e *Exporter) Flush(ctx context.Context) error {
+	e.mu.Lock()
+	spans, dropped := e.spans, e.dropped
+	e.spans, e.dropped = nil, 0
+	e.mu.Unlock()
+	if dropped > 0 {
+		slog.Warn("dropped spans the collector couldn't keep up with", "spans", dropped)
+	}
+
+	var errs []error
+	if len(spans) > 0 && e.TracesURL != "" {
+		errs = append(errs, e.post(ctx, e.TracesURL, e.encodeSpans(spans)))
+	}
+	if e.MetricsURL != "" {
+		if metrics := e.collect(); len(metrics) > 0 {
+			errs = append(errs, e.post(ctx, e.MetricsURL, e.encodeMetrics(metrics)))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (e *Exporter) collect() []Metric {
+	metrics := requestMetrics()
+	for _, source := range e.Metrics {
+		metrics = append(metrics, source()...)
+	}
+	return metrics
+}
+
+func (e *Exporter) post(ctx context.Context, endpoint string, body any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "gitgraphed")
+	for key, value := range e.Headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := e.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("collector returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// otlpKeyValue is an attribute as OTLP JSON encodes it, its value keyed by type
+type otlpKeyValue struct {
+	Key   string         `json:"key"`
+	Value map[string]any `json:"value"`
+}
+
+func encodeAttrs(attrs []Attr) []otlpKeyValue {
+	encoded := make([]otlpKeyValue, 0, len(attrs))
+	for _, attr := range attrs {
+		var value map[string]any
+		switch v := attr.Value.(type) {
+		case string:
+			value = map[string]any{"stringValue": v}
+		case bool:
+			value = map[string]any{"boolValue": v}
+		case int64:
+			// 64-bit integers are strings in JSON
+			value = map[string]any{"intValue": strconv.FormatInt(v, 10)}
+		case float64:
+			value = map[string]any{"doubleValue": v}
+		default:
+			value = map[string]any{"stringValue": fmt.Sprint(v)}
+		}
+		encoded = append(encoded, otlpKeyValue{attr.Key, value})
+	}
+	return encoded
+}
+
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+type otlpSpan struct {
+	TraceID      string         `json:"traceId"`
+	SpanID       string         `json:"spanId"`
+	ParentSpanID string         `json:"parentSpanId,omitempty"`
+	Name         string         `json:"name"`
+	Kind         SpanKind       `json:"kind"`
+	Start        string         `json:"startTimeUnixNano"`
+	End          string         `json:"endTimeUnixNano"`
+	Attributes   []otlpKeyValue `json:"attributes"`
+	Status       otlpStatus     `json:"status"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"` // 2 for an error, unset otherwise
+	Message string `json:"message,omitempty"`
+}
+
+// encodeSpans is an ExportTraceServiceRequest of spans
+func (e *Exporter) encodeSpans(spans []*Span) map[string]any {
+	encoded := make([]otlpSpan, 0, len(spans))
+	for _, span := range spans {
+		span.mu.Lock()
+		s := otlpSpan{
+			TraceID:    hex.EncodeToString(span.id.traceID[:]),
+			SpanID:     hex.EncodeToString(span.id.spanID[:]),
+			Name:       span.name,
+			Kind:       span.kind,
+			Start:      unixNano(span.start),
+			End:        unixNano(span.end),
+			Attributes: encodeAttrs(span.attrs),
+		}
+		if span.parent != [8]byte{} {
+			s.ParentSpanID = hex.EncodeToString(span.parent[:])
+		}
+		if span.err != "" {
+			s.Status = otlpStatus{Code: 2, Message: span.err}
+		}
+		span.mu.Unlock()
+		encoded = append(encoded, s)
+	}
+	return map[string]any{"resourceSpans": []any{map[string]any{
+		"resource":   map[string]any{"attributes": encodeAttrs(e.Resource)},
+		"scopeSpans": []any{map[string]any{"scope": map[string]any{"name": scopeName}, "spans": encoded}},
+	}}}
+}
+
+// encodeMetrics is an ExportMetricsServiceRequest of metrics, cumulative
+// since the exporter started
+func (e *Exporter) encodeMetrics(metrics []Metric) map[string]any {
+	start, now := unixNano(e.started), unixNano(time.Now())
+	encoded := make([]map[string]any, 0, len(metrics))
+	for _, m := range metrics {
+		points := make([]map[string]any, 0, len(m.Points))
+		for _, p := range m.Points {
+			point := map[string]any{"attributes": encodeAttrs(p.Attrs), "startTimeUnixNano": start, "timeUnixNano": now}
+			if m.Kind == Histogram {
+				counts := make([]string, len(p.Counts))
+				for i, c := range p.Counts {
+					counts[i] = strconv.FormatUint(c, 10)
+				}
+				point["count"] = strconv.FormatUint(p.Count, 10)
+				point["sum"] = p.Sum
+				point["bucketCounts"] = counts
+				point["explicitBounds"] = p.Bounds
+			} else {
+				point["asDouble"] = p.Value
+			}
+			points = append(points, point)
+		}
+		metric := map[string]any{"name": m.Name, "description": m.Description, "unit": m.Unit}
+		switch m.Kind {
+		case Counter:
+			metric["sum"] = map[string]any{"dataPoints": points, "aggregationTemporality": 2, "isMonotonic": true}
+		case Gauge:
+			metric["gauge"] = map[string]any{"dataPoints": points}
+		case Histogram:
+			metric["histogram"] = map[string]any{"dataPoints": points, "aggregationTemporality": 2}
+		}
+		encoded = append(encoded, metric)
+	}
+	return map[string]any{"resourceMetrics": []any{map[string]any{
+		"resource":     map[string]any{"attributes": encodeAttrs(e.Resource)},
+		"scopeMetrics": []any{map[string]any{"scope": map[string]any{"name": scopeName}, "metrics": encoded}},
+	}}}
+}
@@ -0,0 +1,145 @@
+// Package telemetry traces fetches, renders and server requests as
+// OpenTelemetry spans and exports them, with metrics, to a collector over
+// OTLP/HTTP. Until an Exporter is started every span is a no-op.
+package telemetry
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Attr is an attribute of a span or metric point. Value is a string, bool,
+// int64 or float64.
+type Attr struct {
+	Key   string
+	Value any
+}
+
+func String(key, value string) Attr    { return Attr{key, value} }
+func Int(key string, value int) Attr   { return Attr{key, int64(value)} }
+func Bool(key string, value bool) Attr { return Attr{key, value} }
+
+// SpanKind is the OTLP kind of a span
+type SpanKind int
+
+const (
+	KindInternal SpanKind = 1
+	KindServer   SpanKind = 2
+	KindClient   SpanKind = 3
+)
+
+// spanContext identifies a span, started here or propagated by a caller
+type spanContext struct {
+	traceID [16]byte
+	spanID  [8]byte
+}
+
+// Span is an operation being traced. A nil Span, returned while telemetry
+// is disabled, ignores every call.
+type Span struct {
+	id       spanContext
+	parent   [8]byte
+	kind     SpanKind
+	start    time.Time
+	exporter *Exporter
+
+	mu    sync.Mutex
+	name  string
+	end   time.Time
+	attrs []Attr
+	err   string
+}
+
+// active is the started Exporter spans are queued on
+var active atomic.Pointer[Exporter]
+
+// Enabled reports whether an Exporter is started
+func Enabled() bool {
+	return active.Load() != nil
+}
+
+type contextKey struct{}
+
+// Start begins a span named name, a child of the span in ctx when there is
+// one, and returns ctx carrying it
+func Start(ctx context.Context, name string, kind SpanKind, attrs ...Attr) (context.Context, *Span) {
+	exporter := active.Load()
+	if exporter == nil {
+		return ctx, nil
+	}
+	span := &Span{name: name, kind: kind, start: time.Now(), attrs: attrs, exporter: exporter}
+	if parent, ok := ctx.Value(contextKey{}).(spanContext); ok {
+		span.id.traceID = parent.traceID
+		span.parent = parent.spanID
+	} else {
+		rand.Read(span.id.traceID[:])
+	}
+	rand.Read(span.id.spanID[:])
+	return context.WithValue(ctx, contextKey{}, span.id), span
+}
+
+// SetName renames s, e.g. once the route serving a request is known
+func (s *Span) SetName(name string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.name = name
+}
+
+// SetAttributes adds attrs to s
+func (s *Span) SetAttributes(attrs ...Attr) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attrs = append(s.attrs, attrs...)
+}
+
+// End ends s, with an error status when err isn't nil, and queues it for export
+func (s *Span) End(err error) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.end = time.Now()
+	if err != nil {
+		s.err = err.Error()
+	}
+	s.mu.Unlock()
+	s.exporter.queue(s)
+}
+
+// Extract returns ctx carrying the caller's span from a W3C traceparent
+// header, so spans started from it continue the caller's trace
+func Extract(ctx context.Context, header http.Header) context.Context {
+	// version-traceid-parentid-flags, e.g. 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01
+	parts := strings.Split(header.Get("traceparent"), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || len(parts[1]) != 32 || len(parts[2]) != 16 {
+		return ctx
+	}
+	var id spanContext
+	if _, err := hex.Decode(id.traceID[:], []byte(parts[1])); err != nil || id.traceID == [16]byte{} {
+		return ctx
+	}
+	if _, err := hex.Decode(id.spanID[:], []byte(parts[2])); err != nil || id.spanID == [8]byte{} {
+		return ctx
+	}
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// Inject sets the traceparent header of the span in ctx, when there is one
+func Inject(ctx context.Context, header http.Header) {
+	if id, ok := ctx.Value(contextKey{}).(spanContext); ok {
+		// Every span is exported, so each is sampled
+		header.Set("traceparent", "00-"+hex.EncodeToString(id.traceID[:])+"-"+hex.EncodeToString(id.spanID[:])+"-01")
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+	"github.com/JyotinderSingh/gitgraphed/render"
+)
+
+// templateFuncs are the helpers available to --template files
+var templateFuncs = template.FuncMap{
+	// formatDate reformats a YYYY-MM-DD date with a Go layout, e.g. {{formatDate "Jan 2" .Date}}
+	"formatDate": func(layout, date string) string {
+		parsed, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			return date
+		}
+		return parsed.Format(layout)
+	},
+	// levelColor maps a 0-4 level to its hex color in the GitHub theme
+	"levelColor": func(level int) string {
+		return render.LevelColor(level, render.GitHubTheme)
+	},
+	// counts extracts the daily counts of days
+	"counts": func(days []gitgraph.ContributionDay) []int {
+		counts := make([]int, len(days))
+		for i, day := range days {
+			counts[i] = day.Count
+		}
+		return counts
+	},
+	// sparkline draws counts (e.g. {{sparkline (counts .Days)}}) as ASCII characters
+	"sparkline": sparkline,
+	// percent formats part as a percentage of total
+	"percent": func(part, total int) string {
+		if total == 0 {
+			return "0%"
+		}
+		return fmt.Sprintf("%.0f%%", float64(part)/float64(total)*100)
+	},
+	"join":  strings.Join,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+// mustParseTemplate loads the --template file, exiting on parse errors
+func mustParseTemplate(path string) *template.Template {
+	tmpl, err := template.New(filepath.Base(path)).Funcs(templateFuncs).ParseFiles(path)
+	if err != nil {
+		fatal("parsing template", "err", err)
+	}
+	return tmpl
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// setWallpaper makes the image at the absolute path the desktop background,
+// using osascript on macOS, and gsettings on GNOME or feh elsewhere on Linux
+func setWallpaper(ctx context.Context, path string) error {
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf(`tell application "System Events" to tell every desktop to set picture to %q`, path)
+		return runQuiet(ctx, "osascript", "-e", script)
+	case "linux":
+		desktop := strings.ToLower(os.Getenv("XDG_CURRENT_DESKTOP"))
+		if _, err := exec.LookPath("gsettings"); err == nil && (strings.Contains(desktop, "gnome") || strings.Contains(desktop, "unity")) {
+			uri := (&url.URL{Scheme: "file", Path: path}).String()
+			if err := runQuiet(ctx, "gsettings", "set", "org.gnome.desktop.background", "picture-uri", uri); err != nil {
+				return err
+			}
+			// Only GNOME 42 and later have a separate dark-mode wallpaper
+			_ = runQuiet(ctx, "gsettings", "set", "org.gnome.desktop.background", "picture-uri-dark", uri)
+			return nil
+		}
+		if _, err := exec.LookPath("feh"); err == nil {
+			return runQuiet(ctx, "feh", "--no-fehbg", "--bg-center", path)
+		}
+		return errors.New("no supported wallpaper setter found: need gsettings on GNOME or feh")
+	}
+	return fmt.Errorf("setting the wallpaper is not supported on %s", runtime.GOOS)
+}
+
+// runQuiet runs a command, returning its output in the error when it fails
+func runQuiet(ctx context.Context, name string, args ...string) error {
+	out, err := exec.CommandContext(ctx, name, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s: %w: %s", name, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
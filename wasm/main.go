@@ -0,0 +1,162 @@
+//go:build js && wasm
+
+// Command wasm exposes gitgraphed's fetching, parsing and SVG rendering to
+// JavaScript, so browser apps share the CLI's parser. Build it with
+//
+//	GOOS=js GOARCH=wasm go build -o gitgraphed.wasm ./wasm
+//
+// and load it with the wasm_exec.js shipped in $(go env GOROOT)/lib/wasm:
+//
+//	const go = new Go();
+//	const { instance } = await WebAssembly.instantiateStreaming(fetch("gitgraphed.wasm"), go.importObject);
+//	go.run(instance);
+//	const graph = await fetchGraph("octocat", 2024, { baseURL: "https://my-cors-proxy.example" });
+//	document.body.innerHTML = renderSVG(graph, "github-dark");
+//
+// github.com doesn't allow cross-origin requests, so browsers fetch through a
+// proxy given as baseURL, or fetch the contributions page themselves and pass
+// it to parseGraph. Graphs are plain objects in the JSON format of fetch.
+// parseGraph and renderSVG return an Error instead of their result when they
+// fail, since Go functions can't throw.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"syscall/js"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+	"github.com/JyotinderSingh/gitgraphed/render"
+)
+
+func main() {
+	js.Global().Set("fetchGraph", js.FuncOf(fetchGraph))
+	js.Global().Set("parseGraph", js.FuncOf(parseGraph))
+	js.Global().Set("renderSVG", js.FuncOf(renderSVG))
+	// Keep the exported functions callable for the life of the page
+	select {}
+}
+
+// fetchGraph(username, year, {token, baseURL}) returns a Promise of the graph of
+// username's year. A year of 0 selects the current one.
+func fetchGraph(_ js.Value, args []js.Value) any {
+	if len(args) < 1 || args[0].Type() != js.TypeString {
+		return promise(func() (any, error) {
+			return nil, errors.New("fetchGraph(username, year, options) needs a username")
+		})
+	}
+	username := args[0].String()
+	year := 0
+	if len(args) > 1 && args[1].Type() == js.TypeNumber {
+		year = args[1].Int()
+	}
+	var opts []gitgraph.Option
+	token := ""
+	if len(args) > 2 && args[2].Type() == js.TypeObject {
+		if v := args[2].Get("baseURL"); v.Type() == js.TypeString {
+			opts = append(opts, gitgraph.WithBaseURL(strings.TrimRight(v.String(), "/")))
+		}
+		if v := args[2].Get("token"); v.Type() == js.TypeString {
+			token = v.String()
+		}
+	}
+
+	return promise(func() (any, error) {
+		client := gitgraph.NewClient(nil, opts...)
+		client.Token = token
+		graph, err := client.Fetch(context.Background(), username, gitgraph.Options{Year: year})
+		if err != nil {
+			return nil, err
+		}
+		return toJS(graph)
+	})
+}
+
+// parseGraph(html, username) parses a contributions page into a graph
+func parseGraph(_ js.Value, args []js.Value) any {
+	if len(args) < 1 || args[0].Type() != js.TypeString {
+		return jsError(errors.New("parseGraph(html, username) needs the contributions page"))
+	}
+	graph, err := gitgraph.ParseContributions(strings.NewReader(args[0].String()))
+	if err != nil {
+		return jsError(err)
+	}
+	if len(args) > 1 && args[1].Type() == js.TypeString {
+		graph.Username = args[1].String()
+	}
+	v, err := toJS(graph)
+	if err != nil {
+		return jsError(err)
+	}
+	return v
+}
+
+// renderSVG(graph, theme) renders a graph as an SVG document string, in the
+// named built-in theme or GitHub's light one
+func renderSVG(_ js.Value, args []js.Value) any {
+	if len(args) < 1 {
+		return jsError(errors.New("renderSVG(graph, theme) needs a graph"))
+	}
+	var graph gitgraph.ContributionGraph
+	if err := fromJS(args[0], &graph); err != nil {
+		return jsError(fmt.Errorf("invalid graph: %w", err))
+	}
+	opts := render.DefaultSVGOptions
+	if len(args) > 1 && args[1].Type() == js.TypeString && args[1].String() != "" {
+		theme, ok := render.LookupTheme(args[1].String())
+		if !ok {
+			return jsError(fmt.Errorf("unknown theme %q (built-in: %s)", args[1].String(), strings.Join(render.ThemeNames(), ", ")))
+		}
+		opts.Theme = theme
+	}
+	var svg strings.Builder
+	if err := render.SVG(&svg, &graph, opts); err != nil {
+		return jsError(err)
+	}
+	return svg.String()
+}
+
+// promise runs fn off the event loop, since network requests block until the
+// browser answers them, settling a Promise with its result
+func promise(fn func() (any, error)) js.Value {
+	var handler js.Func
+	handler = js.FuncOf(func(_ js.Value, args []js.Value) any {
+		resolve, reject := args[0], args[1]
+		go func() {
+			defer handler.Release()
+			v, err := fn()
+			if err != nil {
+				reject.Invoke(jsError(err))
+				return
+			}
+			resolve.Invoke(v)
+		}()
+		return nil
+	})
+	return js.Global().Get("Promise").New(handler)
+}
+
+// toJS converts v to a plain object through its JSON encoding
+func toJS(v any) (js.Value, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return js.Undefined(), err
+	}
+	return js.Global().Get("JSON").Call("parse", string(data)), nil
+}
+
+// fromJS decodes a plain object, or a JSON string, into v
+func fromJS(value js.Value, v any) error {
+	data := value
+	if value.Type() != js.TypeString {
+		data = js.Global().Get("JSON").Call("stringify", value)
+	}
+	return json.Unmarshal([]byte(data.String()), v)
+}
+
+func jsError(err error) js.Value {
+	return js.Global().Get("Error").New(err.Error())
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/JyotinderSingh/gitgraphed/gitgraph"
+	"github.com/JyotinderSingh/gitgraphed/store"
+)
+
+// watchEvent is one JSON line emitted by watch mode
+type watchEvent struct {
+	Type     string                    `json:"type"` // snapshot, change, daily-summary, milestone, streak-at-risk or error
+	Time     time.Time                 `json:"time"`
+	Username string                    `json:"username"`
+	Total    int                       `json:"totalContributions,omitempty"`
+	Streak   int                       `json:"currentStreak,omitempty"`
+	Change   *gitgraph.DayChange       `json:"change,omitempty"`
+	Day      *gitgraph.ContributionDay `json:"day,omitempty"` // the finished day of a daily-summary
+	// Milestone is the total reached, or for other MilestoneTypes the
+	// streak's length, the day's count or the year's first count
+	Milestone     int    `json:"milestone,omitempty"`
+	MilestoneType string `json:"milestoneType,omitempty"` // a gitgraph.Milestone type
+	MilestoneDate string `json:"milestoneDate,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+// watchOptions control polling and notifications in watch mode
+type watchOptions struct {
+	Interval   time.Duration
+	NotifyURL  string      // webhook receiving change and streak-at-risk events
+	SlackURL   string      // Slack incoming webhook receiving daily-summary, milestone and streak-at-risk events
+	DiscordURL string      // Discord webhook receiving the same events as Slack, with a heatmap
+	NudgeHour  int         // local hour after which an empty today triggers streak-at-risk
+	Desktop    bool        // show streak-at-risk and milestone events as desktop notifications
+	Store      store.Store // when set, every successful poll is saved
+}
+
+// runWatch polls every interval until interrupted, emitting a snapshot event
+// for the first fetch and a change event per day whose count changed since.
+// Events are also delivered to each configured notifier that wants them.
+func runWatch(ctx context.Context, client *gitgraph.Client, username string, p period, workers int, opts watchOptions) {
+	notifiers := newNotifiers(opts, client.HTTPClient)
+	nudge := &streakNudge{Hour: opts.NudgeHour}
+
+	// Every poll must reach upstream, but keep the cache warm for other commands
+	client.Refresh = true
+	// latest is the graph from the most recent successful poll
+	var previous, latest *gitgraph.ContributionGraph
+	encoder := json.NewEncoder(os.Stdout)
+	emit := func(event watchEvent) {
+		event.Time = time.Now()
+		event.Username = username
+		if err := encoder.Encode(event); err != nil {
+			slog.Error("encoding event", "err", err)
+		}
+		for _, n := range notifiers {
+			if !n.Wants(event.Type) {
+				continue
+			}
+			if err := n.Notify(ctx, event, latest); err != nil && ctx.Err() == nil {
+				slog.Error("sending notification", "notifier", n.Name(), "err", err)
+			}
+		}
+	}
+
+	lastDate := time.Now().Format("2006-01-02")
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		graph, err := fetchGraph(ctx, client, username, p, workers)
+		if graph != nil && graph.Stale {
+			// Report the outage rather than diff or store a cached graph
+			graph, err = nil, gitgraph.ErrCircuitOpen
+		}
+		if graph != nil {
+			latest = graph
+		}
+		switch {
+		case err != nil:
+			if ctx.Err() != nil {
+				return
+			}
+			emit(watchEvent{Type: "error", Error: err.Error()})
+		case previous == nil:
+			emit(watchEvent{Type: "snapshot", Total: graph.TotalContribs})
+		default:
+			for _, change := range gitgraph.DiffDays(previous, graph) {
+				emit(watchEvent{Type: "change", Total: graph.TotalContribs, Change: &change})
+			}
+			streak := gitgraph.ComputeStreaks(graph.Days, time.Now()).Current.Length
+			for _, m := range newMilestones(gitgraph.FindMilestones(previous.Days), gitgraph.FindMilestones(graph.Days)) {
+				emit(milestoneEvent(m, graph.TotalContribs, streak))
+			}
+		}
+		if graph != nil {
+			previous = graph
+			// The first poll of a new local day summarizes the day that just ended
+			if today := time.Now().Format("2006-01-02"); today != lastDate {
+				finished := lastDate
+				lastDate = today
+				if day, ok := findDay(graph, finished); ok {
+					streak := gitgraph.ComputeStreaks(graph.Days, time.Now()).Current.Length
+					emit(watchEvent{Type: "daily-summary", Total: graph.TotalContribs, Streak: streak, Day: &day})
+				}
+			}
+			if opts.Store != nil {
+				if err := opts.Store.Save(ctx, graph, time.Now()); err != nil && ctx.Err() == nil {
+					emit(watchEvent{Type: "error", Error: "saving to store: " + err.Error()})
+				}
+			}
+			now := time.Now()
+			streak := gitgraph.ComputeStreaks(graph.Days, now).Current.Length
+			if todayCount, ok := dayCount(graph, now); ok && nudge.Due(now, todayCount, streak) {
+				emit(watchEvent{Type: "streak-at-risk", Total: graph.TotalContribs, Streak: streak})
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// dayCount returns graph's count for the local date of t, if the graph covers it
+func dayCount(graph *gitgraph.ContributionGraph, t time.Time) (int, bool) {
+	day, ok := findDay(graph, t.Format("2006-01-02"))
+	return day.Count, ok
+}
+
+// findDay returns graph's day for date, if the graph covers it
+func findDay(graph *gitgraph.ContributionGraph, date string) (gitgraph.ContributionDay, bool) {
+	for _, day := range graph.Days {
+		if day.Date == date {
+			return day, true
+		}
+	}
+	return gitgraph.ContributionDay{}, false
+}